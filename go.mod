@@ -9,10 +9,15 @@ require (
 	cloud.google.com/go/logging v1.1.2
 	cloud.google.com/go/pubsub v1.8.3
 	cloud.google.com/go/storage v1.12.0
+	github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace v0.13.0
 	github.com/google/uuid v1.1.2
 	github.com/open-policy-agent/opa v0.24.0
+	go.opentelemetry.io/otel v0.13.0
+	go.opentelemetry.io/otel/sdk v0.13.0
 	golang.org/x/oauth2 v0.0.0-20201109201403-9fd604954f58
 	google.golang.org/api v0.35.0
 	google.golang.org/genproto v0.0.0-20201119123407-9b1e624d6bc4
+	google.golang.org/grpc v1.33.2
+	google.golang.org/protobuf v1.25.0
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,542 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convertauditlog2feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/alm"
+	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/erm"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/gps"
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	"google.golang.org/api/googleapi"
+
+	"cloud.google.com/go/functions/metadata"
+	pubsub "cloud.google.com/go/pubsub/apiv1"
+	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
+)
+
+// Severity (string) incompatible with both pakage, see convertlog2feed
+type logEntry struct {
+	InsertID         string    `json:"insertId"`
+	LogName          string    `json:"logName"`
+	Timestamp        time.Time `json:"timestamp"`
+	ReceiveTimestamp time.Time `json:"receiveTimestamp"`
+	Resource         struct {
+		Type   string            `json:"type"`
+		Labels map[string]string `json:"labels"`
+	} `json:"resource"`
+	ProtoPayload json.RawMessage `json:"protoPayload"`
+}
+
+// https://cloud.google.com/logging/docs/reference/audit/auditlog/rest/Shared.Types/AuditLog
+type protoPayload struct {
+	ServiceName  string `json:"serviceName"`
+	MethodName   string `json:"methodName"`
+	ResourceName string `json:"resourceName"`
+	Status       struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+// feedMessage Cloud Asset Inventory feed message format
+type feedMessage struct {
+	Asset     asset         `json:"asset"`
+	Window    cai.Window    `json:"window"`
+	Deleted   bool          `json:"deleted"`
+	Origin    string        `json:"origin"`
+	StepStack logging.Steps `json:"step_stack,omitempty"`
+}
+
+// asset Cloud Asset Inventory asset format
+type asset struct {
+	Name         string          `json:"name"`
+	AssetType    string          `json:"assetType"`
+	Ancestors    []string        `json:"ancestors"`
+	AncestryPath string          `json:"ancestryPath"`
+	IamPolicy    json.RawMessage `json:"iamPolicy,omitempty"`
+	Resource     *resource       `json:"resource,omitempty"`
+}
+
+// resource Cloud Asset Inventory resource format
+type resource struct {
+	DiscoveryName string          `json:"discoveryName"`
+	ResourceURL   string          `json:"resourceUrl"`
+	Parent        string          `json:"parent"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Global structure for global variables to optimize the cloud function performances
+type Global struct {
+	cloudresourcemanagerService *cloudresourcemanager.Service
+	ctx                         context.Context
+	deadLetter                  dlq.Sender
+	environment                 string
+	httpClient                  *http.Client
+	iamPoliciesTopicName        string
+	instanceName                string
+	logEntry                    logEntry
+	logStep                     logging.Step
+	mappingNames                []string
+	mappings                    map[string]alm.Mapping
+	microserviceName            string
+	projectID                   string
+	PubSubID                    string
+	pubsubPublisherClient       *pubsub.PublisherClient
+	retryTimeOutSeconds         int64
+	step                        logging.Step
+	stepStack                   logging.Steps
+}
+
+// Initialize is to be executed in the init() function of the cloud function to optimize the cold start
+func Initialize(ctx context.Context, global *Global) (err error) {
+	log.SetFlags(0)
+	global.ctx = ctx
+
+	var instanceDeployment InstanceDeployment
+
+	initID := fmt.Sprintf("%v", uuid.New())
+	err = ffo.ReadUnmarshalYAML(solution.PathToFunctionCode+solution.SettingsFileName, &instanceDeployment)
+	if err != nil {
+		log.Println(logging.Entry{
+			Severity:    "CRITICAL",
+			Message:     "init_failed",
+			Description: fmt.Sprintf("ReadUnmarshalYAML %s %v", solution.SettingsFileName, err),
+			InitID:      initID,
+		})
+		return err
+	}
+
+	global.environment = instanceDeployment.Core.EnvironmentName
+	global.instanceName = instanceDeployment.Core.InstanceName
+	global.microserviceName = instanceDeployment.Core.ServiceName
+
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "coldstart",
+		InitID:           initID,
+	})
+
+	global.iamPoliciesTopicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.IAMPolicies
+	global.mappings = instanceDeployment.Settings.Instance.AuditLog.Mappings
+	for mappingName := range global.mappings {
+		global.mappingNames = append(global.mappingNames, mappingName)
+	}
+	sort.Strings(global.mappingNames)
+	global.projectID = instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
+	global.retryTimeOutSeconds = instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds
+
+	global.httpClient, err = google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("google.DefaultClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.pubsubPublisherClient, err = pubsub.NewPublisherClient(global.ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("global.pubsubPublisherClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.cloudresourcemanagerService, err = cloudresourcemanager.NewService(ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("cloudresourcemanager.NewService %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	return nil
+}
+
+// EntryPoint is the function to be executed for each cloud function occurence
+func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, global *Global) error {
+	metadata, err := metadata.FromContext(ctxEvent)
+	if err != nil {
+		// Assume an error on the function invoker and try again.
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "redo_on_transient",
+			Description:        fmt.Sprintf("pubsub_id no available metadata.FromContext: %v", err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return err
+	}
+	global.stepStack = nil
+	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
+	parts := strings.Split(metadata.Resource.Name, "/")
+	global.step = logging.Step{
+		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
+		StepTimestamp: metadata.Timestamp,
+	}
+
+	now := time.Now()
+	d := now.Sub(metadata.Timestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:           global.microserviceName,
+		InstanceName:               global.instanceName,
+		Environment:                global.environment,
+		Severity:                   "NOTICE",
+		Message:                    "start",
+		TriggeringPubsubID:         global.PubSubID,
+		TriggeringPubsubAgeSeconds: d.Seconds(),
+		TriggeringPubsubTimestamp:  &metadata.Timestamp,
+		Now:                        &now,
+	})
+
+	if d.Seconds() > float64(global.retryTimeOutSeconds) {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:           global.microserviceName,
+			InstanceName:               global.instanceName,
+			Environment:                global.environment,
+			Severity:                   "CRITICAL",
+			Message:                    "noretry",
+			Description:                "Pubsub message too old",
+			TriggeringPubsubID:         global.PubSubID,
+			TriggeringPubsubAgeSeconds: d.Seconds(),
+			TriggeringPubsubTimestamp:  &metadata.Timestamp,
+			Now:                        &now,
+		})
+		return nil
+	}
+
+	err = json.Unmarshal(PubSubMessage.Data, &global.logEntry)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal logentry %v %v", PubSubMessage.Data, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	global.logStep.StepTimestamp = global.logEntry.Timestamp
+
+	var protoPayload protoPayload
+	err = json.Unmarshal(global.logEntry.ProtoPayload, &protoPayload)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal protoPaylaod %v %v", global.logEntry.ProtoPayload, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	global.logStep.StepID = fmt.Sprintf("%s/%s", protoPayload.ResourceName, global.logEntry.InsertID)
+	global.stepStack = append(global.stepStack, global.logStep)
+	global.stepStack = append(global.stepStack, global.step)
+
+	if protoPayload.Status.Code != 0 {
+		logCancel(fmt.Sprintf("failed call, nothing changed %s %s status %d %s", protoPayload.ServiceName, protoPayload.MethodName, protoPayload.Status.Code, protoPayload.Status.Message), global)
+		return nil
+	}
+
+	for _, mappingName := range global.mappingNames {
+		mapping := global.mappings[mappingName]
+		if matched, isDeletion := mapping.Match(protoPayload.ServiceName, protoPayload.MethodName); matched {
+			err = convertAuditLog(mapping, protoPayload.ResourceName, isDeletion, global)
+			if err != nil {
+				log.Println(logging.Entry{
+					MicroserviceName:   global.microserviceName,
+					InstanceName:       global.instanceName,
+					Environment:        global.environment,
+					Severity:           "CRITICAL",
+					Message:            "redo_on_transient",
+					Description:        fmt.Sprintf("mapping %s %v", mappingName, err),
+					TriggeringPubsubID: global.PubSubID,
+				})
+				return err
+			}
+			return nil
+		}
+	}
+	logCancel(fmt.Sprintf("no mapping for serviceName %s methodName %s", protoPayload.ServiceName, protoPayload.MethodName), global)
+	return nil
+}
+
+func convertAuditLog(mapping alm.Mapping, resourceName string, isDeletion bool, global *Global) (err error) {
+	var feedMessage feedMessage
+	feedMessage.Asset.AssetType = mapping.AssetType
+	feedMessage.Asset.Name, err = alm.ExpandTemplate(mapping.AssetName, resourceName, global.logEntry.Resource.Labels)
+	if err != nil {
+		logNoRetry(fmt.Sprintf("assetName %v", err), global)
+		return nil
+	}
+	getURL, err := alm.ExpandTemplate(mapping.GetURL, resourceName, global.logEntry.Resource.Labels)
+	if err != nil {
+		logNoRetry(fmt.Sprintf("getURL %v", err), global)
+		return nil
+	}
+	projectID := global.logEntry.Resource.Labels["project_id"]
+	if projectID == "" {
+		logNoRetry(fmt.Sprintf("no project_id label in log entry resource %s, cannot get ancestors", global.logEntry.Resource.Type), global)
+		return nil
+	}
+	feedMessage.Asset.Ancestors, err = getAncestors(projectID, global)
+	if err != nil {
+		return err
+	}
+	feedMessage.Asset.AncestryPath = cai.BuildAncestryPath(feedMessage.Asset.Ancestors)
+	feedMessage.Window.StartTime = global.logEntry.Timestamp
+	feedMessage.Origin = "real-time-log-export"
+	feedMessage.StepStack = global.stepStack
+
+	if isDeletion {
+		feedMessage.Deleted = true
+	} else {
+		var content json.RawMessage
+		var found bool
+		content, found, err = getContent(mapping.GetHTTPMethod, getURL, global)
+		if err != nil {
+			var apiError *googleapi.Error
+			if errors.As(err, &apiError) && !erm.IsTransient(err) {
+				// a 4xx answer will not change on retry
+				if erm.IsPermissionDenied(err) {
+					logNoRetry(fmt.Sprintf("%v check the mapping permissions are granted to the microservice service account", err), global)
+				} else {
+					logNoRetry(err.Error(), global)
+				}
+				return nil
+			}
+			return err
+		}
+		// Not found after a non deleting method means the asset has been deleted since
+		feedMessage.Deleted = !found
+		if found {
+			switch mapping.ContentType {
+			case "IAM_POLICY":
+				feedMessage.Asset.IamPolicy = content
+			default:
+				parts := strings.Split(mapping.AssetType, "/")
+				feedMessage.Asset.Resource = &resource{
+					DiscoveryName: parts[len(parts)-1],
+					ResourceURL:   getURL,
+					Parent:        fmt.Sprintf("//cloudresourcemanager.googleapis.com/%s", feedMessage.Asset.Ancestors[0]),
+					Data:          content,
+				}
+			}
+		}
+	}
+
+	var topicName string
+	switch mapping.ContentType {
+	case "IAM_POLICY":
+		topicName = global.iamPoliciesTopicName
+	default:
+		topicName = fmt.Sprintf("cai-rces-%s", cai.GetAssetShortTypeName(mapping.AssetType))
+	}
+	return publishFeedMessage(feedMessage, topicName, global)
+}
+
+// getAncestors returns CAI like ancestors: project number, folders, organization
+func getAncestors(projectID string, global *Global) (ancestors []string, err error) {
+	project, err := global.cloudresourcemanagerService.Projects.Get(projectID).Context(global.ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("cloudresourcemanagerService.Projects.Get %s %v", projectID, err)
+	}
+	ancestry, err := global.cloudresourcemanagerService.Projects.GetAncestry(projectID, &cloudresourcemanager.GetAncestryRequest{}).Context(global.ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("cloudresourcemanagerService.Projects.GetAncestry %s %v", projectID, err)
+	}
+	for _, ancestor := range ancestry.Ancestor {
+		switch ancestor.ResourceId.Type {
+		case "project":
+			ancestors = append(ancestors, fmt.Sprintf("projects/%d", project.ProjectNumber))
+		case "folder":
+			ancestors = append(ancestors, fmt.Sprintf("folders/%s", ancestor.ResourceId.Id))
+		case "organization":
+			ancestors = append(ancestors, fmt.Sprintf("organizations/%s", ancestor.ResourceId.Id))
+		}
+	}
+	if len(ancestors) == 0 {
+		return nil, fmt.Errorf("no ancestor found for project %s", projectID)
+	}
+	return ancestors, nil
+}
+
+// getContent calls the mapping follow up URL, found is false on http 404, other non 2xx status are returned as a wrapped googleapi.Error
+func getContent(httpMethod string, getURL string, global *Global) (content json.RawMessage, found bool, err error) {
+	var body *strings.Reader
+	if httpMethod == "POST" {
+		// e.g. getIamPolicy methods
+		body = strings.NewReader("{}")
+	} else {
+		body = strings.NewReader("")
+	}
+	request, err := http.NewRequestWithContext(global.ctx, httpMethod, getURL, body)
+	if err != nil {
+		return nil, false, fmt.Errorf("http.NewRequestWithContext %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := global.httpClient.Do(request)
+	if err != nil {
+		return nil, false, fmt.Errorf("httpClient.Do %s %s %v", httpMethod, getURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	// non 2xx status as a googleapi.Error so that erm can classify it
+	if err = googleapi.CheckResponse(response); err != nil {
+		return nil, false, fmt.Errorf("%s %s %w", httpMethod, getURL, err)
+	}
+	bytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("ioutil.ReadAll %v", err)
+	}
+	return json.RawMessage(bytes), true, nil
+}
+
+func publishFeedMessage(feedMessage feedMessage, topicShortName string, global *Global) (err error) {
+	feedMessageJSON, err := json.Marshal(feedMessage)
+	if err != nil {
+		logNoRetry(fmt.Sprintf("json.Marshal(feedMessage) %v %v", feedMessage, err), global)
+		return nil
+	}
+	var pubSubMessage pubsubpb.PubsubMessage
+	pubSubMessage.Data = feedMessageJSON
+	pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
+
+	var pubsubMessages []*pubsubpb.PubsubMessage
+	pubsubMessages = append(pubsubMessages, &pubSubMessage)
+
+	var publishRequest pubsubpb.PublishRequest
+	topicName := fmt.Sprintf("projects/%s/topics/%s", global.projectID, topicShortName)
+	publishRequest.Topic = topicName
+	publishRequest.Messages = pubsubMessages
+
+	pubsubResponse, err := global.pubsubPublisherClient.Publish(global.ctx, &publishRequest)
+	if err != nil {
+		return fmt.Errorf("%s global.pubsubPublisherClient.Publish: %v", topicShortName, err)
+	}
+	now := time.Now()
+	latency := now.Sub(global.step.StepTimestamp)
+	latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:     global.microserviceName,
+		InstanceName:         global.instanceName,
+		Environment:          global.environment,
+		Severity:             "NOTICE",
+		Message:              fmt.Sprintf("finish %s", feedMessage.Asset.Name),
+		Description:          fmt.Sprintf("asset published to pubsub (isdeleted status=%v) %s topic %s ids %v %s", feedMessage.Deleted, feedMessage.Asset.Name, topicName, pubsubResponse.MessageIds, string(feedMessageJSON)),
+		Now:                  &now,
+		TriggeringPubsubID:   global.PubSubID,
+		OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+		LatencySeconds:       latency.Seconds(),
+		LatencyE2ESeconds:    latencyE2E.Seconds(),
+		StepStack:            global.stepStack,
+	})
+	return nil
+}
+
+func logCancel(description string, global *Global) {
+	now := time.Now()
+	latency := now.Sub(global.step.StepTimestamp)
+	latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:     global.microserviceName,
+		InstanceName:         global.instanceName,
+		Environment:          global.environment,
+		Severity:             "NOTICE",
+		Message:              "cancel",
+		Description:          description,
+		Now:                  &now,
+		TriggeringPubsubID:   global.PubSubID,
+		OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+		LatencySeconds:       latency.Seconds(),
+		LatencyE2ESeconds:    latencyE2E.Seconds(),
+		StepStack:            global.stepStack,
+	})
+}
+
+func logNoRetry(description string, global *Global) {
+	global.deadLetter.NoRetry(logging.Entry{
+		MicroserviceName:   global.microserviceName,
+		InstanceName:       global.instanceName,
+		Environment:        global.environment,
+		Severity:           "CRITICAL",
+		Message:            "noretry",
+		Description:        description,
+		TriggeringPubsubID: global.PubSubID,
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package convertauditlog2feed convert Cloud Audit Log entries into Cloud Asset Inventory like feed messages for GCP resources not covered by CAI real-time feeds
+
+Instances
+
+One per monitored organization, triggered by the log-org<organizationID>-audit-resources topic, set by setlogsinks with a filter built from the mappings.
+
+Mappings
+
+Defined in solution.yaml monitoring.auditLogMappings, one entry per mapping name:
+
+ auditLogMappings:
+   bigqueryTableIAM:
+     serviceName: bigquery.googleapis.com
+     methodNames:
+     - google.iam.v1.IAMPolicy.SetIamPolicy
+     logName: activity
+     assetType: bigquery.googleapis.com/Table
+     contentType: IAM_POLICY
+     assetName: //bigquery.googleapis.com/{resourceName}
+     getURL: https://bigquery.googleapis.com/bigquery/v2/{resourceName}:getIamPolicy
+     getHTTPMethod: POST
+     permissions:
+     - bigquery.tables.getIamPolicy
+   secretManagerSecretVersion:
+     serviceName: secretmanager.googleapis.com
+     methodNames:
+     - google.cloud.secretmanager.v1.SecretManagerService.AddSecretVersion
+     - google.cloud.secretmanager.v1.SecretManagerService.DisableSecretVersion
+     - google.cloud.secretmanager.v1.SecretManagerService.EnableSecretVersion
+     deleteMethodNames:
+     - google.cloud.secretmanager.v1.SecretManagerService.DestroySecretVersion
+     logName: activity
+     assetType: secretmanager.googleapis.com/SecretVersion
+     contentType: RESOURCE
+     assetName: //secretmanager.googleapis.com/{resourceName}
+     getURL: https://secretmanager.googleapis.com/v1/{resourceName}
+     getHTTPMethod: GET
+     permissions:
+     - secretmanager.versions.get
+   runServiceIAM:
+     serviceName: run.googleapis.com
+     methodNames:
+     - google.cloud.run.v1.Services.SetIamPolicy
+     logName: activity
+     assetType: run.googleapis.com/Service
+     contentType: IAM_POLICY
+     assetName: //run.googleapis.com/{resourceName}
+     getURL: https://run.googleapis.com/v1/{resourceName}:getIamPolicy
+     getHTTPMethod: GET
+     permissions:
+     - run.services.getIamPolicy
+
+Templates accept {resourceName}, the audit log protoPayload.resourceName, and {label.<key>}, a label of the log entry resource, e.g. {label.project_id}.
+
+Output
+
+RESOURCE mappings publish to cai-rces-<serviceName>-<assetTypeName>, the same topic than CAI feeds, so monitor instances apply. IAM_POLICY mappings publish to the IAM policies topic.
+
+Cardinality
+
+One-one: one log entry, one feed message. Failed calls and log entries matching no mapping are canceled.
+
+Notes
+
+- The follow up call gets the asset current state, using the microservice service account. Its custom role on the monitoring org is completed with the mapping permissions.
+
+- A not found answer to the follow up call publishes the asset as deleted. Other 4xx answers, e.g. permission denied, are sent to the dead letter topic, 429 and 5xx are retried.
+
+- Data access audit logs must be enabled on the monitored organization for data_access mappings.
+*/
+package convertauditlog2feed
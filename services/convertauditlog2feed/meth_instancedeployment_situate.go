@@ -0,0 +1,53 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convertauditlog2feed
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/str"
+)
+
+// Situate complement settings taking in account the situation for service and instance settings
+func (instanceDeployment *InstanceDeployment) Situate() (err error) {
+	instanceDeployment.Settings.Service.GCF.FunctionType = "backgroundPubSub"
+	instanceDeployment.Settings.Service.GCF.Description = fmt.Sprintf("For each audit log entry published to Pubsub topic %s get the asset current state to publish a feed like message to Pubsub adhoc topics",
+		instanceDeployment.Settings.Instance.GCF.TriggerTopic)
+
+	instanceDeployment.Artifacts.OutputTopicNames = []string{}
+	for _, mapping := range instanceDeployment.Settings.Instance.AuditLog.Mappings {
+		var topicName string
+		switch mapping.ContentType {
+		case "IAM_POLICY":
+			topicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.IAMPolicies
+		default:
+			topicName = fmt.Sprintf("cai-rces-%s", cai.GetAssetShortTypeName(mapping.AssetType))
+		}
+		if !str.Find(instanceDeployment.Artifacts.OutputTopicNames, topicName) {
+			instanceDeployment.Artifacts.OutputTopicNames = append(instanceDeployment.Artifacts.OutputTopicNames, topicName)
+		}
+		// the run role on the monitoring org is completed with the permissions the mappings follow up calls need
+		for _, permission := range mapping.Permissions {
+			if !str.Find(instanceDeployment.Settings.Service.IAM.RunRoles.MonitoringOrg[0].IncludedPermissions, permission) {
+				instanceDeployment.Settings.Service.IAM.RunRoles.MonitoringOrg[0].IncludedPermissions = append(instanceDeployment.Settings.Service.IAM.RunRoles.MonitoringOrg[0].IncludedPermissions, permission)
+			}
+		}
+	}
+	sort.Strings(instanceDeployment.Artifacts.OutputTopicNames)
+	sort.Strings(instanceDeployment.Settings.Service.IAM.RunRoles.MonitoringOrg[0].IncludedPermissions)
+	return nil
+}
@@ -19,11 +19,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/BrunoReboul/ram/utilities/aut"
 	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gfs"
 	"github.com/BrunoReboul/ram/utilities/gps"
@@ -59,10 +61,13 @@ type logEntry struct {
 
 // https://developers.google.com/admin-sdk/reports/v1/reference/activity-ref-appendix-a/admin-event-names
 type protoPayload struct {
-	ServiceName  string `json:"serviceName"`
-	MethodName   string `json:"methodName"`
-	ResourceName string `json:"resourceName"`
-	Metadata     struct {
+	ServiceName        string `json:"serviceName"`
+	MethodName         string `json:"methodName"`
+	ResourceName       string `json:"resourceName"`
+	AuthenticationInfo struct {
+		PrincipalEmail string `json:"principalEmail"`
+	} `json:"authenticationInfo"`
+	Metadata struct {
 		Events []event `json:"event"`
 	} `json:"metadata"`
 }
@@ -81,11 +86,30 @@ type groupSettingsParameters []struct {
 	Value string `json:"value"`
 }
 
+// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-domain-settings
+// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings
+type adminSettingsParameters []struct {
+	Label string `json:"label"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-user-settings
+type userSettingsParameters []struct {
+	Label string `json:"label"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
 // Global structure for global variables to optimize the cloud function performances
 type Global struct {
 	cloudresourcemanagerService *cloudresourcemanager.Service
 	collectionID                string
+	convertUserSettings         bool
 	ctx                         context.Context
+	deadLetter                  dlq.Sender
 	dirAdminService             *admin.Service
 	directoryCustomerID         string
 	environment                 string
@@ -144,6 +168,7 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 
 	gciAdminUserToImpersonate := instanceDeployment.Settings.Instance.GCI.SuperAdminEmail
 	global.collectionID = instanceDeployment.Core.SolutionSettings.Hosting.FireStore.CollectionIDs.Assets
+	global.convertUserSettings = instanceDeployment.Settings.Instance.GCI.UserSettings
 	global.GCIGroupMembersTopicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupMembers
 	global.GCIGroupSettingsTopicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupSettings
 	global.projectID = instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
@@ -153,6 +178,10 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 	serviceAccountEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
 		instanceDeployment.Core.ServiceName,
 		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID)
+	scopes := []string{"https://www.googleapis.com/auth/apps.groups.settings", "https://www.googleapis.com/auth/admin.directory.group.readonly"}
+	if global.convertUserSettings {
+		scopes = append(scopes, admin.AdminDirectoryUserReadonlyScope)
+	}
 
 	global.firestoreClient, err = firestore.NewClient(global.ctx, global.projectID)
 	if err != nil {
@@ -168,32 +197,45 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		return err
 	}
 
-	serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
-	if err != nil {
-		log.Println(logging.Entry{
-			MicroserviceName: global.microserviceName,
-			InstanceName:     global.instanceName,
-			Environment:      global.environment,
-			Severity:         "CRITICAL",
-			Message:          "init_failed",
-			Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
-			InitID:           initID,
-		})
-		return err
-	}
+	if instanceDeployment.Settings.Instance.GCI.Keyless {
+		if clientOption, ok = aut.GetClientOptionKeyless(ctx,
+			serviceAccountEmail,
+			gciAdminUserToImpersonate,
+			scopes,
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionKeyless")
+		}
+	} else {
+		serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "init_failed",
+				Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
+				InitID:           initID,
+			})
+			return err
+		}
 
-	if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
-		serviceAccountEmail,
-		keyJSONFilePath,
-		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
-		gciAdminUserToImpersonate,
-		[]string{"https://www.googleapis.com/auth/apps.groups.settings", "https://www.googleapis.com/auth/admin.directory.group.readonly"},
-		serviceAccountKeyNames,
-		initID,
-		global.microserviceName,
-		global.instanceName,
-		global.environment); !ok {
-		return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
+		if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
+			serviceAccountEmail,
+			keyJSONFilePath,
+			instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+			gciAdminUserToImpersonate,
+			scopes,
+			serviceAccountKeyNames,
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
+		}
 	}
 	global.dirAdminService, err = admin.NewService(ctx, clientOption)
 	if err != nil {
@@ -256,6 +298,24 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			InitID:           initID,
 		})
 	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
 	return nil
 }
 
@@ -278,6 +338,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	global.stepStack = nil
 	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -299,7 +360,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
@@ -316,7 +377,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 
 	err = json.Unmarshal(PubSubMessage.Data, &global.logEntry)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -340,22 +401,14 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 			return nil
 		default:
 			log.Printf("pubsub_id %s NORETRY_ERROR unmanaged global.logEntry.Resource.Labels service  %s", global.PubSubID, global.logEntry.Resource.Labels["service"])
-			now := time.Now()
-			latency := now.Sub(global.step.StepTimestamp)
-			latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
-			log.Println(logging.Entry{
-				MicroserviceName:     global.microserviceName,
-				InstanceName:         global.instanceName,
-				Environment:          global.environment,
-				Severity:             "NOTICE",
-				Message:              "cancel",
-				Description:          fmt.Sprintf("unmanaged global.logEntry.Resource.Labels service  %s", global.logEntry.Resource.Labels["service"]),
-				Now:                  &now,
-				TriggeringPubsubID:   global.PubSubID,
-				OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
-				LatencySeconds:       latency.Seconds(),
-				LatencyE2ESeconds:    latencyE2E.Seconds(),
-				StepStack:            global.stepStack,
+			global.deadLetter.NoRetry(logging.Entry{
+				MicroserviceName:   global.microserviceName,
+				InstanceName:       global.instanceName,
+				Environment:        global.environment,
+				Severity:           "CRITICAL",
+				Message:            "noretry",
+				Description:        fmt.Sprintf("unmanaged global.logEntry.Resource.Labels service  %s", global.logEntry.Resource.Labels["service"]),
+				TriggeringPubsubID: global.PubSubID,
 			})
 			return nil
 		}
@@ -387,7 +440,7 @@ func convertAdminActivityEvent(global *Global) (err error) {
 
 	err = json.Unmarshal(global.logEntry.ProtoPayload, &protoPayload)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -406,7 +459,7 @@ func convertAdminActivityEvent(global *Global) (err error) {
 	global.organizationID = parts[1]
 	getCustomerID(global)
 	if global.directoryCustomerID == "" {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -418,33 +471,52 @@ func convertAdminActivityEvent(global *Global) (err error) {
 		return nil
 	}
 
-	for _, event := range protoPayload.Metadata.Events {
-		switch event.EventType {
-		case "GROUP_SETTINGS":
-			return convertGroupSettings(&event, global)
-		default:
-			now := time.Now()
-			latency := now.Sub(global.step.StepTimestamp)
-			latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
-			log.Println(logging.Entry{
-				MicroserviceName:     global.microserviceName,
-				InstanceName:         global.instanceName,
-				Environment:          global.environment,
-				Severity:             "NOTICE",
-				Message:              "cancel",
-				Description:          fmt.Sprintf("unmanaged event.EventType %s", event.EventType),
-				Now:                  &now,
-				TriggeringPubsubID:   global.PubSubID,
-				OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
-				LatencySeconds:       latency.Seconds(),
-				LatencyE2ESeconds:    latencyE2E.Seconds(),
-				StepStack:            global.stepStack,
-			})
-			return nil
+	// A log entry may hold several events, e.g. a group creation with its settings
+	// All events are converted, a transient error on one event retries the full log entry
+	for i := range protoPayload.Metadata.Events {
+		err = convertEvent(&protoPayload.Metadata.Events[i], protoPayload.AuthenticationInfo.PrincipalEmail, global)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
+
+func convertEvent(event *event, actorEmail string, global *Global) (err error) {
+	switch event.EventType {
+	case "GROUP_SETTINGS":
+		return convertGroupSettings(event, global)
+	case "DOMAIN_SETTINGS":
+		return convertDomainSettings(event, actorEmail, global)
+	case "DELEGATED_ADMIN_SETTINGS":
+		return convertDelegatedAdminSettings(event, actorEmail, global)
+	case "USER_SETTINGS":
+		if global.convertUserSettings {
+			return convertUserSettings(event, global)
+		}
+		fallthrough
+	default:
+		now := time.Now()
+		latency := now.Sub(global.step.StepTimestamp)
+		latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+		log.Println(logging.Entry{
+			MicroserviceName:     global.microserviceName,
+			InstanceName:         global.instanceName,
+			Environment:          global.environment,
+			Severity:             "NOTICE",
+			Message:              "cancel",
+			Description:          fmt.Sprintf("unmanaged event.EventType %s", event.EventType),
+			Now:                  &now,
+			TriggeringPubsubID:   global.PubSubID,
+			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+			LatencySeconds:       latency.Seconds(),
+			LatencyE2ESeconds:    latencyE2E.Seconds(),
+			StepStack:            global.stepStack,
+		})
+		return nil
+	}
+}
+
 func getCustomerID(global *Global) {
 	documentID := fmt.Sprintf("//cloudresourcemanager.googleapis.com/organizations/%s", global.organizationID)
 	documentID = str.RevertSlash(documentID)
@@ -523,7 +595,7 @@ func convertGroupSettings(event *event, global *Global) (err error) {
 	var parameters groupSettingsParameters
 	err = json.Unmarshal(event.Parameter, &parameters)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -543,7 +615,7 @@ func convertGroupSettings(event *event, global *Global) (err error) {
 		}
 	}
 	if groupEmail == "" {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -571,11 +643,20 @@ func convertGroupSettings(event *event, global *Global) (err error) {
 			switch parameter.Name {
 			case "USER_EMAIL":
 				// The parmeter is no only a user email. It is a member email, can be group, service account or user
-				memberEmail = parameter.Value
+				memberEmail = strings.ToLower(parameter.Value)
 			}
 		}
 		if memberEmail == "" {
 			log.Printf("pubsub_id %s NORETRY_ERROR ADD_GROUP_MEMBER expected parameter USER_EMAIL aka member, not found, insertId %s", global.PubSubID, global.logEntry.InsertID)
+			global.deadLetter.NoRetry(logging.Entry{
+				MicroserviceName:   global.microserviceName,
+				InstanceName:       global.instanceName,
+				Environment:        global.environment,
+				Severity:           "CRITICAL",
+				Message:            "noretry",
+				Description:        fmt.Sprintf("ADD_GROUP_MEMBER expected parameter USER_EMAIL aka member, not found, insertId %s", global.logEntry.InsertID),
+				TriggeringPubsubID: global.PubSubID,
+			})
 			return nil
 		}
 		return publishGroupMemberCreationOrUpdate(groupEmail, memberEmail, global)
@@ -586,18 +667,18 @@ func convertGroupSettings(event *event, global *Global) (err error) {
 			switch parameter.Name {
 			case "USER_EMAIL":
 				// The parmeter is no only a user email. It is a member email, can be group, service account or user
-				memberEmail = parameter.Value
+				memberEmail = strings.ToLower(parameter.Value)
 			}
 		}
 		if memberEmail == "" {
-			log.Printf("pubsub_id %s NORETRY_ERROR ADD_GROUP_MEMBER expected parameter USER_EMAIL aka member, not found, insertId %s", global.PubSubID, global.logEntry.InsertID)
-			log.Println(logging.Entry{
+			log.Printf("pubsub_id %s NORETRY_ERROR REMOVE_GROUP_MEMBER expected parameter USER_EMAIL aka member, not found, insertId %s", global.PubSubID, global.logEntry.InsertID)
+			global.deadLetter.NoRetry(logging.Entry{
 				MicroserviceName:   global.microserviceName,
 				InstanceName:       global.instanceName,
 				Environment:        global.environment,
 				Severity:           "CRITICAL",
 				Message:            "noretry",
-				Description:        fmt.Sprintf("ADD_GROUP_MEMBER expected parameter USER_EMAIL aka member, not found, insertId %s", global.logEntry.InsertID),
+				Description:        fmt.Sprintf("REMOVE_GROUP_MEMBER expected parameter USER_EMAIL aka member, not found, insertId %s", global.logEntry.InsertID),
 				TriggeringPubsubID: global.PubSubID,
 			})
 			return nil
@@ -758,7 +839,7 @@ func publishGroupDeletion(groupEmail string, global *Global) (err error) {
 func publishGroup(feedMessage interface{}, isDeleted bool, groupEmail string, assetName string, global *Global) (err error) {
 	feedMessageJSON, err := json.Marshal(feedMessage)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -771,6 +852,7 @@ func publishGroup(feedMessage interface{}, isDeleted bool, groupEmail string, as
 	}
 	var pubSubMessage pubsubpb.PubsubMessage
 	pubSubMessage.Data = feedMessageJSON
+	pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
 
 	var pubsubMessages []*pubsubpb.PubsubMessage
 	pubsubMessages = append(pubsubMessages, &pubSubMessage)
@@ -778,7 +860,7 @@ func publishGroup(feedMessage interface{}, isDeleted bool, groupEmail string, as
 	var publishRequest pubsubpb.PublishRequest
 	topicShortName := fmt.Sprintf("gci-groups-%s", global.directoryCustomerID)
 	if err = gps.CreateTopic(global.ctx, global.pubsubPublisherClient, &global.topicList, topicShortName, global.projectID); err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -964,7 +1046,7 @@ func publishGroupMember(feedMessage interface{}, isDeleted bool, groupEmail stri
 	feedMessageJSON, err := json.Marshal(feedMessage)
 	if err != nil {
 		log.Printf("pubsub_id %s NORETRY_ERROR %s json.Marshal(feedMessage): %v", global.PubSubID, assetName, err)
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -977,6 +1059,7 @@ func publishGroupMember(feedMessage interface{}, isDeleted bool, groupEmail stri
 	}
 	var pubSubMessage pubsubpb.PubsubMessage
 	pubSubMessage.Data = feedMessageJSON
+	pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
 
 	var pubsubMessages []*pubsubpb.PubsubMessage
 	pubsubMessages = append(pubsubMessages, &pubSubMessage)
@@ -1037,7 +1120,7 @@ func publishGroupSettings(groupEmail string, global *Global) (err error) {
 	feedMessageGroupSettingsJSON, err := json.Marshal(feedMessageGroupSettings)
 	if err != nil {
 		log.Printf("pubsub_id %s NORETRY_ERROR json.Marshal(feedMessageGroupSettings)", global.PubSubID)
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -1051,6 +1134,7 @@ func publishGroupSettings(groupEmail string, global *Global) (err error) {
 
 	var pubSubMessage pubsubpb.PubsubMessage
 	pubSubMessage.Data = feedMessageGroupSettingsJSON
+	pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageGroupSettingsJSON)
 
 	var pubsubMessages []*pubsubpb.PubsubMessage
 	pubsubMessages = append(pubsubMessages, &pubSubMessage)
@@ -1084,6 +1168,414 @@ func publishGroupSettings(groupEmail string, global *Global) (err error) {
 	return nil
 }
 
+// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-user-settings
+func convertUserSettings(event *event, global *Global) (err error) {
+	var parameters userSettingsParameters
+	err = json.Unmarshal(event.Parameter, &parameters)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal(event.Parameter, &parameters) %v %v", event.Parameter, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	var userEmail string
+	for _, parameter := range parameters {
+		switch parameter.Name {
+		case "USER_EMAIL":
+			userEmail = strings.ToLower(parameter.Value)
+		}
+	}
+	if userEmail == "" {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("expected parameter USER_EMAIL not found, insertId %s", global.logEntry.InsertID),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	switch event.EventName {
+	case "DELETE_USER":
+		// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-user-settings#DELETE_USER
+		return publishUserDeletion(userEmail, global)
+	default:
+		// Any other user settings event, e.g. CREATE_USER, SUSPEND_USER, GRANT_ADMIN_PRIVILEGE, CHANGE_USER_ORGANIZATION, ENFORCE_STRONG_AUTHENTICATION
+		// the user is read again to publish its current state
+		return publishUserCreationOrUpdate(userEmail, global)
+	}
+}
+
+func publishUserCreationOrUpdate(userEmail string, global *Global) (err error) {
+	// userKey: The value can be the user's primary email address, alias email address, or unique user ID.
+	// https://developers.google.com/admin-sdk/directory/v1/reference/users/get
+	user, err := global.dirAdminService.Users.Get(userEmail).Context(global.ctx).Do()
+	if err != nil {
+		return fmt.Errorf("dirAdminService.Users.Get %v", err)
+	}
+	feedMessage := cai.MakeFeedMessageUser(global.directoryCustomerID, user)
+	feedMessage.Window.StartTime = global.logEntry.Timestamp
+	feedMessage.Origin = "real-time-log-export"
+	feedMessage.Deleted = false
+	feedMessage.StepStack = global.stepStack
+	return publishUser(feedMessage, feedMessage.Deleted, userEmail, feedMessage.Asset.Name, global)
+}
+
+func publishUserDeletion(userEmail string, global *Global) (err error) {
+	assets := global.firestoreClient.Collection(global.collectionID)
+	query := assets.Where(
+		"asset.assetType", "==", "www.googleapis.com/admin/directory/users").Where(
+		"asset.resource.primaryEmail", "==", userEmail)
+	var documentSnap *firestore.DocumentSnapshot
+	iter := query.Documents(global.ctx)
+	defer iter.Stop()
+	type cachedFeedMessageUser struct {
+		Asset struct {
+			Name         string   `firestore:"name"`
+			Ancestors    []string `firestore:"ancestors"`
+			AncestryPath string   `firestore:"ancestryPath"`
+			Resource     struct {
+				PrimaryEmail string `firestore:"primaryEmail"`
+				ID           string `firestore:"id"`
+				Kind         string `firestore:"kind"`
+			} `firestore:"resource"`
+		} `firestore:"asset"`
+	}
+	found := false
+	// multiple documents may be found in case of orphans in cache
+	for {
+		documentSnap, err = iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("publishUserDeletion iter.Next() %v", err)
+		}
+		if !documentSnap.Exists() {
+			return fmt.Errorf("document does not exist %s", documentSnap.Ref.Path)
+		}
+		found = true
+		var retreivedFeedMessageUser cachedFeedMessageUser
+		err = documentSnap.DataTo(&retreivedFeedMessageUser)
+		if err != nil {
+			return fmt.Errorf("publishUserDeletion documentSnap.DataTo %v", err)
+		}
+		var feedMessage cai.FeedMessageUser
+		feedMessage.Asset.Name = retreivedFeedMessageUser.Asset.Name
+		feedMessage.Asset.AssetType = "www.googleapis.com/admin/directory/users"
+		feedMessage.Asset.Ancestors = retreivedFeedMessageUser.Asset.Ancestors
+		feedMessage.Asset.AncestryPath = retreivedFeedMessageUser.Asset.AncestryPath
+		feedMessage.Asset.Resource.PrimaryEmail = retreivedFeedMessageUser.Asset.Resource.PrimaryEmail
+		feedMessage.Asset.Resource.ID = retreivedFeedMessageUser.Asset.Resource.ID
+		feedMessage.Asset.Resource.Kind = retreivedFeedMessageUser.Asset.Resource.Kind
+		feedMessage.Window.StartTime = global.logEntry.Timestamp
+		feedMessage.Origin = "real-time-log-export"
+		feedMessage.Deleted = true
+		feedMessage.StepStack = global.stepStack
+		err = publishUser(feedMessage, feedMessage.Deleted, userEmail, feedMessage.Asset.Name, global)
+		if err != nil {
+			return fmt.Errorf("publishUser(feedMessage %v", err)
+		}
+	}
+	if !found {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "WARNING",
+			Message:            "deleted user not found in cache, cannot clean up RAM data",
+			Description:        fmt.Sprintf("userEmail %s", userEmail),
+			TriggeringPubsubID: global.PubSubID,
+		})
+	}
+	return nil
+}
+
+func publishUser(feedMessage interface{}, isDeleted bool, userEmail string, assetName string, global *Global) (err error) {
+	feedMessageJSON, err := json.Marshal(feedMessage)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("publishUser json.Marshal(feedMessage) %v %v", feedMessage, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	var pubSubMessage pubsubpb.PubsubMessage
+	pubSubMessage.Data = feedMessageJSON
+	pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
+
+	var pubsubMessages []*pubsubpb.PubsubMessage
+	pubsubMessages = append(pubsubMessages, &pubSubMessage)
+
+	var publishRequest pubsubpb.PublishRequest
+	topicShortName := fmt.Sprintf("gci-users-%s", global.directoryCustomerID)
+	if err = gps.CreateTopic(global.ctx, global.pubsubPublisherClient, &global.topicList, topicShortName, global.projectID); err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("gps.CreateTopic %s %v", topicShortName, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	topicName := fmt.Sprintf("projects/%s/topics/%s", global.projectID, topicShortName)
+	publishRequest.Topic = topicName
+	publishRequest.Messages = pubsubMessages
+
+	pubsubResponse, err := global.pubsubPublisherClient.Publish(global.ctx, &publishRequest)
+	if err != nil {
+		return fmt.Errorf("%s global.pubsubPublisherClient.Publish: %v", topicShortName, err)
+	}
+	now := time.Now()
+	latency := now.Sub(global.step.StepTimestamp)
+	latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:     global.microserviceName,
+		InstanceName:         global.instanceName,
+		Environment:          global.environment,
+		Severity:             "NOTICE",
+		Message:              fmt.Sprintf("finish user %s", userEmail),
+		Description:          fmt.Sprintf("user published to pubsub %s (isdeleted status=%v) %s topic %s ids %v %s", userEmail, isDeleted, assetName, topicName, pubsubResponse.MessageIds, string(feedMessageJSON)),
+		Now:                  &now,
+		TriggeringPubsubID:   global.PubSubID,
+		OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+		LatencySeconds:       latency.Seconds(),
+		LatencyE2ESeconds:    latencyE2E.Seconds(),
+		StepStack:            global.stepStack,
+	})
+	return nil
+}
+
+// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-domain-settings
+func convertDomainSettings(event *event, actorEmail string, global *Global) (err error) {
+	var parameters adminSettingsParameters
+	err = json.Unmarshal(event.Parameter, &parameters)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal(event.Parameter, &parameters) %v %v", event.Parameter, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	var feedMessage cai.FeedMessageDomainSetting
+	feedMessage.Asset.Resource.EventName = event.EventName
+	feedMessage.Asset.Resource.ActorEmail = actorEmail
+	for _, parameter := range parameters {
+		switch parameter.Name {
+		case "SETTING_NAME":
+			feedMessage.Asset.Resource.SettingName = parameter.Value
+		case "APPLICATION_NAME":
+			feedMessage.Asset.Resource.ApplicationName = parameter.Value
+		case "DOMAIN_NAME":
+			feedMessage.Asset.Resource.DomainName = parameter.Value
+		case "ORG_UNIT_NAME":
+			feedMessage.Asset.Resource.OrgUnitName = parameter.Value
+		case "GROUP_EMAIL":
+			feedMessage.Asset.Resource.GroupEmail = strings.ToLower(parameter.Value)
+		case "OLD_VALUE":
+			feedMessage.Asset.Resource.OldValue = parameter.Value
+		case "NEW_VALUE":
+			feedMessage.Asset.Resource.NewValue = parameter.Value
+		}
+	}
+	// There is no API to read domain settings: the asset is the last change seen for a given setting and scope
+	assetName := fmt.Sprintf("//directories/%s/domainSettings/%s", global.directoryCustomerID, event.EventName)
+	for _, part := range []string{feedMessage.Asset.Resource.ApplicationName,
+		feedMessage.Asset.Resource.SettingName,
+		feedMessage.Asset.Resource.DomainName,
+		feedMessage.Asset.Resource.OrgUnitName,
+		feedMessage.Asset.Resource.GroupEmail} {
+		if part != "" {
+			assetName = fmt.Sprintf("%s/%s", assetName, url.PathEscape(part))
+		}
+	}
+	feedMessage.Asset.Name = assetName
+	feedMessage.Asset.AssetType = "www.googleapis.com/admin/directory/domainSettings"
+	feedMessage.Asset.Ancestors = []string{fmt.Sprintf("directories/%s", global.directoryCustomerID)}
+	feedMessage.Asset.AncestryPath = fmt.Sprintf("directories/%s", global.directoryCustomerID)
+	feedMessage.Window.StartTime = global.logEntry.Timestamp
+	feedMessage.Origin = "real-time-log-export"
+	feedMessage.Deleted = false
+	feedMessage.StepStack = global.stepStack
+	return publishAdminSetting(feedMessage, feedMessage.Deleted,
+		fmt.Sprintf("gci-domainSettings-%s", global.directoryCustomerID), assetName, global)
+}
+
+// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings
+func convertDelegatedAdminSettings(event *event, actorEmail string, global *Global) (err error) {
+	var isDeleted bool
+	switch event.EventName {
+	case "ASSIGN_ROLE":
+		// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings#ASSIGN_ROLE
+		isDeleted = false
+	case "UNASSIGN_ROLE":
+		// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings#UNASSIGN_ROLE
+		isDeleted = true
+	default:
+		now := time.Now()
+		latency := now.Sub(global.step.StepTimestamp)
+		latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+		log.Println(logging.Entry{
+			MicroserviceName:     global.microserviceName,
+			InstanceName:         global.instanceName,
+			Environment:          global.environment,
+			Severity:             "NOTICE",
+			Message:              "cancel",
+			Description:          fmt.Sprintf("unmanaged event.EventName %s", event.EventName),
+			Now:                  &now,
+			TriggeringPubsubID:   global.PubSubID,
+			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+			LatencySeconds:       latency.Seconds(),
+			LatencyE2ESeconds:    latencyE2E.Seconds(),
+			StepStack:            global.stepStack,
+		})
+		return nil
+		// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings#CREATE_ROLE
+		// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings#ADD_PRIVILEGE
+		// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings#REMOVE_PRIVILEGE
+		// https://developers.google.com/admin-sdk/reports/v1/appendix/activity/admin-delegated-admin-settings#DELETE_ROLE
+	}
+	var parameters adminSettingsParameters
+	err = json.Unmarshal(event.Parameter, &parameters)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal(event.Parameter, &parameters) %v %v", event.Parameter, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	var feedMessage cai.FeedMessageRoleAssignment
+	feedMessage.Asset.Resource.ActorEmail = actorEmail
+	for _, parameter := range parameters {
+		switch parameter.Name {
+		case "ROLE_NAME":
+			feedMessage.Asset.Resource.RoleName = parameter.Value
+		case "USER_EMAIL":
+			feedMessage.Asset.Resource.AssignedTo = strings.ToLower(parameter.Value)
+		case "ORG_UNIT_NAME":
+			feedMessage.Asset.Resource.OrgUnitName = parameter.Value
+		}
+	}
+	if feedMessage.Asset.Resource.RoleName == "" || feedMessage.Asset.Resource.AssignedTo == "" {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("%s expected parameters ROLE_NAME and USER_EMAIL not found, insertId %s", event.EventName, global.logEntry.InsertID),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	// The event references the role name and user email, not the role assignment ID: the asset name is built from them
+	assetName := fmt.Sprintf("//directories/%s/roleAssignments/%s/users/%s",
+		global.directoryCustomerID,
+		url.PathEscape(feedMessage.Asset.Resource.RoleName),
+		feedMessage.Asset.Resource.AssignedTo)
+	if feedMessage.Asset.Resource.OrgUnitName != "" {
+		assetName = fmt.Sprintf("%s/orgUnits/%s", assetName, url.PathEscape(feedMessage.Asset.Resource.OrgUnitName))
+	}
+	feedMessage.Asset.Name = assetName
+	feedMessage.Asset.AssetType = "www.googleapis.com/admin/directory/roleAssignments"
+	feedMessage.Asset.Ancestors = []string{fmt.Sprintf("directories/%s", global.directoryCustomerID)}
+	feedMessage.Asset.AncestryPath = fmt.Sprintf("directories/%s", global.directoryCustomerID)
+	feedMessage.Window.StartTime = global.logEntry.Timestamp
+	feedMessage.Origin = "real-time-log-export"
+	feedMessage.Deleted = isDeleted
+	feedMessage.StepStack = global.stepStack
+	return publishAdminSetting(feedMessage, feedMessage.Deleted,
+		fmt.Sprintf("gci-roleAssignments-%s", global.directoryCustomerID), assetName, global)
+}
+
+func publishAdminSetting(feedMessage interface{}, isDeleted bool, topicShortName string, assetName string, global *Global) (err error) {
+	feedMessageJSON, err := json.Marshal(feedMessage)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("publishAdminSetting json.Marshal(feedMessage) %v %v", feedMessage, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	var pubSubMessage pubsubpb.PubsubMessage
+	pubSubMessage.Data = feedMessageJSON
+	pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
+
+	var pubsubMessages []*pubsubpb.PubsubMessage
+	pubsubMessages = append(pubsubMessages, &pubSubMessage)
+
+	var publishRequest pubsubpb.PublishRequest
+	if err = gps.CreateTopic(global.ctx, global.pubsubPublisherClient, &global.topicList, topicShortName, global.projectID); err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("gps.CreateTopic %s %v", topicShortName, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	topicName := fmt.Sprintf("projects/%s/topics/%s", global.projectID, topicShortName)
+	publishRequest.Topic = topicName
+	publishRequest.Messages = pubsubMessages
+
+	pubsubResponse, err := global.pubsubPublisherClient.Publish(global.ctx, &publishRequest)
+	if err != nil {
+		return fmt.Errorf("%s global.pubsubPublisherClient.Publish: %v", topicShortName, err)
+	}
+	now := time.Now()
+	latency := now.Sub(global.step.StepTimestamp)
+	latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:     global.microserviceName,
+		InstanceName:         global.instanceName,
+		Environment:          global.environment,
+		Severity:             "NOTICE",
+		Message:              fmt.Sprintf("finish %s", assetName),
+		Description:          fmt.Sprintf("admin setting published to pubsub (isdeleted status=%v) %s topic %s ids %v %s", isDeleted, assetName, topicName, pubsubResponse.MessageIds, string(feedMessageJSON)),
+		Now:                  &now,
+		TriggeringPubsubID:   global.PubSubID,
+		OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+		LatencySeconds:       latency.Seconds(),
+		LatencyE2ESeconds:    latencyE2E.Seconds(),
+		StepStack:            global.stepStack,
+	})
+	return nil
+}
+
 func getGroupFromEmail(groupEmail string, global *Global) (group *admin.Group, err error) {
 	// groupKey: The value can be the group's email address, group alias, or the unique group ID.
 	// https://developers.google.com/admin-sdk/directory/v1/reference/groups/get
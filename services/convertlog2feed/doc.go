@@ -21,13 +21,19 @@ Some.
 
 At least one to convert gsuite admin logs, related to groups members and groups settings, from GCP Cloud Audit Logs at organization level.
 
+When userSettings is true in instance.yaml, USER_SETTINGS admin events are also converted into user feed messages published to gci-users-<directoryCustomerID>. This requires the https://www.googleapis.com/auth/admin.directory.user.readonly oauth scope. ramcli sets it when listUsersDefaultSchedulers are defined in solution.yaml.
+
+DOMAIN_SETTINGS events are converted into domain setting feed messages published to gci-domainSettings-<directoryCustomerID>. There is no API to read these settings, so the asset holds the last change seen: setting name, scope, old and new values and actor.
+
+DELEGATED_ADMIN_SETTINGS ASSIGN_ROLE and UNASSIGN_ROLE events are converted into role assignment feed messages published to gci-roleAssignments-<directoryCustomerID>. The asset name is built from the role name and the user email found in the event.
+
 Output
 
-Publish pubsub message into several topics, e.g. gci-grouMembers and gci-groupSettings.
+Publish pubsub message into several topics, e.g. gci-grouMembers, gci-groupSettings, gci-domainSettings-<directoryCustomerID> and gci-roleAssignments-<directoryCustomerID>.
 
 Cardinality
 
-One one: one log message one feed message.
+One to many: one log message, one feed message per event it holds. All events of a log entry are converted.
 
 Notes
 
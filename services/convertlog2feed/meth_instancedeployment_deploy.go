@@ -37,6 +37,11 @@ func (instanceDeployment *InstanceDeployment) Deploy() (err error) {
 		if err = instanceDeployment.deployIAMServiceAccount(); err != nil {
 			return err
 		}
+		if instanceDeployment.Settings.Instance.GCI.Keyless {
+			if err = instanceDeployment.deployIAMBindings(); err != nil {
+				return err
+			}
+		}
 		if err = instanceDeployment.deployGRMProjectBindings(); err != nil {
 			return err
 		}
@@ -44,13 +49,25 @@ func (instanceDeployment *InstanceDeployment) Deploy() (err error) {
 		if err = instanceDeployment.deployGPSTopic(); err != nil {
 			return err
 		}
+		if err = instanceDeployment.deployDeadLetter(); err != nil {
+			return err
+		}
 	}
-	if err = instanceDeployment.deployGCFFunction(); err != nil {
+	switch instanceDeployment.Settings.Instance.Compute {
+	case "run":
+		err = instanceDeployment.deployRUNService()
+	default:
+		err = instanceDeployment.deployGCFFunction()
+	}
+	if err != nil {
 		return err
 	}
 	log.Printf("%s this cloud function service account needs domain wide delegation", instanceDeployment.Core.InstanceName)
 	log.Printf("%s this cloud function service account needs oauth scope https://www.googleapis.com/auth/apps.groups.settings", instanceDeployment.Core.InstanceName)
 	log.Printf("%s this cloud function service account needs oauth scope https://www.googleapis.com/auth/admin.directory.group.readonly", instanceDeployment.Core.InstanceName)
+	if instanceDeployment.Settings.Instance.GCI.UserSettings {
+		log.Printf("%s this cloud function service account needs oauth scope https://www.googleapis.com/auth/admin.directory.user.readonly", instanceDeployment.Core.InstanceName)
+	}
 	log.Printf("%s done in %v minutes", instanceDeployment.Core.InstanceName, time.Since(start).Minutes())
 	return nil
 }
@@ -18,10 +18,12 @@ import (
 	"time"
 
 	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/gcb"
 	"github.com/BrunoReboul/ram/utilities/gcf"
 	"github.com/BrunoReboul/ram/utilities/gsu"
 	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/run"
 	"google.golang.org/api/iam/v1"
 )
 
@@ -35,13 +37,17 @@ type InstanceDeployment struct {
 			IAM             iamgt.Parameters
 			GCB             gcb.Parameters
 			GCF             gcf.Parameters
+			RUN             run.Parameters
 			KeyJSONFileName string        `yaml:"keyJSONFileName"`
 			RetriesNumber   time.Duration `yaml:"time.Duration"`
 		}
 		Instance struct {
-			GCF gcf.Event
-			GCI struct {
-				SuperAdminEmail string `yaml:"superAdminEmail"`
+			Compute string `yaml:"compute,omitempty" valid:"isOneOf,gcf,run"`
+			GCF     gcf.Event
+			GCI     struct {
+				SuperAdminEmail string `yaml:"superAdminEmail" valid:"isEmail"`
+				Keyless         bool   `yaml:"keyless,omitempty"`
+				UserSettings    bool   `yaml:"userSettings,omitempty"`
 			}
 		}
 	}
@@ -55,14 +61,18 @@ func NewInstanceDeployment() *InstanceDeployment {
 		"cloudfunctions.googleapis.com",
 		"pubsub.googleapis.com",
 		"admin.googleapis.com",
-		"groupssettings.googleapis.com"}
+		"groupssettings.googleapis.com",
+		"run.googleapis.com",
+		"iamcredentials.googleapis.com"}
 	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
 
 	instanceDeployment.Settings.Service.IAM.RunRoles.Project = []iam.Role{
 		projectRunRole()}
 	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
 		projectDeployCoreRole(),
-		iamgt.ProjectDeployExtendedRole()}
+		iamgt.ProjectDeployExtendedRole(),
+		run.ProjectDeployRunRole(),
+		dlq.ProjectDeployDeadLetterRole()}
 
 	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
 	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
@@ -71,7 +81,9 @@ func NewInstanceDeployment() *InstanceDeployment {
 		"roles/datastore.owner"}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
 		projectDeployCoreRole().Title,
-		iamgt.ProjectDeployExtendedRole().Title}
+		iamgt.ProjectDeployExtendedRole().Title,
+		run.ProjectDeployRunRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
 		"roles/iam.serviceAccountUser"}
 
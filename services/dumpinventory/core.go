@@ -27,6 +27,7 @@ import (
 	"cloud.google.com/go/functions/metadata"
 	assetpb "google.golang.org/genproto/googleapis/cloud/asset/v1"
 
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gfs"
 	"github.com/BrunoReboul/ram/utilities/gps"
@@ -39,6 +40,7 @@ import (
 type Global struct {
 	assetClient         *asset.Client
 	ctx                 context.Context
+	deadLetter          dlq.Sender
 	dumpName            string
 	environment         string
 	firestoreClient     *firestore.Client
@@ -152,6 +154,24 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		})
 		return err
 	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
 	return nil
 }
 
@@ -174,6 +194,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	global.stepStack = nil
 	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -196,7 +217,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
@@ -243,7 +264,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 		global.PubSubID,
 		5)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
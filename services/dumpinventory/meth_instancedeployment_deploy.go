@@ -48,6 +48,9 @@ func (instanceDeployment *InstanceDeployment) Deploy() (err error) {
 		if err = instanceDeployment.deployGPSTopic(); err != nil {
 			return err
 		}
+		if err = instanceDeployment.deployDeadLetter(); err != nil {
+			return err
+		}
 		if err = instanceDeployment.deploySCHJob(); err != nil {
 			return err
 		}
@@ -55,7 +58,13 @@ func (instanceDeployment *InstanceDeployment) Deploy() (err error) {
 			return err
 		}
 	}
-	if err = instanceDeployment.deployGCFFunction(); err != nil {
+	switch instanceDeployment.Settings.Instance.Compute {
+	case "run":
+		err = instanceDeployment.deployRUNService()
+	default:
+		err = instanceDeployment.deployGCFFunction()
+	}
+	if err != nil {
 		return err
 	}
 	log.Printf("%s done in %v minutes", instanceDeployment.Core.InstanceName, time.Since(start).Minutes())
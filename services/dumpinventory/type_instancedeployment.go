@@ -19,10 +19,12 @@ import (
 
 	"github.com/BrunoReboul/ram/utilities/cai"
 	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/gcb"
 	"github.com/BrunoReboul/ram/utilities/gcf"
 	"github.com/BrunoReboul/ram/utilities/gsu"
 	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/run"
 	"github.com/BrunoReboul/ram/utilities/sch"
 	"google.golang.org/api/iam/v1"
 )
@@ -42,10 +44,12 @@ type InstanceDeployment struct {
 			IAM iamgt.Parameters
 			GCB gcb.Parameters
 			GCF gcf.Parameters
+			RUN run.Parameters
 		}
 		Instance struct {
-			CAI cai.Parameters
-			SCH sch.Parameters
+			Compute string `yaml:"compute,omitempty" valid:"isOneOf,gcf,run"`
+			CAI     cai.Parameters
+			SCH     sch.Parameters
 		}
 	}
 }
@@ -57,7 +61,8 @@ func NewInstanceDeployment() *InstanceDeployment {
 		"appengine.googleapis.com",
 		"cloudfunctions.googleapis.com",
 		"pubsub.googleapis.com",
-		"cloudscheduler.googleapis.com"}
+		"cloudscheduler.googleapis.com",
+		"run.googleapis.com"}
 	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
 
 	instanceDeployment.Settings.Service.IAM.RunRoles.MonitoringOrg = []iam.Role{
@@ -66,7 +71,9 @@ func NewInstanceDeployment() *InstanceDeployment {
 		monitoringOrgDeployExtendedRole()}
 	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
 		projectDeployCoreRole(),
-		iamgt.ProjectDeployExtendedRole()}
+		iamgt.ProjectDeployExtendedRole(),
+		run.ProjectDeployRunRole(),
+		dlq.ProjectDeployDeadLetterRole()}
 
 	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
 	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
@@ -75,7 +82,9 @@ func NewInstanceDeployment() *InstanceDeployment {
 		monitoringOrgDeployExtendedRole().Title}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
 		projectDeployCoreRole().Title,
-		iamgt.ProjectDeployExtendedRole().Title}
+		iamgt.ProjectDeployExtendedRole().Title,
+		run.ProjectDeployRunRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
 		"roles/iam.serviceAccountUser"}
 
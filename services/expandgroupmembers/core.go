@@ -0,0 +1,483 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expandgroupmembers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/gps"
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/ngr"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/functions/metadata"
+	"cloud.google.com/go/pubsub"
+)
+
+// Global structure for global variables to optimize the cloud function performances
+type Global struct {
+	collectionID        string
+	ctx                 context.Context
+	deadLetter          dlq.Sender
+	environment         string
+	firestoreClient     *firestore.Client
+	instanceName        string
+	maxNestingDepth     int64
+	microserviceName    string
+	outputTopicName     string
+	pubSubClient        *pubsub.Client
+	PubSubID            string
+	retryTimeOutSeconds int64
+	step                logging.Step
+	stepStack           logging.Steps
+}
+
+// group a group which effective members are to be refreshed
+type group struct {
+	assetName    string
+	ancestors    []string
+	ancestryPath string
+}
+
+// cachedMember group member as persisted in FireStore by publish2fs
+type cachedMember struct {
+	Asset struct {
+		Name         string   `firestore:"name"`
+		Ancestors    []string `firestore:"ancestors"`
+		AncestryPath string   `firestore:"ancestryPath"`
+		Resource     struct {
+			GroupEmail  string `firestore:"groupEmail"`
+			ID          string `firestore:"id"`
+			MemberEmail string `firestore:"memberEmail"`
+			Type        string `firestore:"type"`
+		} `firestore:"resource"`
+	} `firestore:"asset"`
+}
+
+// Initialize is to be executed in the init() function of the cloud function to optimize the cold start
+func Initialize(ctx context.Context, global *Global) (err error) {
+	log.SetFlags(0)
+	global.ctx = ctx
+
+	var instanceDeployment InstanceDeployment
+
+	initID := fmt.Sprintf("%v", uuid.New())
+	err = ffo.ReadUnmarshalYAML(solution.PathToFunctionCode+solution.SettingsFileName, &instanceDeployment)
+	if err != nil {
+		log.Println(logging.Entry{
+			Severity:    "CRITICAL",
+			Message:     "init_failed",
+			Description: fmt.Sprintf("ReadUnmarshalYAML %s %v", solution.SettingsFileName, err),
+			InitID:      initID,
+		})
+		return err
+	}
+
+	global.environment = instanceDeployment.Core.EnvironmentName
+	global.instanceName = instanceDeployment.Core.InstanceName
+	global.microserviceName = instanceDeployment.Core.ServiceName
+
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "coldstart",
+		InitID:           initID,
+	})
+
+	global.collectionID = instanceDeployment.Core.SolutionSettings.Hosting.FireStore.CollectionIDs.Assets
+	global.maxNestingDepth = instanceDeployment.Settings.Service.MaxNestingDepth
+	global.outputTopicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupEffectiveMembers
+	global.retryTimeOutSeconds = instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds
+	projectID := instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
+
+	global.firestoreClient, err = firestore.NewClient(global.ctx, projectID)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("firestore.NewClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.pubSubClient, err = pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("pubsub.NewClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	return nil
+}
+
+// EntryPoint is the function to be executed for each cloud function occurence
+func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, global *Global) error {
+	metadata, err := metadata.FromContext(ctxEvent)
+	if err != nil {
+		// Assume an error on the function invoker and try again.
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "redo_on_transient",
+			Description:        fmt.Sprintf("pubsub_id no available metadata.FromContext: %v", err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return err
+	}
+	global.stepStack = nil
+	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
+	parts := strings.Split(metadata.Resource.Name, "/")
+	global.step = logging.Step{
+		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
+		StepTimestamp: metadata.Timestamp,
+	}
+
+	now := time.Now()
+	d := now.Sub(metadata.Timestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:           global.microserviceName,
+		InstanceName:               global.instanceName,
+		Environment:                global.environment,
+		Severity:                   "NOTICE",
+		Message:                    "start",
+		TriggeringPubsubID:         global.PubSubID,
+		TriggeringPubsubAgeSeconds: d.Seconds(),
+		TriggeringPubsubTimestamp:  &metadata.Timestamp,
+		Now:                        &now,
+	})
+
+	if d.Seconds() > float64(global.retryTimeOutSeconds) {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:           global.microserviceName,
+			InstanceName:               global.instanceName,
+			Environment:                global.environment,
+			Severity:                   "CRITICAL",
+			Message:                    "noretry",
+			Description:                "Pubsub message too old",
+			TriggeringPubsubID:         global.PubSubID,
+			TriggeringPubsubAgeSeconds: d.Seconds(),
+			TriggeringPubsubTimestamp:  &metadata.Timestamp,
+			Now:                        &now,
+		})
+		return nil
+	}
+
+	var feedMessageMember cai.FeedMessageMember
+	err = json.Unmarshal(PubSubMessage.Data, &feedMessageMember)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal(pubSubMessage.Data, &feedMessageMember) %v %v", PubSubMessage.Data, err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	if feedMessageMember.StepStack != nil {
+		global.stepStack = append(feedMessageMember.StepStack, global.step)
+	} else {
+		global.stepStack = append(global.stepStack, global.step)
+	}
+
+	if feedMessageMember.Asset.Resource.GroupEmail == "" || !strings.Contains(feedMessageMember.Asset.Name, "/members/") {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "NOTICE",
+			Message:            "cancel",
+			Description:        fmt.Sprintf("not a group member feed message %s", feedMessageMember.Asset.Name),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+
+	// The group which direct membership changed, then all the groups containing it directly or not
+	groupEmail := strings.ToLower(feedMessageMember.Asset.Resource.GroupEmail)
+	groups := make(map[string]group)
+	groups[groupEmail] = group{
+		assetName:    strings.Split(feedMessageMember.Asset.Name, "/members/")[0],
+		ancestors:    feedMessageMember.Asset.Ancestors,
+		ancestryPath: feedMessageMember.Asset.AncestryPath,
+	}
+	directory := cacheDirectory{
+		feedMessageMember: &feedMessageMember,
+		global:            global,
+		groups:            groups,
+	}
+	_, truncatedEmails, err := ngr.GetParentGroups(directory, groupEmail, global.maxNestingDepth)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "redo_on_transient",
+			Description:        fmt.Sprintf("ngr.GetParentGroups %v", err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return err
+	}
+	for _, truncatedEmail := range truncatedEmails {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "WARNING",
+			Message:            "getParentGroups",
+			Description:        fmt.Sprintf("stop looking for parent groups of %s, max nesting depth %d reached", truncatedEmail, global.maxNestingDepth),
+			TriggeringPubsubID: global.PubSubID,
+		})
+	}
+
+	var membersNumber int
+	for email, g := range groups {
+		effectiveMembers, truncatedGroupEmails, err := ngr.GetEffectiveMembers(directory, email, global.maxNestingDepth)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName:   global.microserviceName,
+				InstanceName:       global.instanceName,
+				Environment:        global.environment,
+				Severity:           "CRITICAL",
+				Message:            "redo_on_transient",
+				Description:        fmt.Sprintf("ngr.GetEffectiveMembers %s %v", email, err),
+				TriggeringPubsubID: global.PubSubID,
+			})
+			return err
+		}
+		for _, truncatedGroupEmail := range truncatedGroupEmails {
+			log.Println(logging.Entry{
+				MicroserviceName:   global.microserviceName,
+				InstanceName:       global.instanceName,
+				Environment:        global.environment,
+				Severity:           "WARNING",
+				Message:            "getEffectiveMembers",
+				Description:        fmt.Sprintf("do not expand %s, max nesting depth %d reached from %s", truncatedGroupEmail, global.maxNestingDepth, email),
+				TriggeringPubsubID: global.PubSubID,
+			})
+		}
+		err = publishEffectiveMembers(email, g, effectiveMembers, &feedMessageMember, global)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName:   global.microserviceName,
+				InstanceName:       global.instanceName,
+				Environment:        global.environment,
+				Severity:           "CRITICAL",
+				Message:            "redo_on_transient",
+				Description:        fmt.Sprintf("publishEffectiveMembers %s %v", email, err),
+				TriggeringPubsubID: global.PubSubID,
+			})
+			return err
+		}
+		membersNumber = membersNumber + len(effectiveMembers)
+	}
+
+	now = time.Now()
+	latency := now.Sub(global.step.StepTimestamp)
+	latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:     global.microserviceName,
+		InstanceName:         global.instanceName,
+		Environment:          global.environment,
+		Severity:             "NOTICE",
+		Message:              fmt.Sprintf("finish %s %d groups", groupEmail, len(groups)),
+		Description:          fmt.Sprintf("Member %s isDeleted %v in group %s, effective members of %d groups published to pubsub topic %s, %d effective members in total", feedMessageMember.Asset.Resource.MemberEmail, feedMessageMember.Deleted, groupEmail, len(groups), global.outputTopicName, membersNumber),
+		Now:                  &now,
+		TriggeringPubsubID:   global.PubSubID,
+		OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+		LatencySeconds:       latency.Seconds(),
+		LatencyE2ESeconds:    latencyE2E.Seconds(),
+		StepStack:            global.stepStack,
+	})
+	return nil
+}
+
+// cacheDirectory reads the direct memberships of groups from the FireStore cache
+type cacheDirectory struct {
+	feedMessageMember *cai.FeedMessageMember
+	global            *Global
+	groups            map[string]group
+}
+
+// GetDirectParents returns the groups having memberEmail as a member of type GROUP, and records them in groups to refresh their effective members
+func (directory cacheDirectory) GetDirectParents(memberEmail string) (parentEmails []string, err error) {
+	var documentSnap *firestore.DocumentSnapshot
+	assets := directory.global.firestoreClient.Collection(directory.global.collectionID)
+	query := assets.Where(
+		"asset.assetType", "==", "www.googleapis.com/admin/directory/members").Where(
+		"asset.resource.memberEmail", "==", memberEmail).Where(
+		"asset.resource.type", "==", "GROUP")
+	iter := query.Documents(directory.global.ctx)
+	defer iter.Stop()
+	for {
+		documentSnap, err = iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("GetDirectParents iter.Next() %v", err)
+		}
+		var member cachedMember
+		err = documentSnap.DataTo(&member)
+		if err != nil {
+			return nil, fmt.Errorf("GetDirectParents documentSnap.DataTo %v", err)
+		}
+		parentEmail := strings.ToLower(member.Asset.Resource.GroupEmail)
+		if _, ok := directory.groups[parentEmail]; !ok {
+			directory.groups[parentEmail] = group{
+				assetName:    strings.Split(member.Asset.Name, "/members/")[0],
+				ancestors:    member.Asset.Ancestors,
+				ancestryPath: member.Asset.AncestryPath,
+			}
+		}
+		parentEmails = append(parentEmails, parentEmail)
+	}
+	return parentEmails, nil
+}
+
+// GetDirectMembers see getDirectMembers
+func (directory cacheDirectory) GetDirectMembers(groupEmail string) (directMembers []cai.EffectiveMember, err error) {
+	return getDirectMembers(groupEmail, directory.feedMessageMember, directory.global)
+}
+
+// getDirectMembers reads the direct members of a group from FireStore cache, then applies the triggering change as the cache may not be updated yet
+func getDirectMembers(groupEmail string, feedMessageMember *cai.FeedMessageMember, global *Global) (directMembers []cai.EffectiveMember, err error) {
+	var documentSnap *firestore.DocumentSnapshot
+	members := make(map[string]cai.EffectiveMember)
+	assets := global.firestoreClient.Collection(global.collectionID)
+	query := assets.Where(
+		"asset.assetType", "==", "www.googleapis.com/admin/directory/members").Where(
+		"asset.resource.groupEmail", "==", groupEmail)
+	iter := query.Documents(global.ctx)
+	defer iter.Stop()
+	for {
+		documentSnap, err = iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getDirectMembers iter.Next() %v", err)
+		}
+		var member cachedMember
+		err = documentSnap.DataTo(&member)
+		if err != nil {
+			return nil, fmt.Errorf("getDirectMembers documentSnap.DataTo %v", err)
+		}
+		memberEmail := strings.ToLower(member.Asset.Resource.MemberEmail)
+		members[memberEmail] = cai.EffectiveMember{
+			MemberEmail: memberEmail,
+			ID:          member.Asset.Resource.ID,
+			Type:        member.Asset.Resource.Type,
+		}
+	}
+	if strings.ToLower(feedMessageMember.Asset.Resource.GroupEmail) == groupEmail {
+		memberEmail := strings.ToLower(feedMessageMember.Asset.Resource.MemberEmail)
+		if feedMessageMember.Deleted {
+			delete(members, memberEmail)
+		} else {
+			members[memberEmail] = cai.EffectiveMember{
+				MemberEmail: memberEmail,
+				ID:          feedMessageMember.Asset.Resource.ID,
+				Type:        feedMessageMember.Asset.Resource.Type,
+			}
+		}
+	}
+	for _, member := range members {
+		directMembers = append(directMembers, member)
+	}
+	sort.Slice(directMembers, func(i, j int) bool { return directMembers[i].MemberEmail < directMembers[j].MemberEmail })
+	return directMembers, nil
+}
+
+// publishEffectiveMembers publishes one feed message per group, flagged as deleted when the group has no more members
+func publishEffectiveMembers(groupEmail string, g group, effectiveMembers []cai.EffectiveMember, feedMessageMember *cai.FeedMessageMember, global *Global) (err error) {
+	var feedMessage cai.FeedMessageEffectiveMembers
+	feedMessage.Asset.Name = g.assetName + "/effectiveMembers"
+	feedMessage.Asset.AssetType = "www.googleapis.com/admin/directory/effectiveMembers"
+	feedMessage.Asset.Ancestors = g.ancestors
+	feedMessage.Asset.AncestryPath = g.ancestryPath
+	feedMessage.Asset.Resource.GroupEmail = groupEmail
+	feedMessage.Asset.Resource.Members = effectiveMembers
+	feedMessage.Window.StartTime = feedMessageMember.Window.StartTime
+	feedMessage.Origin = feedMessageMember.Origin
+	feedMessage.Deleted = len(effectiveMembers) == 0
+	feedMessage.StepStack = global.stepStack
+	feedMessageJSON, err := json.Marshal(feedMessage)
+	if err != nil {
+		return fmt.Errorf("json.Marshal(feedMessage) %v", err)
+	}
+	topic := global.pubSubClient.Topic(global.outputTopicName)
+	id, err := topic.Publish(global.ctx, &pubsub.Message{Data: feedMessageJSON, Attributes: gps.GetFeedMessageAttributes(feedMessageJSON)}).Get(global.ctx)
+	if err != nil {
+		return fmt.Errorf("topic.Publish %s %v", feedMessage.Asset.Name, err)
+	}
+	log.Println(logging.Entry{
+		MicroserviceName:   global.microserviceName,
+		InstanceName:       global.instanceName,
+		Environment:        global.environment,
+		Severity:           "INFO",
+		Message:            fmt.Sprintf("effective members published %s", groupEmail),
+		Description:        fmt.Sprintf("%d effective members (isdeleted status=%v) %s topic %s id %s", len(effectiveMembers), feedMessage.Deleted, feedMessage.Asset.Name, global.outputTopicName, id),
+		TriggeringPubsubID: global.PubSubID,
+	})
+	return nil
+}
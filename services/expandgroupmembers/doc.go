@@ -0,0 +1,78 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package expandgroupmembers maintains the effective members of groups, aka transitive membership, nested groups being expanded and not listed as effective members
+
+Triggered by
+
+PubSub messages from the GCI group members topic, published by listgroupmembers and convertlog2feed.
+
+Instances
+
+Only one.
+
+Output
+
+PubSub messages to a dedicated topic formated like Cloud Asset Inventory feed messages, asset type www.googleapis.com/admin/directory/effectiveMembers.
+
+One message per group lists all its direct and nested members. Each member comes with the path of groups leading to it, the shortest one when several exist.
+
+A group without any member is published as deleted.
+
+Cardinality
+
+One-many: one membership change refreshes the effective members of the changed group and of all the groups containing it, directly or not.
+
+Automatic retrying
+
+Yes.
+
+Domain Wide Delegation
+
+No. Direct memberships are read from the FireStore assets collection fed by publish2fs, so publish2fs must be deployed on the GCI group members topic.
+
+The triggering change is applied over the cached direct members, as the cache may not be updated yet.
+
+Persistence and rules
+
+The effective members topic is consumed by publish2fs to FireStore and by upload2gcs to Cloud Storage and BigQuery.
+
+Monitor rules evaluate effective members using this topic as trigger topic, e.g. ramcli -newrule -asset www.googleapis.com/admin/directory/effectiveMembers -rule no_external_user
+
+Nesting cycles are detected, and expansion stops at maxNestingDepth set in the service settings.
+
+Implementation example
+
+ package p
+ import (
+     "context"
+
+     "github.com/BrunoReboul/ram/services/expandgroupmembers"
+     "github.com/BrunoReboul/ram/utilities/ram"
+ )
+ var global expandgroupmembers.Global
+ var ctx = context.Background()
+
+ // EntryPoint is the function to be executed for each cloud function occurence
+ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage) error {
+     return expandgroupmembers.EntryPoint(ctxEvent, PubSubMessage, &global)
+ }
+
+ func init() {
+     expandgroupmembers.Initialize(ctx, &global)
+ }
+
+*/
+package expandgroupmembers
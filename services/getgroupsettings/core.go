@@ -24,10 +24,12 @@ import (
 
 	"github.com/BrunoReboul/ram/utilities/aut"
 	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gfs"
 	"github.com/BrunoReboul/ram/utilities/gps"
 	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/qta"
 	"github.com/BrunoReboul/ram/utilities/solution"
 	"github.com/google/uuid"
 	"google.golang.org/api/groupssettings/v1"
@@ -42,6 +44,7 @@ import (
 // Global structure for global variables to optimize the cloud function performances
 type Global struct {
 	ctx                   context.Context
+	deadLetter            dlq.Sender
 	environment           string
 	firestoreClient       *firestore.Client
 	groupsSettingsService *groupssettings.Service
@@ -51,9 +54,12 @@ type Global struct {
 	projectID             string
 	PubSubID              string
 	pubsubPublisherClient *pubsub.PublisherClient
+	rateLimit             qta.Parameters
 	retryTimeOutSeconds   int64
 	step                  logging.Step
 	stepStack             logging.Steps
+	timeout               time.Duration
+	tokenBucket           *qta.TokenBucket
 }
 
 // Initialize is to be executed in the init() function of the cloud function to optimize the cold start
@@ -93,7 +99,12 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 	gciAdminUserToImpersonate := instanceDeployment.Settings.Instance.GCI.SuperAdminEmail
 	global.outputTopicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupSettings
 	global.projectID = instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
+	global.rateLimit = instanceDeployment.Settings.Service.RateLimit
 	global.retryTimeOutSeconds = instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds
+	global.timeout, err = time.ParseDuration(instanceDeployment.Settings.Service.GCF.Timeout)
+	if err != nil {
+		global.timeout = 60 * time.Second
+	}
 	keyJSONFilePath := solution.PathToFunctionCode + instanceDeployment.Settings.Service.KeyJSONFileName
 	serviceAccountEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
 		instanceDeployment.Core.ServiceName,
@@ -112,8 +123,51 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		})
 		return err
 	}
+	// one bucket per directory customer and API, shared by all the instances calling it
+	global.tokenBucket = qta.NewTokenBucket(global.firestoreClient,
+		fmt.Sprintf("groupssettings_%s", instanceDeployment.Settings.Instance.GCI.DirectoryCustomerID),
+		global.rateLimit)
 
-	serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
+	if instanceDeployment.Settings.Instance.GCI.Keyless {
+		if clientOption, ok = aut.GetClientOptionKeyless(ctx,
+			serviceAccountEmail,
+			gciAdminUserToImpersonate,
+			[]string{"https://www.googleapis.com/auth/apps.groups.settings"},
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionKeyless")
+		}
+	} else {
+		serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "init_failed",
+				Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
+				InitID:           initID,
+			})
+			return err
+		}
+		if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
+			serviceAccountEmail,
+			keyJSONFilePath,
+			instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+			gciAdminUserToImpersonate,
+			[]string{"https://www.googleapis.com/auth/apps.groups.settings"},
+			serviceAccountKeyNames,
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
+		}
+	}
+	global.groupsSettingsService, err = groupssettings.NewService(ctx, clientOption)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -121,25 +175,12 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
+			Description:      fmt.Sprintf("groupssettings.NewService %v", err),
 			InitID:           initID,
 		})
 		return err
 	}
-	if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
-		serviceAccountEmail,
-		keyJSONFilePath,
-		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
-		gciAdminUserToImpersonate,
-		[]string{"https://www.googleapis.com/auth/apps.groups.settings"},
-		serviceAccountKeyNames,
-		initID,
-		global.microserviceName,
-		global.instanceName,
-		global.environment); !ok {
-		return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
-	}
-	global.groupsSettingsService, err = groupssettings.NewService(ctx, clientOption)
+	global.pubsubPublisherClient, err = pubsub.NewPublisherClient(global.ctx)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -147,12 +188,17 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("groupssettings.NewService %v", err),
+			Description:      fmt.Sprintf("global.pubsubPublisherClient %v", err),
 			InitID:           initID,
 		})
 		return err
 	}
-	global.pubsubPublisherClient, err = pubsub.NewPublisherClient(global.ctx)
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -160,7 +206,7 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("global.pubsubPublisherClient %v", err),
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
 			InitID:           initID,
 		})
 		return err
@@ -187,6 +233,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	global.stepStack = nil
 	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -208,13 +255,13 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
 			Severity:                   "CRITICAL",
-			Message:                    "noretry",
-			Description:                "Pubsub message too old",
+			Message:                    "dropped",
+			Description:                "Pubsub message too old, retryTimeOutSeconds reached, work dropped",
 			TriggeringPubsubID:         global.PubSubID,
 			TriggeringPubsubAgeSeconds: d.Seconds(),
 			TriggeringPubsubTimestamp:  &metadata.Timestamp,
@@ -228,7 +275,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	err = json.Unmarshal(PubSubMessage.Data, &feedMessageGroup)
 	if err != nil {
 		log.Printf("pubsub_id %s NORETRY_ERROR json.Unmarshal(pubSubMessage.Data, &feedMessageGroup)", global.PubSubID)
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -254,7 +301,16 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	feedMessageGroupSettings.Asset.Name = feedMessageGroup.Asset.Name + "/groupSettings"
 	feedMessageGroupSettings.Deleted = feedMessageGroup.Deleted
 	if !feedMessageGroup.Deleted {
-		groupSettings, err := global.groupsSettingsService.Groups.Get(feedMessageGroup.Asset.Resource.Email).Do()
+		var groupSettings *groupssettings.Groups
+		_, err = qta.CallWithBackoff(global.ctx, global.tokenBucket, global.rateLimit, now.Add(global.timeout*3/4), logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			TriggeringPubsubID: global.PubSubID,
+		}, func() (err error) {
+			groupSettings, err = global.groupsSettingsService.Groups.Get(feedMessageGroup.Asset.Resource.Email).Do()
+			return err
+		})
 		if err != nil {
 			log.Println(logging.Entry{
 				MicroserviceName:   global.microserviceName,
@@ -273,7 +329,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 
 	feedMessageGroupSettingsJSON, err := json.Marshal(feedMessageGroupSettings)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -287,6 +343,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 
 	var pubSubMessage pubsubpb.PubsubMessage
 	pubSubMessage.Data = feedMessageGroupSettingsJSON
+	pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageGroupSettingsJSON)
 
 	var pubsubMessages []*pubsubpb.PubsubMessage
 	pubsubMessages = append(pubsubMessages, &pubSubMessage)
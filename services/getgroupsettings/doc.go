@@ -35,12 +35,30 @@ Automatic retrying
 
 Yes.
 
+Quota management
+
+- Groups Settings API calls are throttled by a token bucket per directory customer, shared by all the function instances through the firestore documents rateLimiters/groupssettings_<directory customer ID>_<shard>. The service account is granted roles/datastore.user to write them.
+
+- Set rateLimit ratePerSecond and burst in service.yaml, ratePerSecond: 0 disables throttling. Optionnaly limit gcf maxInstances too.
+
+- rateLimit shards splits the rate and the burst evenly across documents, as a firestore document sustains about one write per second. Keep ratePerSecond / shards around 1.
+
+- 429 and rateLimitExceeded errors are retried with an exponential backoff, up to rateLimit maxAttempts, from initialBackoff to maxBackoff.
+
+- When the throttling or backoff would exceed the function timeout, the function returns an error so PubSub redelivers the message.
+
+- Work dropped once retryTimeOutSeconds is reached is logged with the message "dropped", e.g. to build a log based counter metric on jsonPayload.message="dropped".
+
 Domain Wide Delegation
 
 Yes. The service account used to run this cloud function must have domain wide delegation and the following Oauth scopes:
 
 - https://www.googleapis.com/auth/apps.groups.settings
 
+Keyless option
+
+Same as listgroups microservice.
+
 Implementation example
 
  package p
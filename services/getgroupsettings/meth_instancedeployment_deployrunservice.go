@@ -0,0 +1,66 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package getgroupsettings
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/BrunoReboul/ram/utilities/gfs"
+	"github.com/BrunoReboul/ram/utilities/run"
+)
+
+func (instanceDeployment *InstanceDeployment) deployRUNService() (err error) {
+	instanceDeployment.DumpTimestamp = time.Now()
+	instanceDeploymentYAMLBytes, err := yaml.Marshal(instanceDeployment)
+	if err != nil {
+		return err
+	}
+	serviceDeployment := run.NewServiceDeployment()
+	serviceDeployment.Core = instanceDeployment.Core
+	serviceDeployment.Artifacts.InstanceDeploymentYAMLContent = string(instanceDeploymentYAMLBytes)
+	serviceDeployment.Settings.Service.GCF = instanceDeployment.Settings.Service.GCF
+	serviceDeployment.Settings.Service.RUN = instanceDeployment.Settings.Service.RUN
+	serviceDeployment.Settings.Instance.GCF.TriggerTopic = instanceDeployment.Settings.Instance.GCF.TriggerTopic
+
+	if !instanceDeployment.Core.Commands.Check && !instanceDeployment.Settings.Instance.GCI.Keyless {
+		serviceAccountKey, err := instanceDeployment.getServiceAccountKey()
+		if err != nil {
+			return fmt.Errorf("getServiceAccountKey %v", err)
+		}
+		err = gfs.RecordKeyName(instanceDeployment.Core, serviceAccountKey.Name, 5)
+		if err != nil {
+			return fmt.Errorf("gfs.RecordKeyName %v", err)
+		}
+
+		bytes, err := json.Marshal(serviceAccountKey)
+		if err != nil {
+			return fmt.Errorf("json.Marshal %v", err)
+		}
+		specificZipFiles := make(map[string]string)
+		specificZipFiles[instanceDeployment.Settings.Service.KeyJSONFileName] = string(bytes)
+		serviceDeployment.Artifacts.ZipFiles = specificZipFiles
+	}
+
+	err = serviceDeployment.Deploy()
+	if err != nil {
+		return fmt.Errorf("serviceDeployment.Deploy %v", err)
+	}
+
+	return nil
+}
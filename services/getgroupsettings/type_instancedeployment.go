@@ -18,10 +18,13 @@ import (
 	"time"
 
 	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/gcb"
 	"github.com/BrunoReboul/ram/utilities/gcf"
 	"github.com/BrunoReboul/ram/utilities/gsu"
 	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/qta"
+	"github.com/BrunoReboul/ram/utilities/run"
 	"google.golang.org/api/iam/v1"
 )
 
@@ -35,12 +38,17 @@ type InstanceDeployment struct {
 			IAM             iamgt.Parameters
 			GCB             gcb.Parameters
 			GCF             gcf.Parameters
-			KeyJSONFileName string `yaml:"keyJSONFileName"`
+			RUN             run.Parameters
+			KeyJSONFileName string         `yaml:"keyJSONFileName"`
+			RateLimit       qta.Parameters `yaml:"rateLimit"`
 		}
 		Instance struct {
-			GCF gcf.Event
-			GCI struct {
-				SuperAdminEmail string `yaml:"superAdminEmail"`
+			Compute string `yaml:"compute,omitempty" valid:"isOneOf,gcf,run"`
+			GCF     gcf.Event
+			GCI     struct {
+				DirectoryCustomerID string `yaml:"directoryCustomerID"`
+				SuperAdminEmail     string `yaml:"superAdminEmail" valid:"isEmail"`
+				Keyless             bool   `yaml:"keyless,omitempty"`
 			}
 		}
 	}
@@ -53,14 +61,18 @@ func NewInstanceDeployment() *InstanceDeployment {
 		"appengine.googleapis.com",
 		"cloudfunctions.googleapis.com",
 		"pubsub.googleapis.com",
-		"groupssettings.googleapis.com"}
+		"groupssettings.googleapis.com",
+		"run.googleapis.com",
+		"iamcredentials.googleapis.com"}
 	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
 
 	instanceDeployment.Settings.Service.IAM.RunRoles.Project = []iam.Role{
 		projectRunRole()}
 	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
 		projectDeployCoreRole(),
-		iamgt.ProjectDeployExtendedRole()}
+		iamgt.ProjectDeployExtendedRole(),
+		run.ProjectDeployRunRole(),
+		dlq.ProjectDeployDeadLetterRole()}
 
 	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
 	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
@@ -69,19 +81,28 @@ func NewInstanceDeployment() *InstanceDeployment {
 		"roles/datastore.owner"}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
 		projectDeployCoreRole().Title,
-		iamgt.ProjectDeployExtendedRole().Title}
+		iamgt.ProjectDeployExtendedRole().Title,
+		run.ProjectDeployRunRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
 		"roles/iam.serviceAccountUser"}
 
 	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
 		projectRunRole().Title}
 	// Data store permissions are not supported in custom roles
+	// datastore.user as the token bucket writes its state in the rateLimiters firestore collection
 	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Hosting.Project.Roles = []string{
-		"roles/datastore.viewer"}
+		"roles/datastore.user"}
 
 	instanceDeployment.Settings.Service.GCF.AvailableMemoryMb = 128
 	instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds = 93600
 	instanceDeployment.Settings.Service.GCF.Timeout = "60s"
+	instanceDeployment.Settings.Service.RateLimit.RatePerSecond = 10
+	instanceDeployment.Settings.Service.RateLimit.Burst = 20
+	instanceDeployment.Settings.Service.RateLimit.Shards = 10
+	instanceDeployment.Settings.Service.RateLimit.MaxAttempts = 5
+	instanceDeployment.Settings.Service.RateLimit.InitialBackoff = "1s"
+	instanceDeployment.Settings.Service.RateLimit.MaxBackoff = "32s"
 
 	instanceDeployment.Settings.Service.KeyJSONFileName = "key.json"
 
@@ -0,0 +1,458 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importsnapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/functions/metadata"
+	pubsub "cloud.google.com/go/pubsub/apiv1"
+	"cloud.google.com/go/storage"
+	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/gcs"
+	"github.com/BrunoReboul/ram/utilities/gps"
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/mca"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/trc"
+	"github.com/google/uuid"
+	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
+)
+
+// publishBatchSize max number of messages per publish request
+const publishBatchSize = 100
+
+// Global structure for global variables to optimize the cloud function performances
+type Global struct {
+	ctx                   context.Context
+	awsEntityPaths        []string
+	deadLetter            dlq.Sender
+	environment           string
+	instanceName          string
+	microserviceName      string
+	objectNameRegexp      *regexp.Regexp
+	projectID             string
+	PubSubID              string
+	pubsubPublisherClient *pubsub.PublisherClient
+	retryTimeOutSeconds   int64
+	source                string
+	spanCtx               context.Context
+	step                  logging.Step
+	stepStack             logging.Steps
+	storageBucket         *storage.BucketHandle
+	trace                 string
+	tracer                trc.Tracer
+}
+
+// feedMessage Cloud Asset Inventory feed message
+type feedMessage struct {
+	Asset     mca.Asset     `json:"asset"`
+	Window    cai.Window    `json:"window"`
+	Origin    string        `json:"origin"`
+	StepStack logging.Steps `json:"step_stack,omitempty"`
+}
+
+// Initialize is to be executed in the init() function of the cloud function to optimize the cold start
+func Initialize(ctx context.Context, global *Global) (err error) {
+	log.SetFlags(0)
+	global.ctx = ctx
+
+	var instanceDeployment InstanceDeployment
+	var storageClient *storage.Client
+
+	initID := fmt.Sprintf("%v", uuid.New())
+	err = ffo.ReadUnmarshalYAML(solution.PathToFunctionCode+solution.SettingsFileName, &instanceDeployment)
+	if err != nil {
+		log.Println(logging.Entry{
+			Severity:    "CRITICAL",
+			Message:     "init_failed",
+			Description: fmt.Sprintf("ReadUnmarshalYAML %s %v", solution.SettingsFileName, err),
+			InitID:      initID,
+		})
+		return err
+	}
+
+	global.environment = instanceDeployment.Core.EnvironmentName
+	global.instanceName = instanceDeployment.Core.InstanceName
+	global.microserviceName = instanceDeployment.Core.ServiceName
+
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "coldstart",
+		InitID:           initID,
+	})
+
+	global.awsEntityPaths = instanceDeployment.Settings.Instance.AWS.EntityPaths
+	global.projectID = instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
+	global.retryTimeOutSeconds = instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds
+	global.source = instanceDeployment.Settings.Instance.Source
+
+	global.objectNameRegexp, err = regexp.Compile(instanceDeployment.Settings.Instance.ObjectNameRegex)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("regexp.Compile objectNameRegex %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	storageClient, err = storage.NewClient(ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("storage.NewClient(ctx) %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.storageBucket = storageClient.Bucket(instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.Snapshots.Name)
+	global.pubsubPublisherClient, err = pubsub.NewPublisherClient(global.ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("pubsub.NewPublisherClient(global.ctx) %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.tracer, err = trc.NewTracer(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		global.microserviceName,
+		instanceDeployment.Settings.Service.Trace)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("trc.NewTracer %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	return nil
+}
+
+// EntryPoint is the function to be executed for each cloud function occurence
+func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) error {
+	metadata, err := metadata.FromContext(ctxEvent)
+	if err != nil {
+		// Assume an error on the function invoker and try again.
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "redo_on_transient",
+			Description:        fmt.Sprintf("pubsub_id no available metadata.FromContext: %v", err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return err
+	}
+	global.stepStack = nil
+	global.trace = ""
+	global.PubSubID = metadata.EventID
+	gcsEventJSON, _ := json.Marshal(gcsEvent)
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, gcsEventJSON, nil)
+	parts := strings.Split(metadata.Resource.Name, "/")
+	global.step = logging.Step{
+		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
+		StepTimestamp: metadata.Timestamp,
+	}
+
+	now := time.Now()
+	d := now.Sub(metadata.Timestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:           global.microserviceName,
+		InstanceName:               global.instanceName,
+		Environment:                global.environment,
+		Severity:                   "NOTICE",
+		Message:                    "start",
+		TriggeringPubsubID:         global.PubSubID,
+		TriggeringPubsubAgeSeconds: d.Seconds(),
+		TriggeringPubsubTimestamp:  &metadata.Timestamp,
+		Now:                        &now,
+	})
+
+	if d.Seconds() > float64(global.retryTimeOutSeconds) {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:           global.microserviceName,
+			InstanceName:               global.instanceName,
+			Environment:                global.environment,
+			Severity:                   "CRITICAL",
+			Message:                    "noretry",
+			Description:                "Pubsub message too old",
+			TriggeringPubsubID:         global.PubSubID,
+			TriggeringPubsubAgeSeconds: d.Seconds(),
+			TriggeringPubsubTimestamp:  &metadata.Timestamp,
+			Now:                        &now,
+		})
+		return nil
+	}
+
+	if gcsEvent.ResourceState == "not_exists" {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "NOTICE",
+			Message:            "cancel",
+			Description:        fmt.Sprintf("deleted object %v", gcsEvent.Name),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	if gcsEvent.Size == "0" {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "NOTICE",
+			Message:            "cancel",
+			Description:        fmt.Sprintf("empty object %v", gcsEvent.Name),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	if !global.objectNameRegexp.MatchString(gcsEvent.Name) {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "NOTICE",
+			Message:            "cancel",
+			Description:        fmt.Sprintf("not a %s snapshot %v", global.source, gcsEvent.Name),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+
+	var gcsStep logging.Step
+	gcsStep.StepTimestamp = gcsEvent.Updated
+	gcsStep.StepID = gcsEvent.ID
+	global.stepStack = append(global.stepStack, gcsStep)
+	global.stepStack = append(global.stepStack, global.step)
+
+	spanCtx, span := global.tracer.StartSpan(global.ctx, global.instanceName, nil, global.stepStack)
+	defer global.tracer.EndSpan(span)
+	global.spanCtx = spanCtx
+	global.trace = global.tracer.GetLogTrace(spanCtx)
+
+	content, err := readSnapshot(gcsEvent.Name, global)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "redo_on_transient",
+			Description:        fmt.Sprintf("readSnapshot %v", err),
+			TriggeringPubsubID: global.PubSubID,
+			Trace:              global.trace,
+		})
+		return err
+	}
+
+	var assets []mca.Asset
+	var ignoredNumber int
+	switch global.source {
+	case "aws":
+		assets, ignoredNumber, err = mca.GetAWSConfigAssets(content, global.awsEntityPaths)
+	case "azure":
+		assets, ignoredNumber, err = mca.GetAzureResourceGraphAssets(content)
+	default:
+		err = fmt.Errorf("unsupported source %s", global.source)
+	}
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("transpose %s %v", gcsEvent.Name, err),
+			TriggeringPubsubID: global.PubSubID,
+			Trace:              global.trace,
+		})
+		return nil
+	}
+
+	start := time.Now()
+	pubSubMsgNumber, err := publishAssets(assets, gcsEvent.Updated, global)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "redo_on_transient",
+			Description:        fmt.Sprintf("publishAssets %v", err),
+			TriggeringPubsubID: global.PubSubID,
+			Trace:              global.trace,
+		})
+		return err
+	}
+	now = time.Now()
+	latency := now.Sub(global.step.StepTimestamp)
+	latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:     global.microserviceName,
+		InstanceName:         global.instanceName,
+		Environment:          global.environment,
+		Severity:             "NOTICE",
+		Message:              fmt.Sprintf("finish import %d assets %s", len(assets), gcsEvent.Name),
+		Description:          fmt.Sprintf("pubSubMsgNumber %d ignoredNumber %d gcsEvent.Generation %v duration %v", pubSubMsgNumber, ignoredNumber, gcsEvent.Generation, time.Since(start)),
+		Now:                  &now,
+		TriggeringPubsubID:   global.PubSubID,
+		Trace:                global.trace,
+		OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+		LatencySeconds:       latency.Seconds(),
+		LatencyE2ESeconds:    latencyE2E.Seconds(),
+		StepStack:            global.stepStack,
+	})
+	return nil
+}
+
+// readSnapshot reads a snapshot object, gzip content is uncompressed as AWS Config delivers .json.gz files
+func readSnapshot(objectName string, global *Global) (content []byte, err error) {
+	storageObjectReader, err := global.storageBucket.Object(objectName).NewReader(global.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("storageObject.NewReader %v", err)
+	}
+	defer storageObjectReader.Close()
+	content, err = ioutil.ReadAll(storageObjectReader)
+	if err != nil {
+		return nil, fmt.Errorf("ioutil.ReadAll %v", err)
+	}
+	if len(content) > 2 && content[0] == 0x1f && content[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("gzip.NewReader %v", err)
+		}
+		defer gzipReader.Close()
+		content, err = ioutil.ReadAll(gzipReader)
+		if err != nil {
+			return nil, fmt.Errorf("ioutil.ReadAll gzip %v", err)
+		}
+	}
+	return content, nil
+}
+
+// publishAssets publishes one feed message per asset in the topic of its asset type, creating missing topics
+func publishAssets(assets []mca.Asset, startTime time.Time, global *Global) (pubSubMsgNumber int64, err error) {
+	var topicList []string
+	err = gps.GetTopicList(global.ctx, global.pubsubPublisherClient, global.projectID, &topicList)
+	if err != nil {
+		return 0, fmt.Errorf("gps.GetTopicList %v", err)
+	}
+	var topicNames []string
+	messagesByTopic := make(map[string][]*pubsubpb.PubsubMessage)
+	for _, asset := range assets {
+		topicName := "cai-rces-" + cai.GetAssetShortTypeName(asset.AssetType)
+		if _, ok := messagesByTopic[topicName]; !ok {
+			topicNames = append(topicNames, topicName)
+		}
+		feedMessageJSON, err := json.Marshal(feedMessage{
+			Asset:     asset,
+			Window:    cai.Window{StartTime: startTime},
+			Origin:    "batch-export",
+			StepStack: global.stepStack,
+		})
+		if err != nil {
+			return pubSubMsgNumber, fmt.Errorf("json.Marshal(feedMessage) %v", err)
+		}
+		var pubSubMessage pubsubpb.PubsubMessage
+		pubSubMessage.Data = feedMessageJSON
+		pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
+		trc.Inject(global.spanCtx, pubSubMessage.Attributes)
+		messagesByTopic[topicName] = append(messagesByTopic[topicName], &pubSubMessage)
+	}
+
+	for _, topicName := range topicNames {
+		if err = gps.CreateTopic(global.ctx, global.pubsubPublisherClient, &topicList, topicName, global.projectID); err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName:   global.microserviceName,
+				InstanceName:       global.instanceName,
+				Environment:        global.environment,
+				Severity:           "WARNING",
+				Message:            fmt.Sprintf("ignored %d assets: no topic to publish %s", len(messagesByTopic[topicName]), topicName),
+				Description:        fmt.Sprintf("err %v", err),
+				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
+			})
+			continue
+		}
+		messages := messagesByTopic[topicName]
+		for i := 0; i < len(messages); i = i + publishBatchSize {
+			j := i + publishBatchSize
+			if j > len(messages) {
+				j = len(messages)
+			}
+			var publishRequest pubsubpb.PublishRequest
+			publishRequest.Topic = fmt.Sprintf("projects/%s/topics/%s", global.projectID, topicName)
+			publishRequest.Messages = messages[i:j]
+			if _, err = global.pubsubPublisherClient.Publish(global.ctx, &publishRequest); err != nil {
+				return pubSubMsgNumber, fmt.Errorf("global.pubsubPublisherClient.Publish %s %v", topicName, err)
+			}
+			pubSubMsgNumber = pubSubMsgNumber + int64(j-i)
+		}
+	}
+	return pubSubMsgNumber, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package importsnapshot imports AWS Config snapshots and Azure Resource Graph exports as PubSub asset feed messages
+
+One snapshot item = one PubSub message.
+
+Triggered by
+
+Google Cloud Storage event when a snapshot is copied to the snapshots bucket, e.g. by Storage Transfer Service from S3 or Azure Blob Storage.
+
+Instances
+
+One per source:
+
+- aws: AWS Config snapshot files, e.g. objectNameRegex ConfigSnapshot.*\.json(\.gz)?$. Set AWS entityPaths to locate accounts in organizational units.
+
+- azure: Azure Resource Graph exports of the resources and resourcecontainers tables, as JSON array, az graph query output or JSON lines, e.g. objectNameRegex ^azure/.*\.json(l)?(\.gz)?$
+
+Output
+
+- PubSub messages formated like Cloud Asset Inventory real-time feed messages, with aws/ and azure/ asset types e.g. aws/ec2/SecurityGroup.
+
+- Delivered in per asset type topics, named like CAI ones e.g. cai-rces-awsec2-SecurityGroup, so that monitor and stream2bq instances subscribe to them the same way.
+
+- Create missing topics en the fly.
+
+- Constraints evaluating these assets must target their ancestry path e.g. aws-organizations/o-a1b2c3d4e5/* or azure-tenants/<tenantID>/*
+
+Cardinality
+
+One-many: one snapshot is nibbled in many feed messages.
+
+Automatic retrying
+
+Yes.
+
+Tracing
+
+Starts the trace of the snapshot and sets the traceparent attribute on each published feed message. Set trace samplePercent in service.yaml, 0 disables the export to Cloud Trace.
+
+Implementation example
+
+ package p
+ import (
+     "context"
+
+     "github.com/BrunoReboul/ram/services/importsnapshot"
+     "github.com/BrunoReboul/ram/utilities/gcs"
+ )
+ var global importsnapshot.Global
+ var ctx = context.Background()
+
+ // EntryPoint is the function to be executed for each cloud function occurence
+ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event) error {
+     return importsnapshot.EntryPoint(ctxEvent, gcsEvent, &global)
+ }
+
+ func init() {
+     importsnapshot.Initialize(ctx, &global)
+ }
+
+*/
+package importsnapshot
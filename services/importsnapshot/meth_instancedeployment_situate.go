@@ -0,0 +1,36 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importsnapshot
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Situate complement settings taking in account the situation for service and instance settings
+func (instanceDeployment *InstanceDeployment) Situate() (err error) {
+	if instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.Snapshots.Name == "" {
+		return fmt.Errorf("missing hosting gcs buckets snapshots name for environment %s in solution settings", instanceDeployment.Core.EnvironmentName)
+	}
+	if _, err = regexp.Compile(instanceDeployment.Settings.Instance.ObjectNameRegex); err != nil {
+		return fmt.Errorf("objectNameRegex %v", err)
+	}
+	instanceDeployment.Settings.Service.GCF.FunctionType = "backgroundGCS"
+	instanceDeployment.Settings.Service.GCF.Description = fmt.Sprintf("import %s snapshots matching %s from gcs bucket %s as asset feed messages",
+		instanceDeployment.Settings.Instance.Source,
+		instanceDeployment.Settings.Instance.ObjectNameRegex,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.Snapshots.Name)
+	return nil
+}
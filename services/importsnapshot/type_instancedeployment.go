@@ -0,0 +1,122 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importsnapshot
+
+import (
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/gcb"
+	"github.com/BrunoReboul/ram/utilities/gcf"
+	"github.com/BrunoReboul/ram/utilities/gsu"
+	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/trc"
+	"google.golang.org/api/iam/v1"
+)
+
+// InstanceDeployment settings and artifacts structure
+type InstanceDeployment struct {
+	DumpTimestamp time.Time `yaml:"dumpTimestamp"`
+	Core          *deploy.Core
+	Settings      struct {
+		Service struct {
+			GSU   gsu.Parameters
+			IAM   iamgt.Parameters
+			GCB   gcb.Parameters
+			GCF   gcf.Parameters
+			Trace trc.Parameters
+		}
+		Instance struct {
+			Source          string `valid:"isOneOf,aws,azure"`
+			ObjectNameRegex string `yaml:"objectNameRegex" valid:"isNotZeroValue"`
+			AWS             struct {
+				// EntityPaths AWS Organizations entity paths of the accounts e.g. o-a1b2c3d4e5/r-ab12/ou-ab12-11111111/111111111111/
+				EntityPaths []string `yaml:"entityPaths,omitempty"`
+			} `yaml:"AWS,omitempty"`
+		}
+	}
+}
+
+// NewInstanceDeployment create deployment structure with default settings set
+func NewInstanceDeployment() *InstanceDeployment {
+	var instanceDeployment InstanceDeployment
+	instanceDeployment.Settings.Service.GSU.APIList = []string{
+		"cloudfunctions.googleapis.com",
+		"cloudtrace.googleapis.com",
+		"pubsub.googleapis.com"}
+	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
+
+	instanceDeployment.Settings.Service.IAM.RunRoles.Project = []iam.Role{
+		projectRunRole()}
+	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
+		projectDeployCoreRole(),
+		iamgt.ProjectDeployExtendedRole(),
+		dlq.ProjectDeployDeadLetterRole()}
+
+	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
+	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
+	instanceDeployment.Settings.Service.GCB.DeployIAMBindings = true
+	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
+		projectDeployCoreRole().Title,
+		iamgt.ProjectDeployExtendedRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
+	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
+		"roles/iam.serviceAccountUser"}
+
+	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
+		projectRunRole().Title}
+	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Hosting.Project.Roles = []string{
+		"roles/cloudtrace.agent"}
+
+	instanceDeployment.Settings.Service.GCF.AvailableMemoryMb = 2048
+	instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds = 600
+	instanceDeployment.Settings.Service.GCF.Timeout = "540s" //is max value
+
+	instanceDeployment.Settings.Service.Trace.SamplePercent = 10
+
+	return &instanceDeployment
+}
+
+func projectRunRole() (role iam.Role) {
+	role.Title = "ram_importsnapshot_run"
+	role.Description = "Real-time Asset Monitor import snapshot microservice permissions to run"
+	role.Stage = "GA"
+	role.IncludedPermissions = []string{
+		"storage.objects.get",
+		"pubsub.topics.create",
+		"pubsub.topics.list",
+		"pubsub.topics.publish"}
+	return role
+}
+
+func projectDeployCoreRole() (role iam.Role) {
+	role.Title = "ram_importsnapshot_deploy_core"
+	role.Description = "Real-time Asset Monitor import snapshot microservice core permissions to deploy"
+	role.Stage = "GA"
+	role.IncludedPermissions = []string{
+		"pubsub.topics.get",
+		"pubsub.topics.create",
+		"pubsub.topics.update",
+		"storage.buckets.get",
+		"storage.buckets.create",
+		"storage.buckets.update",
+		"cloudfunctions.functions.sourceCodeSet",
+		"cloudfunctions.functions.get",
+		"cloudfunctions.functions.create",
+		"cloudfunctions.functions.update",
+		"cloudfunctions.operations.get"}
+	return role
+}
@@ -25,10 +25,12 @@ import (
 
 	"github.com/BrunoReboul/ram/utilities/aut"
 	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gfs"
 	"github.com/BrunoReboul/ram/utilities/gps"
 	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/qta"
 	"github.com/BrunoReboul/ram/utilities/solution"
 	"github.com/google/uuid"
 	"google.golang.org/api/iterator"
@@ -40,8 +42,7 @@ import (
 	admin "google.golang.org/api/admin/directory/v1"
 )
 
-// Global variable to deal with GroupsListCall Pages constraint: no possible to pass variable to the function in pages()
-// https://pkg.go.dev/google.golang.org/api/admin/directory/v1?tab=doc#GroupsListCall.Pages
+// Global variable shared with browseMembers, executed for each page of members
 var ancestors []string
 var ancestryPath string
 var ctx context.Context
@@ -64,6 +65,7 @@ var timestamp time.Time
 type Global struct {
 	collectionID            string
 	ctx                     context.Context
+	deadLetter              dlq.Sender
 	dirAdminService         *admin.Service
 	environment             string
 	firestoreClient         *firestore.Client
@@ -75,9 +77,12 @@ type Global struct {
 	projectID               string
 	pubSubClient            *pubsub.Client
 	PubSubID                string
+	rateLimit               qta.Parameters
 	retryTimeOutSeconds     int64
 	step                    logging.Step
 	stepStack               logging.Steps
+	timeout                 time.Duration
+	tokenBucket             *qta.TokenBucket
 }
 
 // Initialize is to be executed in the init() function of the cloud function to optimize the cold start
@@ -119,7 +124,12 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 	global.outputTopicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupMembers
 	global.maxResultsPerPage = instanceDeployment.Settings.Service.MaxResultsPerPage
 	global.projectID = instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
+	global.rateLimit = instanceDeployment.Settings.Service.RateLimit
 	global.retryTimeOutSeconds = instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds
+	global.timeout, err = time.ParseDuration(instanceDeployment.Settings.Service.GCF.Timeout)
+	if err != nil {
+		global.timeout = 60 * time.Second
+	}
 	projectID := instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
 	keyJSONFilePath := solution.PathToFunctionCode + instanceDeployment.Settings.Service.KeyJSONFileName
 	serviceAccountEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
@@ -139,8 +149,52 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		})
 		return err
 	}
+	// one bucket per directory customer and API, shared by all the instances calling it
+	global.tokenBucket = qta.NewTokenBucket(global.firestoreClient,
+		fmt.Sprintf("directory_%s", instanceDeployment.Settings.Instance.GCI.DirectoryCustomerID),
+		global.rateLimit)
 
-	serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
+	if instanceDeployment.Settings.Instance.GCI.Keyless {
+		if clientOption, ok = aut.GetClientOptionKeyless(ctx,
+			serviceAccountEmail,
+			gciAdminUserToImpersonate,
+			[]string{admin.AdminDirectoryGroupMemberReadonlyScope},
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionKeyless")
+		}
+	} else {
+		serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "init_failed",
+				Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
+				InitID:           initID,
+			})
+			return err
+		}
+
+		if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
+			serviceAccountEmail,
+			keyJSONFilePath,
+			instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+			gciAdminUserToImpersonate,
+			[]string{admin.AdminDirectoryGroupMemberReadonlyScope},
+			serviceAccountKeyNames,
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
+		}
+	}
+	global.dirAdminService, err = admin.NewService(ctx, clientOption)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -148,26 +202,12 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
+			Description:      fmt.Sprintf("admin.NewService %v", err),
 			InitID:           initID,
 		})
 		return err
 	}
-
-	if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
-		serviceAccountEmail,
-		keyJSONFilePath,
-		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
-		gciAdminUserToImpersonate,
-		[]string{admin.AdminDirectoryGroupMemberReadonlyScope},
-		serviceAccountKeyNames,
-		initID,
-		global.microserviceName,
-		global.instanceName,
-		global.environment); !ok {
-		return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
-	}
-	global.dirAdminService, err = admin.NewService(ctx, clientOption)
+	global.pubSubClient, err = pubsub.NewClient(ctx, projectID)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -175,12 +215,17 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("admin.NewService %v", err),
+			Description:      fmt.Sprintf("pubsub.NewClient %v", err),
 			InitID:           initID,
 		})
 		return err
 	}
-	global.pubSubClient, err = pubsub.NewClient(ctx, projectID)
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -188,7 +233,7 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("pubsub.NewClient %v", err),
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
 			InitID:           initID,
 		})
 		return err
@@ -215,6 +260,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	global.stepStack = nil
 	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -236,13 +282,13 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
 			Severity:                   "CRITICAL",
-			Message:                    "noretry",
-			Description:                "Pubsub message too old",
+			Message:                    "dropped",
+			Description:                "Pubsub message too old, retryTimeOutSeconds reached, work dropped",
 			TriggeringPubsubID:         global.PubSubID,
 			TriggeringPubsubAgeSeconds: d.Seconds(),
 			TriggeringPubsubTimestamp:  &metadata.Timestamp,
@@ -266,7 +312,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	err = json.Unmarshal(PubSubMessage.Data, &feedMessageGroup)
 	if err != nil {
 		log.Printf("pubsub_id %s NORETRY_ERROR json.Unmarshal(pubSubMessage.Data, &feedMessageGroup)", global.PubSubID)
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -308,19 +354,38 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 		}
 	} else {
 		// retreive members from admin SDK
-		// pages function except just the name of the callback function. Not an invocation of the function
-		err = global.dirAdminService.Members.List(feedMessageGroup.Asset.Resource.Id).MaxResults(global.maxResultsPerPage).Pages(ctx, browseMembers)
-		if err != nil {
-			log.Println(logging.Entry{
+		// each page is throttled and retried with backoff on rate limit errors, so a retry does not republish the previous pages
+		membersListCall := global.dirAdminService.Members.List(feedMessageGroup.Asset.Resource.Id).MaxResults(global.maxResultsPerPage).Context(ctx)
+		deadline := now.Add(global.timeout * 3 / 4)
+		pageToken := ""
+		for {
+			var members *admin.Members
+			_, err = qta.CallWithBackoff(ctx, global.tokenBucket, global.rateLimit, deadline, logging.Entry{
 				MicroserviceName:   global.microserviceName,
 				InstanceName:       global.instanceName,
 				Environment:        global.environment,
-				Severity:           "CRITICAL",
-				Message:            "redo_on_transient",
-				Description:        fmt.Sprintf("dirAdminService.Members.List %v", err),
 				TriggeringPubsubID: global.PubSubID,
+			}, func() (err error) {
+				members, err = membersListCall.PageToken(pageToken).Do()
+				return err
 			})
-			return err
+			if err != nil {
+				log.Println(logging.Entry{
+					MicroserviceName:   global.microserviceName,
+					InstanceName:       global.instanceName,
+					Environment:        global.environment,
+					Severity:           "CRITICAL",
+					Message:            "redo_on_transient",
+					Description:        fmt.Sprintf("dirAdminService.Members.List %v", err),
+					TriggeringPubsubID: global.PubSubID,
+				})
+				return err
+			}
+			browseMembers(members)
+			if members.NextPageToken == "" {
+				break
+			}
+			pageToken = members.NextPageToken
 		}
 	}
 	if pubSubMsgNumber > 0 {
@@ -419,7 +484,8 @@ func browseFeedMessageGroupMembersFromCache(global *Global) (err error) {
 						})
 					} else {
 						pubSubMessage := &pubsub.Message{
-							Data: feedMessageMemberJSON,
+							Data:       feedMessageMemberJSON,
+							Attributes: gps.GetFeedMessageAttributes(feedMessageMemberJSON),
 						}
 						publishResult := topic.Publish(ctx, pubSubMessage)
 						waitgroup.Add(1)
@@ -454,10 +520,8 @@ func browseFeedMessageGroupMembersFromCache(global *Global) (err error) {
 }
 
 // browseMembers is executed for each page returning a set of members
-// A non-nil error returned will halt the iteration
-// the only accepted parameter is groups: https://pkg.go.dev/google.golang.org/api/admin/directory/v1?tab=doc#GroupsListCall.Pages
-// so, it use global variables to this package
-func browseMembers(members *admin.Members) error {
+// it use global variables to this package
+func browseMembers(members *admin.Members) {
 	var waitgroup sync.WaitGroup
 	topic := pubSubClient.Topic(outputTopicName)
 	for _, member := range members.Members {
@@ -468,8 +532,9 @@ func browseMembers(members *admin.Members) error {
 		feedMessageMember.Asset.AncestryPath = ancestryPath
 		feedMessageMember.Asset.AssetType = "www.googleapis.com/admin/directory/members"
 		feedMessageMember.Asset.Name = groupAssetName + "/members/" + member.Id
-		feedMessageMember.Asset.Resource.GroupEmail = groupEmail
-		feedMessageMember.Asset.Resource.MemberEmail = member.Email
+		// emails are lower case in the cache, as queried by expandgroupmembers and convertlog2feed
+		feedMessageMember.Asset.Resource.GroupEmail = strings.ToLower(groupEmail)
+		feedMessageMember.Asset.Resource.MemberEmail = strings.ToLower(member.Email)
 		feedMessageMember.Asset.Resource.ID = member.Id
 		feedMessageMember.Asset.Resource.Kind = member.Kind
 		feedMessageMember.Asset.Resource.Role = member.Role
@@ -488,7 +553,8 @@ func browseMembers(members *admin.Members) error {
 			})
 		} else {
 			pubSubMessage := &pubsub.Message{
-				Data: feedMessageMemberJSON,
+				Data:       feedMessageMemberJSON,
+				Attributes: gps.GetFeedMessageAttributes(feedMessageMemberJSON),
 			}
 			publishResult := topic.Publish(ctx, pubSubMessage)
 			waitgroup.Add(1)
@@ -505,5 +571,4 @@ func browseMembers(members *admin.Members) error {
 		}
 	}
 	waitgroup.Wait()
-	return nil
 }
@@ -37,7 +37,7 @@ func (instanceDeployment *InstanceDeployment) deployGCFFunction() (err error) {
 	functionDeployment.Settings.Service.GCF = instanceDeployment.Settings.Service.GCF
 	functionDeployment.Settings.Instance.GCF.TriggerTopic = instanceDeployment.Settings.Instance.GCF.TriggerTopic
 
-	if !instanceDeployment.Core.Commands.Check {
+	if !instanceDeployment.Core.Commands.Check && !instanceDeployment.Settings.Instance.GCI.Keyless {
 		serviceAccountKey, err := instanceDeployment.getServiceAccountKey()
 		if err != nil {
 			return fmt.Errorf("getServiceAccountKey %v", err)
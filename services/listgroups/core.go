@@ -25,6 +25,8 @@ import (
 
 	"github.com/BrunoReboul/ram/utilities/aut"
 	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gfs"
 	"github.com/BrunoReboul/ram/utilities/gps"
@@ -37,6 +39,7 @@ import (
 	"cloud.google.com/go/functions/metadata"
 	"cloud.google.com/go/pubsub"
 	admin "google.golang.org/api/admin/directory/v1"
+	cloudidentity "google.golang.org/api/cloudidentity/v1beta1"
 )
 
 // Global variable to deal with GroupsListCall Pages constraint: no possible to pass variable to the function in pages()
@@ -57,9 +60,20 @@ var pubSubMsgNumber uint64
 var stepStack logging.Steps
 var timestamp time.Time
 
+// groupsCache keeps the Cloud Identity groups got when refreshing the groups of a member, as many members share the same groups
+// it is emptied every groupsCacheTTL to catch up with groups changes
+var groupsCache = make(map[string]*cloudidentity.Group)
+var groupsCacheMutex sync.Mutex
+var groupsCacheTime time.Time
+
+const groupsCacheTTL = 15 * time.Minute
+
 // Global structure for global variables to optimize the cloud function performances
 type Global struct {
+	backend                 string
+	cloudIdentityService    *cloudidentity.Service
 	ctx                     context.Context
+	deadLetter              dlq.Sender
 	dirAdminService         *admin.Service
 	directoryCustomerID     string
 	environment             string
@@ -82,6 +96,7 @@ type Settings struct {
 	DirectoryCustomerID string        `json:"directoryCustomerID"`
 	Domain              string        `json:"domain"`
 	EmailPrefix         string        `json:"emailPrefix"`
+	MemberKey           string        `json:"memberKey,omitempty"`
 	StepStack           logging.Steps `json:"step_stack,omitempty"`
 }
 
@@ -120,6 +135,7 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 	})
 
 	gciAdminUserToImpersonate := instanceDeployment.Settings.Instance.GCI.SuperAdminEmail
+	global.backend = instanceDeployment.Settings.Instance.GCI.Backend
 	global.directoryCustomerID = instanceDeployment.Settings.Instance.GCI.DirectoryCustomerID
 	global.inputTopicName = instanceDeployment.Artifacts.TopicName
 	global.logEventEveryXPubSubMsg = instanceDeployment.Settings.Service.LogEventEveryXPubSubMsg
@@ -131,6 +147,10 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 	serviceAccountEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
 		instanceDeployment.Core.ServiceName,
 		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID)
+	scopes := []string{admin.AdminDirectoryGroupReadonlyScope, admin.AdminDirectoryDomainReadonlyScope}
+	if global.backend == "cloudidentity" {
+		scopes = []string{cloudidentity.CloudIdentityGroupsReadonlyScope}
+	}
 
 	global.firestoreClient, err = firestore.NewClient(global.ctx, projectID)
 	if err != nil {
@@ -146,34 +166,76 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		return err
 	}
 
-	serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
-	if err != nil {
-		log.Println(logging.Entry{
-			MicroserviceName: global.microserviceName,
-			InstanceName:     global.instanceName,
-			Environment:      global.environment,
-			Severity:         "CRITICAL",
-			Message:          "init_failed",
-			Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
-			InitID:           initID,
-		})
-		return err
-	}
+	if instanceDeployment.Settings.Instance.GCI.Keyless {
+		if clientOption, ok = aut.GetClientOptionKeyless(ctx,
+			serviceAccountEmail,
+			gciAdminUserToImpersonate,
+			scopes,
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionKeyless")
+		}
+	} else {
+		serviceAccountKeyNames, err := gfs.ListKeyNames(ctx, global.firestoreClient, instanceDeployment.Core.ServiceName)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "init_failed",
+				Description:      fmt.Sprintf("gfs.ListKeyNames %v", err),
+				InitID:           initID,
+			})
+			return err
+		}
 
-	if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
-		serviceAccountEmail,
-		keyJSONFilePath,
-		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
-		gciAdminUserToImpersonate,
-		[]string{admin.AdminDirectoryGroupReadonlyScope, admin.AdminDirectoryDomainReadonlyScope},
-		serviceAccountKeyNames,
-		initID,
-		global.microserviceName,
-		global.instanceName,
-		global.environment); !ok {
-		return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
+		if clientOption, ok = aut.GetClientOptionAndCleanKeys(ctx,
+			serviceAccountEmail,
+			keyJSONFilePath,
+			instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+			gciAdminUserToImpersonate,
+			scopes,
+			serviceAccountKeyNames,
+			initID,
+			global.microserviceName,
+			global.instanceName,
+			global.environment); !ok {
+			return fmt.Errorf("aut.GetClientOptionAndCleanKeys")
+		}
+	}
+	if global.backend == "cloudidentity" {
+		global.cloudIdentityService, err = cloudidentity.NewService(ctx, clientOption)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "init_failed",
+				Description:      fmt.Sprintf("cloudidentity.NewService %v", err),
+				InitID:           initID,
+			})
+			return err
+		}
+	} else {
+		global.dirAdminService, err = admin.NewService(ctx, clientOption)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "init_failed",
+				Description:      fmt.Sprintf("admin.NewService %v", err),
+				InitID:           initID,
+			})
+			return err
+		}
 	}
-	global.dirAdminService, err = admin.NewService(ctx, clientOption)
+	global.pubSubClient, err = pubsub.NewClient(ctx, projectID)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -181,12 +243,17 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("admin.NewService %v", err),
+			Description:      fmt.Sprintf("pubsub.NewClient %v", err),
 			InitID:           initID,
 		})
 		return err
 	}
-	global.pubSubClient, err = pubsub.NewClient(ctx, projectID)
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName: global.microserviceName,
@@ -194,7 +261,7 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			Environment:      global.environment,
 			Severity:         "CRITICAL",
 			Message:          "init_failed",
-			Description:      fmt.Sprintf("pubsub.NewClient %v", err),
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
 			InitID:           initID,
 		})
 		return err
@@ -221,6 +288,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	global.stepStack = nil
 	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -242,7 +310,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
@@ -272,6 +340,24 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	if strings.HasPrefix(string(PubSubMessage.Data), "cron schedule") {
 		global.stepStack = append(global.stepStack, global.step)
 
+		if global.backend == "cloudidentity" {
+			// No sharding: the Cloud Identity API lists all the groups of the customer, whatever their domain or email first character
+			stepStack = global.stepStack
+			err = queryCloudIdentity(global)
+			if err != nil {
+				log.Println(logging.Entry{
+					MicroserviceName:   global.microserviceName,
+					InstanceName:       global.instanceName,
+					Environment:        global.environment,
+					Severity:           "CRITICAL",
+					Message:            "redo_on_transient",
+					Description:        fmt.Sprintf("queryCloudIdentity %v", err),
+					TriggeringPubsubID: global.PubSubID,
+				})
+				return err
+			}
+			return nil
+		}
 		err = initiateQueries(global)
 		if err != nil {
 			log.Println(logging.Entry{
@@ -306,7 +392,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 		var settings Settings
 		err = json.Unmarshal(PubSubMessage.Data, &settings)
 		if err != nil {
-			log.Println(logging.Entry{
+			global.deadLetter.NoRetry(logging.Entry{
 				MicroserviceName:   global.microserviceName,
 				InstanceName:       global.instanceName,
 				Environment:        global.environment,
@@ -337,6 +423,34 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 			}
 			stepStack = global.stepStack // as a global variable used in the browse function
 
+			if settings.MemberKey != "" {
+				if global.backend != "cloudidentity" {
+					global.deadLetter.NoRetry(logging.Entry{
+						MicroserviceName:   global.microserviceName,
+						InstanceName:       global.instanceName,
+						Environment:        global.environment,
+						Severity:           "CRITICAL",
+						Message:            "noretry",
+						Description:        fmt.Sprintf("memberKey %s requires the cloudidentity backend", settings.MemberKey),
+						TriggeringPubsubID: global.PubSubID,
+					})
+					return nil
+				}
+				err = queryTransitiveGroups(settings.MemberKey, global)
+				if err != nil {
+					log.Println(logging.Entry{
+						MicroserviceName:   global.microserviceName,
+						InstanceName:       global.instanceName,
+						Environment:        global.environment,
+						Severity:           "CRITICAL",
+						Message:            "redo_on_transient",
+						Description:        fmt.Sprintf("queryTransitiveGroups %v", err),
+						TriggeringPubsubID: global.PubSubID,
+					})
+					return err
+				}
+				return nil
+			}
 			err = queryDirectory(settings.Domain, settings.EmailPrefix, global)
 			if err != nil {
 				log.Println(logging.Entry{
@@ -441,7 +555,7 @@ func queryDirectory(domain string, emailPrefix string, global *Global) error {
 	// pages function expect just the name of the callback function. Not an invocation of the function
 	err := global.dirAdminService.Groups.List().Customer(global.directoryCustomerID).Domain(domain).Query(query).MaxResults(global.maxResultsPerPage).OrderBy("email").Pages(global.ctx, browseGroups)
 	if err != nil {
-		if strings.Contains(err.Error(), "Domain not found") {
+		if erm.IsNotFound(err) || (erm.IsBadRequest(err) && erm.HasReason(err, "notFound")) {
 			now := time.Now()
 			latency := now.Sub(global.step.StepTimestamp)
 			latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
@@ -463,6 +577,83 @@ func queryDirectory(domain string, emailPrefix string, global *Global) error {
 			return fmt.Errorf("dirAdminService.Groups.List: %v", err)
 		}
 	}
+	logQueryResult(fmt.Sprintf("domain '%s' emailPrefix '%s'", domain, emailPrefix), global)
+	return nil
+}
+
+// queryCloudIdentity lists all the groups of the directory customer using the Cloud Identity Groups API
+func queryCloudIdentity(global *Global) error {
+	log.Println(logging.Entry{
+		MicroserviceName:   global.microserviceName,
+		InstanceName:       global.instanceName,
+		Environment:        global.environment,
+		Severity:           "INFO",
+		Message:            fmt.Sprintf("launch Cloud Identity groups query on customer %s", global.directoryCustomerID),
+		TriggeringPubsubID: global.PubSubID,
+	})
+	pubSubMsgNumber = 0
+	pubSubErrNumber = 0
+	// FULL view to get dynamic group metadata
+	err := global.cloudIdentityService.Groups.List().Parent(fmt.Sprintf("customers/%s", global.directoryCustomerID)).View("FULL").PageSize(global.maxResultsPerPage).Pages(global.ctx, browseCloudIdentityGroups)
+	if err != nil {
+		return fmt.Errorf("cloudIdentityService.Groups.List: %v", err)
+	}
+	logQueryResult("all domains", global)
+	return nil
+}
+
+// queryTransitiveGroups refreshes the groups a member belongs to, directly or through nested groups
+func queryTransitiveGroups(memberKey string, global *Global) error {
+	pubSubMsgNumber = 0
+	pubSubErrNumber = 0
+	var waitgroup sync.WaitGroup
+	topic := pubSubClient.Topic(outputTopicName)
+	// escape the member key as a CEL string literal so it cannot alter the query
+	query := fmt.Sprintf("member_key_id == '%s' && 'cloudidentity.googleapis.com/groups.discussion_forum' in labels",
+		strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(memberKey))
+	err := global.cloudIdentityService.Groups.Memberships.SearchTransitiveGroups("groups/-").Query(query).Pages(global.ctx,
+		func(response *cloudidentity.SearchTransitiveGroupsResponse) error {
+			for _, groupRelation := range response.Memberships {
+				group, err := getCloudIdentityGroup(groupRelation.Group, global)
+				if err != nil {
+					return err
+				}
+				publishCloudIdentityGroup(topic, group, &waitgroup)
+			}
+			return nil
+		})
+	waitgroup.Wait()
+	if err != nil {
+		return fmt.Errorf("cloudIdentityService.Groups.Memberships.SearchTransitiveGroups: %v", err)
+	}
+	logQueryResult(fmt.Sprintf("groups of member '%s'", memberKey), global)
+	return nil
+}
+
+// getCloudIdentityGroup returns the group from the cache, else gets it with the FULL view to get dynamic group metadata
+func getCloudIdentityGroup(groupName string, global *Global) (group *cloudidentity.Group, err error) {
+	groupsCacheMutex.Lock()
+	if time.Since(groupsCacheTime) > groupsCacheTTL {
+		groupsCache = make(map[string]*cloudidentity.Group)
+		groupsCacheTime = time.Now()
+	}
+	group, ok := groupsCache[groupName]
+	groupsCacheMutex.Unlock()
+	if ok {
+		return group, nil
+	}
+	group, err = global.cloudIdentityService.Groups.Get(groupName).Context(global.ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("cloudIdentityService.Groups.Get %s %v", groupName, err)
+	}
+	groupsCacheMutex.Lock()
+	groupsCache[groupName] = group
+	groupsCacheMutex.Unlock()
+	return group, nil
+}
+
+// logQueryResult logs the number of groups published, or a cancel when none
+func logQueryResult(scope string, global *Global) {
 	if pubSubMsgNumber > 0 {
 		now := time.Now()
 		latency := now.Sub(global.step.StepTimestamp)
@@ -473,7 +664,7 @@ func queryDirectory(domain string, emailPrefix string, global *Global) error {
 			Environment:          global.environment,
 			Severity:             "NOTICE",
 			Message:              fmt.Sprintf("finish %d groups", pubSubMsgNumber),
-			Description:          fmt.Sprintf("directory %s domain '%s' emailPrefix '%s' Number of groups published %d to topic %s", directoryCustomerID, domain, emailPrefix, pubSubMsgNumber, outputTopicName),
+			Description:          fmt.Sprintf("directory %s %s Number of groups published %d to topic %s", directoryCustomerID, scope, pubSubMsgNumber, outputTopicName),
 			Now:                  &now,
 			TriggeringPubsubID:   global.PubSubID,
 			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
@@ -491,7 +682,7 @@ func queryDirectory(domain string, emailPrefix string, global *Global) error {
 			Environment:          global.environment,
 			Severity:             "NOTICE",
 			Message:              "cancel",
-			Description:          fmt.Sprintf("no group found for directory %s domain '%s' emailPrefix '%s'", directoryCustomerID, domain, emailPrefix),
+			Description:          fmt.Sprintf("no group found for directory %s %s", directoryCustomerID, scope),
 			Now:                  &now,
 			TriggeringPubsubID:   global.PubSubID,
 			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
@@ -511,7 +702,6 @@ func queryDirectory(domain string, emailPrefix string, global *Global) error {
 			TriggeringPubsubID: pubSubID,
 		})
 	}
-	return nil
 }
 
 // browseGroups is executed for each page returning a set of groups
@@ -546,7 +736,8 @@ func browseGroups(groups *admin.Groups) error {
 			})
 		} else {
 			pubSubMessage := &pubsub.Message{
-				Data: feedMessageJSON,
+				Data:       feedMessageJSON,
+				Attributes: gps.GetFeedMessageAttributes(feedMessageJSON),
 			}
 			publishResult := topic.Publish(ctx, pubSubMessage)
 			waitgroup.Add(1)
@@ -567,6 +758,57 @@ func browseGroups(groups *admin.Groups) error {
 	return nil
 }
 
+// browseCloudIdentityGroups is executed for each page returning a set of Cloud Identity groups
+// https://pkg.go.dev/google.golang.org/api/cloudidentity/v1beta1?tab=doc#GroupsListCall.Pages
+func browseCloudIdentityGroups(response *cloudidentity.ListGroupsResponse) error {
+	var waitgroup sync.WaitGroup
+	topic := pubSubClient.Topic(outputTopicName)
+	for _, group := range response.Groups {
+		publishCloudIdentityGroup(topic, group, &waitgroup)
+	}
+	waitgroup.Wait()
+	return nil
+}
+
+// publishCloudIdentityGroup publishes a group feed message, the caller waits for the waitgroup
+func publishCloudIdentityGroup(topic *pubsub.Topic, group *cloudidentity.Group, waitgroup *sync.WaitGroup) {
+	feedMessage := cai.MakeFeedMessageCloudIdentityGroup(directoryCustomerID, group)
+	feedMessage.Window.StartTime = timestamp
+	feedMessage.Origin = "batch-listgroups"
+	feedMessage.Deleted = false
+	feedMessage.StepStack = stepStack
+	feedMessageJSON, err := json.Marshal(feedMessage)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName:   microserviceName,
+			InstanceName:       instanceName,
+			Environment:        environment,
+			Severity:           "WARNING",
+			Message:            "json.Marshal(feedMessage)",
+			Description:        fmt.Sprintf("feedMessage %v", feedMessage),
+			TriggeringPubsubID: pubSubID,
+		})
+		return
+	}
+	pubSubMessage := &pubsub.Message{
+		Data:       feedMessageJSON,
+		Attributes: gps.GetFeedMessageAttributes(feedMessageJSON),
+	}
+	publishResult := topic.Publish(ctx, pubSubMessage)
+	waitgroup.Add(1)
+	go gps.GetPublishCallResult(ctx,
+		publishResult,
+		waitgroup,
+		directoryCustomerID+"/"+feedMessage.Asset.Resource.Email,
+		&pubSubErrNumber,
+		&pubSubMsgNumber,
+		logEventEveryXPubSubMsg,
+		pubSubID,
+		microserviceName,
+		instanceName,
+		environment)
+}
+
 // getByteSet return a set of lenght contiguous bytes starting at bytes
 func getByteSet(start byte, length int) []byte {
 	byteSet := make([]byte, length)
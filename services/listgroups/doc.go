@@ -61,6 +61,28 @@ Key rotation strategy
 
 - So, how to rotate service accout key? just redeploy the cloud function.
 
+Keyless option
+
+- Set keyless: true in instance.yaml, or in solution.yaml directoryCustomerIDs, to avoid creating any service account key.
+
+- The JSON Web token is then signed using the IAM Credentials API signJwt method and exchanged for an access token.
+
+- The service account is granted roles/iam.serviceAccountTokenCreator on itself during the deployment.
+
+Cloud Identity backend
+
+- Set backend: cloudidentity in instance.yaml, or in solution.yaml directoryCustomerIDs, to list groups using the Cloud Identity Groups API instead of the Admin SDK Directory API.
+
+- The domain wide delegation Oauth scope is then https://www.googleapis.com/auth/cloud-identity.groups.readonly
+
+- No sharding: groups.list with parent=customers/<directory_customer_id> returns the groups of all the domains in one query.
+
+- The FULL view is used so dynamic group queries and labels, e.g. security group label, are carried into the group asset resource.
+
+- Asset names and asset type are the same than with the directory backend, so downstream microservices are not impacted.
+
+- A PubSub message with a memberKey setting, e.g. {"memberKey":"user@example.com"}, refreshes only the groups the member belongs to, directly or through nested groups, using memberships.searchTransitiveGroups. The groups are cached for 15 minutes by function instance, as many members share the same groups.
+
 GCI authentication notes
 
 - Read the service account json key file created during the cloud function deployment.
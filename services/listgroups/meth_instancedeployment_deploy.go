@@ -37,6 +37,11 @@ func (instanceDeployment *InstanceDeployment) Deploy() (err error) {
 		if err = instanceDeployment.deployIAMServiceAccount(); err != nil {
 			return err
 		}
+		if instanceDeployment.Settings.Instance.GCI.Keyless {
+			if err = instanceDeployment.deployIAMBindings(); err != nil {
+				return err
+			}
+		}
 		if err = instanceDeployment.deployGRMProjectBindings(); err != nil {
 			return err
 		}
@@ -44,11 +49,20 @@ func (instanceDeployment *InstanceDeployment) Deploy() (err error) {
 		if err = instanceDeployment.deployGPSTopic(); err != nil {
 			return err
 		}
+		if err = instanceDeployment.deployDeadLetter(); err != nil {
+			return err
+		}
 		if err = instanceDeployment.deploySCHJob(); err != nil {
 			return err
 		}
 	}
-	if err = instanceDeployment.deployGCFFunction(); err != nil {
+	switch instanceDeployment.Settings.Instance.Compute {
+	case "run":
+		err = instanceDeployment.deployRUNService()
+	default:
+		err = instanceDeployment.deployGCFFunction()
+	}
+	if err != nil {
 		return err
 	}
 	log.Printf("%s this cloud function service account needs domain wide delegation", instanceDeployment.Core.InstanceName)
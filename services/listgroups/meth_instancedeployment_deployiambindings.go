@@ -0,0 +1,37 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listgroups
+
+import (
+	"fmt"
+
+	"github.com/BrunoReboul/ram/utilities/iamgt"
+)
+
+// deployIAMBindings lets the microservice service account sign JWT as itself, for keyless domain wide delegation
+func (instanceDeployment *InstanceDeployment) deployIAMBindings() (err error) {
+	serviceAccountEmail := fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
+		instanceDeployment.Core.ServiceName,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID)
+	bindingsDeployment := iamgt.NewBindingsDeployment()
+	bindingsDeployment.Core = instanceDeployment.Core
+	bindingsDeployment.Settings.Service.IAM.RolesOnServiceAccounts = []string{
+		"roles/iam.serviceAccountTokenCreator"}
+	bindingsDeployment.Artifacts.ServiceAccountName = fmt.Sprintf("projects/%s/serviceAccounts/%s",
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		serviceAccountEmail)
+	bindingsDeployment.Artifacts.Member = fmt.Sprintf("serviceAccount:%s", serviceAccountEmail)
+	return bindingsDeployment.Deploy()
+}
@@ -18,10 +18,12 @@ import (
 	"time"
 
 	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/gcb"
 	"github.com/BrunoReboul/ram/utilities/gcf"
 	"github.com/BrunoReboul/ram/utilities/gsu"
 	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/run"
 	"github.com/BrunoReboul/ram/utilities/sch"
 	"google.golang.org/api/iam/v1"
 )
@@ -42,14 +44,18 @@ type InstanceDeployment struct {
 			IAM                     iamgt.Parameters
 			GCB                     gcb.Parameters
 			GCF                     gcf.Parameters
+			RUN                     run.Parameters
 			KeyJSONFileName         string `yaml:"keyJSONFileName"`
 			LogEventEveryXPubSubMsg uint64 `yaml:"logEventEveryXPubSubMsg"`
-			MaxResultsPerPage       int64  `yaml:"maxResultsPerPage"`
+			MaxResultsPerPage       int64  `yaml:"maxResultsPerPage" valid:"isInRange,1,200"`
 		}
 		Instance struct {
-			GCI struct {
+			Compute string `yaml:"compute,omitempty" valid:"isOneOf,gcf,run"`
+			GCI     struct {
 				DirectoryCustomerID string `yaml:"directoryCustomerID"`
-				SuperAdminEmail     string `yaml:"superAdminEmail"`
+				SuperAdminEmail     string `yaml:"superAdminEmail" valid:"isEmail"`
+				Keyless             bool   `yaml:"keyless,omitempty"`
+				Backend             string `yaml:"backend,omitempty" valid:"isOneOf,directory,cloudidentity"`
 			}
 			SCH sch.Parameters
 		}
@@ -64,14 +70,19 @@ func NewInstanceDeployment() *InstanceDeployment {
 		"cloudfunctions.googleapis.com",
 		"pubsub.googleapis.com",
 		"cloudscheduler.googleapis.com",
-		"admin.googleapis.com"}
+		"admin.googleapis.com",
+		"cloudidentity.googleapis.com",
+		"run.googleapis.com",
+		"iamcredentials.googleapis.com"}
 	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
 
 	instanceDeployment.Settings.Service.IAM.RunRoles.Project = []iam.Role{
 		projectRunRole()}
 	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
 		projectDeployCoreRole(),
-		iamgt.ProjectDeployExtendedRole()}
+		iamgt.ProjectDeployExtendedRole(),
+		run.ProjectDeployRunRole(),
+		dlq.ProjectDeployDeadLetterRole()}
 
 	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
 	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
@@ -80,7 +91,9 @@ func NewInstanceDeployment() *InstanceDeployment {
 		"roles/datastore.owner"}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
 		projectDeployCoreRole().Title,
-		iamgt.ProjectDeployExtendedRole().Title}
+		iamgt.ProjectDeployExtendedRole().Title,
+		run.ProjectDeployRunRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
 		"roles/iam.serviceAccountUser"}
 
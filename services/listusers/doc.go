@@ -0,0 +1,86 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package listusers extract all users from a GCI directory using the Admin SDK API
+
+Triggered by
+
+Cloud Scheduler Job, through PubSub messages.
+
+Instances
+
+few, one per directory customer ID.
+
+Output
+
+PubSub messages to a dedicated topic, gci-users-<directoryCustomerID>, formated like Cloud Asset Inventory feed messages.
+
+Asset type www.googleapis.com/admin/directory/users, the resource being a subset of the admin SDK user: suspension, archiving, admin flags, 2SV enrolment and enforcement, last login time and org unit. Personal data like phones or addresses are not exported.
+
+Cardinality
+
+- one-several: one extraction job is scalled into x queries.
+
+- x = (number of domains in GCI directory) x (36 email prefixes).
+
+- email prefixes: a..z 0..9.
+
+Automatic retrying
+
+Yes.
+
+Is recurssive
+
+Yes.
+
+Domain Wide Delegation
+
+Yes. The service account used to run this cloud function must have domain wide delegation and the following Oauth scopes:
+
+- https://www.googleapis.com/auth/admin.directory.user.readonly
+
+- https://www.googleapis.com/auth/admin.directory.domain.readonly
+
+Key rotation strategy, keyless option, GCI authentication and request notes
+
+Same as listgroups microservice.
+
+Cache
+
+Users are persisted in FireStore by publish2fs, so convertlog2feed can publish deleted users from the cache.
+
+Implementation example
+
+ package p
+ import (
+     "context"
+
+     "github.com/BrunoReboul/ram/services/listusers"
+     "github.com/BrunoReboul/ram/utilities/ram"
+ )
+ var global listusers.Global
+ var ctx = context.Background()
+
+ // EntryPoint is the function to be executed for each cloud function occurence
+ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage) error {
+     return listusers.EntryPoint(ctxEvent, PubSubMessage, &global)
+ }
+
+ func init() {
+     listusers.Initialize(ctx, &global)
+ }
+
+*/
+package listusers
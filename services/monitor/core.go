@@ -28,10 +28,12 @@ import (
 	"cloud.google.com/go/functions/metadata"
 	pubsub "cloud.google.com/go/pubsub/apiv1"
 	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gps"
 	"github.com/BrunoReboul/ram/utilities/logging"
 	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/trc"
 	"github.com/google/uuid"
 	"github.com/open-policy-agent/opa/rego"
 	"google.golang.org/api/cloudresourcemanager/v1"
@@ -42,11 +44,12 @@ import (
 // Global structure for global variables to optimize the cloud function performances
 type Global struct {
 	assetsCollectionID            string
-	assetsFilePath                string
-	assetsFolderPath              string
+	assetsFileName                string
+	assetsFolderName              string
 	cloudresourcemanagerService   *cloudresourcemanager.Service
 	cloudresourcemanagerServiceV2 *cloudresourcemanagerv2.Service // v2 is needed for folders
 	ctx                           context.Context
+	deadLetter                    dlq.Sender
 	deploymentTime                time.Time
 	environment                   string
 	firestoreClient               *firestore.Client
@@ -62,8 +65,11 @@ type Global struct {
 	ramViolationTopicName         string
 	regoModulesFolderPath         string
 	retryTimeOutSeconds           int64
+	spanCtx                       context.Context
 	step                          logging.Step
 	stepStack                     logging.Steps
+	trace                         string
+	tracer                        trc.Tracer
 	violationResolverLabelKeyName string
 	writabelOPAFolderPath         string
 }
@@ -161,6 +167,7 @@ type ComplianceStatus struct {
 	RuleDeploymentTimeStamp time.Time     `json:"ruleDeploymentTimeStamp"`
 	Compliant               bool          `json:"compliant"`
 	Deleted                 bool          `json:"deleted"`
+	ViolatedConstraintNames []string      `json:"violatedConstraintNames,omitempty"`
 	StepStack               logging.Steps `json:"step_stack,omitempty"`
 }
 
@@ -196,9 +203,9 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		InitID:           initID,
 	})
 
-	assetsFileName := instanceDeployment.Settings.Service.AssetsFileName
-	assetsFolderName := instanceDeployment.Settings.Service.AssetsFolderName
 	global.assetsCollectionID = instanceDeployment.Core.SolutionSettings.Hosting.FireStore.CollectionIDs.Assets
+	global.assetsFileName = instanceDeployment.Settings.Service.AssetsFileName
+	global.assetsFolderName = instanceDeployment.Settings.Service.AssetsFolderName
 	global.deploymentTime = instanceDeployment.Settings.Instance.DeploymentTime
 	global.functionName = instanceDeployment.Core.InstanceName
 	global.opaFolderPath = instanceDeployment.Settings.Service.OPAFolderPath
@@ -211,8 +218,6 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 	global.writabelOPAFolderPath = instanceDeployment.Settings.Service.WritabelOPAFolderPath
 	regoModulesFolderName := instanceDeployment.Settings.Service.RegoModulesFolderName
 
-	global.assetsFolderPath = global.writabelOPAFolderPath + "/" + assetsFolderName
-	global.assetsFilePath = global.assetsFolderPath + "/" + assetsFileName
 	global.regoModulesFolderPath = global.opaFolderPath + "/" + regoModulesFolderName
 
 	// services are initialized with context.Background() because it should
@@ -269,6 +274,40 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		})
 		return err
 	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.tracer, err = trc.NewTracer(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		global.microserviceName,
+		instanceDeployment.Settings.Service.Trace)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("trc.NewTracer %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
 	return nil
 }
 
@@ -290,7 +329,9 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 		return err
 	}
 	global.stepStack = nil
+	global.trace = ""
 	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -312,7 +353,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
@@ -345,7 +386,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 
 	assetsJSONDocument, feedMessage, err := buildAssetsDocument(PubSubMessage, global)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -358,6 +399,11 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	compliantLog.AssetsJSONDocument = assetsJSONDocument
 
+	spanCtx, span := global.tracer.StartSpan(global.ctx, global.instanceName, PubSubMessage.Attributes, global.stepStack)
+	defer global.tracer.EndSpan(span)
+	global.spanCtx = spanCtx
+	global.trace = global.tracer.GetLogTrace(spanCtx)
+
 	complianceStatus.AssetName = feedMessage.Asset.Name
 	complianceStatus.AssetInventoryTimeStamp = feedMessage.Window.StartTime
 	complianceStatus.AssetInventoryOrigin = feedMessage.Origin
@@ -365,6 +411,13 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	complianceStatus.RuleDeploymentTimeStamp = global.deploymentTime
 	complianceStatus.StepStack = global.stepStack
 	countViolations := 0
+	attributes := gps.Attributes{
+		AncestryPath: feedMessage.Asset.AncestryPath,
+		AssetType:    feedMessage.Asset.AssetType,
+		Deleted:      feedMessage.Deleted,
+		Origin:       feedMessage.Origin,
+		RuleName:     global.functionName,
+	}
 	if feedMessage.Deleted == true {
 		complianceStatus.Deleted = feedMessage.Deleted
 		// bool cannot be nil and have a zero value to false
@@ -373,7 +426,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 		complianceStatus.Deleted = false
 		resultSet, feedMessage, err := evalutateConstraints(assetsJSONDocument, feedMessage, global)
 		if err != nil {
-			log.Println(logging.Entry{
+			global.deadLetter.NoRetry(logging.Entry{
 				MicroserviceName:   global.microserviceName,
 				InstanceName:       global.instanceName,
 				Environment:        global.environment,
@@ -381,12 +434,13 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 				Message:            "noretry",
 				Description:        fmt.Sprintf("evalutateConstraints(assetsJSONDocument, feedMessage, global) %v", err),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 			return nil
 		}
 		violations, err := inspectResultSet(resultSet, feedMessage, global)
 		if err != nil {
-			log.Println(logging.Entry{
+			global.deadLetter.NoRetry(logging.Entry{
 				MicroserviceName:   global.microserviceName,
 				InstanceName:       global.instanceName,
 				Environment:        global.environment,
@@ -394,6 +448,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 				Message:            "noretry",
 				Description:        fmt.Sprintf("inspectResultSet(resultSet, feedMessage, global) %v", err),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 			return nil
 		}
@@ -403,10 +458,11 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 			complianceStatus.Compliant = false
 			for i, violation := range violations {
 				countViolations = i
+				complianceStatus.ViolatedConstraintNames = append(complianceStatus.ViolatedConstraintNames, violation.ConstraintConfig.Metadata.Name)
 				violation.StepStack = global.stepStack
 				violationJSON, err := json.Marshal(violation)
 				if err != nil {
-					log.Println(logging.Entry{
+					global.deadLetter.NoRetry(logging.Entry{
 						MicroserviceName:   global.microserviceName,
 						InstanceName:       global.instanceName,
 						Environment:        global.environment,
@@ -414,6 +470,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 						Message:            "noretry",
 						Description:        fmt.Sprintf("json.Marshal(violation) %v", err),
 						TriggeringPubsubID: global.PubSubID,
+						Trace:              global.trace,
 					})
 					return nil
 				}
@@ -425,8 +482,11 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 					Message:            fmt.Sprintf("not_compliant %s violationNum %d", complianceStatus.AssetName, i),
 					Description:        fmt.Sprintf("origin %s timestamp %v violationJSON %s", complianceStatus.AssetInventoryOrigin, complianceStatus.AssetInventoryTimeStamp, string(violationJSON)),
 					TriggeringPubsubID: global.PubSubID,
+					Trace:              global.trace,
 				})
-				err = publishPubSubMessage(violationJSON, global.ramViolationTopicName, global)
+				violationAttributes := attributes
+				violationAttributes.Severity = violation.ConstraintConfig.Spec.Severity
+				err = publishPubSubMessage(violationJSON, violationAttributes.ToMap(), global.ramViolationTopicName, global)
 				if err != nil {
 					log.Println(logging.Entry{
 						MicroserviceName:   global.microserviceName,
@@ -436,6 +496,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 						Message:            "redo_on_transient",
 						Description:        err.Error(),
 						TriggeringPubsubID: global.PubSubID,
+						Trace:              global.trace,
 					})
 					return err
 				}
@@ -444,7 +505,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	complianceStatusJSON, err := json.Marshal(complianceStatus)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -452,10 +513,11 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 			Message:            "noretry",
 			Description:        fmt.Sprintf("json.Marshal(complianceStatus) %v", err),
 			TriggeringPubsubID: global.PubSubID,
+			Trace:              global.trace,
 		})
 		return nil
 	}
-	err = publishPubSubMessage(complianceStatusJSON, global.ramComplianceStatusTopicName, global)
+	err = publishPubSubMessage(complianceStatusJSON, attributes.ToMap(), global.ramComplianceStatusTopicName, global)
 	if err != nil {
 		log.Println(logging.Entry{
 			MicroserviceName:   global.microserviceName,
@@ -465,6 +527,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 			Message:            "redo_on_transient",
 			Description:        err.Error(),
 			TriggeringPubsubID: global.PubSubID,
+			Trace:              global.trace,
 		})
 		return err
 	}
@@ -473,7 +536,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	if complianceStatus.Compliant == true {
 		CompliantLogJSON, err := json.Marshal(compliantLog)
 		if err != nil {
-			log.Println(logging.Entry{
+			global.deadLetter.NoRetry(logging.Entry{
 				MicroserviceName:   global.microserviceName,
 				InstanceName:       global.instanceName,
 				Environment:        global.environment,
@@ -481,6 +544,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 				Message:            "noretry",
 				Description:        fmt.Sprintf("json.Marshal(compliantLog) %v", err),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 			return nil
 		}
@@ -493,6 +557,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 				Message:            fmt.Sprintf("deleted %s", complianceStatus.AssetName),
 				Description:        fmt.Sprintf("origin %s timestamp %v CompliantLogJSON %s", complianceStatus.AssetInventoryOrigin, complianceStatus.AssetInventoryTimeStamp, string(CompliantLogJSON)),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 		} else {
 			log.Println(logging.Entry{
@@ -503,6 +568,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 				Message:            fmt.Sprintf("compliant %s", complianceStatus.AssetName),
 				Description:        fmt.Sprintf("origin %s timestamp %v CompliantLogJSON %s", complianceStatus.AssetInventoryOrigin, complianceStatus.AssetInventoryTimeStamp, string(CompliantLogJSON)),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 		}
 	}
@@ -524,6 +590,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 		Description:          fmt.Sprintf("number of violations %d", countViolations),
 		Now:                  &now,
 		TriggeringPubsubID:   global.PubSubID,
+		Trace:                global.trace,
 		OriginEventTimestamp: &metadata.Timestamp,
 		LatencySeconds:       latency.Seconds(),
 		LatencyE2ESeconds:    latencyE2E.Seconds(),
@@ -532,9 +599,11 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	return nil
 }
 
-func publishPubSubMessage(docJSON []byte, topicName string, global *Global) error {
+func publishPubSubMessage(docJSON []byte, attributes map[string]string, topicName string, global *Global) error {
 	var pubSubMessage pubsubpb.PubsubMessage
 	pubSubMessage.Data = docJSON
+	pubSubMessage.Attributes = attributes
+	trc.Inject(global.spanCtx, pubSubMessage.Attributes)
 
 	var pubsubMessages []*pubsubpb.PubsubMessage
 	pubsubMessages = append(pubsubMessages, &pubSubMessage)
@@ -556,6 +625,7 @@ func publishPubSubMessage(docJSON []byte, topicName string, global *Global) erro
 		Message:            fmt.Sprintf("published to topic %s", topicName),
 		Description:        fmt.Sprintf("msg ids %v", pubsubResponse.MessageIds),
 		TriggeringPubsubID: global.PubSubID,
+		Trace:              global.trace,
 	})
 	_ = pubsubResponse
 	return nil
@@ -670,27 +740,25 @@ func importRegoModulesCode(global *Global) (regoModules map[string]string, err e
 // evalutateConstraints audit assets data to rego rules
 func evalutateConstraints(assetsJSONDocument []byte, feedMessage feedMessage, global *Global) (rego.ResultSet, feedMessage, error) {
 	var resultSet rego.ResultSet
-	if _, err := os.Stat(global.assetsFilePath); os.IsExist(err) {
-		// log.Println("Found ", assetsFilePath)
-		err := os.Remove(global.assetsFilePath)
-		if err != nil {
-			return resultSet, feedMessage, fmt.Errorf("os.Remove(assetsFilePath) %v", err)
-		}
-	}
-	if _, err := os.Stat(global.assetsFolderPath); os.IsNotExist(err) {
-		err = os.MkdirAll(global.assetsFolderPath, 0755)
+	// One writable folder per event, so that concurrent evaluations in the same instance do not share the assets file
+	eventOPAFolderPath := global.writabelOPAFolderPath + "/" + global.PubSubID
+	assetsFolderPath := eventOPAFolderPath + "/" + global.assetsFolderName
+	assetsFilePath := assetsFolderPath + "/" + global.assetsFileName
+	defer os.RemoveAll(eventOPAFolderPath)
+	if _, err := os.Stat(assetsFolderPath); os.IsNotExist(err) {
+		err = os.MkdirAll(assetsFolderPath, 0755)
 		if err != nil {
 			return resultSet, feedMessage, fmt.Errorf("os.MkdirAll(assetsFolderPath, 0755) %v", err)
 		}
 	}
-	err := ioutil.WriteFile(global.assetsFilePath, assetsJSONDocument, 0644)
+	err := ioutil.WriteFile(assetsFilePath, assetsJSONDocument, 0644)
 	if err != nil {
 		return resultSet, feedMessage, fmt.Errorf("ioutil.WriteFile(assetsFilePath, assetsJSONDocument, 0644) %v", err)
 	}
 
 	ctx := context.Background()
 	rego := rego.New(rego.Query("audit"),
-		rego.Load([]string{global.opaFolderPath, global.writabelOPAFolderPath}, nil),
+		rego.Load([]string{global.opaFolderPath, eventOPAFolderPath}, nil),
 		rego.Package("validator.gcp.lib"))
 
 	resultSet, err = rego.Eval(ctx)
@@ -35,12 +35,16 @@ Cardinality
 
 - When compliant: one-one, only the compliance state, no violations.
 
-- When not compliant: one-few, 1 compliance state + n violations.
+- When not compliant: one-few, 1 compliance state listing the violated constraint names + n violations.
 
 Automatic retrying
 
 Yes.
 
+Tracing
+
+One span per asset evaluation, child of the traceparent message attribute, else of the origin step of the step stack. The traceparent is propagated to the compliance status and violation messages. Set trace samplePercent in service.yaml, 0 disables the export to Cloud Trace.
+
 Implementation example
 
  package p
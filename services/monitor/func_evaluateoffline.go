@@ -0,0 +1,98 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"github.com/open-policy-agent/opa/util"
+)
+
+// EvaluateOffline audits an assets JSON array against the rule and constraints of a monitor instance folder, with the same rego modules as the deployed function
+func EvaluateOffline(ctx context.Context, instanceFolderPath string, instanceName string, assetsJSONDocument []byte) (offlineViolations []OfflineViolation, err error) {
+	regoRule, err := ioutil.ReadFile(fmt.Sprintf("%s/%s.rego", instanceFolderPath, instanceName))
+	if err != nil {
+		return nil, fmt.Errorf("ioutil.ReadFile %v", err)
+	}
+	regoConstraintsFolderPath := fmt.Sprintf("%s/%s", instanceFolderPath, solution.RegoConstraintsFolderName)
+	childs, err := ioutil.ReadDir(regoConstraintsFolderPath)
+	if err != nil {
+		return nil, fmt.Errorf("ioutil.ReadDir %v", err)
+	}
+	constraints := make(map[string]interface{})
+	for _, child := range childs {
+		if child.IsDir() {
+			bytes, err := ioutil.ReadFile(fmt.Sprintf("%s/%s/constraint.yaml", regoConstraintsFolderPath, child.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("ioutil.ReadFile %v", err)
+			}
+			var constraint interface{}
+			if err = util.Unmarshal(bytes, &constraint); err != nil {
+				return nil, fmt.Errorf("util.Unmarshal constraint %s %v", child.Name(), err)
+			}
+			constraints[child.Name()] = constraint
+		}
+	}
+	var assets interface{}
+	if err = util.UnmarshalJSON(assetsJSONDocument, &assets); err != nil {
+		return nil, fmt.Errorf("util.UnmarshalJSON assets %v", err)
+	}
+
+	store := inmem.NewFromObject(map[string]interface{}{
+		"assets":      assets,
+		"constraints": constraints,
+	})
+	resultSet, err := rego.New(rego.Query("audit"),
+		rego.Module("audit.rego", auditRego),
+		rego.Module("constraints.rego", constraintsRego),
+		rego.Module("util.rego", utilRego),
+		rego.Module(instanceName+".rego", string(regoRule)),
+		rego.Store(store),
+		rego.Package("validator.gcp.lib")).Eval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("rego.Eval %v", err)
+	}
+
+	if len(resultSet) == 0 || len(resultSet[0].Expressions) == 0 {
+		return offlineViolations, nil
+	}
+	if values, ok := resultSet[0].Expressions[0].Value.([]interface{}); ok {
+		for _, valueInterface := range values {
+			value, ok := valueInterface.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var offlineViolation OfflineViolation
+			offlineViolation.AssetName, _ = value["asset"].(string)
+			offlineViolation.ConstraintName, _ = value["constraint"].(string)
+			if ruleViolation, ok := value["violation"].(map[string]interface{}); ok {
+				offlineViolation.Message, _ = ruleViolation["msg"].(string)
+				offlineViolation.Metadata, _ = ruleViolation["details"].(map[string]interface{})
+			}
+			if constraintConfig, ok := value["constraint_config"].(map[string]interface{}); ok {
+				if spec, ok := constraintConfig["spec"].(map[string]interface{}); ok {
+					offlineViolation.Severity, _ = spec["severity"].(string)
+				}
+			}
+			offlineViolations = append(offlineViolations, offlineViolation)
+		}
+	}
+	return offlineViolations, nil
+}
@@ -51,8 +51,17 @@ func (instanceDeployment *InstanceDeployment) Deploy() (err error) {
 		if err = instanceDeployment.deployGPSTopic(); err != nil {
 			return err
 		}
+		if err = instanceDeployment.deployDeadLetter(); err != nil {
+			return err
+		}
+	}
+	switch instanceDeployment.Settings.Instance.Compute {
+	case "run":
+		err = instanceDeployment.deployRUNService()
+	default:
+		err = instanceDeployment.deployGCFFunction()
 	}
-	if err = instanceDeployment.deployGCFFunction(); err != nil {
+	if err != nil {
 		return err
 	}
 	log.Printf("%s done in %v minutes", instanceDeployment.Core.InstanceName, time.Since(start).Minutes())
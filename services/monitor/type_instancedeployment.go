@@ -18,11 +18,14 @@ import (
 	"time"
 
 	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/gcb"
 	"github.com/BrunoReboul/ram/utilities/gcf"
 	"github.com/BrunoReboul/ram/utilities/gsu"
 	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/run"
 	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/trc"
 	"google.golang.org/api/iam/v1"
 )
 
@@ -39,6 +42,8 @@ type InstanceDeployment struct {
 			IAM                   iamgt.Parameters
 			GCB                   gcb.Parameters
 			GCF                   gcf.Parameters
+			RUN                   run.Parameters
+			Trace                 trc.Parameters
 			AssetsFileName        string `yaml:"assetsFileName"`
 			AssetsFolderName      string `yaml:"assetsFolderName"`
 			OPAFolderPath         string `yaml:"opaFolderPath"`
@@ -46,6 +51,7 @@ type InstanceDeployment struct {
 			WritabelOPAFolderPath string `yaml:"writabelOPAFolderPath"`
 		}
 		Instance struct {
+			Compute        string `yaml:"compute,omitempty" valid:"isOneOf,gcf,run"`
 			GCF            gcf.Event
 			DeploymentTime time.Time `yaml:"deploymentTime" valid:"-"` // variable of type time.Type MUST discard validater. time.Time is retreived as struct with only unexported field, leading to crash recurusivity of validater
 		}
@@ -58,7 +64,9 @@ func NewInstanceDeployment() *InstanceDeployment {
 	instanceDeployment.Settings.Service.GSU.APIList = []string{
 		"appengine.googleapis.com",
 		"cloudfunctions.googleapis.com",
-		"pubsub.googleapis.com"}
+		"cloudtrace.googleapis.com",
+		"pubsub.googleapis.com",
+		"run.googleapis.com"}
 	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
 
 	instanceDeployment.Settings.Service.IAM.RunRoles.MonitoringOrg = []iam.Role{
@@ -67,14 +75,18 @@ func NewInstanceDeployment() *InstanceDeployment {
 	// 	projectRunRole()}
 	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
 		projectDeployCoreRole(),
-		iamgt.ProjectDeployExtendedRole()}
+		iamgt.ProjectDeployExtendedRole(),
+		run.ProjectDeployRunRole(),
+		dlq.ProjectDeployDeadLetterRole()}
 
 	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
 	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
 	instanceDeployment.Settings.Service.GCB.DeployIAMBindings = true
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
 		projectDeployCoreRole().Title,
-		iamgt.ProjectDeployExtendedRole().Title}
+		iamgt.ProjectDeployExtendedRole().Title,
+		run.ProjectDeployRunRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
 		"roles/iam.serviceAccountUser"}
 
@@ -86,18 +98,25 @@ func NewInstanceDeployment() *InstanceDeployment {
 	// 	projectRunRole().Title}
 	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Hosting.Project.Roles = []string{
 		"roles/datastore.viewer",
-		"roles/pubsub.publisher"}
+		"roles/pubsub.publisher",
+		"roles/cloudtrace.agent"}
 
 	instanceDeployment.Settings.Service.GCF.AvailableMemoryMb = 128
 	instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds = 3600
 	instanceDeployment.Settings.Service.GCF.Timeout = "60s"
 
+	// On cloud run one container instance evaluates several assets concurrently
+	instanceDeployment.Settings.Service.RUN.Concurrency = 80
+	instanceDeployment.Settings.Service.RUN.MemoryMb = 512
+
 	instanceDeployment.Settings.Service.AssetsFolderName = "/assets"
 	instanceDeployment.Settings.Service.AssetsFileName = "data.json"
 	instanceDeployment.Settings.Service.OPAFolderPath = solution.PathToFunctionCode + "opa"
 	instanceDeployment.Settings.Service.RegoModulesFolderName = "modules"
 	instanceDeployment.Settings.Service.WritabelOPAFolderPath = "/tmp/opa"
 
+	instanceDeployment.Settings.Service.Trace.SamplePercent = 10
+
 	return &instanceDeployment
 }
 
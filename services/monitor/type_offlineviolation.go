@@ -0,0 +1,24 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+// OfflineViolation a violation found when evaluating assets outside of the cloud function, e.g. from a terraform plan
+type OfflineViolation struct {
+	AssetName      string                 `json:"assetName"`
+	ConstraintName string                 `json:"constraintName"`
+	Severity       string                 `json:"severity"`
+	Message        string                 `json:"message"`
+	Metadata       map[string]interface{} `json:"metadata"`
+}
@@ -0,0 +1,480 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publish2scc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/functions/metadata"
+	securitycenter "cloud.google.com/go/securitycenter/apiv1p1beta1"
+	"github.com/BrunoReboul/ram/services/monitor"
+	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/erm"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/gps"
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/scc"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/trc"
+	"github.com/google/uuid"
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Global structure for global variables to optimize the cloud function performances
+type Global struct {
+	ctx                  context.Context
+	deadLetter           dlq.Sender
+	defaultSourceName    string
+	environment          string
+	instanceName         string
+	messageType          string
+	microserviceName     string
+	PubSubID             string
+	retryTimeOutSeconds  int64
+	securitycenterClient *securitycenter.Client
+	sourceNames          map[string]string // key is organizations/<organizationID>
+	step                 logging.Step
+	stepStack            logging.Steps
+	tracer               trc.Tracer
+}
+
+// stepStackMessage step stack of both violation and compliance status messages
+type stepStackMessage struct {
+	StepStack logging.Steps `json:"step_stack,omitempty"`
+}
+
+// violation from the "audit" rego policy in "audit.rego" module
+type violation struct {
+	NonCompliance    nonCompliance    `json:"nonCompliance"`
+	FunctionConfig   functionConfig   `json:"functionConfig"`
+	ConstraintConfig constraintConfig `json:"constraintConfig"`
+	FeedMessage      feedMessage      `json:"feedMessage"`
+	StepStack        logging.Steps    `json:"step_stack,omitempty"`
+}
+
+// nonCompliance form the "deny" rego policy in a <templateName>.rego module
+type nonCompliance struct {
+	Message  string          `json:"message"`
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// functionConfig function deployment settings
+type functionConfig struct {
+	FunctionName   string    `json:"functionName"`
+	DeploymentTime time.Time `json:"deploymentTime"`
+}
+
+// constraintConfig expose content of the constraint yaml file
+type constraintConfig struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Severity string `json:"severity"`
+	} `json:"spec"`
+}
+
+// feedMessage Cloud Asset Inventory feed message
+type feedMessage struct {
+	Asset struct {
+		Name                    string `json:"name"`
+		Owner                   string `json:"owner"`
+		ViolationResolver       string `json:"violationResolver"`
+		AncestryPathDisplayName string `json:"ancestryPathDisplayName"`
+		AncestryPath            string `json:"ancestryPath"`
+		AssetType               string `json:"assetType"`
+	} `json:"asset"`
+	Window cai.Window `json:"window"`
+	Origin string     `json:"origin"`
+}
+
+// Initialize is to be executed in the init() function of the cloud function to optimize the cold start
+func Initialize(ctx context.Context, global *Global) (err error) {
+	log.SetFlags(0)
+	global.ctx = ctx
+
+	var instanceDeployment InstanceDeployment
+
+	initID := fmt.Sprintf("%v", uuid.New())
+	err = ffo.ReadUnmarshalYAML(solution.PathToFunctionCode+solution.SettingsFileName, &instanceDeployment)
+	if err != nil {
+		log.Println(logging.Entry{
+			Severity:    "CRITICAL",
+			Message:     "init_failed",
+			Description: fmt.Sprintf("ReadUnmarshalYAML %s %v", solution.SettingsFileName, err),
+			InitID:      initID,
+		})
+		return err
+	}
+
+	global.environment = instanceDeployment.Core.EnvironmentName
+	global.instanceName = instanceDeployment.Core.InstanceName
+	global.microserviceName = instanceDeployment.Core.ServiceName
+
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "coldstart",
+		InitID:           initID,
+	})
+
+	global.messageType = instanceDeployment.Settings.Instance.SCC.MessageType
+	global.retryTimeOutSeconds = instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds
+
+	global.securitycenterClient, err = securitycenter.NewClient(global.ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("securitycenter.NewClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.sourceNames = make(map[string]string)
+	for _, organizationID := range instanceDeployment.Core.SolutionSettings.Monitoring.OrganizationIDs {
+		parent := fmt.Sprintf("organizations/%s", organizationID)
+		sourceName, err := scc.GetSourceName(global.ctx, global.securitycenterClient, parent, instanceDeployment.Settings.Service.SCC.SourceDisplayName)
+		if err == nil && sourceName == "" {
+			err = fmt.Errorf("no security command center source named %s in %s", instanceDeployment.Settings.Service.SCC.SourceDisplayName, parent)
+		}
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "init_failed",
+				Description:      fmt.Sprintf("scc.GetSourceName %v", err),
+				InitID:           initID,
+			})
+			return err
+		}
+		global.sourceNames[parent] = sourceName
+		if global.defaultSourceName == "" {
+			global.defaultSourceName = sourceName
+		}
+	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.tracer, err = trc.NewTracer(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		global.microserviceName,
+		instanceDeployment.Settings.Service.Trace)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("trc.NewTracer %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	return nil
+}
+
+// EntryPoint is the function to be executed for each cloud function occurence
+func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, global *Global) error {
+	metadata, err := metadata.FromContext(ctxEvent)
+	if err != nil {
+		// Assume an error on the function invoker and try again.
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "redo_on_transient",
+			Description:        fmt.Sprintf("pubsub_id no available metadata.FromContext: %v", err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return err
+	}
+	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
+	parts := strings.Split(metadata.Resource.Name, "/")
+	global.step = logging.Step{
+		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
+		StepTimestamp: metadata.Timestamp,
+	}
+	// unmarshal errors are reported when processing the message
+	var message stepStackMessage
+	_ = json.Unmarshal(PubSubMessage.Data, &message)
+	global.stepStack = append(message.StepStack, global.step)
+	spanCtx, span := global.tracer.StartSpan(global.ctx, global.instanceName, PubSubMessage.Attributes, global.stepStack)
+	defer global.tracer.EndSpan(span)
+
+	now := time.Now()
+	d := now.Sub(metadata.Timestamp)
+	log.Println(logging.Entry{
+		MicroserviceName:           global.microserviceName,
+		InstanceName:               global.instanceName,
+		Environment:                global.environment,
+		Severity:                   "NOTICE",
+		Message:                    "start",
+		TriggeringPubsubID:         global.PubSubID,
+		TriggeringPubsubAgeSeconds: d.Seconds(),
+		TriggeringPubsubTimestamp:  &metadata.Timestamp,
+		Now:                        &now,
+	})
+
+	if d.Seconds() > float64(global.retryTimeOutSeconds) {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:           global.microserviceName,
+			InstanceName:               global.instanceName,
+			Environment:                global.environment,
+			Severity:                   "CRITICAL",
+			Message:                    "noretry",
+			Description:                "Pubsub message too old",
+			TriggeringPubsubID:         global.PubSubID,
+			TriggeringPubsubAgeSeconds: d.Seconds(),
+			TriggeringPubsubTimestamp:  &metadata.Timestamp,
+			Now:                        &now,
+		})
+		return nil
+	}
+
+	var result string
+	switch global.messageType {
+	case "complianceStatus":
+		result, err = deactivateFindings(PubSubMessage, global)
+	case "violations":
+		result, err = upsertFinding(PubSubMessage.Data, global)
+	}
+	if err != nil {
+		if erm.IsTransient(err) {
+			log.Println(logging.Entry{
+				MicroserviceName:   global.microserviceName,
+				InstanceName:       global.instanceName,
+				Environment:        global.environment,
+				Severity:           "CRITICAL",
+				Message:            "redo_on_transient",
+				Description:        err.Error(),
+				TriggeringPubsubID: global.PubSubID,
+			})
+			return err
+		}
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        err.Error(),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return nil
+	}
+	if result != "" {
+		now := time.Now()
+		latency := now.Sub(metadata.Timestamp)
+		latencyE2E := now.Sub(global.stepStack[0].StepTimestamp)
+		log.Println(logging.Entry{
+			MicroserviceName:     global.microserviceName,
+			InstanceName:         global.instanceName,
+			Environment:          global.environment,
+			Severity:             "NOTICE",
+			Message:              fmt.Sprintf("finish %s", result),
+			Now:                  &now,
+			TriggeringPubsubID:   global.PubSubID,
+			Trace:                global.tracer.GetLogTrace(spanCtx),
+			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
+			LatencySeconds:       latency.Seconds(),
+			LatencyE2ESeconds:    latencyE2E.Seconds(),
+			StepStack:            global.stepStack,
+		})
+	}
+	return nil
+}
+
+// getSourceName returns the RAM source of the organization the asset belongs to, else the one of the first monitored organization
+func getSourceName(ancestryPath string, global *Global) string {
+	organization, _, err := cai.GetAncestryPathScope(ancestryPath)
+	if err == nil {
+		if sourceName, ok := global.sourceNames[organization]; ok {
+			return sourceName
+		}
+	}
+	return global.defaultSourceName
+}
+
+func upsertFinding(pubSubJSONDoc []byte, global *Global) (result string, err error) {
+	var violation violation
+	err = json.Unmarshal(pubSubJSONDoc, &violation)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal(pubSubJSONDoc, &violation) %v", err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return "", nil
+	}
+	if violation.FeedMessage.Asset.Name == "" || violation.FunctionConfig.FunctionName == "" {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        "missing asset name or rule name in violation",
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return "", nil
+	}
+	sourceName := getSourceName(violation.FeedMessage.Asset.AncestryPath, global)
+	if sourceName == "" {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        "no security command center source found, check monitored organizationIDs",
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return "", nil
+	}
+
+	var metadataValue structpb.Value
+	if len(violation.NonCompliance.Metadata) > 0 {
+		if err = protojson.Unmarshal(violation.NonCompliance.Metadata, &metadataValue); err != nil {
+			metadataValue = *structpb.NewStringValue(string(violation.NonCompliance.Metadata))
+		}
+	} else {
+		metadataValue = *structpb.NewNullValue()
+	}
+
+	finding := &securitycenterpb.Finding{
+		Name: fmt.Sprintf("%s/findings/%s", sourceName, scc.GetFindingID(violation.FeedMessage.Asset.Name,
+			violation.FunctionConfig.FunctionName,
+			violation.ConstraintConfig.Metadata.Name)),
+		Parent:       sourceName,
+		ResourceName: violation.FeedMessage.Asset.Name,
+		State:        securitycenterpb.Finding_ACTIVE,
+		Category:     violation.FunctionConfig.FunctionName,
+		Severity:     scc.GetSeverity(violation.ConstraintConfig.Spec.Severity),
+		EventTime:    timestamppb.New(violation.FeedMessage.Window.StartTime),
+		SourceProperties: map[string]*structpb.Value{
+			"message":                 structpb.NewStringValue(violation.NonCompliance.Message),
+			"metadata":                &metadataValue,
+			"ruleName":                structpb.NewStringValue(violation.FunctionConfig.FunctionName),
+			"constraintName":          structpb.NewStringValue(violation.ConstraintConfig.Metadata.Name),
+			"severity":                structpb.NewStringValue(violation.ConstraintConfig.Spec.Severity),
+			"assetType":               structpb.NewStringValue(violation.FeedMessage.Asset.AssetType),
+			"ancestryPath":            structpb.NewStringValue(violation.FeedMessage.Asset.AncestryPath),
+			"ancestryPathDisplayName": structpb.NewStringValue(violation.FeedMessage.Asset.AncestryPathDisplayName),
+			"owner":                   structpb.NewStringValue(violation.FeedMessage.Asset.Owner),
+			"violationResolver":       structpb.NewStringValue(violation.FeedMessage.Asset.ViolationResolver),
+			"origin":                  structpb.NewStringValue(violation.FeedMessage.Origin),
+		},
+	}
+	upserted, err := scc.UpsertFinding(global.ctx, global.securitycenterClient, finding)
+	if err != nil {
+		return "", err
+	}
+	if !upserted {
+		return fmt.Sprintf("skip %s more recent event time", finding.Name), nil
+	}
+	return fmt.Sprintf("upsert %s", finding.Name), nil
+}
+
+func deactivateFindings(PubSubMessage gps.PubSubMessage, global *Global) (result string, err error) {
+	var complianceStatus monitor.ComplianceStatus
+	err = json.Unmarshal(PubSubMessage.Data, &complianceStatus)
+	if err != nil {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        fmt.Sprintf("json.Unmarshal(pubSubJSONDoc, &complianceStatus) %v", err),
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return "", nil
+	}
+	if !complianceStatus.Compliant && len(complianceStatus.ViolatedConstraintNames) == 0 {
+		// compliance status published without the violated constraint names, active findings are upserted from the violations
+		log.Println(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "INFO",
+			Message:            fmt.Sprintf("not_compliant %s %s", complianceStatus.AssetName, complianceStatus.RuleName),
+			Description:        "no finding to deactivate",
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return "", nil
+	}
+
+	sourceName := getSourceName(PubSubMessage.Attributes[gps.AttributeAncestryPath], global)
+	if sourceName == "" {
+		global.deadLetter.NoRetry(logging.Entry{
+			MicroserviceName:   global.microserviceName,
+			InstanceName:       global.instanceName,
+			Environment:        global.environment,
+			Severity:           "CRITICAL",
+			Message:            "noretry",
+			Description:        "no security command center source found, check monitored organizationIDs",
+			TriggeringPubsubID: global.PubSubID,
+		})
+		return "", nil
+	}
+	count, err := scc.DeactivateFindings(global.ctx, global.securitycenterClient, sourceName,
+		complianceStatus.AssetName,
+		complianceStatus.RuleName,
+		complianceStatus.ViolatedConstraintNames,
+		complianceStatus.AssetInventoryTimeStamp)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("deactivated %d findings %s %s", count, complianceStatus.AssetName, complianceStatus.RuleName), nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package publish2scc publishes RAM violations as Security Command Center findings
+
+One RAM source per monitored organization, named after service settings scc sourceDisplayName. One finding per asset, rule and constraint, updated with the latest non compliance message: the category is the rule name, the resource name is the asset name, the severity is mapped from the constraint severity, and the non compliance message and metadata are set as source properties.
+
+A violation older than the finding event time is skipped, so a late or redelivered violation does not reactivate the finding.
+
+Findings are set INACTIVE when monitor reports the asset compliant again for the rule, including when the asset is deleted, and for the constraints the asset no longer violates while other constraints of the rule are still violated. The event time of an inactive finding is the one of the compliance state.
+
+Triggered by
+
+Messages in related PubSub topics.
+
+Instances
+
+- one for violations, creating or updating active findings.
+
+- one for compliance states, setting findings inactive.
+
+Output
+
+Security Command Center findings.
+
+Cardinality
+
+- violations: one-one, one pubsub message - one finding upserted.
+
+- compliance states: one-many, one pubsub message - the active findings of this asset and rule, except the still violated constraints, set inactive.
+
+Automatic retrying
+
+Yes, on transient Security Command Center errors, other errors are sent to the dead letter topic.
+
+Tracing
+
+One span per processed message, started on message reception, child of the traceparent message attribute, else of the origin step of the step stack. Set trace samplePercent in service.yaml, 0 disables the export to Cloud Trace.
+
+Implementation example
+
+ package p
+ import (
+     "context"
+
+     "github.com/BrunoReboul/ram/services/publish2scc"
+     "github.com/BrunoReboul/ram/utilities/gps"
+ )
+ var global publish2scc.Global
+ var ctx = context.Background()
+
+ // EntryPoint is the function to be executed for each cloud function occurence
+ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage) error {
+     return publish2scc.EntryPoint(ctxEvent, PubSubMessage, &global)
+ }
+
+ func init() {
+     publish2scc.Initialize(ctx, &global)
+ }
+
+*/
+package publish2scc
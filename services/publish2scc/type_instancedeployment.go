@@ -0,0 +1,141 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package publish2scc
+
+import (
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/gcb"
+	"github.com/BrunoReboul/ram/utilities/gcf"
+	"github.com/BrunoReboul/ram/utilities/gsu"
+	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/scc"
+	"github.com/BrunoReboul/ram/utilities/trc"
+	"google.golang.org/api/iam/v1"
+)
+
+// InstanceDeployment settings and artifacts structure
+type InstanceDeployment struct {
+	DumpTimestamp time.Time `yaml:"dumpTimestamp"`
+	Core          *deploy.Core
+	Settings      struct {
+		Service struct {
+			GSU   gsu.Parameters
+			IAM   iamgt.Parameters
+			GCB   gcb.Parameters
+			GCF   gcf.Parameters
+			SCC   scc.Parameters
+			Trace trc.Parameters
+		}
+		Instance struct {
+			GCF gcf.Event
+			SCC struct {
+				MessageType string `yaml:"messageType" valid:"isOneOf,violations,complianceStatus"`
+			}
+		}
+	}
+}
+
+// NewInstanceDeployment create deployment structure with default settings set
+func NewInstanceDeployment() *InstanceDeployment {
+	var instanceDeployment InstanceDeployment
+	instanceDeployment.Settings.Service.GSU.APIList = []string{
+		"cloudfunctions.googleapis.com",
+		"cloudtrace.googleapis.com",
+		"pubsub.googleapis.com",
+		"securitycenter.googleapis.com"}
+	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
+
+	instanceDeployment.Settings.Service.IAM.RunRoles.MonitoringOrg = []iam.Role{
+		monitoringOrgRunRole()}
+	instanceDeployment.Settings.Service.IAM.DeployRoles.MonitoringOrg = []iam.Role{
+		monitoringOrgDeployExtendedRole()}
+	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
+		projectDeployCoreRole(),
+		iamgt.ProjectDeployExtendedRole(),
+		dlq.ProjectDeployDeadLetterRole()}
+
+	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
+	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
+	instanceDeployment.Settings.Service.GCB.DeployIAMBindings = true
+	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Monitoring.Org.CustomRoles = []string{
+		monitoringOrgDeployExtendedRole().Title}
+	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
+		projectDeployCoreRole().Title,
+		iamgt.ProjectDeployExtendedRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
+	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
+		"roles/iam.serviceAccountUser"}
+
+	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Monitoring.Org.CustomRoles = []string{
+		monitoringOrgRunRole().Title}
+	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Hosting.Project.Roles = []string{
+		"roles/cloudtrace.agent"}
+
+	instanceDeployment.Settings.Service.GCF.AvailableMemoryMb = 128
+	instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds = 3600
+	instanceDeployment.Settings.Service.GCF.Timeout = "60s"
+
+	instanceDeployment.Settings.Service.SCC.SourceDisplayName = "Real-time Asset Monitor"
+
+	instanceDeployment.Settings.Service.Trace.SamplePercent = 10
+
+	return &instanceDeployment
+}
+
+func monitoringOrgRunRole() (role iam.Role) {
+	role.Title = "ram_publish2scc_monitoring_org_run"
+	role.Description = "Real-time Asset Monitor publish to security command center microservice permissions to run on monitoring org"
+	role.Stage = "GA"
+	role.IncludedPermissions = []string{
+		"securitycenter.findings.list",
+		"securitycenter.findings.update",
+		"securitycenter.sources.list"}
+	return role
+}
+
+func monitoringOrgDeployExtendedRole() (role iam.Role) {
+	role.Title = "ram_publish2scc_monitoring_org_deploy_extended"
+	role.Description = "Real-time Asset Monitor publish to security command center microservice extended permissions to deploy on monitoring org"
+	role.Stage = "GA"
+	role.IncludedPermissions = []string{
+		"iam.roles.create",
+		"iam.roles.get",
+		"iam.roles.update",
+		"resourcemanager.organizations.getIamPolicy",
+		"resourcemanager.organizations.setIamPolicy",
+		"securitycenter.sources.create",
+		"securitycenter.sources.get",
+		"securitycenter.sources.list"}
+	return role
+}
+
+func projectDeployCoreRole() (role iam.Role) {
+	role.Title = "ram_publish2scc_deploy_core"
+	role.Description = "Real-time Asset Monitor publish to security command center microservice core permissions to deploy"
+	role.Stage = "GA"
+	role.IncludedPermissions = []string{
+		"pubsub.topics.get",
+		"pubsub.topics.create",
+		"pubsub.topics.update",
+		"cloudfunctions.functions.sourceCodeSet",
+		"cloudfunctions.functions.get",
+		"cloudfunctions.functions.create",
+		"cloudfunctions.functions.update",
+		"cloudfunctions.operations.get"}
+	return role
+}
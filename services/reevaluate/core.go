@@ -0,0 +1,683 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reevaluate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudasset "cloud.google.com/go/asset/apiv1"
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/firestore"
+	pubsub "cloud.google.com/go/pubsub/apiv1"
+	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/erm"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/gps"
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/str"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+	assetpb "google.golang.org/genproto/googleapis/cloud/asset/v1"
+	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	origin                = "on-demand"
+	defaultWaitSeconds    = 30
+	maxWaitSeconds        = 90
+	maxAssets             = 1000
+	batchGetMaxNames      = 100
+	publishMaxMessages    = 100
+	pollIntervalSeconds   = 5
+	searchPageSize        = 500
+	sourceCAI             = "cai"
+	sourceCache           = "cache"
+	complianceStatusTable = "complianceStatus"
+)
+
+var errTooManyAssets = fmt.Errorf("more than %d assets to re-evaluate, narrow the ancestryPrefix or set assetTypes", maxAssets)
+
+// Global structure for global variables to optimize the cloud function performances
+type Global struct {
+	assetClient           *cloudasset.Client
+	bigQueryClient        *bigquery.Client
+	collectionID          string
+	ctx                   context.Context
+	datasetName           string
+	environment           string
+	firestoreClient       *firestore.Client
+	iamTopicName          string
+	instanceName          string
+	microserviceName      string
+	organizationIDs       []string
+	projectID             string
+	pubsubPublisherClient *pubsub.PublisherClient
+	topicList             []string
+}
+
+// requestParameters what to re-evaluate, where to read it from and how long to wait for the result
+type requestParameters struct {
+	ancestryPrefix string
+	assetName      string
+	assetTypes     []string
+	source         string
+	waitSeconds    int64
+}
+
+// asset uses the new CAI feed format
+type asset struct {
+	Name      string          `json:"name"`
+	AssetType string          `json:"assetType"`
+	Ancestors []string        `json:"ancestors"`
+	IamPolicy json.RawMessage `json:"iamPolicy"`
+	Resource  json.RawMessage `json:"resource"`
+}
+
+// feedMessage Cloud Asset Inventory feed message
+type feedMessage struct {
+	Asset     asset         `json:"asset"`
+	Window    cai.Window    `json:"window"`
+	Deleted   bool          `json:"deleted"`
+	Origin    string        `json:"origin"`
+	StepStack logging.Steps `json:"step_stack,omitempty"`
+}
+
+// cachedFeedMessage feed message as persisted in firestore by publish2fs
+type cachedFeedMessage struct {
+	Asset struct {
+		Name      string                 `firestore:"name"`
+		AssetType string                 `firestore:"assetType"`
+		Ancestors []string               `firestore:"ancestors"`
+		IamPolicy map[string]interface{} `firestore:"iamPolicy"`
+		Resource  map[string]interface{} `firestore:"resource"`
+	} `firestore:"asset"`
+}
+
+// statusRow one compliance status resulting from the re-evaluation
+type statusRow struct {
+	AssetName string `bigquery:"assetName" json:"assetName"`
+	RuleName  string `bigquery:"ruleName" json:"ruleName"`
+	Compliant bool   `bigquery:"compliant" json:"compliant"`
+	Deleted   bool   `bigquery:"deleted" json:"deleted"`
+}
+
+// result JSON response body
+type result struct {
+	Origin              string      `json:"origin"`
+	StartTime           time.Time   `json:"startTime"`
+	AssetCount          int         `json:"assetCount"`
+	EvaluatedAssetCount int         `json:"evaluatedAssetCount"`
+	Complete            bool        `json:"complete"`
+	Items               []statusRow `json:"items"`
+}
+
+// Only the statuses of this re-evaluation are selected, identified by the on-demand origin and the feed messages window start time
+const statusQuery = `
+SELECT DISTINCT
+    assetName,
+    ruleName,
+    compliant,
+    deleted
+FROM
+    %s
+WHERE
+    assetInventoryOrigin = @origin
+    AND assetInventoryTimeStamp = @startTime
+    AND assetName IN UNNEST(@assetNames)
+ORDER BY
+    assetName,
+    ruleName`
+
+// Initialize is to be executed in the init() function of the cloud function to optimize the cold start
+func Initialize(ctx context.Context, global *Global) (err error) {
+	log.SetFlags(0)
+	global.ctx = ctx
+
+	var instanceDeployment InstanceDeployment
+
+	initID := fmt.Sprintf("%v", uuid.New())
+	err = ffo.ReadUnmarshalYAML(solution.PathToFunctionCode+solution.SettingsFileName, &instanceDeployment)
+	if err != nil {
+		log.Println(logging.Entry{
+			Severity:    "CRITICAL",
+			Message:     "init_failed",
+			Description: fmt.Sprintf("ReadUnmarshalYAML %s %v", solution.SettingsFileName, err),
+			InitID:      initID,
+		})
+		return err
+	}
+
+	global.environment = instanceDeployment.Core.EnvironmentName
+	global.instanceName = instanceDeployment.Core.InstanceName
+	global.microserviceName = instanceDeployment.Core.ServiceName
+
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "coldstart",
+		InitID:           initID,
+	})
+
+	global.collectionID = instanceDeployment.Core.SolutionSettings.Hosting.FireStore.CollectionIDs.Assets
+	global.datasetName = instanceDeployment.Core.SolutionSettings.Hosting.Bigquery.Dataset.Name
+	global.iamTopicName = instanceDeployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.IAMPolicies
+	global.organizationIDs = instanceDeployment.Core.SolutionSettings.Monitoring.OrganizationIDs
+	global.projectID = instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
+
+	global.assetClient, err = cloudasset.NewClient(ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("asset.NewClient(ctx) %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.bigQueryClient, err = bigquery.NewClient(ctx, global.projectID)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("bigquery.NewClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.firestoreClient, err = firestore.NewClient(ctx, global.projectID)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("firestore.NewClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.pubsubPublisherClient, err = pubsub.NewPublisherClient(ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("pubsub.NewPublisherClient(ctx) %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	err = gps.GetTopicList(ctx, global.pubsubPublisherClient, global.projectID, &global.topicList)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("gps.GetTopicList %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	return nil
+}
+
+// EntryPoint is the function to be executed for each cloud function occurence
+func EntryPoint(w http.ResponseWriter, r *http.Request, global *Global) {
+	start := time.Now()
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "start",
+		Description:      fmt.Sprintf("%s %s", r.Method, r.URL.RequestURI()),
+		Now:              &start,
+	})
+
+	if r.Method != http.MethodPost {
+		finish(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed, use POST", r.Method), start, global)
+		return
+	}
+	parameters, err := getRequestParameters(r.URL.Query())
+	if err != nil {
+		finish(w, http.StatusBadRequest, err.Error(), start, global)
+		return
+	}
+	if parameters.ancestryPrefix != "" {
+		organization, _, _ := cai.GetAncestryPathScope(parameters.ancestryPrefix)
+		if !str.Find(global.organizationIDs, strings.TrimPrefix(organization, "organizations/")) {
+			finish(w, http.StatusBadRequest, fmt.Sprintf("%s is not a monitored organization", organization), start, global)
+			return
+		}
+	}
+
+	// BigQuery timestamps have a microsecond precision, the window start time is truncated to match the compliance status rows
+	startTime := start.UTC().Truncate(time.Microsecond)
+	stepStack := logging.Steps{
+		logging.Step{
+			StepID:        fmt.Sprintf("%s/%s", global.instanceName, uuid.New()),
+			StepTimestamp: startTime,
+		},
+	}
+
+	var feedMessages []feedMessage
+	switch parameters.source {
+	case sourceCache:
+		feedMessages, err = getFeedMessagesFromCache(parameters, global)
+	default:
+		feedMessages, err = getFeedMessagesFromCAI(parameters, startTime, global)
+	}
+	if err != nil {
+		if errors.Is(err, errTooManyAssets) {
+			finish(w, http.StatusBadRequest, err.Error(), start, global)
+			return
+		}
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "noretry",
+			Description:      fmt.Sprintf("get feed messages from %s %v", parameters.source, err),
+			StepStack:        stepStack,
+		})
+		finish(w, http.StatusInternalServerError, fmt.Sprintf("reading assets from %s failed", parameters.source), start, global)
+		return
+	}
+	if len(feedMessages) == 0 {
+		finish(w, http.StatusNotFound, fmt.Sprintf("no asset found in %s", parameters.source), start, global)
+		return
+	}
+
+	var assetNames []string
+	for i := range feedMessages {
+		feedMessages[i].Window.StartTime = startTime
+		feedMessages[i].Origin = origin
+		feedMessages[i].StepStack = stepStack
+		if !str.Find(assetNames, feedMessages[i].Asset.Name) {
+			assetNames = append(assetNames, feedMessages[i].Asset.Name)
+		}
+	}
+	if err = publishFeedMessages(feedMessages, global); err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "noretry",
+			Description:      fmt.Sprintf("publishFeedMessages %v", err),
+			StepStack:        stepStack,
+		})
+		finish(w, http.StatusInternalServerError, "publishing feed messages failed", start, global)
+		return
+	}
+
+	var body result
+	body.Origin = origin
+	body.StartTime = startTime
+	body.AssetCount = len(assetNames)
+	body.Items = []statusRow{}
+	if parameters.waitSeconds > 0 {
+		body.Items, body.EvaluatedAssetCount, err = waitComplianceStatuses(assetNames, startTime, parameters.waitSeconds, global)
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "WARNING",
+				Message:          "compliance status not available",
+				Description:      fmt.Sprintf("waitComplianceStatuses %v", err),
+				StepStack:        stepStack,
+			})
+		}
+		body.Complete = body.EvaluatedAssetCount == body.AssetCount
+	}
+
+	// 202 Accepted when the re-evaluation is still running, the statuses can be read later with servecompliance
+	statusCode := http.StatusAccepted
+	if body.Complete {
+		statusCode = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+
+	now := time.Now()
+	log.Println(logging.Entry{
+		MicroserviceName:     global.microserviceName,
+		InstanceName:         global.instanceName,
+		Environment:          global.environment,
+		Severity:             "NOTICE",
+		Message:              fmt.Sprintf("finish %d", statusCode),
+		Description:          fmt.Sprintf("%d feed messages published from %s for %d assets, %d evaluated", len(feedMessages), parameters.source, body.AssetCount, body.EvaluatedAssetCount),
+		Now:                  &now,
+		OriginEventTimestamp: &startTime,
+		LatencySeconds:       now.Sub(start).Seconds(),
+		StepStack:            stepStack,
+	})
+}
+
+// finish answers an error status code with a plain text message
+func finish(w http.ResponseWriter, statusCode int, message string, start time.Time, global *Global) {
+	http.Error(w, message, statusCode)
+	now := time.Now()
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "WARNING",
+		Message:          fmt.Sprintf("finish %d", statusCode),
+		Description:      message,
+		Now:              &now,
+		LatencySeconds:   now.Sub(start).Seconds(),
+	})
+}
+
+// getRequestParameters validates the URL query: either one asset name or one ancestry prefix
+func getRequestParameters(values url.Values) (parameters requestParameters, err error) {
+	parameters.assetName = values.Get("assetName")
+	parameters.ancestryPrefix = values.Get("ancestryPrefix")
+	if (parameters.assetName == "") == (parameters.ancestryPrefix == "") {
+		return parameters, fmt.Errorf("set either assetName or ancestryPrefix")
+	}
+	if parameters.ancestryPrefix != "" {
+		if _, _, err = cai.GetAncestryPathScope(parameters.ancestryPrefix); err != nil {
+			return parameters, err
+		}
+	}
+
+	if assetTypes := values.Get("assetTypes"); assetTypes != "" {
+		if parameters.assetName != "" {
+			return parameters, fmt.Errorf("assetTypes filter applies only to ancestryPrefix")
+		}
+		parameters.assetTypes = strings.Split(assetTypes, ",")
+	}
+
+	parameters.source = values.Get("source")
+	if parameters.source == "" {
+		parameters.source = sourceCAI
+	}
+	if parameters.source != sourceCAI && parameters.source != sourceCache {
+		return parameters, fmt.Errorf("source must be %s or %s, got %s", sourceCAI, sourceCache, parameters.source)
+	}
+
+	parameters.waitSeconds = defaultWaitSeconds
+	if waitSeconds := values.Get("waitSeconds"); waitSeconds != "" {
+		parameters.waitSeconds, err = strconv.ParseInt(waitSeconds, 10, 64)
+		if err != nil || parameters.waitSeconds < 0 || parameters.waitSeconds > maxWaitSeconds {
+			return parameters, fmt.Errorf("waitSeconds must be an integer from 0 to %d, got %s", maxWaitSeconds, waitSeconds)
+		}
+	}
+	return parameters, nil
+}
+
+// getFeedMessagesFromCAI reads the current state of the assets, resources and IAM policies, from Cloud Asset Inventory
+// An asset name is looked up in each monitored organization, an ancestry prefix is first expanded into asset names with a resource search
+func getFeedMessagesFromCAI(parameters requestParameters, startTime time.Time, global *Global) (feedMessages []feedMessage, err error) {
+	if parameters.ancestryPrefix != "" {
+		organization, scope, _ := cai.GetAncestryPathScope(parameters.ancestryPrefix)
+		assetNames, err := searchAssetNames(scope, parameters.assetTypes, global)
+		if err != nil {
+			return feedMessages, err
+		}
+		if len(assetNames) == 0 {
+			return feedMessages, nil
+		}
+		return batchGetAssets(organization, assetNames, startTime, global)
+	}
+	for _, organizationID := range global.organizationIDs {
+		feedMessages, err = batchGetAssets("organizations/"+organizationID, []string{parameters.assetName}, startTime, global)
+		if err != nil {
+			// The asset may belong to another monitored organization
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "INFO",
+				Message:          fmt.Sprintf("asset not read from organizations/%s", organizationID),
+				Description:      fmt.Sprintf("batchGetAssets %s %v", parameters.assetName, err),
+			})
+			continue
+		}
+		if len(feedMessages) > 0 {
+			return feedMessages, nil
+		}
+	}
+	return feedMessages, nil
+}
+
+// searchAssetNames lists the names of the resources under a scope
+func searchAssetNames(scope string, assetTypes []string, global *Global) (assetNames []string, err error) {
+	var request assetpb.SearchAllResourcesRequest
+	request.Scope = scope
+	request.AssetTypes = assetTypes
+	request.PageSize = searchPageSize
+	resourceSearchResultIterator := global.assetClient.SearchAllResources(global.ctx, &request)
+	for {
+		resourceSearchResult, err := resourceSearchResultIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return assetNames, fmt.Errorf("resourceSearchResultIterator.Next %v", err)
+		}
+		assetNames = append(assetNames, resourceSearchResult.Name)
+		if len(assetNames) > maxAssets {
+			return assetNames, errTooManyAssets
+		}
+	}
+	return assetNames, nil
+}
+
+// batchGetAssets reads the resource and the IAM policy snapshot of each asset at the start time
+func batchGetAssets(parent string, assetNames []string, startTime time.Time, global *Global) (feedMessages []feedMessage, err error) {
+	for _, contentType := range []assetpb.ContentType{assetpb.ContentType_RESOURCE, assetpb.ContentType_IAM_POLICY} {
+		for i := 0; i < len(assetNames); i = i + batchGetMaxNames {
+			j := i + batchGetMaxNames
+			if j > len(assetNames) {
+				j = len(assetNames)
+			}
+			var request assetpb.BatchGetAssetsHistoryRequest
+			request.Parent = parent
+			request.AssetNames = assetNames[i:j]
+			request.ContentType = contentType
+			request.ReadTimeWindow = &assetpb.TimeWindow{EndTime: timestamppb.New(startTime)}
+			var response *assetpb.BatchGetAssetsHistoryResponse
+			err = erm.Retry(3, time.Second, 10*time.Second, func() (err error) {
+				response, err = global.assetClient.BatchGetAssetsHistory(global.ctx, &request)
+				return err
+			})
+			if err != nil {
+				return feedMessages, fmt.Errorf("global.assetClient.BatchGetAssetsHistory %s %v", contentType, err)
+			}
+			for _, temporalAsset := range response.Assets {
+				if temporalAsset.Asset == nil {
+					continue
+				}
+				var feedMessage feedMessage
+				assetJSON, err := protojson.Marshal(temporalAsset.Asset)
+				if err != nil {
+					return feedMessages, fmt.Errorf("protojson.Marshal %s %v", temporalAsset.Asset.Name, err)
+				}
+				if err = json.Unmarshal(assetJSON, &feedMessage.Asset); err != nil {
+					return feedMessages, fmt.Errorf("json.Unmarshal %s %v", temporalAsset.Asset.Name, err)
+				}
+				feedMessage.Deleted = temporalAsset.Deleted
+				feedMessages = append(feedMessages, feedMessage)
+			}
+		}
+	}
+	return feedMessages, nil
+}
+
+// getFeedMessagesFromCache reads the assets last persisted in firestore by publish2fs
+// An ancestry prefix is matched on the deepest ancestor, the cache holding the CAI ancestors not the ancestry path
+func getFeedMessagesFromCache(parameters requestParameters, global *Global) (feedMessages []feedMessage, err error) {
+	var documentSnaps []*firestore.DocumentSnapshot
+	if parameters.assetName != "" {
+		documentSnap, err := global.firestoreClient.Doc(global.collectionID + "/" + str.RevertSlash(parameters.assetName)).Get(global.ctx)
+		if err != nil {
+			if erm.IsNotFound(err) {
+				return feedMessages, nil
+			}
+			return feedMessages, fmt.Errorf("global.firestoreClient.Doc.Get %v", err)
+		}
+		documentSnaps = append(documentSnaps, documentSnap)
+	} else {
+		_, scope, _ := cai.GetAncestryPathScope(parameters.ancestryPrefix)
+		documentIterator := global.firestoreClient.Collection(global.collectionID).Where("asset.ancestors", "array-contains", scope).Documents(global.ctx)
+		for {
+			documentSnap, err := documentIterator.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return feedMessages, fmt.Errorf("documentIterator.Next %v", err)
+			}
+			documentSnaps = append(documentSnaps, documentSnap)
+			if len(documentSnaps) > maxAssets {
+				return feedMessages, errTooManyAssets
+			}
+		}
+	}
+	for _, documentSnap := range documentSnaps {
+		var cachedFeedMessage cachedFeedMessage
+		if err = documentSnap.DataTo(&cachedFeedMessage); err != nil {
+			return feedMessages, fmt.Errorf("documentSnap.DataTo %s %v", documentSnap.Ref.ID, err)
+		}
+		if len(parameters.assetTypes) > 0 && !str.Find(parameters.assetTypes, cachedFeedMessage.Asset.AssetType) {
+			continue
+		}
+		var feedMessage feedMessage
+		feedMessage.Asset.Name = cachedFeedMessage.Asset.Name
+		feedMessage.Asset.AssetType = cachedFeedMessage.Asset.AssetType
+		feedMessage.Asset.Ancestors = cachedFeedMessage.Asset.Ancestors
+		if cachedFeedMessage.Asset.IamPolicy != nil {
+			if feedMessage.Asset.IamPolicy, err = json.Marshal(cachedFeedMessage.Asset.IamPolicy); err != nil {
+				return feedMessages, fmt.Errorf("json.Marshal iamPolicy %s %v", documentSnap.Ref.ID, err)
+			}
+		}
+		if cachedFeedMessage.Asset.Resource != nil {
+			if feedMessage.Asset.Resource, err = json.Marshal(cachedFeedMessage.Asset.Resource); err != nil {
+				return feedMessages, fmt.Errorf("json.Marshal resource %s %v", documentSnap.Ref.ID, err)
+			}
+		}
+		feedMessages = append(feedMessages, feedMessage)
+	}
+	return feedMessages, nil
+}
+
+// publishFeedMessages publishes IAM policies to the IAM policies topic and resources to the cai-rces-<assetShortTypeName> topics, like splitdump
+func publishFeedMessages(feedMessages []feedMessage, global *Global) (err error) {
+	pubsubMessagesByTopic := make(map[string][]*pubsubpb.PubsubMessage)
+	for _, feedMessage := range feedMessages {
+		var topicName string
+		if feedMessage.Asset.IamPolicy != nil {
+			topicName = global.iamTopicName
+		} else {
+			topicName = "cai-rces-" + cai.GetAssetShortTypeName(feedMessage.Asset.AssetType)
+		}
+		feedMessageJSON, err := json.Marshal(feedMessage)
+		if err != nil {
+			return fmt.Errorf("json.Marshal(feedMessage) %s %v", feedMessage.Asset.Name, err)
+		}
+		var pubSubMessage pubsubpb.PubsubMessage
+		pubSubMessage.Data = feedMessageJSON
+		pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
+		pubsubMessagesByTopic[topicName] = append(pubsubMessagesByTopic[topicName], &pubSubMessage)
+	}
+	for topicName, pubsubMessages := range pubsubMessagesByTopic {
+		if err = gps.CreateTopic(global.ctx, global.pubsubPublisherClient, &global.topicList, topicName, global.projectID); err != nil {
+			return fmt.Errorf("gps.CreateTopic %s %v", topicName, err)
+		}
+		for i := 0; i < len(pubsubMessages); i = i + publishMaxMessages {
+			j := i + publishMaxMessages
+			if j > len(pubsubMessages) {
+				j = len(pubsubMessages)
+			}
+			var publishRequest pubsubpb.PublishRequest
+			publishRequest.Topic = fmt.Sprintf("projects/%s/topics/%s", global.projectID, topicName)
+			publishRequest.Messages = pubsubMessages[i:j]
+			if _, err = global.pubsubPublisherClient.Publish(global.ctx, &publishRequest); err != nil {
+				return fmt.Errorf("global.pubsubPublisherClient.Publish %s %v", topicName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// waitComplianceStatuses polls the compliance status table until each asset has at least one status, or until waitSeconds
+// Assets not targeted by any rule never get a status, so the wait may end incomplete
+func waitComplianceStatuses(assetNames []string, startTime time.Time, waitSeconds int64, global *Global) (statusRows []statusRow, evaluatedAssetCount int, err error) {
+	deadline := startTime.Add(time.Duration(waitSeconds) * time.Second)
+	q := global.bigQueryClient.Query(fmt.Sprintf(statusQuery, fmt.Sprintf("`%s.%s.%s`", global.projectID, global.datasetName, complianceStatusTable)))
+	q.Parameters = []bigquery.QueryParameter{
+		{Name: "origin", Value: origin},
+		{Name: "startTime", Value: startTime},
+		{Name: "assetNames", Value: assetNames},
+	}
+	for {
+		time.Sleep(pollIntervalSeconds * time.Second)
+		rowIterator, err := q.Read(global.ctx)
+		if err != nil {
+			return statusRows, evaluatedAssetCount, fmt.Errorf("q.Read %v", err)
+		}
+		statusRows = []statusRow{}
+		var evaluatedAssetNames []string
+		for {
+			var row statusRow
+			err = rowIterator.Next(&row)
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return statusRows, evaluatedAssetCount, fmt.Errorf("rowIterator.Next %v", err)
+			}
+			statusRows = append(statusRows, row)
+			if !str.Find(evaluatedAssetNames, row.AssetName) {
+				evaluatedAssetNames = append(evaluatedAssetNames, row.AssetName)
+			}
+		}
+		evaluatedAssetCount = len(evaluatedAssetNames)
+		if evaluatedAssetCount == len(assetNames) || time.Now().Add(pollIntervalSeconds*time.Second).After(deadline) {
+			return statusRows, evaluatedAssetCount, nil
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package reevaluate re-evaluates on demand the compliance of an asset, or of all the assets under an ancestry path, and answers the resulting compliance status
+
+When an owner fixes a resource the real-time feed may not cover its asset type, and the next batch export may be a day away.
+
+Triggered by
+
+HTTP POST requests, authenticated with a Google identity token: the cloud function does not allow unauthenticated invocations.
+Callers are the members listed in instance.yaml iam invokers, granted the cloud functions invoker role on the function.
+
+	curl -X POST -H "Authorization: Bearer $(gcloud auth print-identity-token)" \
+	  "https://<region>-<projectID>.cloudfunctions.net/reevaluate_single_instance?assetName=//storage.googleapis.com/<bucketName>"
+
+Parameters
+
+- assetName: full resource name of one asset, looked up in each monitored organization.
+
+- ancestryPrefix: ancestry path, e.g. organization/123/folder/456/project/789, all the resources under the deepest ancestor are re-evaluated. Either assetName or ancestryPrefix.
+
+- assetTypes: comma separated asset types, ancestryPrefix only.
+
+- source: cai, default, reads the current state with the Cloud Asset Inventory BatchGetAssetsHistory API, an ancestry prefix being expanded with SearchAllResources. cache reads the assets last persisted in firestore by publish2fs.
+
+- waitSeconds: from 0 to 90, default 30, how long to wait for the compliance status.
+
+Instances
+
+One single instance.
+
+Cardinality
+
+One-many, one HTTP request - one feed message per asset resource and IAM policy, up to 1000 assets.
+
+Automatic retrying
+
+Not applicable, HTTP callers retry on 5xx status codes.
+
+Output
+
+Feed messages with origin on-demand published to the cai-rces-<assetShortTypeName> topics for resources and to the IAM policies topic, like splitdump, so the monitor instances evaluate them.
+The window start time is the request time, including for assets read from the cache.
+
+JSON HTTP response: the compliance statuses of this re-evaluation, read from the bigquery complianceStatus table.
+200 when each asset got at least one status before waitSeconds, else 202 with the statuses received so far. Assets not targeted by any rule never get a status: use servecompliance to read the statuses later.
+
+Implementation example
+
+	package p
+
+	import (
+		"context"
+		"log"
+		"net/http"
+
+		"github.com/BrunoReboul/ram/services/reevaluate"
+	)
+
+	var global reevaluate.Global
+	var ctx = context.Background()
+
+	// EntryPoint is the function to be executed for each cloud function occurence
+	func EntryPoint(w http.ResponseWriter, r *http.Request) {
+		reevaluate.EntryPoint(w, r, &global)
+	}
+
+	func init() {
+		err := reevaluate.Initialize(ctx, &global)
+		if err != nil {
+			log.Fatalf("INIT_FAILURE %v", err)
+		}
+	}
+
+*/
+package reevaluate
@@ -0,0 +1,459 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servecompliance
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/google/uuid"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// Global structure for global variables to optimize the cloud function performances
+type Global struct {
+	bigQueryClient   *bigquery.Client
+	ctx              context.Context
+	datasetName      string
+	environment      string
+	instanceName     string
+	microserviceName string
+	projectID        string
+}
+
+// queryParameters filters and pagination of a request
+type queryParameters struct {
+	ancestryPrefix string
+	compliant      string
+	format         string
+	offset         int64
+	owner          string
+	pageSize       int64
+	ruleName       string
+	severity       string
+}
+
+// statusRow one row of the last_compliancestatus view
+type statusRow struct {
+	RuleName                string    `bigquery:"ruleName" json:"ruleName"`
+	RuleNameShort           string    `bigquery:"ruleNameShort" json:"ruleNameShort"`
+	ServiceName             string    `bigquery:"serviceName" json:"serviceName"`
+	Compliant               bool      `bigquery:"compliant" json:"compliant"`
+	AssetName               string    `bigquery:"assetName" json:"assetName"`
+	AssetType               string    `bigquery:"assetType" json:"assetType"`
+	AssetInventoryTimeStamp time.Time `bigquery:"assetInventoryTimeStamp" json:"assetInventoryTimeStamp"`
+	Owner                   string    `bigquery:"owner" json:"owner"`
+	ViolationResolver       string    `bigquery:"violationResolver" json:"violationResolver"`
+	AncestryPath            string    `bigquery:"ancestryPath" json:"ancestryPath"`
+	AncestryPathDisplayName string    `bigquery:"ancestryPathDisplayName" json:"ancestryPathDisplayName"`
+}
+
+// violationRow one row of the active_violations view
+type violationRow struct {
+	RuleName                string    `bigquery:"ruleName" json:"ruleName"`
+	RuleNameShort           string    `bigquery:"ruleNameShort" json:"ruleNameShort"`
+	ServiceName             string    `bigquery:"serviceName" json:"serviceName"`
+	Severity                string    `bigquery:"severity" json:"severity"`
+	AssetName               string    `bigquery:"assetName" json:"assetName"`
+	AssetType               string    `bigquery:"assetType" json:"assetType"`
+	AssetInventoryTimeStamp time.Time `bigquery:"assetInventoryTimeStamp" json:"assetInventoryTimeStamp"`
+	Owner                   string    `bigquery:"owner" json:"owner"`
+	ViolationResolver       string    `bigquery:"violationResolver" json:"violationResolver"`
+	AncestryPath            string    `bigquery:"ancestryPath" json:"ancestryPath"`
+	AncestryPathDisplayName string    `bigquery:"ancestryPathDisplayName" json:"ancestryPathDisplayName"`
+	Message                 string    `bigquery:"message" json:"message"`
+	Metadata                string    `bigquery:"metadata" json:"metadata"`
+}
+
+// page JSON response body
+type page struct {
+	Items         interface{} `json:"items"`
+	NextPageToken string      `json:"nextPageToken,omitempty"`
+}
+
+// Columns selected from each view, NULL values are replaced by zero values to fit the row structs
+const statusColumns = `
+    ruleName,
+    IFNULL(ruleNameShort, "") AS ruleNameShort,
+    IFNULL(serviceName, "") AS serviceName,
+    compliant,
+    assetName,
+    IFNULL(assetType, "") AS assetType,
+    assetInventoryTimeStamp,
+    IFNULL(owner, "") AS owner,
+    IFNULL(violationResolver, "") AS violationResolver,
+    IFNULL(ancestryPath, "") AS ancestryPath,
+    IFNULL(ancestryPathDisplayName, "") AS ancestryPathDisplayName`
+
+const violationColumns = `
+    functionConfig.functionName AS ruleName,
+    IFNULL(ruleNameShort, "") AS ruleNameShort,
+    IFNULL(serviceName, "") AS serviceName,
+    IFNULL(constraintConfig.spec.severity, "") AS severity,
+    feedMessage.asset.name AS assetName,
+    IFNULL(feedMessage.asset.assetType, "") AS assetType,
+    feedMessage.window.startTime AS assetInventoryTimeStamp,
+    IFNULL(feedMessage.asset.owner, "") AS owner,
+    IFNULL(feedMessage.asset.violationResolver, "") AS violationResolver,
+    IFNULL(feedMessage.asset.ancestryPath, "") AS ancestryPath,
+    IFNULL(feedMessage.asset.ancestryPathDisplayName, "") AS ancestryPathDisplayName,
+    nonCompliance.message AS message,
+    IFNULL(nonCompliance.metadata, "") AS metadata`
+
+// Initialize is to be executed in the init() function of the cloud function to optimize the cold start
+func Initialize(ctx context.Context, global *Global) (err error) {
+	log.SetFlags(0)
+	global.ctx = ctx
+
+	var instanceDeployment InstanceDeployment
+
+	initID := fmt.Sprintf("%v", uuid.New())
+	err = ffo.ReadUnmarshalYAML(solution.PathToFunctionCode+solution.SettingsFileName, &instanceDeployment)
+	if err != nil {
+		log.Println(logging.Entry{
+			Severity:    "CRITICAL",
+			Message:     "init_failed",
+			Description: fmt.Sprintf("ReadUnmarshalYAML %s %v", solution.SettingsFileName, err),
+			InitID:      initID,
+		})
+		return err
+	}
+
+	global.environment = instanceDeployment.Core.EnvironmentName
+	global.instanceName = instanceDeployment.Core.InstanceName
+	global.microserviceName = instanceDeployment.Core.ServiceName
+
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "coldstart",
+		InitID:           initID,
+	})
+
+	global.datasetName = instanceDeployment.Core.SolutionSettings.Hosting.Bigquery.Dataset.Name
+	global.projectID = instanceDeployment.Core.SolutionSettings.Hosting.ProjectID
+
+	global.bigQueryClient, err = bigquery.NewClient(global.ctx, global.projectID)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("bigquery.NewClient %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	return nil
+}
+
+// EntryPoint is the function to be executed for each cloud function occurence
+func EntryPoint(w http.ResponseWriter, r *http.Request, global *Global) {
+	start := time.Now()
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          "start",
+		Description:      fmt.Sprintf("%s %s", r.Method, r.URL.RequestURI()),
+		Now:              &start,
+	})
+
+	if r.Method != http.MethodGet {
+		finish(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method), start, global)
+		return
+	}
+	resource := path.Base(r.URL.Path)
+	if resource != "status" && resource != "violations" {
+		finish(w, http.StatusNotFound, fmt.Sprintf("resource %s not found, use status or violations", r.URL.Path), start, global)
+		return
+	}
+	parameters, err := getQueryParameters(r.URL.Query(), r.Header.Get("Accept"))
+	if err != nil {
+		finish(w, http.StatusBadRequest, err.Error(), start, global)
+		return
+	}
+	var query string
+	var queryParams []bigquery.QueryParameter
+	switch resource {
+	case "status":
+		if parameters.severity != "" {
+			finish(w, http.StatusBadRequest, "severity filter applies only to violations", start, global)
+			return
+		}
+		query, queryParams = buildQuery(fmt.Sprintf("`%s.%s.last_compliancestatus`", global.projectID, global.datasetName),
+			statusColumns, map[string]string{
+				"ancestryPath": "ancestryPath",
+				"owner":        "owner",
+				"ruleName":     "ruleName",
+			}, parameters)
+	case "violations":
+		if parameters.compliant != "" {
+			finish(w, http.StatusBadRequest, "compliant filter applies only to status", start, global)
+			return
+		}
+		query, queryParams = buildQuery(fmt.Sprintf("`%s.%s.active_violations`", global.projectID, global.datasetName),
+			violationColumns, map[string]string{
+				"ancestryPath": "feedMessage.asset.ancestryPath",
+				"owner":        "feedMessage.asset.owner",
+				"ruleName":     "functionConfig.functionName",
+			}, parameters)
+	}
+
+	q := global.bigQueryClient.Query(query)
+	q.Parameters = queryParams
+	rowIterator, err := q.Read(global.ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "noretry",
+			Description:      fmt.Sprintf("q.Read %v", err),
+		})
+		finish(w, http.StatusInternalServerError, "query failed", start, global)
+		return
+	}
+
+	var records [][]string
+	var statusRows []statusRow
+	var violationRows []violationRow
+	for {
+		if resource == "status" {
+			var row statusRow
+			err = rowIterator.Next(&row)
+			if err == nil {
+				statusRows = append(statusRows, row)
+				records = append(records, row.csvRecord())
+			}
+		} else {
+			var row violationRow
+			err = rowIterator.Next(&row)
+			if err == nil {
+				violationRows = append(violationRows, row)
+				records = append(records, row.csvRecord())
+			}
+		}
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			log.Println(logging.Entry{
+				MicroserviceName: global.microserviceName,
+				InstanceName:     global.instanceName,
+				Environment:      global.environment,
+				Severity:         "CRITICAL",
+				Message:          "noretry",
+				Description:      fmt.Sprintf("rowIterator.Next %v", err),
+			})
+			finish(w, http.StatusInternalServerError, "reading query results failed", start, global)
+			return
+		}
+	}
+
+	// One extra row is queried to know if there is a next page
+	var nextPageToken string
+	if int64(len(records)) > parameters.pageSize {
+		nextPageToken = strconv.FormatInt(parameters.offset+parameters.pageSize, 10)
+		records = records[:parameters.pageSize]
+		if resource == "status" {
+			statusRows = statusRows[:parameters.pageSize]
+		} else {
+			violationRows = violationRows[:parameters.pageSize]
+		}
+	}
+
+	switch parameters.format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		if nextPageToken != "" {
+			w.Header().Set("X-Next-Page-Token", nextPageToken)
+		}
+		csvWriter := csv.NewWriter(w)
+		if resource == "status" {
+			csvWriter.Write(statusRow{}.csvHeader())
+		} else {
+			csvWriter.Write(violationRow{}.csvHeader())
+		}
+		csvWriter.WriteAll(records)
+	default:
+		var body page
+		body.NextPageToken = nextPageToken
+		if resource == "status" {
+			if statusRows == nil {
+				statusRows = []statusRow{}
+			}
+			body.Items = statusRows
+		} else {
+			if violationRows == nil {
+				violationRows = []violationRow{}
+			}
+			body.Items = violationRows
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}
+
+	now := time.Now()
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "NOTICE",
+		Message:          fmt.Sprintf("finish %s", resource),
+		Description:      fmt.Sprintf("%d rows format %s", len(records), parameters.format),
+		Now:              &now,
+		LatencySeconds:   now.Sub(start).Seconds(),
+	})
+}
+
+// finish answers an error status code with a plain text message
+func finish(w http.ResponseWriter, statusCode int, message string, start time.Time, global *Global) {
+	http.Error(w, message, statusCode)
+	now := time.Now()
+	log.Println(logging.Entry{
+		MicroserviceName: global.microserviceName,
+		InstanceName:     global.instanceName,
+		Environment:      global.environment,
+		Severity:         "WARNING",
+		Message:          fmt.Sprintf("finish %d", statusCode),
+		Description:      message,
+		Now:              &now,
+		LatencySeconds:   now.Sub(start).Seconds(),
+	})
+}
+
+// getQueryParameters validates filters and pagination from the URL query, the format defaults from the Accept header
+func getQueryParameters(values url.Values, accept string) (parameters queryParameters, err error) {
+	parameters.ancestryPrefix = values.Get("ancestryPrefix")
+	parameters.owner = values.Get("owner")
+	parameters.ruleName = values.Get("ruleName")
+	parameters.severity = values.Get("severity")
+
+	parameters.compliant = values.Get("compliant")
+	if parameters.compliant != "" {
+		if _, err = strconv.ParseBool(parameters.compliant); err != nil {
+			return parameters, fmt.Errorf("compliant must be true or false, got %s", parameters.compliant)
+		}
+	}
+
+	parameters.format = values.Get("format")
+	if parameters.format == "" {
+		if strings.Contains(accept, "text/csv") {
+			parameters.format = "csv"
+		} else {
+			parameters.format = "json"
+		}
+	}
+	if parameters.format != "json" && parameters.format != "csv" {
+		return parameters, fmt.Errorf("format must be json or csv, got %s", parameters.format)
+	}
+
+	parameters.pageSize = defaultPageSize
+	if pageSize := values.Get("pageSize"); pageSize != "" {
+		parameters.pageSize, err = strconv.ParseInt(pageSize, 10, 64)
+		if err != nil || parameters.pageSize < 1 || parameters.pageSize > maxPageSize {
+			return parameters, fmt.Errorf("pageSize must be an integer from 1 to %d, got %s", maxPageSize, pageSize)
+		}
+	}
+	if pageToken := values.Get("pageToken"); pageToken != "" {
+		parameters.offset, err = strconv.ParseInt(pageToken, 10, 64)
+		if err != nil || parameters.offset < 0 {
+			return parameters, fmt.Errorf("invalid pageToken %s", pageToken)
+		}
+	}
+	return parameters, nil
+}
+
+// buildQuery select the page of rows matching the filters, all filter values are passed as query parameters
+// filterColumns maps the filter names to the view columns, as select aliases cannot be used in the where clause
+func buildQuery(viewName string, columns string, filterColumns map[string]string, parameters queryParameters) (query string, queryParams []bigquery.QueryParameter) {
+	var conditions []string
+	if parameters.ancestryPrefix != "" {
+		conditions = append(conditions, fmt.Sprintf("STARTS_WITH(%s, @ancestryPrefix)", filterColumns["ancestryPath"]))
+		queryParams = append(queryParams, bigquery.QueryParameter{Name: "ancestryPrefix", Value: parameters.ancestryPrefix})
+	}
+	if parameters.compliant != "" {
+		compliant, _ := strconv.ParseBool(parameters.compliant)
+		conditions = append(conditions, "compliant = @compliant")
+		queryParams = append(queryParams, bigquery.QueryParameter{Name: "compliant", Value: compliant})
+	}
+	if parameters.owner != "" {
+		conditions = append(conditions, fmt.Sprintf("%s = @owner", filterColumns["owner"]))
+		queryParams = append(queryParams, bigquery.QueryParameter{Name: "owner", Value: parameters.owner})
+	}
+	if parameters.ruleName != "" {
+		conditions = append(conditions, fmt.Sprintf("(%s = @ruleName OR ruleNameShort = @ruleName)", filterColumns["ruleName"]))
+		queryParams = append(queryParams, bigquery.QueryParameter{Name: "ruleName", Value: parameters.ruleName})
+	}
+	if parameters.severity != "" {
+		conditions = append(conditions, "constraintConfig.spec.severity = @severity")
+		queryParams = append(queryParams, bigquery.QueryParameter{Name: "severity", Value: parameters.severity})
+	}
+	query = fmt.Sprintf("SELECT%s\nFROM\n    %s", columns, viewName)
+	if len(conditions) > 0 {
+		query = fmt.Sprintf("%s\nWHERE\n    %s", query, strings.Join(conditions, "\n    AND "))
+	}
+	query = fmt.Sprintf("%s\nORDER BY\n    ruleName,\n    assetName\nLIMIT @limit OFFSET @offset", query)
+	queryParams = append(queryParams,
+		bigquery.QueryParameter{Name: "limit", Value: parameters.pageSize + 1},
+		bigquery.QueryParameter{Name: "offset", Value: parameters.offset})
+	return query, queryParams
+}
+
+func (row statusRow) csvHeader() []string {
+	return []string{"ruleName", "ruleNameShort", "serviceName", "compliant", "assetName", "assetType", "assetInventoryTimeStamp",
+		"owner", "violationResolver", "ancestryPath", "ancestryPathDisplayName"}
+}
+
+func (row statusRow) csvRecord() []string {
+	return []string{row.RuleName, row.RuleNameShort, row.ServiceName, strconv.FormatBool(row.Compliant), row.AssetName, row.AssetType,
+		row.AssetInventoryTimeStamp.Format(time.RFC3339Nano), row.Owner, row.ViolationResolver, row.AncestryPath, row.AncestryPathDisplayName}
+}
+
+func (row violationRow) csvHeader() []string {
+	return []string{"ruleName", "ruleNameShort", "serviceName", "severity", "assetName", "assetType", "assetInventoryTimeStamp",
+		"owner", "violationResolver", "ancestryPath", "ancestryPathDisplayName", "message", "metadata"}
+}
+
+func (row violationRow) csvRecord() []string {
+	return []string{row.RuleName, row.RuleNameShort, row.ServiceName, row.Severity, row.AssetName, row.AssetType,
+		row.AssetInventoryTimeStamp.Format(time.RFC3339Nano), row.Owner, row.ViolationResolver, row.AncestryPath, row.AncestryPathDisplayName,
+		row.Message, row.Metadata}
+}
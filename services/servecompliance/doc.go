@@ -0,0 +1,100 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package servecompliance serves the current compliance status and the active violations as JSON or CSV over HTTP
+
+Triggered by
+
+HTTP GET requests, authenticated with a Google identity token: the cloud function does not allow unauthenticated invocations.
+Callers are the members listed in instance.yaml iam invokers, e.g. serviceAccount:ci@<projectID>.iam.gserviceaccount.com, granted the cloud functions invoker role on the function.
+
+	curl -H "Authorization: Bearer $(gcloud auth print-identity-token)" \
+	  "https://<region>-<projectID>.cloudfunctions.net/servecompliance_single_instance/violations?owner=<owner>"
+
+Resources
+
+- /status: one row per asset and rule from the last_compliancestatus view.
+
+- /violations: one row per violation from the active_violations view.
+
+Filters
+
+- ancestryPrefix: ancestry path starts with, e.g. organization/123/folder/456/project/789.
+
+- ruleName: full or short rule name.
+
+- owner: asset owner label value.
+
+- severity: constraint severity, violations only.
+
+- compliant: true or false, status only. "Is project X compliant?" is answered by an empty list of items for /status?compliant=false&ancestryPrefix=<project ancestry path>.
+
+Pagination and format
+
+- pageSize: from 1 to 1000, default 100.
+
+- pageToken: nextPageToken of the previous page, in the JSON body or in the X-Next-Page-Token header for CSV.
+
+- format: json or csv, default csv when the Accept header contains text/csv, else json.
+
+Implementation
+
+Filter values are passed as bigquery query parameters. One more row than pageSize is queried to know if there is a next page.
+
+Instances
+
+One single instance.
+
+Cardinality
+
+One-one, one HTTP request - one bigquery query.
+
+Automatic retrying
+
+Not applicable, HTTP callers retry on 5xx status codes.
+
+Output
+
+JSON or CSV HTTP response. The cloud function service account creates bigquery jobs and reads the dataset tables and views.
+
+Implementation example
+
+	package p
+
+	import (
+		"context"
+		"log"
+		"net/http"
+
+		"github.com/BrunoReboul/ram/services/servecompliance"
+	)
+
+	var global servecompliance.Global
+	var ctx = context.Background()
+
+	// EntryPoint is the function to be executed for each cloud function occurence
+	func EntryPoint(w http.ResponseWriter, r *http.Request) {
+		servecompliance.EntryPoint(w, r, &global)
+	}
+
+	func init() {
+		err := servecompliance.Initialize(ctx, &global)
+		if err != nil {
+			log.Fatalf("INIT_FAILURE %v", err)
+		}
+	}
+
+*/
+package servecompliance
@@ -0,0 +1,32 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servecompliance
+
+import (
+	"fmt"
+
+	"github.com/BrunoReboul/ram/utilities/iamgt"
+)
+
+func (instanceDeployment *InstanceDeployment) deployIAMInvokerBindings() (err error) {
+	invokerBindingsDeployment := iamgt.NewInvokerBindingsDeployment()
+	invokerBindingsDeployment.Core = instanceDeployment.Core
+	invokerBindingsDeployment.Settings.Instance.IAM = instanceDeployment.Settings.Instance.IAM
+	invokerBindingsDeployment.Artifacts.FunctionName = fmt.Sprintf("projects/%s/locations/%s/functions/%s",
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCF.Region,
+		instanceDeployment.Core.InstanceName)
+	return invokerBindingsDeployment.Deploy()
+}
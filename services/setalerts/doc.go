@@ -0,0 +1,57 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package setalerts set log-based metrics and cloud monitoring alert policies for a RAM microservice
+
+Instances
+
+Multiple: one per RAM microservice.
+
+Log-based metrics
+
+Created in the hosting project, where the microservices log, named ram_<microserviceName>_<metric>:
+
+- starts: count of triggered executions.
+
+- errors: count of failed executions, labeled by log message (noretry, redo_on_transient, dropped, dead_letter_failed).
+
+- init_failures: count of failed initializations.
+
+- latency_seconds: distribution of the execution latency.
+
+- latency_e2e_seconds: distribution of the end to end latency from the origin event.
+
+- violations: monitor only, count of violations, labeled by severity.
+
+All metrics are labeled by instance_name.
+
+Alert policies
+
+Created in the stackdriver project, thresholds set in solution.yaml hosting.stackdriver.alerting:
+
+- error rate: errors over starts, default 5 percent.
+
+- end to end latency 95th percentile, default 600 seconds.
+
+- init failures: more than initFailures, default 0.
+
+Notification channels are the ones listed in notificationChannels, in the form projects/<projectID>/notificationChannels/<channelID>.
+
+Output
+
+Log-based metrics and alert policies configured.
+
+*/
+package setalerts
@@ -19,6 +19,38 @@ Instances
 
 Multiple: one per dashboard.
 
+Widgets
+
+widgetTypeList applies the legacy cloud function widget types to each microservice of microServiceNameList.
+
+widgets lists additional widgets, each one either a kind from the catalogue or an inline JSON template, rendered with variables referenced as {{.variableName}}:
+
+- widgetGCFActiveInstances, widgetGCFExecutionCount, widgetGCFExecutionTime, widgetGCFMemoryUsage: variable microserviceName.
+
+- widgetPubSubBacklog, widgetPubSubOldestUnackedAge: variable topicName, matched in the subscription IDs.
+
+- widgetBigQueryStreamingInsertErrors: no variable.
+
+- widgetFirestoreOps: no variable, reads, writes and deletes.
+
+- widgetLogMetricE2ELatency: variable microserviceName, log-based metric set by setalerts.
+
+- widgetLogMetricViolations: no variable, monitor violations per severity log-based metric set by setalerts.
+
+- widgetScorecard: variables title, filter, crossSeriesReducer, perSeriesAligner.
+
+- widgetText: variables title, content in markdown.
+
+Variable values are JSON string escaped, so inline templates insert them within JSON strings, e.g.
+
+	widgets:
+	- kind: widgetPubSubBacklog
+	  variables:
+	    topicName: ram-violations
+	- template: '{"title": "{{.title}}", "blank": {}}'
+	  variables:
+	    title: spacer
+
 Output
 
 Cloud Monitoring dashboard configured.
@@ -31,5 +31,12 @@ func (instanceDeployment *InstanceDeployment) Situate() (err error) {
 			instanceDeployment.Artifacts.Widgets = append(instanceDeployment.Artifacts.Widgets, &widget)
 		}
 	}
+	for _, widgetParameters := range instanceDeployment.Settings.Instance.MON.Widgets {
+		widget, err := mon.GetWidget(widgetParameters)
+		if err != nil {
+			return err
+		}
+		instanceDeployment.Artifacts.Widgets = append(instanceDeployment.Artifacts.Widgets, &widget)
+	}
 	return nil
 }
@@ -27,11 +27,14 @@ import (
 	"time"
 
 	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gcs"
 	"github.com/BrunoReboul/ram/utilities/gfs"
 	"github.com/BrunoReboul/ram/utilities/logging"
 	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/trc"
 	"github.com/google/uuid"
 
 	"cloud.google.com/go/firestore"
@@ -45,6 +48,7 @@ import (
 // Global structure for global variables to optimize the cloud function performances
 type Global struct {
 	ctx                        context.Context
+	deadLetter                 dlq.Sender
 	environment                string
 	firestoreClient            *firestore.Client
 	iamTopicName               string
@@ -55,10 +59,13 @@ type Global struct {
 	pubsubPublisherClient      *pubsub.PublisherClient
 	retryTimeOutSeconds        int64
 	scannerBufferSizeKiloBytes int
+	spanCtx                    context.Context
 	splitThresholdLineNumber   int64
 	step                       logging.Step
 	stepStack                  logging.Steps
 	storageBucket              *storage.BucketHandle
+	trace                      string
+	tracer                     trc.Tracer
 }
 
 // asset uses the new CAI feed format
@@ -171,6 +178,40 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 		})
 		return err
 	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.tracer, err = trc.NewTracer(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		global.microserviceName,
+		instanceDeployment.Settings.Service.Trace)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("trc.NewTracer %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
 	return nil
 }
 
@@ -191,7 +232,10 @@ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) er
 		return err
 	}
 	global.stepStack = nil
+	global.trace = ""
 	global.PubSubID = metadata.EventID
+	gcsEventJSON, _ := json.Marshal(gcsEvent)
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, gcsEventJSON, nil)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -213,7 +257,7 @@ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) er
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
@@ -359,6 +403,11 @@ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) er
 	global.stepStack = append(global.stepStack, gcsStep)
 	global.stepStack = append(global.stepStack, global.step)
 
+	spanCtx, span := global.tracer.StartSpan(global.ctx, global.instanceName, nil, global.stepStack)
+	defer global.tracer.EndSpan(span)
+	global.spanCtx = spanCtx
+	global.trace = global.tracer.GetLogTrace(spanCtx)
+
 	startTime = gcsEvent.Updated
 	dumpLineNumber = 0
 	scanner := bufio.NewScanner(teeStorageObjectReader)
@@ -379,7 +428,7 @@ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) er
 			childDumpNumber,
 			global)
 		if err != nil {
-			log.Println(logging.Entry{
+			global.deadLetter.NoRetry(logging.Entry{
 				MicroserviceName:   global.microserviceName,
 				InstanceName:       global.instanceName,
 				Environment:        global.environment,
@@ -387,6 +436,7 @@ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) er
 				Message:            "noretry",
 				Description:        fmt.Sprintf("splitToChildDumps %v", err),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 			return nil
 		}
@@ -403,6 +453,7 @@ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) er
 			Description:          fmt.Sprintf("dumpLineNumber %d gcsEvent.Generation %s duration %v", dumpLineNumber, gcsEvent.Generation, duration),
 			Now:                  &now,
 			TriggeringPubsubID:   global.PubSubID,
+			Trace:                global.trace,
 			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
 			LatencySeconds:       latency.Seconds(),
 			LatencyE2ESeconds:    latencyE2E.Seconds(),
@@ -422,6 +473,7 @@ func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event, global *Global) er
 			Description:          fmt.Sprintf("pubSubMsgNumber %d gcsEvent.Generation %v duration %v", pubSubMsgNumber, gcsEvent.Generation, duration),
 			Now:                  &now,
 			TriggeringPubsubID:   global.PubSubID,
+			Trace:                global.trace,
 			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
 			LatencySeconds:       latency.Seconds(),
 			LatencyE2ESeconds:    latencyE2E.Seconds(),
@@ -471,6 +523,7 @@ func splitToChildDumps(buffer bytes.Buffer, parentDumpName string, parentGenerat
 						Message:            "fmt.Fprint(storageObjectWriter, childDumpContent)",
 						Description:        fmt.Sprintf("iteration %d err %v", i, err),
 						TriggeringPubsubID: global.PubSubID,
+						Trace:              global.trace,
 					})
 					time.Sleep(i * 100 * time.Millisecond)
 				} else {
@@ -494,6 +547,7 @@ func splitToChildDumps(buffer bytes.Buffer, parentDumpName string, parentGenerat
 						Message:            fmt.Sprintf("storageObjectWriter.Close() %s", childDumpName),
 						Description:        fmt.Sprintf("iteration %d dumpLineNumber %d childDumpLineNumber %d err %v", i, dumpLineNumber, childDumpLineNumber, err),
 						TriggeringPubsubID: global.PubSubID,
+						Trace:              global.trace,
 					})
 					time.Sleep(i * 100 * time.Millisecond)
 				} else {
@@ -521,6 +575,7 @@ func splitToChildDumps(buffer bytes.Buffer, parentDumpName string, parentGenerat
 					Severity:           "WARNING",
 					Message:            fmt.Sprintf("recordDump %v", err),
 					TriggeringPubsubID: global.PubSubID,
+					Trace:              global.trace,
 				})
 			}
 
@@ -546,6 +601,7 @@ func splitToChildDumps(buffer bytes.Buffer, parentDumpName string, parentGenerat
 				Message:            "fmt.Fprint(storageObjectWriter, childDumpContent)",
 				Description:        fmt.Sprintf("iteration %d err %v", i, err),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 			time.Sleep(i * 100 * time.Millisecond)
 		} else {
@@ -569,6 +625,7 @@ func splitToChildDumps(buffer bytes.Buffer, parentDumpName string, parentGenerat
 				Message:            fmt.Sprintf("storageObjectWriter.Close() %s", childDumpName),
 				Description:        fmt.Sprintf("iteration %d dumpLineNumber %d childDumpLineNumber %d err %v", i, dumpLineNumber, childDumpLineNumber, err),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 			time.Sleep(i * 100 * time.Millisecond)
 		} else {
@@ -596,6 +653,7 @@ func splitToChildDumps(buffer bytes.Buffer, parentDumpName string, parentGenerat
 			Severity:           "WARNING",
 			Message:            fmt.Sprintf("recordDump %v", err),
 			TriggeringPubsubID: global.PubSubID,
+			Trace:              global.trace,
 		})
 	}
 
@@ -633,6 +691,7 @@ func processDumpLine(dumpline string, global *Global, pointerTopubSubMsgNumber *
 			Message:            "json.Unmarshal([]byte(dumpline), &assetLegacy)",
 			Description:        fmt.Sprintf("err %v dumpline %s", err, dumpline),
 			TriggeringPubsubID: global.PubSubID,
+			Trace:              global.trace,
 		})
 	} else {
 		asset := transposeAsset(assetLegacy)
@@ -645,6 +704,7 @@ func processDumpLine(dumpline string, global *Global, pointerTopubSubMsgNumber *
 				Message:            "ignored dump line: no IamPolicy object nor Resource object",
 				Description:        fmt.Sprintf("dumpline %s", dumpline),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 		} else {
 			if asset.IamPolicy != nil {
@@ -662,11 +722,12 @@ func processDumpLine(dumpline string, global *Global, pointerTopubSubMsgNumber *
 					Message:            fmt.Sprintf("ignored dump line: no topic to publish %s", topicName),
 					Description:        fmt.Sprintf("err %v dumpline %s", err, dumpline),
 					TriggeringPubsubID: global.PubSubID,
+					Trace:              global.trace,
 				})
 			} else {
 				feedMessageJSON, err := json.Marshal(getFeedMessage(asset, startTime, global))
 				if err != nil {
-					log.Println(logging.Entry{
+					global.deadLetter.NoRetry(logging.Entry{
 						MicroserviceName:   global.microserviceName,
 						InstanceName:       global.instanceName,
 						Environment:        global.environment,
@@ -674,11 +735,14 @@ func processDumpLine(dumpline string, global *Global, pointerTopubSubMsgNumber *
 						Message:            "noretry",
 						Description:        fmt.Sprintf("json.Marshal(getFeedMessage(asset, startTime)) %v", err),
 						TriggeringPubsubID: global.PubSubID,
+						Trace:              global.trace,
 					})
 					return err
 				}
 				var pubSubMessage pubsubpb.PubsubMessage
 				pubSubMessage.Data = feedMessageJSON
+				pubSubMessage.Attributes = gps.GetFeedMessageAttributes(feedMessageJSON)
+				trc.Inject(global.spanCtx, pubSubMessage.Attributes)
 
 				var pubsubMessages []*pubsubpb.PubsubMessage
 				pubsubMessages = append(pubsubMessages, &pubSubMessage)
@@ -696,6 +760,7 @@ func processDumpLine(dumpline string, global *Global, pointerTopubSubMsgNumber *
 						Severity:           "WARNING",
 						Message:            fmt.Sprintf("dump line not publihed to pubsub topic %v", err),
 						TriggeringPubsubID: global.PubSubID,
+						Trace:              global.trace,
 					})
 				}
 				// log.Println(logging.Entry{
@@ -742,7 +807,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 	for i = 0; i < retriesNumber; i++ {
 		documentSnap, err = global.firestoreClient.Doc(documentPath).Get(global.ctx)
 		if err != nil {
-			if strings.Contains(strings.ToLower(strings.Replace(err.Error(), " ", "", -1)), "notfound") {
+			if erm.IsNotFound(err) {
 				log.Println(logging.Entry{
 					MicroserviceName:   global.microserviceName,
 					InstanceName:       global.instanceName,
@@ -751,6 +816,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 					Message:            "recordDump dump document does not exist",
 					Description:        fmt.Sprintf("global.firestoreClient.Doc(documentPath).Get %s %v", documentPath, err),
 					TriggeringPubsubID: global.PubSubID,
+					Trace:              global.trace,
 				})
 				return nil
 			}
@@ -761,6 +827,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 				Severity:           "WARNING",
 				Message:            fmt.Sprintf("iteration %d global.firestoreClient.Doc(documentPath).Get %s %v", i, documentPath, err),
 				TriggeringPubsubID: global.PubSubID,
+				Trace:              global.trace,
 			})
 			time.Sleep(i * 100 * time.Millisecond)
 		} else {
@@ -773,6 +840,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 					Severity:           "WARNING",
 					Message:            fmt.Sprintf("iteration %d stepStack, err := documentSnap.DataAt %s %v", i, documentPath, err),
 					TriggeringPubsubID: global.PubSubID,
+					Trace:              global.trace,
 				})
 				time.Sleep(i * 100 * time.Millisecond)
 			} else {
@@ -785,6 +853,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 						Severity:           "WARNING",
 						Message:            fmt.Sprintf("rawStepStackInterface unexected type is %T", rawStepStackInterface),
 						TriggeringPubsubID: global.PubSubID,
+						Trace:              global.trace,
 					})
 					return nil
 				}
@@ -800,6 +869,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 							Severity:           "WARNING",
 							Message:            fmt.Sprintf("rawStepInterface unexected type is %T", rawStepInterface),
 							TriggeringPubsubID: global.PubSubID,
+							Trace:              global.trace,
 						})
 						return nil
 					}
@@ -813,6 +883,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 							Severity:           "WARNING",
 							Message:            fmt.Sprintf("stepIDInterface unexected type is %T", stepIDInterface),
 							TriggeringPubsubID: global.PubSubID,
+							Trace:              global.trace,
 						})
 						return nil
 					}
@@ -826,6 +897,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 							Severity:           "WARNING",
 							Message:            fmt.Sprintf("stepTimestampInterface unexected type is %T", stepTimestampInterface),
 							TriggeringPubsubID: global.PubSubID,
+							Trace:              global.trace,
 						})
 						return nil
 					}
@@ -841,6 +913,7 @@ func getDumpStepStack(objectName string, global *Global, retriesNumber time.Dura
 					Message:            fmt.Sprintf("dump stepStack retrieved %s", documentPath),
 					Description:        fmt.Sprintf("stepStack %v", stepStack),
 					TriggeringPubsubID: global.PubSubID,
+					Trace:              global.trace,
 				})
 				return stepStack
 			}
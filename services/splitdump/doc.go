@@ -55,6 +55,10 @@ Is recurssive
 
 Yes.
 
+Tracing
+
+Starts the trace of the dump, derived from the dump step stack, and sets the traceparent attribute on each published feed message. Set trace samplePercent in service.yaml, 0 disables the export to Cloud Trace.
+
 Implementation example
 
  package p
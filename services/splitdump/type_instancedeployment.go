@@ -18,10 +18,13 @@ import (
 	"time"
 
 	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/gcb"
 	"github.com/BrunoReboul/ram/utilities/gcf"
 	"github.com/BrunoReboul/ram/utilities/gsu"
 	"github.com/BrunoReboul/ram/utilities/iamgt"
+	"github.com/BrunoReboul/ram/utilities/run"
+	"github.com/BrunoReboul/ram/utilities/trc"
 	"google.golang.org/api/iam/v1"
 )
 
@@ -31,14 +34,17 @@ type InstanceDeployment struct {
 	Core          *deploy.Core
 	Settings      struct {
 		Service struct {
-			GSU gsu.Parameters
-			IAM iamgt.Parameters
-			GCB gcb.Parameters
-			GCF gcf.Parameters
+			GSU   gsu.Parameters
+			IAM   iamgt.Parameters
+			GCB   gcb.Parameters
+			GCF   gcf.Parameters
+			RUN   run.Parameters
+			Trace trc.Parameters
 		}
 		Instance struct {
-			SplitThresholdLineNumber   int64 `yaml:"splitThresholdLineNumber"`
-			ScannerBufferSizeKiloBytes int   `yaml:"scannerBufferSizeKiloBytes"`
+			Compute                    string `yaml:"compute,omitempty" valid:"isOneOf,gcf,run"`
+			SplitThresholdLineNumber   int64  `yaml:"splitThresholdLineNumber"`
+			ScannerBufferSizeKiloBytes int    `yaml:"scannerBufferSizeKiloBytes"`
 		}
 	}
 }
@@ -49,21 +55,27 @@ func NewInstanceDeployment() *InstanceDeployment {
 	instanceDeployment.Settings.Service.GSU.APIList = []string{
 		"appengine.googleapis.com",
 		"cloudfunctions.googleapis.com",
-		"pubsub.googleapis.com"}
+		"cloudtrace.googleapis.com",
+		"pubsub.googleapis.com",
+		"run.googleapis.com"}
 	instanceDeployment.Settings.Service.GSU.APIList = append(deploy.GetCommonAPIlist(), instanceDeployment.Settings.Service.GSU.APIList...)
 
 	instanceDeployment.Settings.Service.IAM.RunRoles.Project = []iam.Role{
 		projectRunRole()}
 	instanceDeployment.Settings.Service.IAM.DeployRoles.Project = []iam.Role{
 		projectDeployCoreRole(),
-		iamgt.ProjectDeployExtendedRole()}
+		iamgt.ProjectDeployExtendedRole(),
+		run.ProjectDeployRunRole(),
+		dlq.ProjectDeployDeadLetterRole()}
 
 	instanceDeployment.Settings.Service.GCB.BuildTimeout = "600s"
 	instanceDeployment.Settings.Service.GCB.DeployIAMServiceAccount = true
 	instanceDeployment.Settings.Service.GCB.DeployIAMBindings = true
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.GRM.Hosting.Project.CustomRoles = []string{
 		projectDeployCoreRole().Title,
-		iamgt.ProjectDeployExtendedRole().Title}
+		iamgt.ProjectDeployExtendedRole().Title,
+		run.ProjectDeployRunRole().Title,
+		dlq.ProjectDeployDeadLetterRole().Title}
 	instanceDeployment.Settings.Service.GCB.ServiceAccountBindings.IAM.RolesOnServiceAccounts = []string{
 		"roles/iam.serviceAccountUser"}
 
@@ -71,12 +83,18 @@ func NewInstanceDeployment() *InstanceDeployment {
 		projectRunRole().Title}
 	// Data store permissions are not supported in custom roles
 	instanceDeployment.Settings.Service.GCF.ServiceAccountBindings.GRM.Hosting.Project.Roles = []string{
-		"roles/datastore.owner"}
+		"roles/datastore.owner",
+		"roles/cloudtrace.agent"}
 
 	instanceDeployment.Settings.Service.GCF.AvailableMemoryMb = 2048
 	instanceDeployment.Settings.Service.GCF.RetryTimeOutSeconds = 600
 	instanceDeployment.Settings.Service.GCF.Timeout = "540s" //is max value
 
+	// Cloud run allows longer processing of large dumps than the cloud function max value
+	instanceDeployment.Settings.Service.RUN.TimeoutSeconds = 3600
+
+	instanceDeployment.Settings.Service.Trace.SamplePercent = 10
+
 	return &instanceDeployment
 }
 
@@ -25,11 +25,13 @@ import (
 	"cloud.google.com/go/functions/metadata"
 	"github.com/BrunoReboul/ram/services/monitor"
 	"github.com/BrunoReboul/ram/utilities/cai"
+	"github.com/BrunoReboul/ram/utilities/dlq"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/gbq"
 	"github.com/BrunoReboul/ram/utilities/gps"
 	"github.com/BrunoReboul/ram/utilities/logging"
 	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/trc"
 	"github.com/google/uuid"
 	"google.golang.org/api/cloudresourcemanager/v1"
 
@@ -44,6 +46,7 @@ type Global struct {
 	cloudresourcemanagerService   *cloudresourcemanager.Service
 	cloudresourcemanagerServiceV2 *cloudresourcemanagerv2.Service // v2 is needed for folders
 	ctx                           context.Context
+	deadLetter                    dlq.Sender
 	environment                   string
 	firestoreClient               *firestore.Client
 	inserter                      *bigquery.Inserter
@@ -55,6 +58,7 @@ type Global struct {
 	step                          logging.Step
 	stepStack                     logging.Steps
 	tableName                     string
+	tracer                        trc.Tracer
 	violationResolverLabelKeyName string
 }
 
@@ -328,6 +332,40 @@ func Initialize(ctx context.Context, global *Global) (err error) {
 			return err
 		}
 	}
+	global.deadLetter, err = dlq.NewSender(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		instanceDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name,
+		global.environment,
+		global.microserviceName,
+		global.instanceName)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("dlq.NewSender %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
+	global.tracer, err = trc.NewTracer(ctx,
+		instanceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		global.microserviceName,
+		instanceDeployment.Settings.Service.Trace)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: global.microserviceName,
+			InstanceName:     global.instanceName,
+			Environment:      global.environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("trc.NewTracer %v", err),
+			InitID:           initID,
+		})
+		return err
+	}
 	return nil
 }
 
@@ -350,6 +388,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	}
 	global.stepStack = nil
 	global.PubSubID = metadata.EventID
+	global.deadLetter.Hold(metadata.Resource.Name, global.PubSubID, metadata.Timestamp, PubSubMessage.Data, PubSubMessage.Attributes)
 	parts := strings.Split(metadata.Resource.Name, "/")
 	global.step = logging.Step{
 		StepID:        fmt.Sprintf("%s/%s", parts[len(parts)-1], global.PubSubID),
@@ -371,7 +410,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 	})
 
 	if d.Seconds() > float64(global.retryTimeOutSeconds) {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:           global.microserviceName,
 			InstanceName:               global.instanceName,
 			Environment:                global.environment,
@@ -419,6 +458,8 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 		})
 		return err
 	}
+	spanCtx, span := global.tracer.StartSpan(global.ctx, global.instanceName, PubSubMessage.Attributes, global.stepStack)
+	defer global.tracer.EndSpan(span)
 	if insertID != "" {
 		now := time.Now()
 		latency := now.Sub(metadata.Timestamp)
@@ -431,6 +472,7 @@ func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage, globa
 			Message:              fmt.Sprintf("finish %s", insertID),
 			Now:                  &now,
 			TriggeringPubsubID:   global.PubSubID,
+			Trace:                global.tracer.GetLogTrace(spanCtx),
 			OriginEventTimestamp: &global.stepStack[0].StepTimestamp,
 			LatencySeconds:       latency.Seconds(),
 			LatencyE2ESeconds:    latencyE2E.Seconds(),
@@ -446,7 +488,7 @@ func persistComplianceStatus(pubSubJSONDoc []byte, global *Global) (insertID str
 	var complianceStatus monitor.ComplianceStatus
 	err = json.Unmarshal(pubSubJSONDoc, &complianceStatus)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -478,7 +520,7 @@ func persistViolation(pubSubJSONDoc []byte, global *Global) (insertID string, er
 	var violationBQ violationBQ
 	err = json.Unmarshal(pubSubJSONDoc, &violation)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -532,7 +574,7 @@ func persistAsset(pubSubJSONDoc []byte, global *Global) (insertID string, err er
 	var feedMessage feedMessage
 	err = json.Unmarshal(pubSubJSONDoc, &feedMessage)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -546,7 +588,7 @@ func persistAsset(pubSubJSONDoc []byte, global *Global) (insertID string, err er
 	var assetFeedMessageBQ assetFeedMessageBQ
 	err = json.Unmarshal(pubSubJSONDoc, &assetFeedMessageBQ)
 	if err != nil {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -558,7 +600,7 @@ func persistAsset(pubSubJSONDoc []byte, global *Global) (insertID string, err er
 		return "", nil
 	}
 	if assetFeedMessageBQ.Asset.Name == "" {
-		log.Println(logging.Entry{
+		global.deadLetter.NoRetry(logging.Entry{
 			MicroserviceName:   global.microserviceName,
 			InstanceName:       global.instanceName,
 			Environment:        global.environment,
@@ -41,6 +41,10 @@ Automatic retrying
 
 Yes.
 
+Tracing
+
+One span per streamed message, child of the traceparent message attribute, else of the origin step of the step stack. Set trace samplePercent in service.yaml, 0 disables the export to Cloud Trace.
+
 Implementation example
 
  package p
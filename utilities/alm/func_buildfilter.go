@@ -0,0 +1,43 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BuildFilter returns a log sink filter matching the audit logs of all mappings, sorted by mapping name to be stable
+func BuildFilter(mappings map[string]Mapping) (filter string) {
+	var names []string
+	for name := range mappings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var mappingFilters []string
+	for _, name := range names {
+		mapping := mappings[name]
+		var methodFilters []string
+		for _, methodName := range append(append([]string{}, mapping.MethodNames...), mapping.DeleteMethodNames...) {
+			methodFilters = append(methodFilters, fmt.Sprintf(`protoPayload.methodName="%s"`, methodName))
+		}
+		mappingFilters = append(mappingFilters, fmt.Sprintf(`(logName:"logs/cloudaudit.googleapis.com%%2F%s" AND protoPayload.serviceName="%s" AND (%s))`,
+			mapping.LogName,
+			mapping.ServiceName,
+			strings.Join(methodFilters, " OR ")))
+	}
+	return strings.Join(mappingFilters, " OR ")
+}
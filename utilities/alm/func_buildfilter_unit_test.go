@@ -0,0 +1,67 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alm
+
+import (
+	"testing"
+)
+
+func TestUnitBuildFilter(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		mappings map[string]Mapping
+		want     string
+	}{
+		{
+			name: "oneMapping",
+			mappings: map[string]Mapping{
+				"runServiceIAM": {
+					ServiceName: "run.googleapis.com",
+					MethodNames: []string{"google.cloud.run.v1.Services.SetIamPolicy"},
+					LogName:     "activity",
+				},
+			},
+			want: `(logName:"logs/cloudaudit.googleapis.com%2Factivity" AND protoPayload.serviceName="run.googleapis.com" AND (protoPayload.methodName="google.cloud.run.v1.Services.SetIamPolicy"))`,
+		},
+		{
+			name: "twoMappingsSortedByName",
+			mappings: map[string]Mapping{
+				"secretVersion": {
+					ServiceName:       "secretmanager.googleapis.com",
+					MethodNames:       []string{"AddSecretVersion"},
+					DeleteMethodNames: []string{"DestroySecretVersion"},
+					LogName:           "activity",
+				},
+				"bigqueryTableIAM": {
+					ServiceName: "bigquery.googleapis.com",
+					MethodNames: []string{"google.iam.v1.IAMPolicy.SetIamPolicy"},
+					LogName:     "data_access",
+				},
+			},
+			want: `(logName:"logs/cloudaudit.googleapis.com%2Fdata_access" AND protoPayload.serviceName="bigquery.googleapis.com" AND (protoPayload.methodName="google.iam.v1.IAMPolicy.SetIamPolicy")) OR (logName:"logs/cloudaudit.googleapis.com%2Factivity" AND protoPayload.serviceName="secretmanager.googleapis.com" AND (protoPayload.methodName="AddSecretVersion" OR protoPayload.methodName="DestroySecretVersion"))`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := BuildFilter(tc.mappings)
+			if got != tc.want {
+				t.Errorf("Want\n%s\ngot\n%s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var placeholderRegexp = regexp.MustCompile(`{[^{}]*}`)
+
+// ExpandTemplate replaces {resourceName} and {label.<key>} placeholders, an unknown or empty placeholder is an error
+func ExpandTemplate(template string, resourceName string, labels map[string]string) (expanded string, err error) {
+	expanded = placeholderRegexp.ReplaceAllStringFunc(template, func(placeholder string) string {
+		key := strings.Trim(placeholder, "{}")
+		var value string
+		switch {
+		case key == "resourceName":
+			value = resourceName
+		case strings.HasPrefix(key, "label."):
+			value = labels[strings.TrimPrefix(key, "label.")]
+		}
+		if value == "" && err == nil {
+			err = fmt.Errorf("cannot resolve placeholder %s in template %s", placeholder, template)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alm
+
+import (
+	"testing"
+)
+
+func TestUnitExpandTemplate(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		template     string
+		resourceName string
+		labels       map[string]string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "resourceName",
+			template:     "//bigquery.googleapis.com/{resourceName}",
+			resourceName: "projects/p1/datasets/d1/tables/t1",
+			want:         "//bigquery.googleapis.com/projects/p1/datasets/d1/tables/t1",
+		},
+		{
+			name:         "resourceNameAndLabel",
+			template:     "https://run.googleapis.com/v1/projects/{label.project_id}/locations/{label.location}/{resourceName}:getIamPolicy",
+			resourceName: "services/s1",
+			labels:       map[string]string{"project_id": "p1", "location": "europe-west1"},
+			want:         "https://run.googleapis.com/v1/projects/p1/locations/europe-west1/services/s1:getIamPolicy",
+		},
+		{
+			name:         "noPlaceholder",
+			template:     "https://example.com/static",
+			resourceName: "whatever",
+			want:         "https://example.com/static",
+		},
+		{
+			name:         "missingLabel",
+			template:     "//run.googleapis.com/projects/{label.project_id}/{resourceName}",
+			resourceName: "services/s1",
+			wantErr:      true,
+		},
+		{
+			name:         "unknownPlaceholder",
+			template:     "//run.googleapis.com/{methodName}",
+			resourceName: "services/s1",
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ExpandTemplate(tc.template, tc.resourceName, tc.labels)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Want an error and got none, result %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Did not expect an error an got %s", err.Error())
+			}
+			if got != tc.want {
+				t.Errorf("Want %s got %s", tc.want, got)
+			}
+		})
+	}
+}
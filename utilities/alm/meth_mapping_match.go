@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alm
+
+// Match returns true when the mapping applies to the audit log serviceName and methodName, and if the method deletes the asset
+func (mapping Mapping) Match(serviceName string, methodName string) (matched bool, isDeletion bool) {
+	if serviceName != mapping.ServiceName {
+		return false, false
+	}
+	for _, deleteMethodName := range mapping.DeleteMethodNames {
+		if methodName == deleteMethodName {
+			return true, true
+		}
+	}
+	for _, name := range mapping.MethodNames {
+		if methodName == name {
+			return true, false
+		}
+	}
+	return false, false
+}
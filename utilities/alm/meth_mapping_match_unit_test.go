@@ -0,0 +1,74 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alm
+
+import (
+	"testing"
+)
+
+func TestUnitMappingMatch(t *testing.T) {
+	mapping := Mapping{
+		ServiceName: "secretmanager.googleapis.com",
+		MethodNames: []string{
+			"google.cloud.secretmanager.v1.SecretManagerService.AddSecretVersion",
+			"google.cloud.secretmanager.v1.SecretManagerService.DisableSecretVersion"},
+		DeleteMethodNames: []string{"google.cloud.secretmanager.v1.SecretManagerService.DestroySecretVersion"},
+	}
+	var testCases = []struct {
+		name           string
+		serviceName    string
+		methodName     string
+		wantMatched    bool
+		wantIsDeletion bool
+	}{
+		{
+			name:        "methodName",
+			serviceName: "secretmanager.googleapis.com",
+			methodName:  "google.cloud.secretmanager.v1.SecretManagerService.DisableSecretVersion",
+			wantMatched: true,
+		},
+		{
+			name:           "deleteMethodName",
+			serviceName:    "secretmanager.googleapis.com",
+			methodName:     "google.cloud.secretmanager.v1.SecretManagerService.DestroySecretVersion",
+			wantMatched:    true,
+			wantIsDeletion: true,
+		},
+		{
+			name:        "otherMethodName",
+			serviceName: "secretmanager.googleapis.com",
+			methodName:  "google.cloud.secretmanager.v1.SecretManagerService.AccessSecretVersion",
+		},
+		{
+			name:        "otherServiceName",
+			serviceName: "bigquery.googleapis.com",
+			methodName:  "google.cloud.secretmanager.v1.SecretManagerService.AddSecretVersion",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			matched, isDeletion := mapping.Match(tc.serviceName, tc.methodName)
+			if matched != tc.wantMatched {
+				t.Errorf("Want matched %v got %v", tc.wantMatched, matched)
+			}
+			if isDeletion != tc.wantIsDeletion {
+				t.Errorf("Want isDeletion %v got %v", tc.wantIsDeletion, isDeletion)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alm
+
+// Mapping from an audit log serviceName and methodNames to an asset type, how to name the asset and how to get its current state
+// Templates accept {resourceName} for protoPayload.resourceName and {label.<key>} for the log entry resource labels, e.g. {label.project_id}
+type Mapping struct {
+	ServiceName       string   `yaml:"serviceName" valid:"isNotZeroValue"`
+	MethodNames       []string `yaml:"methodNames" valid:"isNotZeroValue"`
+	DeleteMethodNames []string `yaml:"deleteMethodNames,omitempty"`
+	LogName           string   `yaml:"logName" valid:"isOneOf,activity,data_access"`
+	AssetType         string   `yaml:"assetType" valid:"isNotZeroValue"`
+	ContentType       string   `yaml:"contentType" valid:"isOneOf,RESOURCE,IAM_POLICY"`
+	AssetName         string   `yaml:"assetName" valid:"isNotZeroValue"`
+	GetURL            string   `yaml:"getURL" valid:"isNotZeroValue"`
+	GetHTTPMethod     string   `yaml:"getHTTPMethod" valid:"isOneOf,GET,POST"`
+	Permissions       []string `yaml:"permissions,omitempty"`
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aut
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/option"
+)
+
+// GetClientOptionKeyless build a clientOption object for domain wide delegation without service account key
+// The JWT is signed by the IAM credentials signJwt API using the runtime identity, that needs iam.serviceAccounts.signJwt on the service account
+func GetClientOptionKeyless(ctx context.Context,
+	serviceAccountEmail string,
+	gciAdminUserToImpersonate string,
+	scopes []string,
+	initID string,
+	microserviceName string,
+	instanceName string,
+	environment string) (
+	option.ClientOption, bool) {
+	var clientOption option.ClientOption
+
+	iamcredentialsService, err := iamcredentials.NewService(ctx)
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: microserviceName,
+			InstanceName:     instanceName,
+			Environment:      environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("iamcredentials.NewService %v", err),
+			InitID:           initID,
+		})
+		return clientOption, false
+	}
+	tokenSource := &signJwtTokenSource{
+		ctx:                   ctx,
+		httpClient:            http.DefaultClient,
+		iamcredentialsService: iamcredentialsService,
+		scopes:                scopes,
+		serviceAccountEmail:   serviceAccountEmail,
+		subject:               gciAdminUserToImpersonate,
+		tokenURL:              googleTokenURL,
+	}
+	// Check the delegation works at init time rather than at first call
+	token, err := tokenSource.Token()
+	if err != nil {
+		log.Println(logging.Entry{
+			MicroserviceName: microserviceName,
+			InstanceName:     instanceName,
+			Environment:      environment,
+			Severity:         "CRITICAL",
+			Message:          "init_failed",
+			Description:      fmt.Sprintf("signJwtTokenSource.Token %v", err),
+			InitID:           initID,
+		})
+		return clientOption, false
+	}
+	log.Println(logging.Entry{
+		MicroserviceName: microserviceName,
+		InstanceName:     instanceName,
+		Environment:      environment,
+		Severity:         "INFO",
+		Message:          "init_keyless_delegation",
+		Description:      fmt.Sprintf("signJwt as %s impersonating %s", serviceAccountEmail, gciAdminUserToImpersonate),
+		InitID:           initID,
+	})
+	clientOption = option.WithTokenSource(oauth2.ReuseTokenSource(token, tokenSource))
+	return clientOption, true
+}
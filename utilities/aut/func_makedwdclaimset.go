@@ -0,0 +1,41 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aut
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// googleTokenURL OAuth2 token endpoint to exchange a signed JWT for an access token
+const googleTokenURL = "https://oauth2.googleapis.com/token"
+
+// makeDWDClaimSet build the JWT claim set of a domain wide delegation, the subject being the user to impersonate
+func makeDWDClaimSet(serviceAccountEmail string, subject string, scopes []string, now time.Time) (payload string, err error) {
+	claimSet := map[string]interface{}{
+		"iss":   serviceAccountEmail,
+		"sub":   subject,
+		"scope": strings.Join(scopes, " "),
+		"aud":   googleTokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	bytes, err := json.Marshal(claimSet)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aut
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestUnitMakeDWDClaimSet(t *testing.T) {
+	now := time.Date(2020, 11, 20, 10, 0, 0, 0, time.UTC)
+	payload, err := makeDWDClaimSet("listgroups@myproject.iam.gserviceaccount.com",
+		"admin@example.com",
+		[]string{"https://www.googleapis.com/auth/admin.directory.group.readonly", "https://www.googleapis.com/auth/admin.directory.domain.readonly"},
+		now)
+	if err != nil {
+		t.Fatalf("Did not expect an error an got %s", err.Error())
+	}
+	var claimSet map[string]interface{}
+	err = json.Unmarshal([]byte(payload), &claimSet)
+	if err != nil {
+		t.Fatalf("json.Unmarshal %v", err)
+	}
+	var testCases = []struct {
+		key       string
+		wantValue interface{}
+	}{
+		{"iss", "listgroups@myproject.iam.gserviceaccount.com"},
+		{"sub", "admin@example.com"},
+		{"scope", "https://www.googleapis.com/auth/admin.directory.group.readonly https://www.googleapis.com/auth/admin.directory.domain.readonly"},
+		{"aud", googleTokenURL},
+		{"iat", float64(now.Unix())},
+		{"exp", float64(now.Add(time.Hour).Unix())},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.key, func(t *testing.T) {
+			t.Parallel()
+			if claimSet[tc.key] != tc.wantValue {
+				t.Errorf("Want %s %v got %v", tc.key, tc.wantValue, claimSet[tc.key])
+			}
+		})
+	}
+}
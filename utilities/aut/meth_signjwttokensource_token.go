@@ -0,0 +1,74 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aut
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/iamcredentials/v1"
+)
+
+// Token signs the JWT claim set using the runtime identity then exchanges it for an access token
+func (ts *signJwtTokenSource) Token() (token *oauth2.Token, err error) {
+	payload, err := makeDWDClaimSet(ts.serviceAccountEmail, ts.subject, ts.scopes, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("makeDWDClaimSet %v", err)
+	}
+	name := fmt.Sprintf("projects/-/serviceAccounts/%s", ts.serviceAccountEmail)
+	signJwtResponse, err := ts.iamcredentialsService.Projects.ServiceAccounts.SignJwt(name,
+		&iamcredentials.SignJwtRequest{Payload: payload}).Context(ts.ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("iamcredentials SignJwt %v", err)
+	}
+
+	values := url.Values{}
+	values.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	values.Set("assertion", signJwtResponse.SignedJwt)
+	request, err := http.NewRequest("POST", ts.tokenURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequest %v", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := ts.httpClient.Do(request.WithContext(ts.ctx))
+	if err != nil {
+		return nil, fmt.Errorf("token exchange %v", err)
+	}
+	defer response.Body.Close()
+	var tokenResponse struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&tokenResponse)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange json.Decode %v", err)
+	}
+	if response.StatusCode != http.StatusOK || tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("token exchange status %d %s %s", response.StatusCode, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+	return &oauth2.Token{
+		AccessToken: tokenResponse.AccessToken,
+		TokenType:   tokenResponse.TokenType,
+		Expiry:      time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aut
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/option"
+)
+
+func TestUnitSignJwtTokenSourceToken(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		tokenStatusCode int
+		wantError       bool
+	}{
+		{
+			name:            "delegationGranted",
+			tokenStatusCode: http.StatusOK,
+		},
+		{
+			name:            "delegationDenied",
+			tokenStatusCode: http.StatusUnauthorized,
+			wantError:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, ":signJwt"):
+					var request iamcredentials.SignJwtRequest
+					json.NewDecoder(r.Body).Decode(&request)
+					if !strings.Contains(request.Payload, `"sub":"admin@example.com"`) {
+						t.Errorf("Want subject in payload got %s", request.Payload)
+					}
+					fmt.Fprint(w, `{"keyId":"123","signedJwt":"header.payload.signature"}`)
+				case r.URL.Path == "/token":
+					r.ParseForm()
+					if r.Form.Get("assertion") != "header.payload.signature" {
+						t.Errorf("Want signed JWT as assertion got %s", r.Form.Get("assertion"))
+					}
+					w.WriteHeader(tc.tokenStatusCode)
+					if tc.tokenStatusCode == http.StatusOK {
+						fmt.Fprint(w, `{"access_token":"ya29.blabla","token_type":"Bearer","expires_in":3600}`)
+					} else {
+						fmt.Fprint(w, `{"error":"unauthorized_client","error_description":"Client is unauthorized to retrieve access tokens"}`)
+					}
+				default:
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			ctx := context.Background()
+			iamcredentialsService, err := iamcredentials.NewService(ctx, option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tokenSource := &signJwtTokenSource{
+				ctx:                   ctx,
+				httpClient:            server.Client(),
+				iamcredentialsService: iamcredentialsService,
+				scopes:                []string{"https://www.googleapis.com/auth/admin.directory.group.readonly"},
+				serviceAccountEmail:   "listgroups@myproject.iam.gserviceaccount.com",
+				subject:               "admin@example.com",
+				tokenURL:              server.URL + "/token",
+			}
+			token, err := tokenSource.Token()
+			if err != nil {
+				if !tc.wantError {
+					t.Errorf("Did not expect an error an got %s", err.Error())
+				}
+			} else {
+				if tc.wantError {
+					t.Errorf("Expect an error and did not get it")
+				}
+				if token.AccessToken != "ya29.blabla" {
+					t.Errorf("Want access token ya29.blabla got %s", token.AccessToken)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aut
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/api/iamcredentials/v1"
+)
+
+// signJwtTokenSource oauth2 token source signing the domain wide delegation JWT with the IAM credentials API, no service account key involved
+type signJwtTokenSource struct {
+	ctx                   context.Context
+	httpClient            *http.Client
+	iamcredentialsService *iamcredentials.Service
+	scopes                []string
+	serviceAccountEmail   string
+	subject               string
+	tokenURL              string
+}
@@ -17,8 +17,8 @@ package bil
 import (
 	"fmt"
 	"log"
-	"strings"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/solution"
 	"google.golang.org/api/cloudbilling/v1"
 )
@@ -30,7 +30,7 @@ func (projectBillingAccount *ProjectBillingAccount) Enable() (err error) {
 	resourceName := fmt.Sprintf("projects/%s", projectBillingAccount.Core.SolutionSettings.Hosting.ProjectID)
 	projectBillingInfo, err := projectsService.GetBillingInfo(resourceName).Context(projectBillingAccount.Core.Ctx).Do()
 	if err != nil {
-		if strings.Contains(err.Error(), "403") {
+		if erm.IsPermissionDenied(err) {
 			log.Printf("%s bil WARNING impossible to GET billing info %v", projectBillingAccount.Core.InstanceName, err)
 			return nil
 		}
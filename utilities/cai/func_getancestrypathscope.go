@@ -0,0 +1,50 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GetAncestryPathScope returns the organization and the deepest ancestor of an ancestry path, e.g. organization/123/folder/456 gives organizations/123 and folders/456
+func GetAncestryPathScope(ancestryPath string) (organization string, scope string, err error) {
+	parts := strings.Split(strings.Trim(ancestryPath, "/"), "/")
+	if len(parts) < 2 || len(parts)%2 != 0 {
+		return "", "", fmt.Errorf("ancestry path must be made of type/number pairs, got %s", ancestryPath)
+	}
+	if parts[0] != "organization" {
+		return "", "", fmt.Errorf("ancestry path %s must start with organization", ancestryPath)
+	}
+	numberRegex := regexp.MustCompile(`^[0-9]+$`)
+	for i := 0; i < len(parts); i = i + 2 {
+		if !numberRegex.MatchString(parts[i+1]) {
+			return "", "", fmt.Errorf("ancestry path %s: %s is not a number", ancestryPath, parts[i+1])
+		}
+		switch {
+		case parts[i] == "organization" && i == 0:
+			scope = "organizations/" + parts[i+1]
+			organization = scope
+		case parts[i] == "folder":
+			scope = "folders/" + parts[i+1]
+		case parts[i] == "project":
+			scope = "projects/" + parts[i+1]
+		default:
+			return "", "", fmt.Errorf("ancestry path %s: unexpected ancestor type %s", ancestryPath, parts[i])
+		}
+	}
+	return organization, scope, nil
+}
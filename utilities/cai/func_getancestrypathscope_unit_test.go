@@ -0,0 +1,91 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cai
+
+import (
+	"testing"
+)
+
+func TestUnitGetAncestryPathScope(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		ancestryPath     string
+		wantOrganization string
+		wantScope        string
+		wantErr          bool
+	}{
+		{
+			name:             "organization",
+			ancestryPath:     "organization/123456789012",
+			wantOrganization: "organizations/123456789012",
+			wantScope:        "organizations/123456789012",
+		},
+		{
+			name:             "project",
+			ancestryPath:     "organization/123456789012/folder/234567890123/folder/345678901234/project/456789012345",
+			wantOrganization: "organizations/123456789012",
+			wantScope:        "projects/456789012345",
+		},
+		{
+			name:             "trailingSlash",
+			ancestryPath:     "organization/123456789012/folder/234567890123/",
+			wantOrganization: "organizations/123456789012",
+			wantScope:        "folders/234567890123",
+		},
+		{
+			name:         "noOrganization",
+			ancestryPath: "folder/234567890123/project/456789012345",
+			wantErr:      true,
+		},
+		{
+			name:         "partialNumber",
+			ancestryPath: "organization/123456789012/folder",
+			wantErr:      true,
+		},
+		{
+			name:         "displayName",
+			ancestryPath: "myorganization.org/folder-lovel1",
+			wantErr:      true,
+		},
+		{
+			name:         "empty",
+			ancestryPath: "",
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			organization, scope, err := GetAncestryPathScope(tc.ancestryPath)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Want an error got organization %s scope %s", organization, scope)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if tc.wantOrganization != organization {
+				t.Errorf("Want organization %s got %s", tc.wantOrganization, organization)
+			}
+			if tc.wantScope != scope {
+				t.Errorf("Want scope %s got %s", tc.wantScope, scope)
+			}
+		})
+	}
+}
@@ -16,7 +16,7 @@ package cai
 
 import "strings"
 
-// GetAssetShortTypeName returns a short version of asset type <serviceName>-<assetType>, like bigquery-Dataset. It deals with k8s exceptions
+// GetAssetShortTypeName returns a short version of asset type <serviceName>-<assetType>, like bigquery-Dataset. It deals with k8s, aws and azure exceptions
 func GetAssetShortTypeName(assetType string) string {
 	var serviceName string
 	tmpArr := strings.Split(assetType, "/")
@@ -29,6 +29,12 @@ func GetAssetShortTypeName(assetType string) string {
 		serviceName = "k8sextensions"
 	case "networking.k8s.io":
 		serviceName = "k8snetworking"
+	case "aws":
+		// aws/ec2/SecurityGroup
+		serviceName = "aws" + tmpArr[1]
+	case "azure":
+		// azure/microsoft.network/networksecuritygroups
+		serviceName = "azure" + strings.TrimPrefix(tmpArr[1], "microsoft.")
 	default:
 		tmpArr := strings.Split(assetType, ".")
 		serviceName = tmpArr[0]
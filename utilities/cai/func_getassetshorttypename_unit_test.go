@@ -29,6 +29,8 @@ func TestUnitGetAssetShortTypeName(t *testing.T) {
 		{"k8snetworkingIngress", "networking.k8s.io/Ingress", "k8snetworking-Ingress"},
 		{"k8sPod", "k8s.io/Pod", "k8s-Pod"},
 		{"gaeApp", "appengine.googleapis.com/Application", "appengine-Application"},
+		{"awsSecurityGroup", "aws/ec2/SecurityGroup", "awsec2-SecurityGroup"},
+		{"azureNetworkSecurityGroup", "azure/microsoft.network/networksecuritygroups", "azurenetwork-networksecuritygroups"},
 	}
 
 	for _, tc := range testCases {
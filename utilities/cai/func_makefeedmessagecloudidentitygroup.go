@@ -0,0 +1,57 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cai
+
+import (
+	"fmt"
+	"strings"
+
+	cloudidentity "google.golang.org/api/cloudidentity/v1beta1"
+)
+
+// securityGroupLabel marks a Cloud Identity group as a security group
+const securityGroupLabel = "cloudidentity.googleapis.com/groups.security"
+
+// MakeFeedMessageCloudIdentityGroup returns a CAI like feed message from a Cloud Identity group, window, origin, deleted status and step stack being left to the caller
+// The group ID in groups/<id> is the same than the Directory API group ID, so the asset name does not depend on the API used
+func MakeFeedMessageCloudIdentityGroup(directoryCustomerID string, group *cloudidentity.Group) (feedMessage FeedMessageCloudIdentityGroup) {
+	groupID := strings.TrimPrefix(group.Name, "groups/")
+	feedMessage.Asset.Ancestors = []string{fmt.Sprintf("directories/%s", directoryCustomerID)}
+	feedMessage.Asset.AncestryPath = fmt.Sprintf("directories/%s", directoryCustomerID)
+	feedMessage.Asset.AssetType = "www.googleapis.com/admin/directory/groups"
+	feedMessage.Asset.Name = fmt.Sprintf("//directories/%s/groups/%s", directoryCustomerID, groupID)
+	feedMessage.Asset.Resource.ID = groupID
+	if group.GroupKey != nil {
+		feedMessage.Asset.Resource.Email = strings.ToLower(group.GroupKey.Id)
+	}
+	feedMessage.Asset.Resource.Name = group.DisplayName
+	feedMessage.Asset.Resource.Description = group.Description
+	feedMessage.Asset.Resource.Kind = "admin#directory#group"
+	feedMessage.Asset.Resource.Labels = group.Labels
+	_, feedMessage.Asset.Resource.SecurityGroup = group.Labels[securityGroupLabel]
+	if group.DynamicGroupMetadata != nil {
+		feedMessage.Asset.Resource.DynamicGroup = true
+		for _, query := range group.DynamicGroupMetadata.Queries {
+			feedMessage.Asset.Resource.DynamicGroupQueries = append(feedMessage.Asset.Resource.DynamicGroupQueries,
+				dynamicGroupQuery{ResourceType: query.ResourceType, Query: query.Query})
+		}
+		if group.DynamicGroupMetadata.Status != nil {
+			feedMessage.Asset.Resource.DynamicGroupStatus = group.DynamicGroupMetadata.Status.Status
+		}
+	}
+	feedMessage.Asset.Resource.CreateTime = group.CreateTime
+	feedMessage.Asset.Resource.UpdateTime = group.UpdateTime
+	return feedMessage
+}
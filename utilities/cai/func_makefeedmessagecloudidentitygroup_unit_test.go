@@ -0,0 +1,109 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cloudidentity "google.golang.org/api/cloudidentity/v1beta1"
+)
+
+func TestUnitMakeFeedMessageCloudIdentityGroup(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		group            cloudidentity.Group
+		wantName         string
+		wantJSONContains []string
+	}{
+		{
+			name: "securityGroup",
+			group: cloudidentity.Group{
+				Name:        "groups/03abc",
+				GroupKey:    &cloudidentity.EntityKey{Id: "Admins@example.com"},
+				DisplayName: "Admins",
+				Labels: map[string]string{
+					"cloudidentity.googleapis.com/groups.discussion_forum": "",
+					"cloudidentity.googleapis.com/groups.security":         "",
+				},
+			},
+			wantName: "//directories/C0123/groups/03abc",
+			wantJSONContains: []string{
+				`"email":"admins@example.com"`,
+				`"securityGroup":true`,
+				`"dynamicGroup":false`,
+				`"kind":"admin#directory#group"`,
+			},
+		},
+		{
+			name: "dynamicGroup",
+			group: cloudidentity.Group{
+				Name:     "groups/03def",
+				GroupKey: &cloudidentity.EntityKey{Id: "engineering@example.com"},
+				Labels: map[string]string{
+					"cloudidentity.googleapis.com/groups.discussion_forum": "",
+				},
+				DynamicGroupMetadata: &cloudidentity.DynamicGroupMetadata{
+					Queries: []*cloudidentity.DynamicGroupQuery{
+						{ResourceType: "USER", Query: "user.organizations.exists(org, org.department=='engineering')"},
+					},
+					Status: &cloudidentity.DynamicGroupStatus{Status: "UP_TO_DATE"},
+				},
+			},
+			wantName: "//directories/C0123/groups/03def",
+			wantJSONContains: []string{
+				`"securityGroup":false`,
+				`"dynamicGroup":true`,
+				`"resourceType":"USER"`,
+				`"dynamicGroupStatus":"UP_TO_DATE"`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			feedMessage := MakeFeedMessageCloudIdentityGroup("C0123", &tc.group)
+			if feedMessage.Asset.Name != tc.wantName {
+				t.Errorf("Want name %s got %s", tc.wantName, feedMessage.Asset.Name)
+			}
+			feedMessageJSON, err := json.Marshal(feedMessage)
+			if err != nil {
+				t.Fatalf("Did not expect an error an got %s", err.Error())
+			}
+			for _, want := range tc.wantJSONContains {
+				if !strings.Contains(string(feedMessageJSON), want) {
+					t.Errorf("Want %s in %s", want, string(feedMessageJSON))
+				}
+			}
+			// Consumers of the groups topic read the message as a FeedMessageGroup
+			var feedMessageGroup FeedMessageGroup
+			err = json.Unmarshal(feedMessageJSON, &feedMessageGroup)
+			if err != nil {
+				t.Fatalf("Did not expect an error an got %s", err.Error())
+			}
+			if feedMessageGroup.Asset.Resource.Id != feedMessage.Asset.Resource.ID ||
+				feedMessageGroup.Asset.Resource.Email != feedMessage.Asset.Resource.Email {
+				t.Errorf("Want admin group id %s email %s got %s %s",
+					feedMessage.Asset.Resource.ID,
+					feedMessage.Asset.Resource.Email,
+					feedMessageGroup.Asset.Resource.Id,
+					feedMessageGroup.Asset.Resource.Email)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cai
+
+import (
+	"fmt"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+// MakeFeedMessageUser returns a CAI like feed message from a directory user, window, origin, deleted status and step stack being left to the caller
+func MakeFeedMessageUser(directoryCustomerID string, adminUser *admin.User) (feedMessage FeedMessageUser) {
+	feedMessage.Asset.Ancestors = []string{fmt.Sprintf("directories/%s", directoryCustomerID)}
+	feedMessage.Asset.AncestryPath = fmt.Sprintf("directories/%s", directoryCustomerID)
+	feedMessage.Asset.AssetType = "www.googleapis.com/admin/directory/users"
+	feedMessage.Asset.Name = fmt.Sprintf("//directories/%s/users/%s", directoryCustomerID, adminUser.Id)
+	feedMessage.Asset.Resource.PrimaryEmail = adminUser.PrimaryEmail
+	feedMessage.Asset.Resource.ID = adminUser.Id
+	feedMessage.Asset.Resource.Kind = adminUser.Kind
+	feedMessage.Asset.Resource.CustomerID = adminUser.CustomerId
+	feedMessage.Asset.Resource.OrgUnitPath = adminUser.OrgUnitPath
+	feedMessage.Asset.Resource.Suspended = adminUser.Suspended
+	feedMessage.Asset.Resource.SuspensionReason = adminUser.SuspensionReason
+	feedMessage.Asset.Resource.Archived = adminUser.Archived
+	feedMessage.Asset.Resource.IsAdmin = adminUser.IsAdmin
+	feedMessage.Asset.Resource.IsDelegatedAdmin = adminUser.IsDelegatedAdmin
+	feedMessage.Asset.Resource.IsEnrolledIn2Sv = adminUser.IsEnrolledIn2Sv
+	feedMessage.Asset.Resource.IsEnforcedIn2Sv = adminUser.IsEnforcedIn2Sv
+	feedMessage.Asset.Resource.IsMailboxSetup = adminUser.IsMailboxSetup
+	feedMessage.Asset.Resource.AgreedToTerms = adminUser.AgreedToTerms
+	feedMessage.Asset.Resource.ChangePasswordAtNextLogin = adminUser.ChangePasswordAtNextLogin
+	feedMessage.Asset.Resource.IncludeInGlobalAddressList = adminUser.IncludeInGlobalAddressList
+	feedMessage.Asset.Resource.CreationTime = adminUser.CreationTime
+	feedMessage.Asset.Resource.LastLoginTime = adminUser.LastLoginTime
+	return feedMessage
+}
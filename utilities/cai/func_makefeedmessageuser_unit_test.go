@@ -0,0 +1,100 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	admin "google.golang.org/api/admin/directory/v1"
+)
+
+func TestUnitMakeFeedMessageUser(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		adminUser        admin.User
+		wantName         string
+		wantAncestryPath string
+		wantJSONContains []string
+		wantJSONExcludes []string
+	}{
+		{
+			name: "superAdminWithout2SV",
+			adminUser: admin.User{
+				Id:              "123",
+				PrimaryEmail:    "admin@example.com",
+				IsAdmin:         true,
+				IsEnrolledIn2Sv: false,
+				LastLoginTime:   "2020-10-01T10:00:00.000Z",
+				OrgUnitPath:     "/IT",
+				RecoveryPhone:   "+33123456789",
+			},
+			wantName:         "//directories/C0123/users/123",
+			wantAncestryPath: "directories/C0123",
+			wantJSONContains: []string{
+				`"assetType":"www.googleapis.com/admin/directory/users"`,
+				`"isAdmin":true`,
+				`"isEnrolledIn2Sv":false`,
+				`"orgUnitPath":"/IT"`,
+				`"lastLoginTime":"2020-10-01T10:00:00.000Z"`,
+			},
+			wantJSONExcludes: []string{"+33123456789"},
+		},
+		{
+			name: "suspendedUser",
+			adminUser: admin.User{
+				Id:               "456",
+				PrimaryEmail:     "former@example.com",
+				Suspended:        true,
+				SuspensionReason: "ADMIN",
+			},
+			wantName:         "//directories/C0123/users/456",
+			wantAncestryPath: "directories/C0123",
+			wantJSONContains: []string{
+				`"suspended":true`,
+				`"suspensionReason":"ADMIN"`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			feedMessage := MakeFeedMessageUser("C0123", &tc.adminUser)
+			if feedMessage.Asset.Name != tc.wantName {
+				t.Errorf("Want name %s got %s", tc.wantName, feedMessage.Asset.Name)
+			}
+			if feedMessage.Asset.AncestryPath != tc.wantAncestryPath {
+				t.Errorf("Want ancestryPath %s got %s", tc.wantAncestryPath, feedMessage.Asset.AncestryPath)
+			}
+			feedMessageJSON, err := json.Marshal(feedMessage)
+			if err != nil {
+				t.Fatalf("Did not expect an error an got %s", err.Error())
+			}
+			for _, want := range tc.wantJSONContains {
+				if !strings.Contains(string(feedMessageJSON), want) {
+					t.Errorf("Want %s in %s", want, string(feedMessageJSON))
+				}
+			}
+			for _, exclude := range tc.wantJSONExcludes {
+				if strings.Contains(string(feedMessageJSON), exclude) {
+					t.Errorf("Do not want %s in %s", exclude, string(feedMessageJSON))
+				}
+			}
+		})
+	}
+}
@@ -18,7 +18,8 @@ import (
 	"fmt"
 	"log"
 	"reflect"
-	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
 
 	assetpb "google.golang.org/genproto/googleapis/cloud/asset/v1"
 )
@@ -34,7 +35,7 @@ func (feedDeployment *FeedDeployment) Deploy() (err error) {
 	// GET
 	feed, err := feedDeployment.Core.Services.AssetClient.GetFeed(feedDeployment.Core.Ctx, &getFeedRequest)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "notfound") {
+		if erm.IsNotFound(err) {
 			feedFound = false
 		} else {
 			return fmt.Errorf("AssetClient.GetFeed %v", err)
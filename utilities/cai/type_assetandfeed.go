@@ -33,6 +33,38 @@ type assetGroup struct {
 	Resource     *admin.Group    `json:"resource"`
 }
 
+// assetCloudIdentityGroup CAI like format
+type assetCloudIdentityGroup struct {
+	Name         string             `json:"name"`
+	AssetType    string             `json:"assetType"`
+	Ancestors    []string           `json:"ancestors"`
+	AncestryPath string             `json:"ancestryPath"`
+	IamPolicy    json.RawMessage    `json:"iamPolicy"`
+	Resource     cloudIdentityGroup `json:"resource"`
+}
+
+// cloudIdentityGroup has the same keys than admin.Group for id, email, name, description and kind, completed with Cloud Identity group labels and dynamic queries
+type cloudIdentityGroup struct {
+	ID                  string              `json:"id"`
+	Email               string              `json:"email"`
+	Name                string              `json:"name"`
+	Description         string              `json:"description"`
+	Kind                string              `json:"kind"`
+	Labels              map[string]string   `json:"labels,omitempty"`
+	SecurityGroup       bool                `json:"securityGroup"`
+	DynamicGroup        bool                `json:"dynamicGroup"`
+	DynamicGroupQueries []dynamicGroupQuery `json:"dynamicGroupQueries,omitempty"`
+	DynamicGroupStatus  string              `json:"dynamicGroupStatus,omitempty"`
+	CreateTime          string              `json:"createTime"`
+	UpdateTime          string              `json:"updateTime"`
+}
+
+// dynamicGroupQuery query defining the memberships of a dynamic group
+type dynamicGroupQuery struct {
+	ResourceType string `json:"resourceType"`
+	Query        string `json:"query"`
+}
+
 // assetGroupSettings CAI like format
 type assetGroupSettings struct {
 	Name         string                 `json:"name"`
@@ -63,6 +95,103 @@ type member struct {
 	Type        string `json:"type"`
 }
 
+// assetEffectiveMembers CAI like format
+type assetEffectiveMembers struct {
+	Name         string           `json:"name"`
+	AssetType    string           `json:"assetType"`
+	Ancestors    []string         `json:"ancestors"`
+	AncestryPath string           `json:"ancestryPath"`
+	IamPolicy    json.RawMessage  `json:"iamPolicy"`
+	Resource     effectiveMembers `json:"resource"`
+}
+
+// effectiveMembers transitive members of a group, nested groups being expanded
+type effectiveMembers struct {
+	GroupEmail string            `json:"groupEmail"`
+	Members    []EffectiveMember `json:"members"`
+}
+
+// EffectiveMember one direct or nested member of a group with the path of groups leading to it
+type EffectiveMember struct {
+	MemberEmail string   `json:"memberEmail"`
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	Path        []string `json:"path"`
+}
+
+// assetUser CAI like format
+type assetUser struct {
+	Name         string          `json:"name"`
+	AssetType    string          `json:"assetType"`
+	Ancestors    []string        `json:"ancestors"`
+	AncestryPath string          `json:"ancestryPath"`
+	IamPolicy    json.RawMessage `json:"iamPolicy"`
+	Resource     user            `json:"resource"`
+}
+
+// user is a subset of admin.User focused on security posture, excluding personal data like phones or addresses
+type user struct {
+	PrimaryEmail               string `json:"primaryEmail"`
+	ID                         string `json:"id"`
+	Kind                       string `json:"kind"`
+	CustomerID                 string `json:"customerId"`
+	OrgUnitPath                string `json:"orgUnitPath"`
+	Suspended                  bool   `json:"suspended"`
+	SuspensionReason           string `json:"suspensionReason"`
+	Archived                   bool   `json:"archived"`
+	IsAdmin                    bool   `json:"isAdmin"`
+	IsDelegatedAdmin           bool   `json:"isDelegatedAdmin"`
+	IsEnrolledIn2Sv            bool   `json:"isEnrolledIn2Sv"`
+	IsEnforcedIn2Sv            bool   `json:"isEnforcedIn2Sv"`
+	IsMailboxSetup             bool   `json:"isMailboxSetup"`
+	AgreedToTerms              bool   `json:"agreedToTerms"`
+	ChangePasswordAtNextLogin  bool   `json:"changePasswordAtNextLogin"`
+	IncludeInGlobalAddressList bool   `json:"includeInGlobalAddressList"`
+	CreationTime               string `json:"creationTime"`
+	LastLoginTime              string `json:"lastLoginTime"`
+}
+
+// assetDomainSetting CAI like format
+type assetDomainSetting struct {
+	Name         string          `json:"name"`
+	AssetType    string          `json:"assetType"`
+	Ancestors    []string        `json:"ancestors"`
+	AncestryPath string          `json:"ancestryPath"`
+	IamPolicy    json.RawMessage `json:"iamPolicy"`
+	Resource     domainSetting   `json:"resource"`
+}
+
+// domainSetting is built from a DOMAIN_SETTINGS admin activity event as there is no API to read these settings
+type domainSetting struct {
+	EventName       string `json:"eventName"`
+	SettingName     string `json:"settingName"`
+	ApplicationName string `json:"applicationName"`
+	DomainName      string `json:"domainName"`
+	OrgUnitName     string `json:"orgUnitName"`
+	GroupEmail      string `json:"groupEmail"`
+	OldValue        string `json:"oldValue"`
+	NewValue        string `json:"newValue"`
+	ActorEmail      string `json:"actorEmail"`
+}
+
+// assetRoleAssignment CAI like format
+type assetRoleAssignment struct {
+	Name         string          `json:"name"`
+	AssetType    string          `json:"assetType"`
+	Ancestors    []string        `json:"ancestors"`
+	AncestryPath string          `json:"ancestryPath"`
+	IamPolicy    json.RawMessage `json:"iamPolicy"`
+	Resource     roleAssignment  `json:"resource"`
+}
+
+// roleAssignment is built from a DELEGATED_ADMIN_SETTINGS admin activity event, so it uses the role name and user email, not their IDs
+type roleAssignment struct {
+	RoleName    string `json:"roleName"`
+	AssignedTo  string `json:"assignedTo"`
+	OrgUnitName string `json:"orgUnitName"`
+	ActorEmail  string `json:"actorEmail"`
+}
+
 // FeedMessageGroup CAI like format
 type FeedMessageGroup struct {
 	Asset     assetGroup    `json:"asset"`
@@ -72,6 +201,15 @@ type FeedMessageGroup struct {
 	StepStack logging.Steps `json:"step_stack,omitempty"`
 }
 
+// FeedMessageCloudIdentityGroup CAI like format, same asset type and name than FeedMessageGroup
+type FeedMessageCloudIdentityGroup struct {
+	Asset     assetCloudIdentityGroup `json:"asset"`
+	Window    Window                  `json:"window"`
+	Deleted   bool                    `json:"deleted"`
+	Origin    string                  `json:"origin"`
+	StepStack logging.Steps           `json:"step_stack,omitempty"`
+}
+
 // FeedMessageGroupSettings CAI like format
 type FeedMessageGroupSettings struct {
 	Asset     assetGroupSettings `json:"asset"`
@@ -90,6 +228,42 @@ type FeedMessageMember struct {
 	StepStack logging.Steps `json:"step_stack,omitempty"`
 }
 
+// FeedMessageUser CAI like format
+type FeedMessageUser struct {
+	Asset     assetUser     `json:"asset"`
+	Window    Window        `json:"window"`
+	Deleted   bool          `json:"deleted"`
+	Origin    string        `json:"origin"`
+	StepStack logging.Steps `json:"step_stack,omitempty"`
+}
+
+// FeedMessageDomainSetting CAI like format
+type FeedMessageDomainSetting struct {
+	Asset     assetDomainSetting `json:"asset"`
+	Window    Window             `json:"window"`
+	Deleted   bool               `json:"deleted"`
+	Origin    string             `json:"origin"`
+	StepStack logging.Steps      `json:"step_stack,omitempty"`
+}
+
+// FeedMessageRoleAssignment CAI like format
+type FeedMessageRoleAssignment struct {
+	Asset     assetRoleAssignment `json:"asset"`
+	Window    Window              `json:"window"`
+	Deleted   bool                `json:"deleted"`
+	Origin    string              `json:"origin"`
+	StepStack logging.Steps       `json:"step_stack,omitempty"`
+}
+
+// FeedMessageEffectiveMembers CAI like format
+type FeedMessageEffectiveMembers struct {
+	Asset     assetEffectiveMembers `json:"asset"`
+	Window    Window                `json:"window"`
+	Deleted   bool                  `json:"deleted"`
+	Origin    string                `json:"origin"`
+	StepStack logging.Steps         `json:"step_stack,omitempty"`
+}
+
 // Window Cloud Asset Inventory feed message time window
 type Window struct {
 	StartTime time.Time `json:"startTime" firestore:"startTime"`
@@ -17,7 +17,7 @@ package cai
 // Parameters structure
 type Parameters struct {
 	Parent       string
-	ContentType  string   `yaml:"contentType" valid:"isNotZeroValue"`
+	ContentType  string   `yaml:"contentType" valid:"isNotZeroValue;isOneOf,RESOURCE,IAM_POLICY"`
 	AssetTypes   []string `yaml:"assetTypes" valid:"isNotZeroValue"`
-	CronSchedule string   `yaml:"cronSchedule,omitempty"`
+	CronSchedule string   `yaml:"cronSchedule,omitempty" valid:"isCron"`
 }
@@ -26,6 +26,7 @@ import (
 	"cloud.google.com/go/firestore"
 	pubsub "cloud.google.com/go/pubsub/apiv1"
 	scheduler "cloud.google.com/go/scheduler/apiv1"
+	securitycenter "cloud.google.com/go/securitycenter/apiv1p1beta1"
 	"cloud.google.com/go/storage"
 	"github.com/BrunoReboul/ram/utilities/solution"
 	"google.golang.org/api/cloudbuild/v1"
@@ -33,7 +34,10 @@ import (
 	"google.golang.org/api/cloudresourcemanager/v1"
 	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
 	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/logging/v2"
 	"google.golang.org/api/monitoring/v1"
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+	"google.golang.org/api/run/v1"
 	"google.golang.org/api/serviceusage/v1"
 )
 
@@ -64,8 +68,13 @@ type Core struct {
 		CloudresourcemanagerServicev2 *cloudresourcemanagerv2.Service `yaml:"-"`
 		FirestoreClient               *firestore.Client               `yaml:"-"`
 		IAMService                    *iam.Service                    `yaml:"-"`
+		LoggingService                *logging.Service                `yaml:"-"`
 		MonitoringService             *monitoring.Service             `yaml:"-"`
+		MonitoringServicev3           *monitoringv3.Service           `yaml:"-"`
 		PubsubPublisherClient         *pubsub.PublisherClient         `yaml:"-"`
+		PubsubSubscriberClient        *pubsub.SubscriberClient        `yaml:"-"`
+		RunService                    *run.APIService                 `yaml:"-"`
+		SecuritycenterClient          *securitycenter.Client          `yaml:"-"`
 		ServiceusageService           *serviceusage.Service           `yaml:"-"`
 		SourcerepoService             *sourcerepo.Service             `yaml:"-"`
 		StorageClient                 *storage.Client                 `yaml:"-"`
@@ -78,5 +87,11 @@ type Core struct {
 		Deploy              bool
 		Check               bool
 		Dumpsettings        bool
+		Lint                bool
+		MakeSchemas         bool
+		NewRule             bool
+		Replay              bool
+		Report              bool
+		Plan                bool
 	} `yaml:"-"`
 }
@@ -0,0 +1,21 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dlq helps with dead letters: messages a microservice gives up processing, aka noretry
+//
+// Each trigger gets a dead letter topic named dlq-<trigger topic or bucket name>.
+//
+// Each dead letter is published to this topic and archived as a JSON object in the dead letters bucket,
+// so it can be replayed to its original topic, with its original attributes and a replayOf attribute, after a fix, see ramcli -replay.
+package dlq
@@ -0,0 +1,27 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import "fmt"
+
+// GetObjectName returns the archive object name of a dead letter, prefixed by the instance name and the day so dead letters can be selected for replay
+// suffixed by the dead letter time, as the sender can only create objects, not overwrite the one of a redelivered message
+func GetObjectName(deadLetter DeadLetter) string {
+	return fmt.Sprintf("%s/%s/%s_%d.json",
+		deadLetter.InstanceName,
+		deadLetter.DeadLetterTime.UTC().Format("2006-01-02"),
+		deadLetter.PubSubID,
+		deadLetter.DeadLetterTime.UnixNano())
+}
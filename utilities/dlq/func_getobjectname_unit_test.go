@@ -0,0 +1,56 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitGetObjectName(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		deadLetter DeadLetter
+		want       string
+	}{
+		{
+			name: "utcDay",
+			deadLetter: DeadLetter{
+				InstanceName:   "publish2fs_instance",
+				DeadLetterTime: time.Date(2020, 12, 1, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60)),
+				PubSubID:       "1234567890",
+			},
+			want: "publish2fs_instance/2020-12-02/1234567890_1606872600000000000.json",
+		},
+		{
+			name: "nanoseconds",
+			deadLetter: DeadLetter{
+				InstanceName:   "publish2fs_instance",
+				DeadLetterTime: time.Date(2020, 12, 2, 1, 30, 1, 5, time.UTC),
+				PubSubID:       "1234567890",
+			},
+			want: "publish2fs_instance/2020-12-02/1234567890_1606872601000000005.json",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := GetObjectName(tc.deadLetter); got != tc.want {
+				t.Errorf("Want %s and got %s", tc.want, got)
+			}
+		})
+	}
+}
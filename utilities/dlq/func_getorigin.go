@@ -0,0 +1,32 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import "strings"
+
+// getOrigin returns the topic or the bucket name from a triggering resource name
+// e.g. projects/<projectID>/topics/<topic> or projects/_/buckets/<bucket>/objects/<object>
+func getOrigin(resourceName string) (originTopic string, originBucket string) {
+	parts := strings.Split(resourceName, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		switch parts[i] {
+		case "topics":
+			return parts[i+1], ""
+		case "buckets":
+			return "", parts[i+1]
+		}
+	}
+	return "", ""
+}
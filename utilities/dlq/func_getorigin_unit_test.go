@@ -0,0 +1,56 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"testing"
+)
+
+func TestUnitGetOrigin(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		resourceName     string
+		wantOriginTopic  string
+		wantOriginBucket string
+	}{
+		{
+			name:            "topic",
+			resourceName:    "projects/qwerty/topics/gci-groups-C0123",
+			wantOriginTopic: "gci-groups-C0123",
+		},
+		{
+			name:             "bucket",
+			resourceName:     "projects/_/buckets/caiexport/objects/dumps/2020-12-01.dump",
+			wantOriginBucket: "caiexport",
+		},
+		{
+			name:         "unknown",
+			resourceName: "",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			originTopic, originBucket := getOrigin(tc.resourceName)
+			if originTopic != tc.wantOriginTopic {
+				t.Errorf("Want topic '%s' and got '%s'", tc.wantOriginTopic, originTopic)
+			}
+			if originBucket != tc.wantOriginBucket {
+				t.Errorf("Want bucket '%s' and got '%s'", tc.wantOriginBucket, originBucket)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"testing"
+)
+
+func TestUnitGetTopicName(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		triggerName string
+		want        string
+	}{
+		{
+			name:        "topic",
+			triggerName: "cai-rces-cloudresourcemanager-Project",
+			want:        "dlq-cai-rces-cloudresourcemanager-Project",
+		},
+		{
+			name:        "bucketStartingWithADigit",
+			triggerName: "123-cai-export",
+			want:        "dlq-123-cai-export",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := GetTopicName(tc.triggerName); got != tc.want {
+				t.Errorf("Want %s and got %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/storage"
+)
+
+// NewSender returns a dead letter sender for a microservice instance
+// The archive is skipped when the dead letters bucket name is empty
+func NewSender(ctx context.Context, projectID string, bucketName string, environment string, microserviceName string, instanceName string) (sender Sender, err error) {
+	sender.ctx = ctx
+	sender.environment = environment
+	sender.instanceName = instanceName
+	sender.microserviceName = microserviceName
+	sender.pubSubClient, err = pubsub.NewClient(ctx, projectID)
+	if err != nil {
+		return sender, fmt.Errorf("pubsub.NewClient %v", err)
+	}
+	if bucketName != "" {
+		storageClient, err := storage.NewClient(ctx)
+		if err != nil {
+			return sender, fmt.Errorf("storage.NewClient %v", err)
+		}
+		sender.bucketHandle = storageClient.Bucket(bucketName)
+	}
+	return sender, nil
+}
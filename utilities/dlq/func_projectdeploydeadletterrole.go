@@ -0,0 +1,38 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"google.golang.org/api/iam/v1"
+)
+
+// ProjectDeployDeadLetterRole permissions to deploy the dead letter topic and bucket, and to grant the microservice service account on them
+func ProjectDeployDeadLetterRole() (role iam.Role) {
+	role.Title = "ram_microservice_deploy_deadletter"
+	role.Description = "Real-time Asset Monitor microservices permissions to deploy dead letters topic and bucket"
+	role.Stage = "GA"
+	role.IncludedPermissions = []string{
+		"pubsub.topics.create",
+		"pubsub.topics.get",
+		"pubsub.topics.getIamPolicy",
+		"pubsub.topics.setIamPolicy",
+		"pubsub.topics.update",
+		"storage.buckets.create",
+		"storage.buckets.get",
+		"storage.buckets.getIamPolicy",
+		"storage.buckets.setIamPolicy",
+		"storage.buckets.update"}
+	return role
+}
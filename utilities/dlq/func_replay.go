@@ -0,0 +1,102 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+
+	pubsub "cloud.google.com/go/pubsub/apiv1"
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
+)
+
+// ReplayedPrefix prefix of the archived dead letters once replayed
+const ReplayedPrefix = "replayed/"
+
+// Replay republishes the archived dead letters of an instance, newer than since, to their original topic with their original attributes, then moves them under the replayed prefix so they are not replayed twice
+// Dead letters triggered by a bucket cannot be replayed this way and are skipped
+func Replay(ctx context.Context, storageClient *storage.Client, pubsubPublisherClient *pubsub.PublisherClient, projectID string, bucketName string, instanceName string, since time.Time) (replayedCount int64, err error) {
+	bucketHandle := storageClient.Bucket(bucketName)
+	objectIterator := bucketHandle.Objects(ctx, &storage.Query{Prefix: instanceName + "/"})
+	for {
+		objectAttrs, err := objectIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return replayedCount, fmt.Errorf("objectIterator.Next %v", err)
+		}
+		storageObjectReader, err := bucketHandle.Object(objectAttrs.Name).NewReader(ctx)
+		if err != nil {
+			return replayedCount, fmt.Errorf("NewReader %s %v", objectAttrs.Name, err)
+		}
+		deadLetterJSON, err := ioutil.ReadAll(storageObjectReader)
+		storageObjectReader.Close()
+		if err != nil {
+			return replayedCount, fmt.Errorf("ioutil.ReadAll %s %v", objectAttrs.Name, err)
+		}
+		var deadLetter DeadLetter
+		if err = json.Unmarshal(deadLetterJSON, &deadLetter); err != nil {
+			return replayedCount, fmt.Errorf("json.Unmarshal %s %v", objectAttrs.Name, err)
+		}
+		if deadLetter.DeadLetterTime.Before(since) {
+			continue
+		}
+		if deadLetter.OriginTopic == "" {
+			log.Printf("%s dlq skip %s triggered by bucket %s, not a topic", instanceName, objectAttrs.Name, deadLetter.OriginBucket)
+			continue
+		}
+		// keep the original attributes, e.g. the routing ones used by subscription filters and the traceparent
+		attributes := make(map[string]string)
+		for key, value := range deadLetter.Attributes {
+			attributes[key] = value
+		}
+		attributes["replayOf"] = deadLetter.PubSubID
+		var publishRequest pubsubpb.PublishRequest
+		publishRequest.Topic = fmt.Sprintf("projects/%s/topics/%s", projectID, deadLetter.OriginTopic)
+		publishRequest.Messages = []*pubsubpb.PubsubMessage{{
+			Data:       deadLetter.Data,
+			Attributes: attributes,
+		}}
+		if _, err = pubsubPublisherClient.Publish(ctx, &publishRequest); err != nil {
+			return replayedCount, fmt.Errorf("pubsubPublisherClient.Publish %s %v", publishRequest.Topic, err)
+		}
+		deadLetter.ReplayCount++
+		replayedJSON, err := json.Marshal(deadLetter)
+		if err != nil {
+			return replayedCount, fmt.Errorf("json.Marshal %v", err)
+		}
+		storageObjectWriter := bucketHandle.Object(ReplayedPrefix + objectAttrs.Name).NewWriter(ctx)
+		storageObjectWriter.ContentType = "application/json"
+		if _, err = storageObjectWriter.Write(replayedJSON); err != nil {
+			return replayedCount, fmt.Errorf("storageObjectWriter.Write %v", err)
+		}
+		if err = storageObjectWriter.Close(); err != nil {
+			return replayedCount, fmt.Errorf("storageObjectWriter.Close %v", err)
+		}
+		if err = bucketHandle.Object(objectAttrs.Name).Delete(ctx); err != nil {
+			return replayedCount, fmt.Errorf("Delete %s %v", objectAttrs.Name, err)
+		}
+		replayedCount++
+		log.Printf("%s dlq replayed %s to topic %s reason was: %s", instanceName, objectAttrs.Name, deadLetter.OriginTopic, deadLetter.Reason)
+	}
+	return replayedCount, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"fmt"
+	"log"
+
+	"cloud.google.com/go/iam"
+	"github.com/BrunoReboul/ram/utilities/gcs"
+	"github.com/BrunoReboul/ram/utilities/gps"
+)
+
+// Deploy the dead letter topic of a trigger and the dead letters bucket, and grant the microservice service account to publish and to archive
+func (deadLetterDeployment *DeadLetterDeployment) Deploy() (err error) {
+	topicName := GetTopicName(deadLetterDeployment.Settings.TriggerName)
+	member := fmt.Sprintf("serviceAccount:%s@%s.iam.gserviceaccount.com",
+		deadLetterDeployment.Core.ServiceName,
+		deadLetterDeployment.Core.SolutionSettings.Hosting.ProjectID)
+
+	topicDeployment := gps.NewTopicDeployment()
+	topicDeployment.Core = deadLetterDeployment.Core
+	topicDeployment.Settings.TopicName = topicName
+	if err = topicDeployment.Deploy(); err != nil {
+		return err
+	}
+	if err = gps.SetTopicRole(deadLetterDeployment.Core.Ctx,
+		deadLetterDeployment.Core.Services.PubsubPublisherClient,
+		fmt.Sprintf("projects/%s/topics/%s", deadLetterDeployment.Core.SolutionSettings.Hosting.ProjectID, topicName),
+		member,
+		"roles/pubsub.publisher"); err != nil {
+		return fmt.Errorf("gps.SetTopicRole %v", err)
+	}
+
+	bucketName := deadLetterDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name
+	if bucketName == "" {
+		log.Printf("%s dlq no dead letters bucket name in solution settings, archive skipped", deadLetterDeployment.Core.InstanceName)
+		return nil
+	}
+	bucketDeployment := gcs.NewBucketDeployment()
+	bucketDeployment.Core = deadLetterDeployment.Core
+	bucketDeployment.Settings.BucketName = bucketName
+	bucketDeployment.Settings.DeleteAgeInDays = deadLetterDeployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.DeleteAgeInDays
+	if err = bucketDeployment.Deploy(); err != nil {
+		return err
+	}
+	var role iam.RoleName = "roles/storage.objectCreator"
+	iamHandle := deadLetterDeployment.Core.Services.StorageClient.Bucket(bucketName).IAM()
+	policy, err := iamHandle.Policy(deadLetterDeployment.Core.Ctx)
+	if err != nil {
+		return fmt.Errorf("iamHandle.Policy %v", err)
+	}
+	if policy.HasRole(member, role) {
+		log.Printf("%s dlq %s already has role %s on bucket %s", deadLetterDeployment.Core.InstanceName, member, role, bucketName)
+		return nil
+	}
+	policy.Add(member, role)
+	if err = iamHandle.SetPolicy(deadLetterDeployment.Core.Ctx, policy); err != nil {
+		return fmt.Errorf("iamHandle.SetPolicy %v", err)
+	}
+	log.Printf("%s dlq granted role %s to %s on bucket %s", deadLetterDeployment.Core.InstanceName, role, member, bucketName)
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import "time"
+
+// Hold keeps the triggering message, data and attributes, so it can be sent as a dead letter, to be called at the begining of each execution
+func (sender *Sender) Hold(resourceName string, pubSubID string, publishTime time.Time, data []byte, attributes map[string]string) {
+	sender.attributes = attributes
+	sender.data = data
+	sender.originTopic, sender.originBucket = getOrigin(resourceName)
+	sender.publishTime = publishTime
+	sender.pubSubID = pubSubID
+}
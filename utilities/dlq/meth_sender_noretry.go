@@ -0,0 +1,95 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/BrunoReboul/ram/utilities/logging"
+)
+
+// NoRetry logs the entry, then sends the held message with the entry description as the reason to the dead letter topic and archive
+// A failure to send is logged, not returned, as the caller gives up processing the message anyway
+func (sender *Sender) NoRetry(entry logging.Entry) {
+	log.Println(entry)
+	if sender.pubSubClient == nil {
+		return
+	}
+	deadLetter := DeadLetter{
+		Attributes:       sender.attributes,
+		Data:             sender.data,
+		DeadLetterTime:   time.Now(),
+		Environment:      sender.environment,
+		InstanceName:     sender.instanceName,
+		MicroserviceName: sender.microserviceName,
+		OriginBucket:     sender.originBucket,
+		OriginTopic:      sender.originTopic,
+		PublishTime:      sender.publishTime,
+		PubSubID:         sender.pubSubID,
+		Reason:           fmt.Sprintf("%s %s", entry.Message, entry.Description),
+	}
+	deadLetterJSON, err := json.Marshal(deadLetter)
+	if err != nil {
+		sender.logFailure(fmt.Sprintf("json.Marshal(deadLetter) %v", err))
+		return
+	}
+	if sender.bucketHandle != nil {
+		storageObjectWriter := sender.bucketHandle.Object(GetObjectName(deadLetter)).NewWriter(sender.ctx)
+		storageObjectWriter.ContentType = "application/json"
+		if _, err = storageObjectWriter.Write(deadLetterJSON); err != nil {
+			sender.logFailure(fmt.Sprintf("storageObjectWriter.Write %v", err))
+		}
+		if err = storageObjectWriter.Close(); err != nil {
+			sender.logFailure(fmt.Sprintf("storageObjectWriter.Close %v", err))
+		}
+	}
+	var triggerName string
+	if sender.originTopic != "" {
+		triggerName = sender.originTopic
+	} else {
+		triggerName = sender.originBucket
+	}
+	if triggerName == "" {
+		sender.logFailure("unknown origin, no dead letter topic")
+		return
+	}
+	publishResult := sender.pubSubClient.Topic(GetTopicName(triggerName)).Publish(sender.ctx, &pubsub.Message{
+		Data: deadLetterJSON,
+		Attributes: map[string]string{
+			"instanceName": sender.instanceName,
+			"originTopic":  sender.originTopic,
+		},
+	})
+	if _, err = publishResult.Get(sender.ctx); err != nil {
+		sender.logFailure(fmt.Sprintf("publishResult.Get %v", err))
+	}
+}
+
+// logFailure logs a critical entry when a dead letter cannot be sent
+func (sender *Sender) logFailure(description string) {
+	log.Println(logging.Entry{
+		MicroserviceName:   sender.microserviceName,
+		InstanceName:       sender.instanceName,
+		Environment:        sender.environment,
+		Severity:           "CRITICAL",
+		Message:            "dead_letter_failed",
+		Description:        description,
+		TriggeringPubsubID: sender.pubSubID,
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import "time"
+
+// DeadLetter a message a microservice instance gave up processing, and why
+type DeadLetter struct {
+	Attributes       map[string]string `json:"attributes,omitempty"`
+	Data             []byte            `json:"data"`
+	DeadLetterTime   time.Time         `json:"deadLetterTime"`
+	Environment      string            `json:"environment"`
+	InstanceName     string            `json:"instanceName"`
+	MicroserviceName string            `json:"microserviceName"`
+	OriginBucket     string            `json:"originBucket,omitempty"`
+	OriginTopic      string            `json:"originTopic,omitempty"`
+	PublishTime      time.Time         `json:"publishTime"`
+	PubSubID         string            `json:"pubSubID"`
+	Reason           string            `json:"reason"`
+	ReplayCount      int64             `json:"replayCount,omitempty"`
+}
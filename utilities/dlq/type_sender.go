@@ -0,0 +1,40 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/storage"
+)
+
+// Sender sends the message being processed to the dead letter topic and archive bucket
+// It is a value in the microservice global structure, so each copy of the global structure, e.g. one per cloud run request, holds its own message
+type Sender struct {
+	attributes       map[string]string
+	bucketHandle     *storage.BucketHandle
+	ctx              context.Context
+	data             []byte
+	environment      string
+	instanceName     string
+	microserviceName string
+	originBucket     string
+	originTopic      string
+	publishTime      time.Time
+	pubSubClient     *pubsub.Client
+	pubSubID         string
+}
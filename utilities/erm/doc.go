@@ -13,4 +13,9 @@
 // limitations under the License.
 
 // Package erm helps with errors management
+//
+// Errors are classified from their HTTP status code and reason, googleapi.Error, or their gRPC status code, not from their message that may contain resource IDs or timestamps.
+// An HTTP 409 is either already exists or aborted depending on its reason.
+//
+// Retry calls a function with an exponential backoff and jitter as long as the error is transient.
 package erm
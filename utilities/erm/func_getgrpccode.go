@@ -0,0 +1,35 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getGRPCCode returns the gRPC status code of a google cloud client library error, wrapped or not, ok is false for other errors
+func getGRPCCode(err error) (code codes.Code, ok bool) {
+	var grpcError interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcError) {
+		return codes.Unknown, false
+	}
+	grpcStatus := grpcError.GRPCStatus()
+	if grpcStatus == nil {
+		return codes.Unknown, false
+	}
+	return grpcStatus.Code(), true
+}
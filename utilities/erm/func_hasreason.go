@@ -0,0 +1,48 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/api/googleapi"
+)
+
+// HasReason check if a google API REST error has one of the reasons, either in its error items, e.g. alreadyExists, or as the canonical status of its body, e.g. ALREADY_EXISTS
+func HasReason(err error, reasons ...string) bool {
+	var apiError *googleapi.Error
+	if !errors.As(err, &apiError) {
+		return false
+	}
+	errorReasons := make(map[string]bool)
+	for _, errorItem := range apiError.Errors {
+		errorReasons[errorItem.Reason] = true
+	}
+	var body struct {
+		Error struct {
+			Status string `json:"status"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiError.Body), &body) == nil && body.Error.Status != "" {
+		errorReasons[body.Error.Status] = true
+	}
+	for _, reason := range reasons {
+		if errorReasons[reason] {
+			return true
+		}
+	}
+	return false
+}
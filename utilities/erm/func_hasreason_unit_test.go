@@ -0,0 +1,79 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnitHasReason(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		err     error
+		reasons []string
+		want    bool
+	}{
+		{
+			name:    "errorItemReason",
+			err:     &googleapi.Error{Code: 409, Errors: []googleapi.ErrorItem{{Reason: "duplicate"}}},
+			reasons: []string{"alreadyExists", "duplicate"},
+			want:    true,
+		},
+		{
+			name:    "bodyStatus",
+			err:     &googleapi.Error{Code: 409, Body: `{"error": {"code": 409, "message": "There were concurrent policy changes.", "status": "ABORTED"}}`},
+			reasons: []string{"ABORTED"},
+			want:    true,
+		},
+		{
+			name:    "wrapped",
+			err:     fmt.Errorf("Groups.List %w", &googleapi.Error{Code: 400, Errors: []googleapi.ErrorItem{{Reason: "notFound"}}}),
+			reasons: []string{"notFound"},
+			want:    true,
+		},
+		{
+			name:    "otherReason",
+			err:     &googleapi.Error{Code: 400, Errors: []googleapi.ErrorItem{{Reason: "badRequest"}}},
+			reasons: []string{"notFound"},
+			want:    false,
+		},
+		{
+			name:    "invalidBody",
+			err:     &googleapi.Error{Code: 409, Body: "conflict"},
+			reasons: []string{"ABORTED"},
+			want:    false,
+		},
+		{
+			name:    "grpc",
+			err:     status.Error(codes.AlreadyExists, "already exists"),
+			reasons: []string{"ALREADY_EXISTS"},
+			want:    false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := HasReason(tc.err, tc.reasons...); got != tc.want {
+				t.Errorf("Want %v and got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// IsAborted check if the error is an HTTP 409 with an aborted reason or a gRPC Aborted, e.g. a setIamPolicy on concurrent policy changes, worth a new read-modify-write cycle
+func IsAborted(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := getHTTPCode(err); ok {
+		return code == 409 && HasReason(err, "aborted", "ABORTED")
+	}
+	if code, ok := getGRPCCode(err); ok {
+		return code == codes.Aborted
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// IsBadRequest check if the error is an HTTP 400 or a gRPC InvalidArgument
+func IsBadRequest(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := getHTTPCode(err); ok {
+		return code == 400
+	}
+	if code, ok := getGRPCCode(err); ok {
+		return code == codes.InvalidArgument
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnitIsNotFound(t *testing.T) {
+	var testCases = []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "http404",
+			err:  &googleapi.Error{Code: 404},
+			want: true,
+		},
+		{
+			name: "http403",
+			err:  &googleapi.Error{Code: 403},
+			want: false,
+		},
+		{
+			name: "grpcNotFound",
+			err:  status.Error(codes.NotFound, "not found"),
+			want: true,
+		},
+		{
+			name: "notFoundInMessageOnly",
+			err:  fmt.Errorf("group notfound-404@example.com"),
+			want: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsNotFound(tc.err); got != tc.want {
+				t.Errorf("Want %v and got %v", tc.want, got)
+			}
+		})
+	}
+}
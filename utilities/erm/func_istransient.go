@@ -0,0 +1,36 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// IsTransient check if the error is worth retrying: HTTP 429 or 5xx except 501, gRPC Unavailable, DeadlineExceeded, ResourceExhausted, Aborted or Internal
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := getHTTPCode(err); ok {
+		return code == 429 || (code >= 500 && code != 501)
+	}
+	if code, ok := getGRPCCode(err); ok {
+		switch code {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+			return true
+		}
+	}
+	return false
+}
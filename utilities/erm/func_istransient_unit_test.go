@@ -0,0 +1,92 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnitIsTransient(t *testing.T) {
+	var testCases = []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "noError",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "http503",
+			err:  &googleapi.Error{Code: 503},
+			want: true,
+		},
+		{
+			name: "http429",
+			err:  &googleapi.Error{Code: 429},
+			want: true,
+		},
+		{
+			name: "http404",
+			err:  &googleapi.Error{Code: 404},
+			want: false,
+		},
+		{
+			name: "http500Wrapped",
+			err:  fmt.Errorf("operationsService.Get %w", &googleapi.Error{Code: 500}),
+			want: true,
+		},
+		{
+			name: "grpcUnavailable",
+			err:  status.Error(codes.Unavailable, "unavailable"),
+			want: true,
+		},
+		{
+			name: "grpcDeadlineExceeded",
+			err:  status.Error(codes.DeadlineExceeded, "deadline exceeded"),
+			want: true,
+		},
+		{
+			name: "grpcUnavailableWrapped",
+			err:  fmt.Errorf("securitycenterClient.UpdateFinding %w", status.Error(codes.Unavailable, "unavailable")),
+			want: true,
+		},
+		{
+			name: "grpcInvalidArgument",
+			err:  status.Error(codes.InvalidArgument, "invalid argument"),
+			want: false,
+		},
+		{
+			name: "digitsInMessage",
+			err:  fmt.Errorf("bucket 502-logs 404"),
+			want: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsTransient(tc.err); got != tc.want {
+				t.Errorf("Want %v and got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -16,24 +16,21 @@ package erm
 
 import (
 	"log"
-	"strings"
 	"time"
 )
 
-// IsNotTransientElseWait check is the error is a 5xx and wait if it is
-func IsNotTransientElseWait(err error, waitSec time.Duration) (isNotTransient bool) {
-	erroMessage := err.Error()
-	transientErrors := []string{"500", "501", "502", "503", "504", "505", "506", "507", "508", "510", "511"}
-	isNotTransient = true
-	for _, transientError := range transientErrors {
-		if strings.Contains(erroMessage, transientError) {
-			isNotTransient = false
-			break
+// Retry calls the function until it succeeds, returns a non transient error or reaches the max number of attempts, waiting an exponential backoff with jitter between attempts
+func Retry(maxAttempts int, initialBackoff time.Duration, maxBackoff time.Duration, call func() error) (err error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		err = call()
+		if !IsTransient(err) {
+			return err
+		}
+		if attempt < maxAttempts-1 {
+			backoff := getBackoffWithJitter(attempt, initialBackoff, maxBackoff)
+			log.Printf("Transient error, attempt %d, wait %v and retry %v", attempt+1, backoff, err)
+			time.Sleep(backoff)
 		}
 	}
-	if !isNotTransient {
-		log.Printf("Transient error, wait %d sec and retry %s", waitSec, erroMessage)
-		time.Sleep(waitSec * time.Second)
-	}
-	return isNotTransient
+	return err
 }
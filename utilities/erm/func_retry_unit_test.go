@@ -0,0 +1,117 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package erm
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestUnitRetry(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "success",
+			errs:      []error{nil},
+			wantCalls: 1,
+			wantErr:   false,
+		},
+		{
+			name:      "successAfterTransient",
+			errs:      []error{&googleapi.Error{Code: 503}, nil},
+			wantCalls: 2,
+			wantErr:   false,
+		},
+		{
+			name:      "nonTransientNotRetried",
+			errs:      []error{&googleapi.Error{Code: 400}},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "maxAttemptsReached",
+			errs:      []error{&googleapi.Error{Code: 503}, &googleapi.Error{Code: 503}, &googleapi.Error{Code: 503}},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var calls int
+			err := Retry(3, time.Millisecond, 2*time.Millisecond, func() error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+			if calls != tc.wantCalls {
+				t.Errorf("Want %d calls and got %d", tc.wantCalls, calls)
+			}
+			if tc.wantErr && err == nil {
+				t.Errorf("Want an error and got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Did not expect an error an got %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestUnitGetBackoffWithJitter(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "firstAttempt",
+			attempt: 0,
+			wantMin: 500 * time.Millisecond,
+			wantMax: time.Second,
+		},
+		{
+			name:    "thirdAttempt",
+			attempt: 2,
+			wantMin: 2 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name:    "cappedToMax",
+			attempt: 20,
+			wantMin: 5 * time.Second,
+			wantMax: 10 * time.Second,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			for i := 0; i < 100; i++ {
+				got := getBackoffWithJitter(tc.attempt, time.Second, 10*time.Second)
+				if got < tc.wantMin || got > tc.wantMax {
+					t.Errorf("Want a backoff between %v and %v and got %v", tc.wantMin, tc.wantMax, got)
+				}
+			}
+		})
+	}
+}
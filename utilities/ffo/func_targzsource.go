@@ -0,0 +1,61 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ffo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"time"
+)
+
+// TarGzSource make a gzipped tar file from a map where the key is the file name and the value the string file content
+func TarGzSource(tarGzFullPath string, files map[string]string) (err error) {
+	tarGzSourceFile, err := os.Create(tarGzFullPath)
+	if err != nil {
+		return err
+	}
+	defer tarGzSourceFile.Close()
+	gzipWriter := gzip.NewWriter(tarGzSourceFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	modTime := time.Now()
+	for name, strContent := range files {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(strContent)),
+			ModTime: modTime,
+		}
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = tarWriter.Write([]byte(strContent))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -20,6 +20,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
+
 	"google.golang.org/api/appengine/v1"
 )
 
@@ -30,13 +32,13 @@ func (appDeployment *AppDeployment) Deploy() (err error) {
 	appsOperationsService := appengine.NewAppsOperationsService(appDeployment.Core.Services.AppengineAPIService)
 	app, err := appsService.Get(appDeployment.Core.SolutionSettings.Hosting.ProjectID).Context(appDeployment.Core.Ctx).Do()
 	if err != nil {
-		if strings.Contains(err.Error(), "404") && strings.Contains(err.Error(), "notFound") {
+		if erm.IsNotFound(err) {
 			var appToCreate appengine.Application
 			appToCreate.Id = appDeployment.Core.SolutionSettings.Hosting.ProjectID
 			appToCreate.LocationId = appDeployment.Core.SolutionSettings.Hosting.GAE.Region
 			operation, err := appsService.Create(&appToCreate).Context(appDeployment.Core.Ctx).Do()
 			if err != nil {
-				if strings.Contains(err.Error(), "403") {
+				if erm.IsPermissionDenied(err) {
 					log.Printf("%s gae WARNING impossible to CREATE application %v", appDeployment.Core.InstanceName, err)
 					return nil
 				}
@@ -59,7 +61,7 @@ func (appDeployment *AppDeployment) Deploy() (err error) {
 			log.Printf("%s gae application created %s", appDeployment.Core.InstanceName, appToCreate.Id)
 			// ffo.JSONMarshalIndentPrint(operation)
 		} else {
-			if strings.Contains(err.Error(), "403") {
+			if erm.IsPermissionDenied(err) {
 				log.Printf("%s gae WARNING impossible to GET application %v", appDeployment.Core.InstanceName, err)
 				return nil
 			}
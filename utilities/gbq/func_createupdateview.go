@@ -20,6 +20,8 @@ import (
 	"log"
 	"strings"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
+
 	"cloud.google.com/go/bigquery"
 )
 
@@ -39,7 +41,7 @@ func createUpdateView(ctx context.Context, tableName string, dataset *bigquery.D
 	table := dataset.Table(viewName)
 	tableMetadataRetreived, err := table.Metadata(ctx)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "notfound") {
+		if erm.IsNotFound(err) {
 			var tableMetadata bigquery.TableMetadata
 			tableMetadata.Name = viewName
 			tableMetadata.Description = fmt.Sprintf("Real-time Asset Monitor - %s", viewName)
@@ -49,7 +51,7 @@ func createUpdateView(ctx context.Context, tableName string, dataset *bigquery.D
 			err = table.Create(ctx, &tableMetadata)
 			if err != nil {
 				// deal with concurent executions
-				if strings.Contains(strings.ToLower(err.Error()), "already exists") {
+				if erm.IsAlreadyExists(err) {
 					return nil
 				}
 				return fmt.Errorf("create view %v", err)
@@ -20,6 +20,8 @@ import (
 	"log"
 	"strings"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
+
 	"cloud.google.com/go/bigquery"
 )
 
@@ -27,7 +29,7 @@ func getDataset(ctx context.Context, datasetName string, location string, bigQue
 	dataset = bigQueryClient.Dataset(datasetName)
 	datasetMetadata, err := dataset.Metadata(ctx)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "notfound") {
+		if erm.IsNotFound(err) {
 			var datasetToCreateMetadata bigquery.DatasetMetadata
 			datasetToCreateMetadata.Name = datasetName
 			datasetToCreateMetadata.Location = location
@@ -37,7 +39,7 @@ func getDataset(ctx context.Context, datasetName string, location string, bigQue
 			err = dataset.Create(ctx, &datasetToCreateMetadata)
 			if err != nil {
 				// deal with concurent executions
-				if strings.Contains(strings.ToLower(err.Error()), "already exists") {
+				if erm.IsAlreadyExists(err) {
 					datasetMetadata, err = dataset.Metadata(ctx)
 					if err != nil {
 						return nil, err
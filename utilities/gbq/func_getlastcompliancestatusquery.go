@@ -101,6 +101,7 @@ WITH complianceStatus0 AS (
           SPLIT(status_for_latest_rules.assetName, "/") [SAFE_OFFSET(6)]
           WHEN "members" THEN "www.googleapis.com/admin/directory/members"
           WHEN "groupSettings" THEN "groupssettings.googleapis.com/groupSettings"
+          WHEN "effectiveMembers" THEN "www.googleapis.com/admin/directory/effectiveMembers"
           ELSE NULL
         END,
         NULL
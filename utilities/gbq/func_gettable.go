@@ -21,6 +21,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
+
 	"cloud.google.com/go/bigquery"
 )
 
@@ -38,7 +40,7 @@ func getTable(ctx context.Context, tableName string, dataset *bigquery.Dataset)
 	table = dataset.Table(tableName)
 	tableMetadata, err := table.Metadata(ctx)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "notfound") {
+		if erm.IsNotFound(err) {
 			var tableToCreateMetadata bigquery.TableMetadata
 			tableToCreateMetadata.Name = tableName
 			tableToCreateMetadata.Description = fmt.Sprintf("Real-time Asset Monitor - %s", tableName)
@@ -53,7 +55,7 @@ func getTable(ctx context.Context, tableName string, dataset *bigquery.Dataset)
 			err = table.Create(ctx, &tableToCreateMetadata)
 			if err != nil {
 				// deal with concurent executions
-				if strings.Contains(strings.ToLower(err.Error()), "already exists") {
+				if erm.IsAlreadyExists(err) {
 					tableMetadata, err = table.Metadata(ctx)
 					if err != nil {
 						return nil, err
@@ -19,6 +19,7 @@ import (
 	"log"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/solution"
@@ -51,19 +52,17 @@ func (triggerDeployment *TriggerDeployment) Deploy() (err error) {
 		if err = triggerDeployment.deleteTriggers(); err != nil {
 			return err
 		}
-		for i := 0; i < retries; i++ {
-			buildTrigger, err := triggerDeployment.Artifacts.ProjectsTriggersService.Create(triggerDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		var buildTrigger *cloudbuild.BuildTrigger
+		err = erm.Retry(retries, 5*time.Second, 30*time.Second, func() (err error) {
+			buildTrigger, err = triggerDeployment.Artifacts.ProjectsTriggersService.Create(triggerDeployment.Core.SolutionSettings.Hosting.ProjectID,
 				&triggerDeployment.Artifacts.BuildTrigger).Context(triggerDeployment.Core.Ctx).Do()
-			if err != nil {
-				if erm.IsNotTransientElseWait(err, 5) {
-					return err
-				}
-			} else {
-				// ffo.JSONMarshalIndentPrint(buildTrigger)
-				log.Printf("%s gcb created trigger %s id %s with tag filter %s", globalTriggerDeployment.Core.InstanceName, buildTrigger.Name, buildTrigger.Id, buildTrigger.TriggerTemplate.TagName)
-				break
-			}
+			return err
+		})
+		if err != nil {
+			return err
 		}
+		// ffo.JSONMarshalIndentPrint(buildTrigger)
+		log.Printf("%s gcb created trigger %s id %s with tag filter %s", globalTriggerDeployment.Core.InstanceName, buildTrigger.Name, buildTrigger.Id, buildTrigger.TriggerTemplate.TagName)
 	}
 	return nil
 }
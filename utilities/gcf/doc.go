@@ -12,5 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package gcf helps with Google cloud functions
+// Package gcf helps with Google cloud functions, background pubsub, background GCS and HTTP functions
+// HTTP functions have an https trigger, their ingress settings and invokers are set by the service, see iamgt InvokerBindings
 package gcf
@@ -0,0 +1,29 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validate checks the rules across event fields
+func (event Event) Validate() error {
+	// Cloud Asset Inventory publishes the feed messages without the RAM attributes the filter applies on
+	if event.Filter != "" && strings.HasPrefix(event.TriggerTopic, "cai-rces-") {
+		return fmt.Errorf("filter is not supported on cloud asset inventory feed trigger topic %s, messages have no attributes to filter on", event.TriggerTopic)
+	}
+	return nil
+}
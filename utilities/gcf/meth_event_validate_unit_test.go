@@ -0,0 +1,51 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcf
+
+import (
+	"testing"
+)
+
+func TestUnitEventValidate(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		event     Event
+		wantError bool
+	}{
+		{
+			name:  "filterOnRAMTopic",
+			event: Event{TriggerTopic: "ram-violation", Filter: `attributes.ruleName = "monitor_gcs_bucket_public"`},
+		},
+		{
+			name:  "noFilterOnCAITopic",
+			event: Event{TriggerTopic: "cai-rces-storage-Bucket"},
+		},
+		{
+			name:      "filterOnCAITopic",
+			event:     Event{TriggerTopic: "cai-rces-storage-Bucket", Filter: `attributes.assetType = "storage.googleapis.com/Bucket"`},
+			wantError: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.event.Validate()
+			if (err != nil) != tc.wantError {
+				t.Errorf("Want error %v and got %v", tc.wantError, err)
+			}
+		})
+	}
+}
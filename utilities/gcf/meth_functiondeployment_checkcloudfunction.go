@@ -17,8 +17,8 @@ package gcf
 import (
 	"fmt"
 	"reflect"
-	"strings"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/str"
 )
 
@@ -26,7 +26,7 @@ import (
 func (functionDeployment *FunctionDeployment) checkCloudFunction() (err error) {
 	retreivedCloudFunction, err := functionDeployment.Artifacts.ProjectsLocationsFunctionsService.Get(functionDeployment.Artifacts.CloudFunction.Name).Context(functionDeployment.Core.Ctx).Do()
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if erm.IsNotFound(err) {
 			return fmt.Errorf("%s gcf function NOT found for this instance", functionDeployment.Core.InstanceName)
 		}
 		return fmt.Errorf("ProjectsLocationsFunctionsService.Get %v", err)
@@ -47,25 +47,45 @@ func (functionDeployment *FunctionDeployment) checkCloudFunction() (err error) {
 			functionDeployment.Artifacts.CloudFunction.EntryPoint,
 			retreivedCloudFunction.EntryPoint)
 	}
-	if functionDeployment.Artifacts.CloudFunction.EventTrigger.EventType != retreivedCloudFunction.EventTrigger.EventType {
-		s = fmt.Sprintf("%seventTrigger.EventType\nwant %s\nhave %s\n", s,
-			functionDeployment.Artifacts.CloudFunction.EventTrigger.EventType,
-			retreivedCloudFunction.EventTrigger.EventType)
-	}
-	if functionDeployment.Artifacts.CloudFunction.EventTrigger.Resource != retreivedCloudFunction.EventTrigger.Resource {
-		s = fmt.Sprintf("%seventTrigger.Resource\nwant %s\nhave %s\n", s,
-			functionDeployment.Artifacts.CloudFunction.EventTrigger.Resource,
-			retreivedCloudFunction.EventTrigger.Resource)
-	}
-	if functionDeployment.Artifacts.CloudFunction.EventTrigger.Service != retreivedCloudFunction.EventTrigger.Service {
-		s = fmt.Sprintf("%seventTrigger.Service\nwant %s\nhave %s\n", s,
-			functionDeployment.Artifacts.CloudFunction.EventTrigger.Service,
-			retreivedCloudFunction.EventTrigger.Service)
+	switch {
+	case functionDeployment.Artifacts.CloudFunction.EventTrigger == nil:
+		if retreivedCloudFunction.EventTrigger != nil {
+			s = fmt.Sprintf("%seventTrigger\nwant none\nhave %s\n", s,
+				retreivedCloudFunction.EventTrigger.EventType)
+		}
+	case retreivedCloudFunction.EventTrigger == nil:
+		s = fmt.Sprintf("%seventTrigger\nwant %s\nhave none\n", s,
+			functionDeployment.Artifacts.CloudFunction.EventTrigger.EventType)
+	default:
+		if functionDeployment.Artifacts.CloudFunction.EventTrigger.EventType != retreivedCloudFunction.EventTrigger.EventType {
+			s = fmt.Sprintf("%seventTrigger.EventType\nwant %s\nhave %s\n", s,
+				functionDeployment.Artifacts.CloudFunction.EventTrigger.EventType,
+				retreivedCloudFunction.EventTrigger.EventType)
+		}
+		if functionDeployment.Artifacts.CloudFunction.EventTrigger.Resource != retreivedCloudFunction.EventTrigger.Resource {
+			s = fmt.Sprintf("%seventTrigger.Resource\nwant %s\nhave %s\n", s,
+				functionDeployment.Artifacts.CloudFunction.EventTrigger.Resource,
+				retreivedCloudFunction.EventTrigger.Resource)
+		}
+		if functionDeployment.Artifacts.CloudFunction.EventTrigger.Service != retreivedCloudFunction.EventTrigger.Service {
+			s = fmt.Sprintf("%seventTrigger.Service\nwant %s\nhave %s\n", s,
+				functionDeployment.Artifacts.CloudFunction.EventTrigger.Service,
+				retreivedCloudFunction.EventTrigger.Service)
+		}
+		if functionDeployment.Artifacts.CloudFunction.EventTrigger.FailurePolicy.Retry != retreivedCloudFunction.EventTrigger.FailurePolicy.Retry {
+			s = fmt.Sprintf("%seventTrigger.FailurePolicy.Retry\nwant %v\nhave %v\n", s,
+				functionDeployment.Artifacts.CloudFunction.EventTrigger.FailurePolicy.Retry,
+				retreivedCloudFunction.EventTrigger.FailurePolicy.Retry)
+		}
 	}
-	if functionDeployment.Artifacts.CloudFunction.EventTrigger.FailurePolicy.Retry != retreivedCloudFunction.EventTrigger.FailurePolicy.Retry {
-		s = fmt.Sprintf("%seventTrigger.FailurePolicy.Retry\nwant %v\nhave %v\n", s,
-			functionDeployment.Artifacts.CloudFunction.EventTrigger.FailurePolicy.Retry,
-			retreivedCloudFunction.EventTrigger.FailurePolicy.Retry)
+	switch {
+	case functionDeployment.Artifacts.CloudFunction.HttpsTrigger == nil:
+		if retreivedCloudFunction.HttpsTrigger != nil {
+			s = fmt.Sprintf("%shttpsTrigger\nwant none\nhave %s\n", s,
+				retreivedCloudFunction.HttpsTrigger.Url)
+		}
+	case retreivedCloudFunction.HttpsTrigger == nil:
+		s = fmt.Sprintf("%shttpsTrigger\nwant https trigger\nhave none\n", s)
 	}
 	if !reflect.DeepEqual(functionDeployment.Artifacts.CloudFunction.Labels, retreivedCloudFunction.Labels) {
 		s = fmt.Sprintf("%slabels\nwant %s\nhave %s\n", s,
@@ -82,6 +102,11 @@ func (functionDeployment *FunctionDeployment) checkCloudFunction() (err error) {
 			functionDeployment.Artifacts.CloudFunction.Runtime,
 			retreivedCloudFunction.Runtime)
 	}
+	if functionDeployment.Artifacts.CloudFunction.MaxInstances != retreivedCloudFunction.MaxInstances {
+		s = fmt.Sprintf("%smaxInstances\nwant %d\nhave %d\n", s,
+			functionDeployment.Artifacts.CloudFunction.MaxInstances,
+			retreivedCloudFunction.MaxInstances)
+	}
 	if functionDeployment.Artifacts.CloudFunction.ServiceAccountEmail != retreivedCloudFunction.ServiceAccountEmail {
 		s = fmt.Sprintf("%sserviceAccountEmail\nwant %s\nhave %s\n", s,
 			functionDeployment.Artifacts.CloudFunction.ServiceAccountEmail,
@@ -17,9 +17,9 @@ package gcf
 import (
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"google.golang.org/api/cloudfunctions/v1"
 )
@@ -30,7 +30,7 @@ func (functionDeployment *FunctionDeployment) createPatchCloudFunction() (err er
 	location := fmt.Sprintf("projects/%s/locations/%s", functionDeployment.Core.SolutionSettings.Hosting.ProjectID, functionDeployment.Core.SolutionSettings.Hosting.GCF.Region)
 	retreivedCloudFunction, err := functionDeployment.Artifacts.ProjectsLocationsFunctionsService.Get(functionDeployment.Artifacts.CloudFunction.Name).Context(functionDeployment.Core.Ctx).Do()
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
+		if erm.IsNotFound(err) {
 			operation, err = functionDeployment.Artifacts.ProjectsLocationsFunctionsService.Create(location,
 				&functionDeployment.Artifacts.CloudFunction).Context(functionDeployment.Core.Ctx).Do()
 			if err != nil {
@@ -53,17 +53,10 @@ func (functionDeployment *FunctionDeployment) createPatchCloudFunction() (err er
 	log.Println(name)
 	for {
 		time.Sleep(5 * time.Second)
-		for i := 0; i < Retries; i++ {
+		err = erm.Retry(Retries, 5*time.Second, 30*time.Second, func() (err error) {
 			operation, err = functionDeployment.Artifacts.OperationsService.Get(name).Context(functionDeployment.Core.Ctx).Do()
-			if err != nil {
-				if strings.Contains(err.Error(), "500") && strings.Contains(err.Error(), "backendError") {
-					log.Printf("%s ERROR getting operation status, iteration %d, wait 5 sec and retry %v", functionDeployment.Core.InstanceName, i, err)
-					time.Sleep(5 * time.Second)
-				} else {
-					return err
-				}
-			}
-		}
+			return err
+		})
 		if err != nil {
 			return err
 		}
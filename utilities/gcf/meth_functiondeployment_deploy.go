@@ -15,6 +15,7 @@
 package gcf
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -56,6 +57,15 @@ func (functionDeployment *FunctionDeployment) Deploy() (err error) {
 		return err
 	}
 	log.Printf("%s gcf function created or patched", functionDeployment.Core.InstanceName)
+	if functionDeployment.Artifacts.CloudFunction.HttpsTrigger != nil {
+		retreivedCloudFunction, err := functionDeployment.Artifacts.ProjectsLocationsFunctionsService.Get(functionDeployment.Artifacts.CloudFunction.Name).Context(functionDeployment.Core.Ctx).Do()
+		if err != nil {
+			return fmt.Errorf("ProjectsLocationsFunctionsService.Get %v", err)
+		}
+		if retreivedCloudFunction.HttpsTrigger != nil {
+			log.Printf("%s gcf https trigger url %s", functionDeployment.Core.InstanceName, retreivedCloudFunction.HttpsTrigger.Url)
+		}
+	}
 	err = os.Remove(functionDeployment.Artifacts.CloudFunctionZipFullPath)
 	if err != nil {
 		return err
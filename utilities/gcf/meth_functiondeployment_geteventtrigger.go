@@ -25,6 +25,10 @@ func (functionDeployment *FunctionDeployment) getEventTrigger() (eventTrigger *c
 	retry := cloudfunctions.Retry{}
 	failurePolicy.Retry = &retry
 
+	if functionDeployment.Settings.Instance.GCF.Filter != "" {
+		return eventTrigger, fmt.Errorf("filter is not supported by cloud functions triggers, set compute to run to use filter: %s", functionDeployment.Settings.Instance.GCF.Filter)
+	}
+
 	switch functionDeployment.Settings.Service.GCF.FunctionType {
 	case "backgroundPubSub":
 		var evtTrigger cloudfunctions.EventTrigger
@@ -40,6 +44,9 @@ func (functionDeployment *FunctionDeployment) getEventTrigger() (eventTrigger *c
 		evtTrigger.Service = "storage.googleapis.com"
 		evtTrigger.FailurePolicy = &failurePolicy
 		return &evtTrigger, nil
+	case "http":
+		// HTTP functions have an https trigger and no event trigger
+		return eventTrigger, nil
 	default:
 		return eventTrigger, fmt.Errorf("functionType provided not managed: %s", functionDeployment.Settings.Service.GCF.FunctionType)
 	}
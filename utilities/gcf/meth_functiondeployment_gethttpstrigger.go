@@ -0,0 +1,27 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcf
+
+import (
+	"google.golang.org/api/cloudfunctions/v1"
+)
+
+// getHTTPSTrigger HTTP functions have an https trigger, its URL being set by the cloud functions API, background functions have none
+func (functionDeployment *FunctionDeployment) getHTTPSTrigger() (httpsTrigger *cloudfunctions.HttpsTrigger) {
+	if functionDeployment.Settings.Service.GCF.FunctionType != "http" {
+		return nil
+	}
+	return &cloudfunctions.HttpsTrigger{}
+}
@@ -110,6 +110,51 @@ func init() {
 }
 `
 
+// httpFunctionGo function.go code skeleton, replace <serviceName> by serviceName
+const httpFunctionGo = `
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// generated code <timeStamp>
+
+// Package p contains an HTTP cloud function
+package p
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/BrunoReboul/ram/services/<serviceName>"
+)
+
+var global <serviceName>.Global
+var ctx = context.Background()
+
+// EntryPoint is the function to be executed for each cloud function occurence
+func EntryPoint(w http.ResponseWriter, r *http.Request) {
+	<serviceName>.EntryPoint(w, r, &global)
+}
+
+func init() {
+	err := <serviceName>.Initialize(ctx, &global)
+	if err != nil {
+		log.Fatalf("INIT_FAILURE %v", err)
+	}
+}
+`
+
 // makeFunctionGoContent craft the content of a cloud function function.go file for a RAM microservice instance
 func (functionDeployment *FunctionDeployment) makeFunctionGoContent() (functionGoContent string, err error) {
 	timeStamp := fmt.Sprintf("%s", time.Now())
@@ -120,6 +165,9 @@ func (functionDeployment *FunctionDeployment) makeFunctionGoContent() (functionG
 	case "backgroundGCS":
 		return strings.Replace(strings.Replace(backgroundGCSFunctionGo,
 			"<serviceName>", functionDeployment.Core.ServiceName, -1), "<timeStamp>", timeStamp, -1), nil
+	case "http":
+		return strings.Replace(strings.Replace(httpFunctionGo,
+			"<serviceName>", functionDeployment.Core.ServiceName, -1), "<timeStamp>", timeStamp, -1), nil
 	default:
 		return "", fmt.Errorf("functionType provided not managed: %s", functionDeployment.Settings.Service.GCF.FunctionType)
 	}
@@ -0,0 +1,56 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BrunoReboul/ram/utilities/deploy"
+)
+
+func TestUnitMakeFunctionGoContent(t *testing.T) {
+	var testCases = []struct {
+		functionType string
+		wantContains string
+		wantErr      bool
+	}{
+		{"backgroundPubSub", "func EntryPoint(ctxEvent context.Context, PubSubMessage gps.PubSubMessage) error {", false},
+		{"backgroundGCS", "func EntryPoint(ctxEvent context.Context, gcsEvent gcs.Event) error {", false},
+		{"http", "func EntryPoint(w http.ResponseWriter, r *http.Request) {", false},
+		{"blabla", "", true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.functionType, func(t *testing.T) {
+			t.Parallel()
+			functionDeployment := NewFunctionDeployment()
+			functionDeployment.Core = &deploy.Core{ServiceName: "myservice"}
+			functionDeployment.Settings.Service.GCF.FunctionType = tc.functionType
+			functionGoContent, err := functionDeployment.makeFunctionGoContent()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tc.wantErr)
+				return
+			}
+			if !strings.Contains(functionGoContent, tc.wantContains) {
+				t.Errorf("Want content containing %s", tc.wantContains)
+			}
+			if strings.Contains(functionGoContent, "<serviceName>") {
+				t.Errorf("Want <serviceName> replaced by myservice")
+			}
+		})
+	}
+}
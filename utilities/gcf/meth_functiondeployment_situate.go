@@ -37,6 +37,8 @@ func (functionDeployment *FunctionDeployment) situate() (err error) {
 	if err != nil {
 		return err
 	}
+	functionDeployment.Artifacts.CloudFunction.HttpsTrigger = functionDeployment.getHTTPSTrigger()
+	functionDeployment.Artifacts.CloudFunction.MaxInstances = functionDeployment.Settings.Service.GCF.MaxInstances
 	functionDeployment.Artifacts.CloudFunction.Labels = map[string]string{"name": strings.ToLower(functionDeployment.Core.InstanceName)}
 	functionDeployment.Artifacts.CloudFunction.Name = fmt.Sprintf("projects/%s/locations/%s/functions/%s",
 		functionDeployment.Core.SolutionSettings.Hosting.ProjectID,
@@ -47,7 +49,10 @@ func (functionDeployment *FunctionDeployment) situate() (err error) {
 		functionDeployment.Core.ServiceName,
 		functionDeployment.Core.SolutionSettings.Hosting.ProjectID)
 	functionDeployment.Artifacts.CloudFunction.Timeout = functionDeployment.Settings.Service.GCF.Timeout
-	functionDeployment.Artifacts.CloudFunction.IngressSettings = "ALLOW_ALL"
+	functionDeployment.Artifacts.CloudFunction.IngressSettings = functionDeployment.Settings.Service.GCF.IngressSettings
+	if functionDeployment.Artifacts.CloudFunction.IngressSettings == "" {
+		functionDeployment.Artifacts.CloudFunction.IngressSettings = "ALLOW_ALL"
+	}
 
 	if len(functionDeployment.Artifacts.ZipFiles) == 0 {
 		functionDeployment.Artifacts.ZipFiles = make(map[string]string)
@@ -16,6 +16,8 @@ package gcf
 
 // Event structure
 type Event struct {
-	TriggerTopic string `yaml:"triggerTopic,omitempty"`
-	BucketName   string `yaml:"bucketName,omitempty"`
+	TriggerTopic string `yaml:"triggerTopic,omitempty" valid:"isMatching,^[a-zA-Z][a-zA-Z0-9_.~+%-]{2,254}$"`
+	BucketName   string `yaml:"bucketName,omitempty" valid:"isMatching,^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$"`
+	// Filter is the Pub/Sub subscription filter on message attributes, only supported when compute is run, and not on cai-rces-* trigger topics
+	Filter string `yaml:"filter,omitempty" valid:"isMatching,^.{1,256}$"`
 }
@@ -23,9 +23,11 @@ import (
 type Parameters struct {
 	AvailableMemoryMb      int64 `yaml:"availableMemoryMb" valid:"isAvailableMemory"`
 	Description            string
-	FunctionType           string `yaml:"functionType"`
+	FunctionType           string `yaml:"functionType" valid:"isOneOf,backgroundPubSub,backgroundGCS,http"`
+	IngressSettings        string `yaml:"ingressSettings,omitempty" valid:"isOneOf,ALLOW_ALL,ALLOW_INTERNAL_ONLY,ALLOW_INTERNAL_AND_GCLB"`
+	MaxInstances           int64  `yaml:"maxInstances,omitempty"`
 	RetryTimeOutSeconds    int64  `yaml:"retryTimeOutSeconds"`
-	Timeout                string
+	Timeout                string `valid:"isDuration"`
 	ServiceAccountBindings struct {
 		GRM grm.Bindings
 		IAM iamgt.Bindings
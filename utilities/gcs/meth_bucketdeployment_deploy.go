@@ -42,7 +42,7 @@ func (bucketDeployment *BucketDeployment) Deploy() (err error) {
 	bucket := bucketDeployment.Core.Services.StorageClient.Bucket(bucketDeployment.Settings.BucketName)
 	retreivedAttrs, err := bucket.Attrs(bucketDeployment.Core.Ctx)
 	if err != nil {
-		if !strings.Contains(strings.ToLower(err.Error()), "doesn't exist") {
+		if err != storage.ErrBucketNotExist {
 			return fmt.Errorf("bucket.Attrs %v", err)
 		}
 		// Create
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/logging"
 )
 
@@ -49,7 +50,7 @@ func RecordDump(ctx context.Context,
 	for i = 0; i < retriesNumber; i++ {
 		_, err = firestoreClient.Doc(documentPath).Get(ctx)
 		if err != nil {
-			if strings.Contains(strings.ToLower(strings.Replace(err.Error(), " ", "", -1)), "notfound") {
+			if erm.IsNotFound(err) {
 				_, err = firestoreClient.Doc(documentPath).Set(ctx, map[string]interface{}{
 					"stepStack": stepStack,
 				})
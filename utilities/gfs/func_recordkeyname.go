@@ -17,11 +17,11 @@ package gfs
 import (
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/erm"
 )
 
 // RecordKeyName records the service account key name used by a deployed microservice instance
@@ -31,7 +31,7 @@ func RecordKeyName(core *deploy.Core, serviceAccountKeyName string, retriesNumbe
 	for i = 0; i < retriesNumber; i++ {
 		_, err = core.Services.FirestoreClient.Doc(documentPath).Get(core.Ctx)
 		if err != nil {
-			if strings.Contains(strings.ToLower(strings.Replace(err.Error(), " ", "", -1)), "notfound") {
+			if erm.IsNotFound(err) {
 				_, err = core.Services.FirestoreClient.Doc(documentPath).Set(core.Ctx, map[string]interface{}{
 					"serviceAccountKeyName": serviceAccountKeyName,
 				})
@@ -13,4 +13,10 @@
 // limitations under the License.
 
 // Package gps helps with Google Pubsub
+//
+// Messages published by RAM microservices carry standard attributes to filter and route them without parsing the data:
+// assetType, origin, deleted, ancestryPath, plus severity and ruleName on violations.
+// Messages published directly by Cloud Asset Inventory real-time feeds have no attributes,
+// a filter that must keep them tests the attribute presence, e.g.
+// NOT attributes:ancestryPath OR hasPrefix(attributes.ancestryPath, "organization/123/folder/456")
 package gps
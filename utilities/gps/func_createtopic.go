@@ -18,10 +18,10 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"regexp"
 	"strings"
 
 	pubsub "cloud.google.com/go/pubsub/apiv1"
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/str"
 	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
 )
@@ -47,8 +47,7 @@ func CreateTopic(ctx context.Context, pubSubPulisherClient *pubsub.PublisherClie
 
 	topic, err := pubSubPulisherClient.CreateTopic(ctx, &topicRequested)
 	if err != nil {
-		matched, _ := regexp.Match(`.*AlreadyExists.*`, []byte(err.Error()))
-		if !matched {
+		if !erm.IsAlreadyExists(err) {
 			return fmt.Errorf("pubSubPulisherClient.CreateTopic: %v", err)
 		}
 		log.Println("Try to create but already exist:", topicName)
@@ -0,0 +1,55 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gps
+
+import (
+	"encoding/json"
+
+	"github.com/BrunoReboul/ram/utilities/cai"
+)
+
+// GetFeedMessageAttributes returns the standard attributes of a feed message JSON document
+// Supports both the CAI feed format and the legacy CAI export format
+func GetFeedMessageAttributes(feedMessageJSON []byte) (attributesMap map[string]string) {
+	var feedMessage struct {
+		Asset struct {
+			AncestryPath       string   `json:"ancestryPath"`
+			AncestryPathLegacy string   `json:"ancestry_path"`
+			Ancestors          []string `json:"ancestors"`
+			AssetType          string   `json:"assetType"`
+			AssetTypeLegacy    string   `json:"asset_type"`
+		} `json:"asset"`
+		Deleted bool   `json:"deleted"`
+		Origin  string `json:"origin"`
+	}
+	var attributes Attributes
+	if err := json.Unmarshal(feedMessageJSON, &feedMessage); err != nil {
+		return attributes.ToMap()
+	}
+	attributes.AncestryPath = feedMessage.Asset.AncestryPath
+	if attributes.AncestryPath == "" {
+		attributes.AncestryPath = feedMessage.Asset.AncestryPathLegacy
+	}
+	if attributes.AncestryPath == "" && len(feedMessage.Asset.Ancestors) > 0 {
+		attributes.AncestryPath = cai.BuildAncestryPath(feedMessage.Asset.Ancestors)
+	}
+	attributes.AssetType = feedMessage.Asset.AssetType
+	if attributes.AssetType == "" {
+		attributes.AssetType = feedMessage.Asset.AssetTypeLegacy
+	}
+	attributes.Deleted = feedMessage.Deleted
+	attributes.Origin = feedMessage.Origin
+	return attributes.ToMap()
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnitGetFeedMessageAttributes(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		feedMessageJSON string
+		want            map[string]string
+	}{
+		{
+			name:            "cai_feed_format",
+			feedMessageJSON: `{"asset":{"name":"//compute.googleapis.com/projects/p/zones/z/instances/i","assetType":"compute.googleapis.com/Instance","ancestryPath":"organization/123/folder/456/project/789"},"deleted":true,"origin":"real-time"}`,
+			want: map[string]string{
+				"ancestryPath": "organization/123/folder/456/project/789",
+				"assetType":    "compute.googleapis.com/Instance",
+				"deleted":      "true",
+				"origin":       "real-time",
+			},
+		},
+		{
+			name:            "legacy_format_with_ancestors",
+			feedMessageJSON: `{"asset":{"asset_type":"compute.googleapis.com/Disk","ancestors":["projects/789","folders/456","organizations/123"]},"origin":"batch-export"}`,
+			want: map[string]string{
+				"ancestryPath": "organization/123/folder/456/project/789",
+				"assetType":    "compute.googleapis.com/Disk",
+				"deleted":      "false",
+				"origin":       "batch-export",
+			},
+		},
+		{
+			name:            "not_a_feed_message",
+			feedMessageJSON: `not json`,
+			want: map[string]string{
+				"deleted": "false",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := GetFeedMessageAttributes([]byte(tc.feedMessageJSON))
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("Want %v got %v", tc.want, got)
+			}
+		})
+	}
+}
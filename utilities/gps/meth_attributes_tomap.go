@@ -0,0 +1,38 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gps
+
+import "strconv"
+
+// ToMap returns the Pub/Sub message attributes map, empty values are omitted, too long values are truncated
+func (attributes Attributes) ToMap() (attributesMap map[string]string) {
+	attributesMap = make(map[string]string)
+	for key, value := range map[string]string{
+		AttributeAncestryPath: attributes.AncestryPath,
+		AttributeAssetType:    attributes.AssetType,
+		AttributeOrigin:       attributes.Origin,
+		AttributeRuleName:     attributes.RuleName,
+		AttributeSeverity:     attributes.Severity,
+	} {
+		if value != "" {
+			if len(value) > maxAttributeValueBytes {
+				value = value[:maxAttributeValueBytes]
+			}
+			attributesMap[key] = value
+		}
+	}
+	attributesMap[AttributeDeleted] = strconv.FormatBool(attributes.Deleted)
+	return attributesMap
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gps
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnitAttributesToMap(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		attributes Attributes
+		want       map[string]string
+	}{
+		{
+			name: "violation",
+			attributes: Attributes{
+				AssetType: "iam.googleapis.com/ServiceAccountKey",
+				Origin:    "real-time",
+				RuleName:  "monitor_iam_key_age",
+				Severity:  "high",
+			},
+			want: map[string]string{
+				"assetType": "iam.googleapis.com/ServiceAccountKey",
+				"deleted":   "false",
+				"origin":    "real-time",
+				"ruleName":  "monitor_iam_key_age",
+				"severity":  "high",
+			},
+		},
+		{
+			name:       "truncated",
+			attributes: Attributes{AncestryPath: strings.Repeat("a", 1100)},
+			want: map[string]string{
+				"ancestryPath": strings.Repeat("a", 1024),
+				"deleted":      "false",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := tc.attributes.ToMap()
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("Want %v got %v", tc.want, got)
+			}
+		})
+	}
+}
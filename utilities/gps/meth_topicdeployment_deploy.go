@@ -19,6 +19,8 @@ import (
 	"log"
 	"strings"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
+
 	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
 	"google.golang.org/genproto/protobuf/field_mask"
 )
@@ -35,7 +37,7 @@ func (topicDeployment *TopicDeployment) Deploy() (err error) {
 	nameLabelToBeUpdated := false
 	topic, err := topicDeployment.Core.Services.PubsubPublisherClient.GetTopic(topicDeployment.Core.Ctx, &getTopicRequest)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "notfound") {
+		if erm.IsNotFound(err) {
 			topicNotFound = true
 		} else {
 			return fmt.Errorf("topicDeployment.Core.Services.PubsubPublisherClient.GetTopic %s", err)
@@ -55,7 +57,7 @@ func (topicDeployment *TopicDeployment) Deploy() (err error) {
 		topicToCreate.Labels = map[string]string{"name": strings.ToLower(topicDeployment.Settings.TopicName)}
 		_, err = topicDeployment.Core.Services.PubsubPublisherClient.CreateTopic(topicDeployment.Core.Ctx, &topicToCreate)
 		if err != nil {
-			if !strings.Contains(strings.ToLower(err.Error()), "alreadyexists") {
+			if !erm.IsAlreadyExists(err) {
 				return fmt.Errorf("topicDeployment.Core.Services.PubsubPublisherClient.CreateTopic %s", err)
 			}
 			log.Printf("%s gps try to create topic but already exist %s", topicDeployment.Core.InstanceName, topicDeployment.Settings.TopicName)
@@ -17,7 +17,8 @@ package grm
 import (
 	"fmt"
 	"log"
-	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
 )
 
 // Deploy FolderDeployment for now, only check the folder exist and is ACTIVE: It does NOT create the folder.
@@ -27,7 +28,7 @@ func (folderDeployment *FolderDeployment) Deploy() (err error) {
 	folderName := fmt.Sprintf("folders/%s", folderDeployment.Core.SolutionSettings.Hosting.FolderID)
 	folder, err := foldersService.Get(folderName).Context(folderDeployment.Core.Ctx).Do()
 	if err != nil {
-		if strings.Contains(err.Error(), "403") {
+		if erm.IsPermissionDenied(err) {
 			log.Printf("%s grm WARNING impossible to GET folder %v", folderDeployment.Core.InstanceName, err)
 			return nil
 		}
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/str"
 	"google.golang.org/api/cloudresourcemanager/v1"
 )
@@ -43,7 +44,7 @@ func (orgBindingsDeployment *OrgBindingsDeployment) Deploy() (err error) {
 			getRequest.Options = &getPolicyOptions
 			policy, err = organizationsService.GetIamPolicy(fmt.Sprintf("organizations/%s", orgBindingsDeployment.Artifacts.OrganizationID), &getRequest).Context(orgBindingsDeployment.Core.Ctx).Do()
 			if err != nil {
-				if strings.Contains(err.Error(), "403") {
+				if erm.IsPermissionDenied(err) {
 					// To not stop on missing org permission, even if checking is not possible
 					log.Printf("%s grm WARNING impossible to check nor set organization iam policies due to insufficiant permissions on organization %s", orgBindingsDeployment.Core.InstanceName, orgBindingsDeployment.Artifacts.OrganizationID)
 					log.Printf("%s grm WARNING moving forward and assuming the required roles have been granted by another chanel on organization %s", orgBindingsDeployment.Core.InstanceName, orgBindingsDeployment.Artifacts.OrganizationID)
@@ -118,7 +119,7 @@ func (orgBindingsDeployment *OrgBindingsDeployment) Deploy() (err error) {
 				var updatedPolicy *cloudresourcemanager.Policy
 				updatedPolicy, err = organizationsService.SetIamPolicy(fmt.Sprintf("organizations/%s", orgBindingsDeployment.Artifacts.OrganizationID), &setRequest).Context(orgBindingsDeployment.Core.Ctx).Do()
 				if err != nil {
-					if !strings.Contains(err.Error(), "There were concurrent policy changes") {
+					if !erm.IsAborted(err) {
 						return fmt.Errorf("organizationsService.SetIamPolicy %v", err)
 					}
 					log.Printf("%s grm there were concurrent policy changes, wait 5 sec and retry a full read-modify-write cycle, iteration %d", orgBindingsDeployment.Core.InstanceName, i)
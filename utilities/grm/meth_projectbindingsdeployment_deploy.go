@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/str"
 	"google.golang.org/api/cloudresourcemanager/v1"
 )
@@ -40,7 +41,7 @@ func (projectBindingsDeployment *ProjectBindingsDeployment) Deploy() (err error)
 			getRequest.Options = &getPolicyOptions
 			policy, err = projectsService.GetIamPolicy(projectBindingsDeployment.Artifacts.ProjectID, &getRequest).Context(projectBindingsDeployment.Core.Ctx).Do()
 			if err != nil {
-				if strings.Contains(err.Error(), "403") {
+				if erm.IsPermissionDenied(err) {
 					log.Printf("%s grm WARNING impossible to GET project iam policy %v", projectBindingsDeployment.Core.InstanceName, err)
 					return nil
 				}
@@ -113,8 +114,8 @@ func (projectBindingsDeployment *ProjectBindingsDeployment) Deploy() (err error)
 				var updatedPolicy *cloudresourcemanager.Policy
 				updatedPolicy, err = projectsService.SetIamPolicy(projectBindingsDeployment.Artifacts.ProjectID, &setRequest).Context(projectBindingsDeployment.Core.Ctx).Do()
 				if err != nil {
-					if !strings.Contains(err.Error(), "There were concurrent policy changes") {
-						if strings.Contains(err.Error(), "403") {
+					if !erm.IsAborted(err) {
+						if erm.IsPermissionDenied(err) {
 							log.Printf("%s grm WARNING impossible to SET project iam policy %v", projectBindingsDeployment.Core.InstanceName, err)
 							return nil
 						}
@@ -17,9 +17,9 @@ package grm
 import (
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"google.golang.org/api/cloudresourcemanager/v1"
 )
@@ -32,7 +32,7 @@ func (projectDeployment *ProjectDeployment) Deploy() (err error) {
 	project, err := projectsService.Get(projectDeployment.Core.SolutionSettings.Hosting.ProjectID).Context(projectDeployment.Core.Ctx).Do()
 	if err != nil {
 		// When a project is not found the API returns 403 forbiden instead of 404 not found
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "403") {
+		if erm.IsNotFound(err) || erm.IsPermissionDenied(err) {
 			var parent cloudresourcemanager.ResourceId
 			parent.Type = "folder"
 			parent.Id = projectDeployment.Core.SolutionSettings.Hosting.FolderID
@@ -43,7 +43,7 @@ func (projectDeployment *ProjectDeployment) Deploy() (err error) {
 			projectToCreate.Labels = projectDeployment.Core.SolutionSettings.Hosting.ProjectLabels
 			operation, err := projectsService.Create(&projectToCreate).Context(projectDeployment.Core.Ctx).Do()
 			if err != nil {
-				if strings.Contains(err.Error(), "403") {
+				if erm.IsPermissionDenied(err) {
 					log.Printf("%s grm WARNING impossible to CREATE project %v", projectDeployment.Core.InstanceName, err)
 					return nil
 				}
@@ -54,17 +54,10 @@ func (projectDeployment *ProjectDeployment) Deploy() (err error) {
 			log.Println(operationName)
 			for {
 				time.Sleep(5 * time.Second)
-				for i := 0; i < Retries; i++ {
+				err = erm.Retry(Retries, 5*time.Second, 30*time.Second, func() (err error) {
 					operation, err = operationsService.Get(operationName).Context(projectDeployment.Core.Ctx).Do()
-					if err != nil {
-						if strings.Contains(err.Error(), "500") && strings.Contains(err.Error(), "backendError") {
-							log.Printf("%s ERROR getting operation status, iteration %d, wait 5 sec and retry %v", projectDeployment.Core.InstanceName, i, err)
-							time.Sleep(5 * time.Second)
-						} else {
-							return err
-						}
-					}
-				}
+					return err
+				})
 				if err != nil {
 					return err
 				}
@@ -17,7 +17,8 @@ package gsr
 import (
 	"fmt"
 	"log"
-	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
 
 	"google.golang.org/api/sourcerepo/v1"
 )
@@ -30,7 +31,7 @@ func (repoDeployment *RepoDeployment) Deploy() (err error) {
 	repoName := fmt.Sprintf("%s/repos/%s", projectName, repoDeployment.Core.SolutionSettings.Hosting.Repository.Name)
 	repo, err := projectsService.Repos.Get(repoName).Context(repoDeployment.Core.Ctx).Do()
 	if err != nil {
-		if strings.Contains(err.Error(), "404") && strings.Contains(err.Error(), "notFound") {
+		if erm.IsNotFound(err) {
 			var repoToCreate sourcerepo.Repo
 			repoToCreate.Name = repoName
 			repo, err = projectsService.Repos.Create(projectName, &repoToCreate).Context(repoDeployment.Core.Ctx).Do()
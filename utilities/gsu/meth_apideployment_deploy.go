@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/str"
 	"google.golang.org/api/serviceusage/v1"
 )
@@ -36,7 +37,7 @@ func (apiDeployment *APIDeployment) Deploy() (err error) {
 	// log.Println(parent)
 	err = apiDeployment.Artifacts.ServicesService.List(parent).Filter("state:ENABLED").PageSize(200).Pages(apiDeployment.Core.Ctx, browseActiveAPIs)
 	if err != nil {
-		if strings.Contains(err.Error(), "403") {
+		if erm.IsPermissionDenied(err) {
 			log.Printf("%s gsu WARNING impossible to LIST APIs %v", apiDeployment.Core.InstanceName, err)
 			return nil
 		}
@@ -12,5 +12,5 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package iamgt helps with Google Identity Access Management, aka Service Accounts and their roles bindings
+// Package iamgt helps with Google Identity Access Management, aka Service Accounts and their roles bindings, and cloud functions invokers
 package iamgt
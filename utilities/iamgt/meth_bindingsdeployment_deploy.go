@@ -17,9 +17,9 @@ package iamgt
 import (
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/str"
 	"google.golang.org/api/iam/v1"
 )
@@ -40,7 +40,7 @@ func (bindingsDeployment *BindingsDeployment) Deploy() (err error) {
 			var policy *iam.Policy
 			policy, err = projectsServiceAccountsService.GetIamPolicy(bindingsDeployment.Artifacts.ServiceAccountName).Context(bindingsDeployment.Core.Ctx).Do()
 			if err != nil {
-				if strings.Contains(err.Error(), "403") {
+				if erm.IsPermissionDenied(err) {
 					log.Printf("%s iam WARNING impossible to GET service account iam policy %v", bindingsDeployment.Core.InstanceName, err)
 					return nil
 				}
@@ -85,8 +85,8 @@ func (bindingsDeployment *BindingsDeployment) Deploy() (err error) {
 				var updatedPolicy *iam.Policy
 				updatedPolicy, err = projectsServiceAccountsService.SetIamPolicy(bindingsDeployment.Artifacts.ServiceAccountName, &setRequest).Context(bindingsDeployment.Core.Ctx).Do()
 				if err != nil {
-					if !strings.Contains(err.Error(), "There were concurrent policy changes") {
-						if strings.Contains(err.Error(), "403") {
+					if !erm.IsAborted(err) {
+						if erm.IsPermissionDenied(err) {
 							log.Printf("%s iam WARNING impossible to SET service account iam policy %v", bindingsDeployment.Core.InstanceName, err)
 							return nil
 						}
@@ -0,0 +1,89 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iamgt
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
+	"google.golang.org/api/cloudfunctions/v1"
+)
+
+// InvokerRole is the role granted to the members allowed to invoke an HTTP cloud function
+const InvokerRole = "roles/cloudfunctions.invoker"
+
+// Deploy InvokerBindingsDeployment sets the invoker members of a cloud function, authoritative for the invoker role
+// use retries on a read-modify-write cycle
+func (invokerBindingsDeployment *InvokerBindingsDeployment) Deploy() (err error) {
+	functionsService := invokerBindingsDeployment.Core.Services.CloudfunctionsService.Projects.Locations.Functions
+	functionName := invokerBindingsDeployment.Artifacts.FunctionName
+	wantMembers := append([]string{}, invokerBindingsDeployment.Settings.Instance.IAM.Invokers...)
+	sort.Strings(wantMembers)
+	for i := 0; i < Retries; i++ {
+		if i > 0 {
+			log.Printf("%s iam retrying a full read-modify-write cycle, iteration %d", invokerBindingsDeployment.Core.InstanceName, i)
+		}
+		// READ
+		var policy *cloudfunctions.Policy
+		policy, err = functionsService.GetIamPolicy(functionName).Context(invokerBindingsDeployment.Core.Ctx).Do()
+		if err != nil {
+			return fmt.Errorf("iam functionsService.GetIamPolicy %s", err)
+		}
+		// MODIFY
+		var haveMembers []string
+		bindings := make([]*cloudfunctions.Binding, 0)
+		for _, binding := range policy.Bindings {
+			if binding.Role == InvokerRole && binding.Condition == nil {
+				haveMembers = append(haveMembers, binding.Members...)
+			} else {
+				bindings = append(bindings, binding)
+			}
+		}
+		sort.Strings(haveMembers)
+		if strings.Join(haveMembers, ",") == strings.Join(wantMembers, ",") {
+			log.Printf("%s iam NO need to update invokers of cloud function %s", invokerBindingsDeployment.Core.InstanceName, functionName)
+			return nil
+		}
+		if invokerBindingsDeployment.Core.Commands.Check {
+			return fmt.Errorf("%s iam invalid invokers of cloud function %s\nwant %v\nhave %v", invokerBindingsDeployment.Core.InstanceName, functionName, wantMembers, haveMembers)
+		}
+		if len(wantMembers) > 0 {
+			bindings = append(bindings, &cloudfunctions.Binding{
+				Role:    InvokerRole,
+				Members: wantMembers,
+			})
+		}
+		policy.Bindings = bindings
+		// WRITE
+		var setRequest cloudfunctions.SetIamPolicyRequest
+		setRequest.Policy = policy
+		_, err = functionsService.SetIamPolicy(functionName, &setRequest).Context(invokerBindingsDeployment.Core.Ctx).Do()
+		if err != nil {
+			if !erm.IsAborted(err) {
+				return fmt.Errorf("iam functionsService.SetIamPolicy %s", err)
+			}
+			log.Printf("%s iam there were concurrent policy changes, wait 5 sec and retry a full read-modify-write cycle, iteration %d", invokerBindingsDeployment.Core.InstanceName, i)
+			time.Sleep(5 * time.Second)
+		} else {
+			log.Printf("%s iam invokers set to %v for cloud function %s iteration %d", invokerBindingsDeployment.Core.InstanceName, wantMembers, functionName, i)
+			break
+		}
+	}
+	return err
+}
@@ -17,7 +17,8 @@ package iamgt
 import (
 	"fmt"
 	"log"
-	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
 
 	"google.golang.org/api/iam/v1"
 )
@@ -33,7 +34,7 @@ func (orgRolesDeployment *OrgRolesDeployment) Deploy() (err error) {
 			orgRolesDeployment.Artifacts.OrganizationID, customRole.Title)
 		retreivedCustomRole, err := organizationsRolesService.Get(name).Context(orgRolesDeployment.Core.Ctx).Do()
 		if err != nil {
-			if strings.Contains(err.Error(), "404") && strings.Contains(err.Error(), "notFound") {
+			if erm.IsNotFound(err) {
 				parent := fmt.Sprintf("organizations/%s", orgRolesDeployment.Artifacts.OrganizationID)
 				var createRoleRequest iam.CreateRoleRequest
 				createRoleRequest.Role = &customRole
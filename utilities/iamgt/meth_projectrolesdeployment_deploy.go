@@ -17,7 +17,8 @@ package iamgt
 import (
 	"fmt"
 	"log"
-	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
 
 	"google.golang.org/api/iam/v1"
 )
@@ -31,7 +32,7 @@ func (projectRolesDeployment *ProjectRolesDeployment) Deploy() (err error) {
 			projectRolesDeployment.Artifacts.ProjectID, customRole.Title)
 		retreivedCustomRole, err := projectsRolesService.Get(name).Context(projectRolesDeployment.Core.Ctx).Do()
 		if err != nil {
-			if strings.Contains(err.Error(), "404") && strings.Contains(err.Error(), "notFound") {
+			if erm.IsNotFound(err) {
 				parent := fmt.Sprintf("projects/%s", projectRolesDeployment.Artifacts.ProjectID)
 				var createRoleRequest iam.CreateRoleRequest
 				createRoleRequest.RoleId = customRole.Title
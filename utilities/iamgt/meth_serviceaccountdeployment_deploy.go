@@ -17,7 +17,8 @@ package iamgt
 import (
 	"fmt"
 	"log"
-	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
 
 	"google.golang.org/api/iam/v1"
 )
@@ -30,7 +31,7 @@ func (serviceaccountDeployment *ServiceaccountDeployment) Deploy() (err error) {
 	projectServiceAccountService := serviceaccountDeployment.Core.Services.IAMService.Projects.ServiceAccounts
 	retreivedServiceAccount, err := projectServiceAccountService.Get(serviceAccountName).Context(serviceaccountDeployment.Core.Ctx).Do()
 	if err != nil {
-		if strings.Contains(err.Error(), "404") && strings.Contains(err.Error(), "notFound") {
+		if erm.IsNotFound(err) {
 			var serviceAccount iam.ServiceAccount
 			serviceAccount.DisplayName = fmt.Sprintf("RAM %s", serviceaccountDeployment.Core.ServiceName)
 			serviceAccount.Description = fmt.Sprintf("Solution: Real-time Asset Monitor, microservice: %s", serviceaccountDeployment.Core.ServiceName)
@@ -40,14 +41,14 @@ func (serviceaccountDeployment *ServiceaccountDeployment) Deploy() (err error) {
 			retreivedServiceAccount, err = projectServiceAccountService.Create(projectName, &request).Context(serviceaccountDeployment.Core.Ctx).Do()
 			if err != nil {
 				// deal with parallel deployments
-				if strings.Contains(err.Error(), "alreadyExists") {
+				if erm.IsAlreadyExists(err) {
 					retreivedServiceAccount, err = projectServiceAccountService.Get(serviceAccountName).Context(serviceaccountDeployment.Core.Ctx).Do()
 					if err != nil {
 						return err
 					}
 					log.Printf("%s iam eventually found service account %s", serviceaccountDeployment.Core.InstanceName, retreivedServiceAccount.Email)
 				} else {
-					if strings.Contains(err.Error(), "403") {
+					if erm.IsPermissionDenied(err) {
 						log.Printf("%s iam WARNING impossible to CREATE service account %v", serviceaccountDeployment.Core.InstanceName, err)
 						return nil
 					}
@@ -56,7 +57,7 @@ func (serviceaccountDeployment *ServiceaccountDeployment) Deploy() (err error) {
 			}
 			log.Printf("%s iam service account created %s", serviceaccountDeployment.Core.InstanceName, retreivedServiceAccount.Email)
 		} else {
-			if strings.Contains(err.Error(), "403") {
+			if erm.IsPermissionDenied(err) {
 				log.Printf("%s iam WARNING impossible to GET service account %v", serviceaccountDeployment.Core.InstanceName, err)
 				return nil
 			}
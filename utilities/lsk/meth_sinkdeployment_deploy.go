@@ -17,8 +17,8 @@ package lsk
 import (
 	"fmt"
 	"log"
-	"strings"
 
+	"github.com/BrunoReboul/ram/utilities/erm"
 	"github.com/BrunoReboul/ram/utilities/gps"
 
 	"cloud.google.com/go/logging/logadmin"
@@ -50,7 +50,7 @@ func (sinkDeployment *SinkDeployment) Deploy() (err error) {
 	sinkRetreived, err = logAdminClient.Sink(sinkDeployment.Core.Ctx, sink.ID)
 
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "notfound") {
+		if erm.IsNotFound(err) {
 			sinkFound = false
 		} else {
 			return fmt.Errorf("logAdminClient.Sink %v", err)
@@ -0,0 +1,27 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mca helps with multi cloud assets
+//
+// It transposes AWS Config snapshot configuration items and Azure Resource Graph rows into assets formated like Cloud Asset Inventory feed assets, so that RAM services process them like GCP assets.
+//
+// Asset types are prefixed by the cloud name: AWS::EC2::SecurityGroup becomes aws/ec2/SecurityGroup, microsoft.network/networksecuritygroups becomes azure/microsoft.network/networksecuritygroups.
+//
+// Ancestors are listed from the asset container to the root, like CAI does:
+// aws-accounts, aws-organizational-units and aws-organizations from the AWS Organizations entity paths provided in settings,
+// azure-subscriptions, azure-management-groups and azure-tenants from the subscriptions management group chain found in the export.
+// Constraints evaluating these assets target them with these ancestor types e.g. aws-organizations/o-a1b2c3d4e5/* instead of organization/*.
+//
+// Tags are copied to resource.data.labels so that owner and violation resolver label keys work across clouds.
+package mca
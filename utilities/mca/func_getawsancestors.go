@@ -0,0 +1,39 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import "strings"
+
+// GetAWSAncestors returns the ancestors of an account from AWS Organizations entity paths e.g. o-a1b2c3d4e5/r-ab12/ou-ab12-11111111/111111111111/
+// The root is skipped as an organization has only one, an account without entity path has only itself as ancestor
+func GetAWSAncestors(accountID string, entityPaths []string) (ancestors []string) {
+	ancestors = []string{"aws-accounts/" + accountID}
+	for _, entityPath := range entityPaths {
+		parts := strings.Split(strings.Trim(entityPath, "/"), "/")
+		if parts[len(parts)-1] != accountID {
+			continue
+		}
+		for i := len(parts) - 2; i >= 0; i-- {
+			switch {
+			case strings.HasPrefix(parts[i], "ou-"):
+				ancestors = append(ancestors, "aws-organizational-units/"+parts[i])
+			case strings.HasPrefix(parts[i], "o-"):
+				ancestors = append(ancestors, "aws-organizations/"+parts[i])
+			}
+		}
+		break
+	}
+	return ancestors
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnitGetAWSAncestors(t *testing.T) {
+	entityPaths := []string{
+		"o-a1b2c3d4e5/r-ab12/ou-ab12-11111111/ou-ab12-22222222/111111111111/",
+		"o-a1b2c3d4e5/r-ab12/222222222222"}
+	var testCases = []struct {
+		name          string
+		accountID     string
+		wantAncestors []string
+	}{
+		{"nestedOUs", "111111111111", []string{"aws-accounts/111111111111", "aws-organizational-units/ou-ab12-22222222", "aws-organizational-units/ou-ab12-11111111", "aws-organizations/o-a1b2c3d4e5"}},
+		{"underRoot", "222222222222", []string{"aws-accounts/222222222222", "aws-organizations/o-a1b2c3d4e5"}},
+		{"unknown", "333333333333", []string{"aws-accounts/333333333333"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := GetAWSAncestors(tc.accountID, entityPaths)
+			if !reflect.DeepEqual(got, tc.wantAncestors) {
+				t.Errorf("Want %v got %v", tc.wantAncestors, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import "strings"
+
+// GetAWSAssetType converts an AWS Config resource type, e.g. AWS::EC2::SecurityGroup to aws/ec2/SecurityGroup
+func GetAWSAssetType(resourceType string) string {
+	parts := strings.Split(resourceType, "::")
+	if len(parts) != 3 {
+		return "aws/" + strings.ToLower(strings.Replace(resourceType, "::", "/", -1))
+	}
+	return "aws/" + strings.ToLower(parts[1]) + "/" + parts[2]
+}
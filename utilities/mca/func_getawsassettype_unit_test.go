@@ -0,0 +1,41 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import (
+	"testing"
+)
+
+func TestUnitGetAWSAssetType(t *testing.T) {
+	var testCases = []struct {
+		resourceType string
+		want         string
+	}{
+		{"AWS::EC2::SecurityGroup", "aws/ec2/SecurityGroup"},
+		{"AWS::S3::Bucket", "aws/s3/Bucket"},
+		{"AWS::IAM::Role", "aws/iam/Role"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.resourceType, func(t *testing.T) {
+			t.Parallel()
+			got := GetAWSAssetType(tc.resourceType)
+			if tc.want != got {
+				t.Errorf("Want %s got %s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GetAWSConfigAssets transposes the configuration items of an AWS Config snapshot into assets, items of deleted or not recorded resources are ignored
+func GetAWSConfigAssets(snapshotJSON []byte, entityPaths []string) (assets []Asset, ignoredNumber int, err error) {
+	var snapshot awsConfigSnapshot
+	if err = json.Unmarshal(snapshotJSON, &snapshot); err != nil {
+		return nil, 0, fmt.Errorf("json.Unmarshal snapshot %v", err)
+	}
+	if snapshot.FileVersion == "" && snapshot.ConfigurationItems == nil {
+		return nil, 0, fmt.Errorf("not an AWS Config snapshot, missing fileVersion and configurationItems")
+	}
+	for _, item := range snapshot.ConfigurationItems {
+		switch item.ConfigurationItemStatus {
+		case "ResourceDeleted", "ResourceDeletedNotRecorded", "ResourceNotRecorded":
+			ignoredNumber++
+			continue
+		}
+		asset, err := transposeAWSConfigurationItem(item, entityPaths)
+		if err != nil {
+			return nil, ignoredNumber, fmt.Errorf("%s %s %v", item.ResourceType, item.ResourceID, err)
+		}
+		assets = append(assets, asset)
+	}
+	return assets, ignoredNumber, nil
+}
+
+func transposeAWSConfigurationItem(item awsConfigurationItem, entityPaths []string) (asset Asset, err error) {
+	data := make(map[string]interface{})
+	if len(item.Configuration) > 0 {
+		var configuration interface{}
+		if err = json.Unmarshal(item.Configuration, &configuration); err != nil {
+			return asset, fmt.Errorf("json.Unmarshal configuration %v", err)
+		}
+		// some resource types deliver the configuration as an escaped JSON string
+		if s, ok := configuration.(string); ok {
+			if err = json.Unmarshal([]byte(s), &configuration); err != nil {
+				return asset, fmt.Errorf("json.Unmarshal configuration string %v", err)
+			}
+		}
+		if m, ok := configuration.(map[string]interface{}); ok {
+			data = m
+		}
+	}
+	if len(item.SupplementaryConfiguration) > 0 {
+		if _, ok := data["supplementaryConfiguration"]; !ok {
+			data["supplementaryConfiguration"] = item.SupplementaryConfiguration
+		}
+	}
+	setLabels(data, item.Tags)
+
+	asset.AssetType = GetAWSAssetType(item.ResourceType)
+	asset.Name = item.ARN
+	if asset.Name == "" {
+		asset.Name = fmt.Sprintf("aws://%s/%s/%s/%s", item.AWSAccountID, item.AWSRegion, asset.AssetType, item.ResourceID)
+	}
+	asset.Ancestors = GetAWSAncestors(item.AWSAccountID, entityPaths)
+	asset.Resource = &Resource{
+		Data:     data,
+		Location: item.AWSRegion,
+	}
+	return asset, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestUnitGetAWSConfigAssets(t *testing.T) {
+	snapshotJSON, err := ioutil.ReadFile("testdata/aws_config_snapshot.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets, ignoredNumber, err := GetAWSConfigAssets(snapshotJSON, []string{"o-a1b2c3d4e5/r-ab12/ou-ab12-11111111/111111111111/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ignoredNumber != 1 {
+		t.Errorf("Want 1 ignored deleted item got %d", ignoredNumber)
+	}
+	if len(assets) != 2 {
+		t.Fatalf("Want 2 assets got %d", len(assets))
+	}
+
+	var testCases = []struct {
+		name          string
+		asset         Asset
+		wantName      string
+		wantAssetType string
+		wantData      string
+	}{
+		{
+			name:          "securityGroup",
+			asset:         assets[0],
+			wantName:      "arn:aws:ec2:eu-west-1:111111111111:security-group/sg-0a1b2c3d4e5f67890",
+			wantAssetType: "aws/ec2/SecurityGroup",
+			wantData:      `{"groupId":"sg-0a1b2c3d4e5f67890","groupName":"ssh","ipPermissions":[{"fromPort":22,"ipProtocol":"tcp","ipRanges":["0.0.0.0/0"],"toPort":22}],"labels":{"owner":"team-a"}}`,
+		},
+		{
+			name:          "bucketConfigurationString",
+			asset:         assets[1],
+			wantName:      "arn:aws:s3:::logs-bucket",
+			wantAssetType: "aws/s3/Bucket",
+			wantData:      `{"name":"logs-bucket","owner":{"id":"abc"},"supplementaryConfiguration":{"PublicAccessBlockConfiguration":{"blockPublicAcls":true,"blockPublicPolicy":true}}}`,
+		},
+	}
+	wantAncestors := []string{"aws-accounts/111111111111", "aws-organizational-units/ou-ab12-11111111", "aws-organizations/o-a1b2c3d4e5"}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if tc.asset.Name != tc.wantName {
+				t.Errorf("Want name %s got %s", tc.wantName, tc.asset.Name)
+			}
+			if tc.asset.AssetType != tc.wantAssetType {
+				t.Errorf("Want assetType %s got %s", tc.wantAssetType, tc.asset.AssetType)
+			}
+			if !reflect.DeepEqual(tc.asset.Ancestors, wantAncestors) {
+				t.Errorf("Want ancestors %v got %v", wantAncestors, tc.asset.Ancestors)
+			}
+			if tc.asset.Resource.Location != "eu-west-1" {
+				t.Errorf("Want location eu-west-1 got %s", tc.asset.Resource.Location)
+			}
+			data, _ := json.Marshal(tc.asset.Resource.Data)
+			if string(data) != tc.wantData {
+				t.Errorf("Want data %s got %s", tc.wantData, string(data))
+			}
+		})
+	}
+}
+
+func TestUnitGetAWSConfigAssetsNotASnapshot(t *testing.T) {
+	if _, _, err := GetAWSConfigAssets([]byte(`{"foo":"bar"}`), nil); err == nil {
+		t.Errorf("Want an error on a JSON document that is not a snapshot")
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+// getAzureAncestors returns the ancestors of a subscription from its management group chain, listed from parent to root
+// The tenant root group is named after the tenant ID
+func getAzureAncestors(subscriptionID string, tenantID string, chains map[string][]string) (ancestors []string) {
+	if subscriptionID == "" {
+		if tenantID == "" {
+			return nil
+		}
+		return []string{"azure-tenants/" + tenantID}
+	}
+	ancestors = []string{"azure-subscriptions/" + subscriptionID}
+	chain, ok := chains[subscriptionID]
+	if !ok {
+		if tenantID != "" {
+			ancestors = append(ancestors, "azure-tenants/"+tenantID)
+		}
+		return ancestors
+	}
+	for _, managementGroupName := range chain {
+		if managementGroupName == tenantID {
+			ancestors = append(ancestors, "azure-tenants/"+tenantID)
+		} else {
+			ancestors = append(ancestors, "azure-management-groups/"+managementGroupName)
+		}
+	}
+	return ancestors
+}
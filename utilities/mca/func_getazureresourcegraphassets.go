@@ -0,0 +1,109 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// GetAzureResourceGraphAssets transposes an Azure Resource Graph export into assets
+// The export is a JSON array of rows, an az graph query output with a data array, or JSON lines
+// Subscription rows from the resourcecontainers table provide the management group ancestors of the subscription resources
+func GetAzureResourceGraphAssets(exportJSON []byte) (assets []Asset, ignoredNumber int, err error) {
+	rows, err := getAzureRows(exportJSON)
+	if err != nil {
+		return nil, 0, err
+	}
+	resources := make([]azureResource, len(rows))
+	chains := make(map[string][]string)
+	for i, row := range rows {
+		if err = json.Unmarshal(row, &resources[i]); err != nil {
+			return nil, 0, fmt.Errorf("json.Unmarshal row %d %v", i, err)
+		}
+		if len(resources[i].Properties.ManagementGroupAncestorsChain) > 0 && resources[i].SubscriptionID != "" {
+			var chain []string
+			for _, managementGroup := range resources[i].Properties.ManagementGroupAncestorsChain {
+				chain = append(chain, managementGroup.Name)
+			}
+			chains[resources[i].SubscriptionID] = chain
+		}
+	}
+	for i, resource := range resources {
+		if resource.ID == "" || resource.Type == "" {
+			ignoredNumber++
+			continue
+		}
+		var data map[string]interface{}
+		if err = json.Unmarshal(rows[i], &data); err != nil {
+			return nil, ignoredNumber, fmt.Errorf("json.Unmarshal row %d %v", i, err)
+		}
+		setLabels(data, resource.Tags)
+		assets = append(assets, Asset{
+			Name:      resource.ID,
+			AssetType: GetAzureAssetType(resource.Type),
+			Ancestors: getAzureAncestors(resource.SubscriptionID, resource.TenantID, chains),
+			Resource: &Resource{
+				Data:     data,
+				Location: resource.Location,
+			},
+		})
+	}
+	return assets, ignoredNumber, nil
+}
+
+// getAzureRows returns the rows of the export whatever its layout
+func getAzureRows(exportJSON []byte) (rows []json.RawMessage, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(exportJSON))
+	for {
+		var value json.RawMessage
+		err = decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoder.Decode %v", err)
+		}
+		value = bytes.TrimSpace(value)
+		if len(value) == 0 {
+			continue
+		}
+		switch value[0] {
+		case '[':
+			var values []json.RawMessage
+			if err = json.Unmarshal(value, &values); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal array %v", err)
+			}
+			rows = append(rows, values...)
+		case '{':
+			var queryOutput struct {
+				Data []json.RawMessage `json:"data"`
+			}
+			if err = json.Unmarshal(value, &queryOutput); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal object %v", err)
+			}
+			if queryOutput.Data != nil {
+				rows = append(rows, queryOutput.Data...)
+			} else {
+				rows = append(rows, value)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected JSON value, expecting arrays or objects")
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestUnitGetAzureResourceGraphAssets(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		exportJSON    string
+		wantAncestors [][]string
+	}{
+		{
+			name:       "array",
+			exportJSON: `[{"id":"/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Compute/disks/d1","type":"microsoft.compute/disks","tenantId":"t1","subscriptionId":"s1"}]`,
+			wantAncestors: [][]string{
+				{"azure-subscriptions/s1", "azure-tenants/t1"}},
+		},
+		{
+			name: "jsonLines",
+			exportJSON: `{"id":"/subscriptions/s1","type":"microsoft.resources/subscriptions","tenantId":"t1","subscriptionId":"s1","properties":{"managementGroupAncestorsChain":[{"name":"mg1"},{"name":"t1"}]}}
+{"id":"/subscriptions/s1/resourceGroups/rg/providers/Microsoft.Compute/disks/d1","type":"microsoft.compute/disks","tenantId":"t1","subscriptionId":"s1"}
+{"name":"no id"}`,
+			wantAncestors: [][]string{
+				{"azure-subscriptions/s1", "azure-management-groups/mg1", "azure-tenants/t1"},
+				{"azure-subscriptions/s1", "azure-management-groups/mg1", "azure-tenants/t1"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assets, _, err := GetAzureResourceGraphAssets([]byte(tc.exportJSON))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(assets) != len(tc.wantAncestors) {
+				t.Fatalf("Want %d assets got %d", len(tc.wantAncestors), len(assets))
+			}
+			for i, asset := range assets {
+				if !reflect.DeepEqual(asset.Ancestors, tc.wantAncestors[i]) {
+					t.Errorf("Want ancestors %v got %v", tc.wantAncestors[i], asset.Ancestors)
+				}
+			}
+		})
+	}
+}
+
+func TestUnitGetAzureResourceGraphAssetsQueryOutput(t *testing.T) {
+	exportJSON, err := ioutil.ReadFile("testdata/azure_resource_graph.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets, ignoredNumber, err := GetAzureResourceGraphAssets(exportJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ignoredNumber != 0 {
+		t.Errorf("Want 0 ignored rows got %d", ignoredNumber)
+	}
+	if len(assets) != 3 {
+		t.Fatalf("Want 3 assets got %d", len(assets))
+	}
+	nsg := assets[1]
+	if nsg.AssetType != "azure/microsoft.network/networksecuritygroups" {
+		t.Errorf("Want assetType azure/microsoft.network/networksecuritygroups got %s", nsg.AssetType)
+	}
+	wantAncestors := []string{
+		"azure-subscriptions/00000000-0000-0000-0000-000000000001",
+		"azure-management-groups/platform",
+		"azure-tenants/11111111-1111-1111-1111-111111111111"}
+	if !reflect.DeepEqual(nsg.Ancestors, wantAncestors) {
+		t.Errorf("Want ancestors %v got %v", wantAncestors, nsg.Ancestors)
+	}
+	if labels, ok := nsg.Resource.Data["labels"].(map[string]interface{}); !ok || labels["owner"] != "team-b" {
+		t.Errorf("Want labels copied from tags got %v", nsg.Resource.Data["labels"])
+	}
+	if nsg.Resource.Location != "westeurope" {
+		t.Errorf("Want location westeurope got %s", nsg.Resource.Location)
+	}
+	wantAncestors = []string{
+		"azure-subscriptions/00000000-0000-0000-0000-000000000002",
+		"azure-tenants/11111111-1111-1111-1111-111111111111"}
+	if !reflect.DeepEqual(assets[2].Ancestors, wantAncestors) {
+		t.Errorf("Want ancestors %v got %v", wantAncestors, assets[2].Ancestors)
+	}
+}
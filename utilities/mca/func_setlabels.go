@@ -0,0 +1,30 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+// setLabels copies string tags to data.labels unless the resource already has labels
+func setLabels(data map[string]interface{}, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	if _, ok := data["labels"]; ok {
+		return
+	}
+	labels := make(map[string]interface{})
+	for key, value := range tags {
+		labels[key] = value
+	}
+	data["labels"] = labels
+}
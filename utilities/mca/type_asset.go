@@ -0,0 +1,29 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+// Asset uses the CAI feed format
+type Asset struct {
+	Name      string    `json:"name"`
+	AssetType string    `json:"assetType"`
+	Ancestors []string  `json:"ancestors"`
+	Resource  *Resource `json:"resource"`
+}
+
+// Resource CAI feed like resource
+type Resource struct {
+	Data     map[string]interface{} `json:"data"`
+	Location string                 `json:"location,omitempty"`
+}
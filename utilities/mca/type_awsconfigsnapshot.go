@@ -0,0 +1,39 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mca
+
+import "encoding/json"
+
+// awsConfigSnapshot AWS Config snapshot file delivered to S3, then copied to GCS
+type awsConfigSnapshot struct {
+	FileVersion        string                 `json:"fileVersion"`
+	ConfigSnapshotID   string                 `json:"configSnapshotId"`
+	ConfigurationItems []awsConfigurationItem `json:"configurationItems"`
+}
+
+// awsConfigurationItem one recorded resource
+type awsConfigurationItem struct {
+	ConfigurationItemCaptureTime string                 `json:"configurationItemCaptureTime"`
+	ConfigurationItemStatus      string                 `json:"configurationItemStatus"`
+	AWSAccountID                 string                 `json:"awsAccountId"`
+	ResourceType                 string                 `json:"resourceType"`
+	ResourceID                   string                 `json:"resourceId"`
+	ResourceName                 string                 `json:"resourceName"`
+	ARN                          string                 `json:"ARN"`
+	AWSRegion                    string                 `json:"awsRegion"`
+	Configuration                json.RawMessage        `json:"configuration"`
+	SupplementaryConfiguration   map[string]interface{} `json:"supplementaryConfiguration"`
+	Tags                         map[string]string      `json:"tags"`
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+// Log-based metric short names, suffixes of ram_<microserviceName>_
+const (
+	logMetricErrors          = "errors"
+	logMetricInitFailures    = "init_failures"
+	logMetricLatency         = "latency_seconds"
+	logMetricLatencyE2E      = "latency_e2e_seconds"
+	logMetricStarts          = "starts"
+	logMetricViolations      = "violations"
+	userLogMetricTypePrefix  = "logging.googleapis.com/user/"
+	alertAlignmentPeriod     = "300s"
+	alertMicroserviceLabel   = "ram_microservice"
+	instanceNameLabelKey     = "instance_name"
+	instanceNameLabelExtract = "EXTRACT(jsonPayload.instance_name)"
+)
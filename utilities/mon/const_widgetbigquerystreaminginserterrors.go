@@ -0,0 +1,50 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+const widgetBigQueryStreamingInsertErrors = `
+{
+	"title": "BigQuery streaming insert errors",
+	"xyChart": {
+	  "chartOptions": {
+		"mode": "COLOR"
+	  },
+	  "dataSets": [
+		{
+		  "minAlignmentPeriod": "60s",
+		  "plotType": "STACKED_BAR",
+		  "timeSeriesQuery": {
+			"timeSeriesFilter": {
+			  "aggregation": {
+				"crossSeriesReducer": "REDUCE_SUM",
+				"groupByFields": [
+				  "metric.label.\"response_code\""
+				],
+				"perSeriesAligner": "ALIGN_RATE"
+			  },
+			  "filter": "metric.type=\"serviceruntime.googleapis.com/api/request_count\" resource.type=\"consumed_api\" resource.label.\"service\"=\"bigquery.googleapis.com\" resource.label.\"method\"=\"google.cloud.bigquery.v2.TableDataService.InsertAll\" metric.label.\"response_code_class\"!=\"2xx\"",
+			  "secondaryAggregation": {}
+			}
+		  }
+		}
+	  ],
+	  "timeshiftDuration": "0s",
+	  "yAxis": {
+		"label": "y1Axis",
+		"scale": "LINEAR"
+	  }
+	}
+}
+`
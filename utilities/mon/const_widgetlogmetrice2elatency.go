@@ -0,0 +1,61 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+const widgetLogMetricE2ELatency = `
+{
+	"title": "{{.microserviceName}} end to end latency",
+	"xyChart": {
+	  "chartOptions": {
+		"mode": "COLOR"
+	  },
+	  "dataSets": [
+		{
+		  "minAlignmentPeriod": "60s",
+		  "plotType": "LINE",
+		  "timeSeriesQuery": {
+			"timeSeriesFilter": {
+			  "aggregation": {
+				"crossSeriesReducer": "REDUCE_MAX",
+				"perSeriesAligner": "ALIGN_PERCENTILE_95"
+			  },
+			  "filter": "metric.type=\"logging.googleapis.com/user/ram_{{.microserviceName}}_latency_e2e_seconds\"",
+			  "secondaryAggregation": {}
+			}
+		  }
+		},
+		{
+		  "minAlignmentPeriod": "60s",
+		  "plotType": "LINE",
+		  "timeSeriesQuery": {
+			"timeSeriesFilter": {
+			  "aggregation": {
+				"crossSeriesReducer": "REDUCE_MAX",
+				"perSeriesAligner": "ALIGN_PERCENTILE_50"
+			  },
+			  "filter": "metric.type=\"logging.googleapis.com/user/ram_{{.microserviceName}}_latency_e2e_seconds\"",
+			  "secondaryAggregation": {}
+			}
+		  }
+		}
+	  ],
+	  "timeshiftDuration": "0s",
+	  "yAxis": {
+		"label": "y1Axis",
+		"scale": "LINEAR"
+	  }
+	}
+}
+`
@@ -0,0 +1,50 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+const widgetPubSubBacklog = `
+{
+	"title": "{{.topicName}} backlog",
+	"xyChart": {
+	  "chartOptions": {
+		"mode": "COLOR"
+	  },
+	  "dataSets": [
+		{
+		  "minAlignmentPeriod": "60s",
+		  "plotType": "LINE",
+		  "timeSeriesQuery": {
+			"timeSeriesFilter": {
+			  "aggregation": {
+				"crossSeriesReducer": "REDUCE_SUM",
+				"groupByFields": [
+				  "resource.label.\"subscription_id\""
+				],
+				"perSeriesAligner": "ALIGN_MEAN"
+			  },
+			  "filter": "metric.type=\"pubsub.googleapis.com/subscription/num_undelivered_messages\" resource.type=\"pubsub_subscription\" resource.label.\"subscription_id\"=monitoring.regex.full_match(\".*{{.topicName}}.*\")",
+			  "secondaryAggregation": {}
+			}
+		  }
+		}
+	  ],
+	  "timeshiftDuration": "0s",
+	  "yAxis": {
+		"label": "y1Axis",
+		"scale": "LINEAR"
+	  }
+	}
+}
+`
@@ -12,5 +12,5 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package mon helps cloud monitoring
+// Package mon helps cloud monitoring: dashboards, log-based metrics and alert policies
 package mon
@@ -0,0 +1,100 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"fmt"
+
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+)
+
+// GetAlertPolicies returns the error rate, end to end latency and init failures alert policies of a microservice
+func GetAlertPolicies(microserviceName string, alerting AlertingParameters) (alertPolicies []*monitoringv3.AlertPolicy, err error) {
+	if microserviceName == "" {
+		return alertPolicies, fmt.Errorf("microserviceName can NOT be a zero value")
+	}
+	sumByInstance := []*monitoringv3.Aggregation{
+		{
+			AlignmentPeriod:    alertAlignmentPeriod,
+			CrossSeriesReducer: "REDUCE_SUM",
+			GroupByFields:      []string{"metric.label." + instanceNameLabelKey},
+			PerSeriesAligner:   "ALIGN_DELTA",
+		},
+	}
+
+	alertPolicies = append(alertPolicies, makeAlertPolicy(microserviceName, "error rate", alerting,
+		fmt.Sprintf("More than %d%% of %s executions end in error, see noretry, redo_on_transient, dropped and dead_letter_failed log entries.", alerting.ErrorRatePercent, microserviceName),
+		&monitoringv3.MetricThreshold{
+			Filter:                  getUserLogMetricFilter(microserviceName, logMetricErrors),
+			Aggregations:            sumByInstance,
+			DenominatorFilter:       getUserLogMetricFilter(microserviceName, logMetricStarts),
+			DenominatorAggregations: sumByInstance,
+			Comparison:              "COMPARISON_GT",
+			ThresholdValue:          float64(alerting.ErrorRatePercent) / 100,
+			Duration:                "0s",
+			Trigger:                 &monitoringv3.Trigger{Count: 1},
+		}))
+	alertPolicies = append(alertPolicies, makeAlertPolicy(microserviceName, "e2e latency p95", alerting,
+		fmt.Sprintf("The 95th percentile of %s end to end latency, from the origin event to the end of the execution, is over %d seconds.", microserviceName, alerting.LatencyE2EP95Seconds),
+		&monitoringv3.MetricThreshold{
+			Filter: getUserLogMetricFilter(microserviceName, logMetricLatencyE2E),
+			Aggregations: []*monitoringv3.Aggregation{
+				{
+					AlignmentPeriod:  alertAlignmentPeriod,
+					PerSeriesAligner: "ALIGN_PERCENTILE_95",
+				},
+			},
+			Comparison:     "COMPARISON_GT",
+			ThresholdValue: float64(alerting.LatencyE2EP95Seconds),
+			Duration:       alertAlignmentPeriod,
+			Trigger:        &monitoringv3.Trigger{Count: 1},
+		}))
+	alertPolicies = append(alertPolicies, makeAlertPolicy(microserviceName, "init failures", alerting,
+		fmt.Sprintf("More than %d %s initializations failed, see init_failed log entries.", alerting.InitFailures, microserviceName),
+		&monitoringv3.MetricThreshold{
+			Filter:         getUserLogMetricFilter(microserviceName, logMetricInitFailures),
+			Aggregations:   sumByInstance,
+			Comparison:     "COMPARISON_GT",
+			ThresholdValue: float64(alerting.InitFailures),
+			Duration:       "0s",
+			Trigger:        &monitoringv3.Trigger{Count: 1},
+		}))
+	return alertPolicies, nil
+}
+
+func getUserLogMetricFilter(microserviceName string, logMetricShortName string) string {
+	return fmt.Sprintf("metric.type=\"%s%s\"", userLogMetricTypePrefix, GetLogMetricName(microserviceName, logMetricShortName))
+}
+
+func makeAlertPolicy(microserviceName string, title string, alerting AlertingParameters, documentation string, metricThreshold *monitoringv3.MetricThreshold) *monitoringv3.AlertPolicy {
+	displayName := fmt.Sprintf("RAM %s %s", microserviceName, title)
+	return &monitoringv3.AlertPolicy{
+		DisplayName: displayName,
+		Combiner:    "OR",
+		Conditions: []*monitoringv3.Condition{
+			{
+				DisplayName:        displayName,
+				ConditionThreshold: metricThreshold,
+			},
+		},
+		Documentation: &monitoringv3.Documentation{
+			Content:  documentation,
+			MimeType: "text/markdown",
+		},
+		Enabled:              true,
+		NotificationChannels: alerting.NotificationChannels,
+		UserLabels:           map[string]string{alertMicroserviceLabel: microserviceName},
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitGetAlertPolicies(t *testing.T) {
+	tests := []struct {
+		name             string
+		microserviceName string
+		alerting         AlertingParameters
+		wantCount        int
+		wantErr          bool
+	}{
+		{
+			name:    "missingMicroserviceName",
+			wantErr: true,
+		},
+		{
+			name:             "stream2bq",
+			microserviceName: "stream2bq",
+			alerting: AlertingParameters{
+				ErrorRatePercent:     5,
+				LatencyE2EP95Seconds: 600,
+				NotificationChannels: []string{"projects/qwerty/notificationChannels/123"},
+			},
+			wantCount: 3,
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			alertPolicies, err := GetAlertPolicies(tt.microserviceName, tt.alerting)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(alertPolicies) != tt.wantCount {
+				t.Errorf("Want %d alert policies got %d", tt.wantCount, len(alertPolicies))
+			}
+			for _, alertPolicy := range alertPolicies {
+				if !strings.Contains(alertPolicy.DisplayName, tt.microserviceName) {
+					t.Errorf("microserviceName = %s is not contained in DisplayName %s", tt.microserviceName, alertPolicy.DisplayName)
+				}
+				if len(alertPolicy.NotificationChannels) != len(tt.alerting.NotificationChannels) {
+					t.Errorf("Want %d notification channels got %d", len(tt.alerting.NotificationChannels), len(alertPolicy.NotificationChannels))
+				}
+				for _, condition := range alertPolicy.Conditions {
+					if !strings.Contains(condition.ConditionThreshold.Filter, GetLogMetricName(tt.microserviceName, "")) {
+						t.Errorf("microserviceName = %s is not contained in Filter %s", tt.microserviceName, condition.ConditionThreshold.Filter)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+)
+
+// getAlertPolicyDiff compares the alert policy fields set by RAM, normalized as the API may return them in another form, e.g. 300s or 5m, returns an empty string when up-to-date
+func getAlertPolicyDiff(want *monitoringv3.AlertPolicy, have *monitoringv3.AlertPolicy) (s string) {
+	if len(have.Conditions) != len(want.Conditions) {
+		s = fmt.Sprintf("%shave a different number of conditions\n", s)
+	} else {
+		for i, condition := range want.Conditions {
+			wantThreshold := condition.ConditionThreshold
+			haveThreshold := have.Conditions[i].ConditionThreshold
+			if haveThreshold == nil {
+				s = fmt.Sprintf("%shave no threshold for condition '%s'\n", s, condition.DisplayName)
+				continue
+			}
+			s = s + getMetricThresholdDiff(condition.DisplayName, wantThreshold, haveThreshold)
+		}
+	}
+	if getSortedList(want.NotificationChannels) != getSortedList(have.NotificationChannels) {
+		s = fmt.Sprintf("%snotificationChannels\nwant %v\nhave %v\n", s, want.NotificationChannels, have.NotificationChannels)
+	}
+	if have.Documentation == nil ||
+		want.Documentation.Content != have.Documentation.Content ||
+		want.Documentation.MimeType != have.Documentation.MimeType {
+		s = fmt.Sprintf("%shave a different documentation\n", s)
+	}
+	return s
+}
+
+func getMetricThresholdDiff(conditionName string, want *monitoringv3.MetricThreshold, have *monitoringv3.MetricThreshold) (s string) {
+	if normalizeFilter(want.Filter) != normalizeFilter(have.Filter) {
+		s = fmt.Sprintf("%scondition '%s' filter\nwant %s\nhave %s\n", s, conditionName, want.Filter, have.Filter)
+	}
+	if normalizeFilter(want.DenominatorFilter) != normalizeFilter(have.DenominatorFilter) {
+		s = fmt.Sprintf("%scondition '%s' denominatorFilter\nwant %s\nhave %s\n", s, conditionName, want.DenominatorFilter, have.DenominatorFilter)
+	}
+	if !isSameAggregations(want.Aggregations, have.Aggregations) {
+		s = fmt.Sprintf("%scondition '%s' have different aggregations\n", s, conditionName)
+	}
+	if !isSameAggregations(want.DenominatorAggregations, have.DenominatorAggregations) {
+		s = fmt.Sprintf("%scondition '%s' have different denominatorAggregations\n", s, conditionName)
+	}
+	if want.Comparison != have.Comparison {
+		s = fmt.Sprintf("%scondition '%s' comparison\nwant %s\nhave %s\n", s, conditionName, want.Comparison, have.Comparison)
+	}
+	if want.ThresholdValue != have.ThresholdValue {
+		s = fmt.Sprintf("%scondition '%s' thresholdValue\nwant %v\nhave %v\n", s, conditionName, want.ThresholdValue, have.ThresholdValue)
+	}
+	if normalizeDuration(want.Duration) != normalizeDuration(have.Duration) {
+		s = fmt.Sprintf("%scondition '%s' duration\nwant %s\nhave %s\n", s, conditionName, want.Duration, have.Duration)
+	}
+	if getTriggerCount(want.Trigger) != getTriggerCount(have.Trigger) {
+		s = fmt.Sprintf("%scondition '%s' trigger count\nwant %d\nhave %d\n", s, conditionName, getTriggerCount(want.Trigger), getTriggerCount(have.Trigger))
+	}
+	return s
+}
+
+func isSameAggregations(want []*monitoringv3.Aggregation, have []*monitoringv3.Aggregation) bool {
+	if len(want) != len(have) {
+		return false
+	}
+	for i := range want {
+		if normalizeDuration(want[i].AlignmentPeriod) != normalizeDuration(have[i].AlignmentPeriod) ||
+			normalizeEnum(want[i].CrossSeriesReducer, "REDUCE_NONE") != normalizeEnum(have[i].CrossSeriesReducer, "REDUCE_NONE") ||
+			normalizeEnum(want[i].PerSeriesAligner, "ALIGN_NONE") != normalizeEnum(have[i].PerSeriesAligner, "ALIGN_NONE") ||
+			strings.Join(want[i].GroupByFields, ",") != strings.Join(have[i].GroupByFields, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeFilter removes the white spaces out of quoted strings
+func normalizeFilter(filter string) string {
+	var builder strings.Builder
+	quoted := false
+	for _, r := range filter {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if !quoted && (r == ' ' || r == '\t' || r == '\n') {
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// normalizeDuration returns the duration in seconds as the API does, an empty or invalid duration being kept as is
+func normalizeDuration(duration string) string {
+	if duration == "" {
+		return "0s"
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return duration
+	}
+	return fmt.Sprintf("%gs", d.Seconds())
+}
+
+// normalizeEnum the API omits the enum zero value
+func normalizeEnum(value string, zeroValue string) string {
+	if value == "" {
+		return zeroValue
+	}
+	return value
+}
+
+// getSortedList the order of notification channels is not meaningful
+func getSortedList(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func getTriggerCount(trigger *monitoringv3.Trigger) int64 {
+	if trigger == nil {
+		return 0
+	}
+	return trigger.Count
+}
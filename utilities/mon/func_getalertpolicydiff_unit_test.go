@@ -0,0 +1,119 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+)
+
+func TestUnitGetAlertPolicyDiff(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		policyIndex      int
+		channels         []string
+		mutate           func(have *monitoringv3.AlertPolicy)
+		wantDiffContains string
+	}{
+		{
+			name:     "upToDate",
+			channels: []string{"projects/qwerty/notificationChannels/123"},
+			mutate:   func(have *monitoringv3.AlertPolicy) {},
+		},
+		{
+			name:        "apiFormsOfTheSameSettings",
+			policyIndex: 1,
+			mutate: func(have *monitoringv3.AlertPolicy) {
+				threshold := have.Conditions[0].ConditionThreshold
+				threshold.Filter = strings.Replace(threshold.Filter, "=", " = ", 1)
+				threshold.Duration = "5m"
+				threshold.Aggregations[0].AlignmentPeriod = "300.0s"
+				threshold.Aggregations[0].CrossSeriesReducer = "REDUCE_NONE"
+				have.NotificationChannels = []string{}
+			},
+		},
+		{
+			name: "zeroDurationOmitted",
+			mutate: func(have *monitoringv3.AlertPolicy) {
+				have.Conditions[0].ConditionThreshold.Duration = ""
+			},
+		},
+		{
+			name: "thresholdChanged",
+			mutate: func(have *monitoringv3.AlertPolicy) {
+				have.Conditions[0].ConditionThreshold.ThresholdValue = 0.5
+			},
+			wantDiffContains: "thresholdValue",
+		},
+		{
+			name: "filterQuotedValueChanged",
+			mutate: func(have *monitoringv3.AlertPolicy) {
+				threshold := have.Conditions[0].ConditionThreshold
+				threshold.DenominatorFilter = strings.Replace(threshold.DenominatorFilter, "stream2bq", "stream2 bq", 1)
+			},
+			wantDiffContains: "denominatorFilter",
+		},
+		{
+			name: "groupByChanged",
+			mutate: func(have *monitoringv3.AlertPolicy) {
+				have.Conditions[0].ConditionThreshold.Aggregations[0].GroupByFields = []string{"resource.label.project_id"}
+			},
+			wantDiffContains: "aggregations",
+		},
+		{
+			name: "notificationChannelAdded",
+			mutate: func(have *monitoringv3.AlertPolicy) {
+				have.NotificationChannels = []string{"projects/qwerty/notificationChannels/456"}
+			},
+			wantDiffContains: "notificationChannels",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			alertPolicies, err := GetAlertPolicies("stream2bq", AlertingParameters{
+				ErrorRatePercent:     5,
+				LatencyE2EP95Seconds: 600,
+				InitFailures:         0,
+				NotificationChannels: tc.channels,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			want := alertPolicies[tc.policyIndex]
+			// the API response is a copy of the wanted policy
+			policyJSON, err := json.Marshal(want)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			var have monitoringv3.AlertPolicy
+			if err = json.Unmarshal(policyJSON, &have); err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			tc.mutate(&have)
+			diff := getAlertPolicyDiff(want, &have)
+			if tc.wantDiffContains == "" && diff != "" {
+				t.Errorf("Want no diff and got %s", diff)
+			}
+			if tc.wantDiffContains != "" && !strings.Contains(diff, tc.wantDiffContains) {
+				t.Errorf("Want diff containing %s and got '%s'", tc.wantDiffContains, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"fmt"
+
+	"google.golang.org/api/logging/v2"
+)
+
+// GetLogMetrics returns the counter and distribution log-based metrics of a microservice, extracted from its structured log entries
+func GetLogMetrics(microserviceName string) (logMetrics []*logging.LogMetric, err error) {
+	if microserviceName == "" {
+		return logMetrics, fmt.Errorf("microserviceName can NOT be a zero value")
+	}
+	microserviceFilter := fmt.Sprintf("jsonPayload.microservice_name=\"%s\"", microserviceName)
+	instanceNameLabel := &logging.LabelDescriptor{
+		Key:         instanceNameLabelKey,
+		Description: "RAM microservice instance name",
+		ValueType:   "STRING",
+	}
+
+	logMetrics = append(logMetrics, &logging.LogMetric{
+		Name:        GetLogMetricName(microserviceName, logMetricStarts),
+		Description: fmt.Sprintf("RAM %s number of triggered executions", microserviceName),
+		Filter:      fmt.Sprintf("%s AND jsonPayload.message=\"start\"", microserviceFilter),
+		LabelExtractors: map[string]string{
+			instanceNameLabelKey: instanceNameLabelExtract,
+		},
+		MetricDescriptor: &logging.MetricDescriptor{
+			Labels:     []*logging.LabelDescriptor{instanceNameLabel},
+			MetricKind: "DELTA",
+			Unit:       "1",
+			ValueType:  "INT64",
+		},
+	})
+	logMetrics = append(logMetrics, &logging.LogMetric{
+		Name:        GetLogMetricName(microserviceName, logMetricErrors),
+		Description: fmt.Sprintf("RAM %s number of executions ended in error, retried or not", microserviceName),
+		Filter:      fmt.Sprintf("%s AND jsonPayload.message=(\"noretry\" OR \"redo_on_transient\" OR \"dropped\" OR \"dead_letter_failed\")", microserviceFilter),
+		LabelExtractors: map[string]string{
+			instanceNameLabelKey: instanceNameLabelExtract,
+			"message":            "EXTRACT(jsonPayload.message)",
+		},
+		MetricDescriptor: &logging.MetricDescriptor{
+			Labels: []*logging.LabelDescriptor{
+				instanceNameLabel,
+				{
+					Key:         "message",
+					Description: "noretry, redo_on_transient, dropped or dead_letter_failed",
+					ValueType:   "STRING",
+				},
+			},
+			MetricKind: "DELTA",
+			Unit:       "1",
+			ValueType:  "INT64",
+		},
+	})
+	logMetrics = append(logMetrics, &logging.LogMetric{
+		Name:        GetLogMetricName(microserviceName, logMetricInitFailures),
+		Description: fmt.Sprintf("RAM %s number of failed initializations", microserviceName),
+		Filter:      fmt.Sprintf("%s AND jsonPayload.message=\"init_failed\"", microserviceFilter),
+		LabelExtractors: map[string]string{
+			instanceNameLabelKey: instanceNameLabelExtract,
+		},
+		MetricDescriptor: &logging.MetricDescriptor{
+			Labels:     []*logging.LabelDescriptor{instanceNameLabel},
+			MetricKind: "DELTA",
+			Unit:       "1",
+			ValueType:  "INT64",
+		},
+	})
+	// latency metric short names are the log entry JSON field names
+	for _, jsonField := range []string{logMetricLatency, logMetricLatencyE2E} {
+		logMetrics = append(logMetrics, &logging.LogMetric{
+			Name:        GetLogMetricName(microserviceName, jsonField),
+			Description: fmt.Sprintf("RAM %s %s of finished executions", microserviceName, jsonField),
+			Filter:      fmt.Sprintf("%s AND jsonPayload.message=~\"^finish\" AND jsonPayload.%s>0", microserviceFilter, jsonField),
+			LabelExtractors: map[string]string{
+				instanceNameLabelKey: instanceNameLabelExtract,
+			},
+			ValueExtractor: fmt.Sprintf("EXTRACT(jsonPayload.%s)", jsonField),
+			BucketOptions: &logging.BucketOptions{
+				ExponentialBuckets: &logging.Exponential{
+					GrowthFactor:     2,
+					NumFiniteBuckets: 24,
+					Scale:            0.01,
+				},
+			},
+			MetricDescriptor: &logging.MetricDescriptor{
+				Labels:     []*logging.LabelDescriptor{instanceNameLabel},
+				MetricKind: "DELTA",
+				Unit:       "s",
+				ValueType:  "DISTRIBUTION",
+			},
+		})
+	}
+	// monitor logs one not_compliant entry per violation, the violation JSON in the description holds the severity
+	if microserviceName == "monitor" {
+		logMetrics = append(logMetrics, &logging.LogMetric{
+			Name:        GetLogMetricName(microserviceName, logMetricViolations),
+			Description: "RAM monitor number of violations per severity",
+			Filter:      fmt.Sprintf("%s AND jsonPayload.message=~\"^not_compliant\"", microserviceFilter),
+			LabelExtractors: map[string]string{
+				instanceNameLabelKey: instanceNameLabelExtract,
+				"severity":           `REGEXP_EXTRACT(jsonPayload.description, "\"severity\":\"([^\"]*)\"")`,
+			},
+			MetricDescriptor: &logging.MetricDescriptor{
+				Labels: []*logging.LabelDescriptor{
+					instanceNameLabel,
+					{
+						Key:         "severity",
+						Description: "violated constraint severity",
+						ValueType:   "STRING",
+					},
+				},
+				MetricKind: "DELTA",
+				Unit:       "1",
+				ValueType:  "INT64",
+			},
+		})
+	}
+	return logMetrics, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitGetLogMetrics(t *testing.T) {
+	tests := []struct {
+		name             string
+		microserviceName string
+		wantCount        int
+		wantErr          bool
+	}{
+		{
+			name:    "missingMicroserviceName",
+			wantErr: true,
+		},
+		{
+			name:             "monitor",
+			microserviceName: "monitor",
+			wantCount:        6,
+			wantErr:          false,
+		},
+		{
+			name:             "stream2bq",
+			microserviceName: "stream2bq",
+			wantCount:        5,
+			wantErr:          false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			logMetrics, err := GetLogMetrics(tt.microserviceName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(logMetrics) != tt.wantCount {
+				t.Errorf("Want %d log metrics got %d", tt.wantCount, len(logMetrics))
+			}
+			for _, logMetric := range logMetrics {
+				if !strings.HasPrefix(logMetric.Name, "ram_"+tt.microserviceName+"_") {
+					t.Errorf("Want name prefix %s got %s", "ram_"+tt.microserviceName+"_", logMetric.Name)
+				}
+				if !strings.Contains(logMetric.Filter, tt.microserviceName) {
+					t.Errorf("microserviceName = %s is not contained in Filter %s", tt.microserviceName, logMetric.Filter)
+				}
+				if _, ok := logMetric.LabelExtractors[instanceNameLabelKey]; !ok {
+					t.Errorf("Want label extractor %s in %s", instanceNameLabelKey, logMetric.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"google.golang.org/api/monitoring/v1"
+)
+
+// GetWidget renders a monitoring widget from a catalogue kind or an inline JSON template
+// Variables are referenced in templates as {{.variableName}}, their values are JSON string escaped
+func GetWidget(widgetParameters WidgetParameters) (widget monitoring.Widget, err error) {
+	widgetTemplate := widgetParameters.Template
+	if widgetTemplate == "" {
+		switch widgetParameters.Kind {
+		case "widgetGCFActiveInstances", "widgetGCFExecutionCount", "widgetGCFExecutionTime", "widgetGCFMemoryUsage":
+			return GetGCFWidget(widgetParameters.Variables["microserviceName"], widgetParameters.Kind)
+		case "widgetBigQueryStreamingInsertErrors":
+			widgetTemplate = widgetBigQueryStreamingInsertErrors
+		case "widgetFirestoreOps":
+			widgetTemplate = widgetFirestoreOps
+		case "widgetLogMetricE2ELatency":
+			widgetTemplate = widgetLogMetricE2ELatency
+		case "widgetLogMetricViolations":
+			widgetTemplate = widgetLogMetricViolations
+		case "widgetPubSubBacklog":
+			widgetTemplate = widgetPubSubBacklog
+		case "widgetPubSubOldestUnackedAge":
+			widgetTemplate = widgetPubSubOldestUnackedAge
+		case "widgetScorecard":
+			widgetTemplate = widgetScorecard
+		case "widgetText":
+			widgetTemplate = widgetText
+		default:
+			return widget, fmt.Errorf("Unsupported widget kind '%s'", widgetParameters.Kind)
+		}
+	}
+	tmpl, err := template.New(widgetParameters.Kind).Option("missingkey=error").Parse(widgetTemplate)
+	if err != nil {
+		return widget, fmt.Errorf("template.Parse %s %v", widgetParameters.Kind, err)
+	}
+	variables := make(map[string]string)
+	for key, value := range widgetParameters.Variables {
+		variables[key] = escapeJSONString(value)
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, variables)
+	if err != nil {
+		return widget, fmt.Errorf("tmpl.Execute %s %v", widgetParameters.Kind, err)
+	}
+	err = json.Unmarshal(buffer.Bytes(), &widget)
+	if err != nil {
+		return widget, fmt.Errorf("json.Unmarshal %s %v", widgetParameters.Kind, err)
+	}
+	return widget, nil
+}
+
+// escapeJSONString escapes a value to be inserted within a JSON string
+func escapeJSONString(value string) string {
+	b, _ := json.Marshal(value)
+	return strings.TrimSuffix(strings.TrimPrefix(string(b), "\""), "\"")
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"testing"
+)
+
+func TestUnitGetWidget(t *testing.T) {
+	tests := []struct {
+		name             string
+		widgetParameters WidgetParameters
+		wantTitle        string
+		wantErr          bool
+	}{
+		{
+			name:             "unsupportedKind",
+			widgetParameters: WidgetParameters{Kind: "blabla"},
+			wantErr:          true,
+		},
+		{
+			name:             "missingVariable",
+			widgetParameters: WidgetParameters{Kind: "widgetPubSubBacklog"},
+			wantErr:          true,
+		},
+		{
+			name:             "invalidTemplate",
+			widgetParameters: WidgetParameters{Template: `{"title": "{{.title}"}`, Variables: map[string]string{"title": "blabla"}},
+			wantErr:          true,
+		},
+		{
+			name:             "widgetGCFExecutionCount",
+			widgetParameters: WidgetParameters{Kind: "widgetGCFExecutionCount", Variables: map[string]string{"microserviceName": "monitor"}},
+			wantTitle:        "monitor execution count",
+		},
+		{
+			name:             "widgetPubSubBacklog",
+			widgetParameters: WidgetParameters{Kind: "widgetPubSubBacklog", Variables: map[string]string{"topicName": "ram-violations"}},
+			wantTitle:        "ram-violations backlog",
+		},
+		{
+			name:             "widgetPubSubOldestUnackedAge",
+			widgetParameters: WidgetParameters{Kind: "widgetPubSubOldestUnackedAge", Variables: map[string]string{"topicName": "ram-violations"}},
+			wantTitle:        "ram-violations oldest unacked message age",
+		},
+		{
+			name:             "widgetBigQueryStreamingInsertErrors",
+			widgetParameters: WidgetParameters{Kind: "widgetBigQueryStreamingInsertErrors"},
+			wantTitle:        "BigQuery streaming insert errors",
+		},
+		{
+			name:             "widgetFirestoreOps",
+			widgetParameters: WidgetParameters{Kind: "widgetFirestoreOps"},
+			wantTitle:        "Firestore operations",
+		},
+		{
+			name:             "widgetLogMetricE2ELatency",
+			widgetParameters: WidgetParameters{Kind: "widgetLogMetricE2ELatency", Variables: map[string]string{"microserviceName": "stream2bq"}},
+			wantTitle:        "stream2bq end to end latency",
+		},
+		{
+			name:             "widgetLogMetricViolations",
+			widgetParameters: WidgetParameters{Kind: "widgetLogMetricViolations"},
+			wantTitle:        "Violations per severity",
+		},
+		{
+			name: "widgetScorecard",
+			widgetParameters: WidgetParameters{Kind: "widgetScorecard", Variables: map[string]string{
+				"title":              "Violations",
+				"crossSeriesReducer": "REDUCE_SUM",
+				"perSeriesAligner":   "ALIGN_DELTA",
+				"filter":             `metric.type="logging.googleapis.com/user/ram_monitor_violations"`}},
+			wantTitle: "Violations",
+		},
+		{
+			name:             "widgetText",
+			widgetParameters: WidgetParameters{Kind: "widgetText", Variables: map[string]string{"title": "About", "content": "# RAM\n\"quoted\""}},
+			wantTitle:        "About",
+		},
+		{
+			name:             "inlineTemplate",
+			widgetParameters: WidgetParameters{Template: `{"title": "{{.title}}", "blank": {}}`, Variables: map[string]string{"title": `my "blank"`}},
+			wantTitle:        `my "blank"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			widget, err := GetWidget(tt.widgetParameters)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if widget.Title != tt.wantTitle {
+				t.Errorf("Want %s got %s", tt.wantTitle, widget.Title)
+			}
+			if tt.name == "widgetScorecard" {
+				if widget.Scorecard.TimeSeriesQuery.TimeSeriesFilter.Filter != tt.widgetParameters.Variables["filter"] {
+					t.Errorf("Want %s got %s", tt.widgetParameters.Variables["filter"], widget.Scorecard.TimeSeriesQuery.TimeSeriesFilter.Filter)
+				}
+			}
+		})
+	}
+}
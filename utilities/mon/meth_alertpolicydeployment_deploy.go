@@ -0,0 +1,76 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"fmt"
+	"log"
+
+	monitoringv3 "google.golang.org/api/monitoring/v3"
+)
+
+// Deploy alert policy in the stackdriver project, found by display name
+func (alertPolicyDeployment AlertPolicyDeployment) Deploy() (err error) {
+	alertPoliciesService := alertPolicyDeployment.Core.Services.MonitoringServicev3.Projects.AlertPolicies
+	alertPolicy := alertPolicyDeployment.Artifacts.AlertPolicy
+	parent := fmt.Sprintf("projects/%s", alertPolicyDeployment.Core.SolutionSettings.Hosting.Stackdriver.ProjectID)
+
+	var retreivedAlertPolicy *monitoringv3.AlertPolicy
+	err = alertPoliciesService.List(parent).Pages(alertPolicyDeployment.Core.Ctx,
+		func(response *monitoringv3.ListAlertPoliciesResponse) error {
+			for _, policy := range response.AlertPolicies {
+				if policy.DisplayName == alertPolicy.DisplayName {
+					retreivedAlertPolicy = policy
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("alertPoliciesService.List %v", err)
+	}
+
+	if retreivedAlertPolicy == nil {
+		if alertPolicyDeployment.Core.Commands.Check {
+			return fmt.Errorf("%s mon alert policy NOT found '%s'", alertPolicyDeployment.Core.InstanceName, alertPolicy.DisplayName)
+		}
+		retreivedAlertPolicy, err = alertPoliciesService.Create(parent, alertPolicy).Context(alertPolicyDeployment.Core.Ctx).Do()
+		if err != nil {
+			return fmt.Errorf("alertPoliciesService.Create %v", err)
+		}
+		log.Printf("%s mon alert policy created '%s' %s", alertPolicyDeployment.Core.InstanceName, retreivedAlertPolicy.DisplayName, retreivedAlertPolicy.Name)
+		return nil
+	}
+
+	s := getAlertPolicyDiff(alertPolicy, retreivedAlertPolicy)
+	if len(s) == 0 {
+		log.Printf("%s mon alert policy is up-to-date '%s'", alertPolicyDeployment.Core.InstanceName, alertPolicy.DisplayName)
+		return nil
+	}
+	if alertPolicyDeployment.Core.Commands.Check {
+		return fmt.Errorf("%s mon invalid alert policy configuration '%s':\n%s", alertPolicyDeployment.Core.InstanceName, alertPolicy.DisplayName, s)
+	}
+	// Patching with the retreived conditions names keeps the conditions identity
+	for i, condition := range alertPolicy.Conditions {
+		if i < len(retreivedAlertPolicy.Conditions) {
+			condition.Name = retreivedAlertPolicy.Conditions[i].Name
+		}
+	}
+	retreivedAlertPolicy, err = alertPoliciesService.Patch(retreivedAlertPolicy.Name, alertPolicy).Context(alertPolicyDeployment.Core.Ctx).Do()
+	if err != nil {
+		return fmt.Errorf("alertPoliciesService.Patch %v", err)
+	}
+	log.Printf("%s mon alert policy updated '%s' %s", alertPolicyDeployment.Core.InstanceName, retreivedAlertPolicy.DisplayName, retreivedAlertPolicy.Name)
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mon
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
+)
+
+// Deploy log-based metric in the hosting project, where the microservices log
+func (logMetricDeployment LogMetricDeployment) Deploy() (err error) {
+	metricsService := logMetricDeployment.Core.Services.LoggingService.Projects.Metrics
+	logMetric := logMetricDeployment.Artifacts.LogMetric
+	parent := fmt.Sprintf("projects/%s", logMetricDeployment.Core.SolutionSettings.Hosting.ProjectID)
+	metricName := fmt.Sprintf("%s/metrics/%s", parent, logMetric.Name)
+
+	retreivedLogMetric, err := metricsService.Get(metricName).Context(logMetricDeployment.Core.Ctx).Do()
+	if err != nil {
+		if !erm.IsNotFound(err) {
+			return fmt.Errorf("metricsService.Get %v", err)
+		}
+		if logMetricDeployment.Core.Commands.Check {
+			return fmt.Errorf("%s mon log metric NOT found %s", logMetricDeployment.Core.InstanceName, logMetric.Name)
+		}
+		_, err = metricsService.Create(parent, logMetric).Context(logMetricDeployment.Core.Ctx).Do()
+		if err != nil {
+			return fmt.Errorf("metricsService.Create %v", err)
+		}
+		log.Printf("%s mon log metric created %s", logMetricDeployment.Core.InstanceName, logMetric.Name)
+		return nil
+	}
+
+	var s string
+	if retreivedLogMetric.Filter != logMetric.Filter {
+		s = fmt.Sprintf("%sfilter\nwant %s\nhave %s\n", s, logMetric.Filter, retreivedLogMetric.Filter)
+	}
+	if retreivedLogMetric.ValueExtractor != logMetric.ValueExtractor {
+		s = fmt.Sprintf("%svalueExtractor\nwant %s\nhave %s\n", s, logMetric.ValueExtractor, retreivedLogMetric.ValueExtractor)
+	}
+	if !reflect.DeepEqual(retreivedLogMetric.LabelExtractors, logMetric.LabelExtractors) {
+		s = fmt.Sprintf("%slabelExtractors\nwant %v\nhave %v\n", s, logMetric.LabelExtractors, retreivedLogMetric.LabelExtractors)
+	}
+	if retreivedLogMetric.Description != logMetric.Description {
+		s = fmt.Sprintf("%sdescription\nwant %s\nhave %s\n", s, logMetric.Description, retreivedLogMetric.Description)
+	}
+	if len(s) == 0 {
+		log.Printf("%s mon log metric is up-to-date %s", logMetricDeployment.Core.InstanceName, logMetric.Name)
+		return nil
+	}
+	if logMetricDeployment.Core.Commands.Check {
+		return fmt.Errorf("%s mon invalid log metric configuration %s:\n%s", logMetricDeployment.Core.InstanceName, logMetric.Name, s)
+	}
+	_, err = metricsService.Update(metricName, logMetric).Context(logMetricDeployment.Core.Ctx).Do()
+	if err != nil {
+		return fmt.Errorf("metricsService.Update %v", err)
+	}
+	log.Printf("%s mon log metric updated %s", logMetricDeployment.Core.InstanceName, logMetric.Name)
+	return nil
+}
@@ -18,6 +18,7 @@ package mon
 type DashboardParameters struct {
 	DisplayName          string
 	Columns              int64
-	MicroServiceNameList []string `yaml:"microServiceNameList"`
-	WidgetTypeList       []string `yaml:"widgetTypeList"`
+	MicroServiceNameList []string           `yaml:"microServiceNameList"`
+	WidgetTypeList       []string           `yaml:"widgetTypeList"`
+	Widgets              []WidgetParameters `yaml:"widgets,omitempty"`
 }
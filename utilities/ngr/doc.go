@@ -0,0 +1,20 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ngr helps with nested groups
+//
+// Groups may be members of other groups, with cycles. The graph is walked breadth first from a group or a member,
+// reading direct memberships from a Directory, e.g. the FireStore cache of group members, up to a max nesting depth.
+// The groups not walked further due to this depth are returned, so that the caller logs them.
+package ngr
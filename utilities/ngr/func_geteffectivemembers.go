@@ -0,0 +1,60 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ngr
+
+import "github.com/BrunoReboul/ram/utilities/cai"
+
+// GetEffectiveMembers browses breadth first the nested groups to list each non group member once with the shortest path leading to it
+// groups deeper than maxDepth, the group itself being at depth 1, are not expanded and returned as truncatedGroupEmails
+func GetEffectiveMembers(directory Directory, groupEmail string, maxDepth int64) (effectiveMembers []cai.EffectiveMember, truncatedGroupEmails []string, err error) {
+	type nestedGroup struct {
+		email string
+		path  []string
+	}
+	visited := map[string]bool{groupEmail: true}
+	listed := make(map[string]bool)
+	queue := []nestedGroup{{email: groupEmail, path: []string{groupEmail}}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if int64(len(current.path)) > maxDepth {
+			truncatedGroupEmails = append(truncatedGroupEmails, current.email)
+			continue
+		}
+		directMembers, err := directory.GetDirectMembers(current.email)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, directMember := range directMembers {
+			// nested groups are expanded, not listed as effective members
+			if directMember.Type != "GROUP" && !listed[directMember.MemberEmail] {
+				listed[directMember.MemberEmail] = true
+				effectiveMembers = append(effectiveMembers, cai.EffectiveMember{
+					MemberEmail: directMember.MemberEmail,
+					ID:          directMember.ID,
+					Type:        directMember.Type,
+					Path:        current.path,
+				})
+			}
+			if directMember.Type == "GROUP" && !visited[directMember.MemberEmail] {
+				visited[directMember.MemberEmail] = true
+				path := make([]string, len(current.path), len(current.path)+1)
+				copy(path, current.path)
+				queue = append(queue, nestedGroup{email: directMember.MemberEmail, path: append(path, directMember.MemberEmail)})
+			}
+		}
+	}
+	return effectiveMembers, truncatedGroupEmails, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ngr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnitGetEffectiveMembers(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		directory     mapDirectory
+		groupEmail    string
+		maxDepth      int64
+		wantMembers   []string
+		wantTruncated []string
+		wantErrorMsg  string
+	}{
+		{
+			name: "directMembersOnly",
+			directory: mapDirectory{
+				"g1@example.com": {"u1@example.com", "u2@example.com"},
+			},
+			groupEmail:  "g1@example.com",
+			maxDepth:    5,
+			wantMembers: []string{"u1@example.com g1@example.com", "u2@example.com g1@example.com"},
+		},
+		{
+			name: "nestedGroups",
+			directory: mapDirectory{
+				"g1@example.com": {"u1@example.com", "g2@example.com"},
+				"g2@example.com": {"u2@example.com", "g3@example.com"},
+				"g3@example.com": {"u3@example.com"},
+			},
+			groupEmail: "g1@example.com",
+			maxDepth:   5,
+			wantMembers: []string{
+				"u1@example.com g1@example.com",
+				"u2@example.com g1@example.com/g2@example.com",
+				"u3@example.com g1@example.com/g2@example.com/g3@example.com",
+			},
+		},
+		{
+			name: "shortestPath",
+			directory: mapDirectory{
+				"g1@example.com": {"g2@example.com", "u1@example.com"},
+				"g2@example.com": {"u1@example.com"},
+			},
+			groupEmail: "g1@example.com",
+			maxDepth:   5,
+			wantMembers: []string{
+				"u1@example.com g1@example.com",
+			},
+		},
+		{
+			name: "cycle",
+			directory: mapDirectory{
+				"g1@example.com": {"g2@example.com"},
+				"g2@example.com": {"g1@example.com", "u1@example.com"},
+			},
+			groupEmail: "g1@example.com",
+			maxDepth:   5,
+			wantMembers: []string{
+				"u1@example.com g1@example.com/g2@example.com",
+			},
+		},
+		{
+			name: "depthCutOff",
+			directory: mapDirectory{
+				"g1@example.com": {"g2@example.com"},
+				"g2@example.com": {"g3@example.com"},
+				"g3@example.com": {"u1@example.com"},
+			},
+			groupEmail:    "g1@example.com",
+			maxDepth:      2,
+			wantTruncated: []string{"g3@example.com"},
+		},
+		{
+			name: "directoryError",
+			directory: mapDirectory{
+				"g1@example.com":    {"error@example.com"},
+				"error@example.com": {"u1@example.com"},
+			},
+			groupEmail:   "g1@example.com",
+			maxDepth:     5,
+			wantErrorMsg: "cannot read the members of error@example.com",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			effectiveMembers, truncatedGroupEmails, err := GetEffectiveMembers(tc.directory, tc.groupEmail, tc.maxDepth)
+			if err != nil {
+				if tc.wantErrorMsg == "" || !strings.Contains(err.Error(), tc.wantErrorMsg) {
+					t.Errorf("Want error '%s' got %v", tc.wantErrorMsg, err)
+				}
+				return
+			}
+			if tc.wantErrorMsg != "" {
+				t.Fatalf("Want error '%s' and got none", tc.wantErrorMsg)
+			}
+			var members []string
+			for _, effectiveMember := range effectiveMembers {
+				members = append(members, fmt.Sprintf("%s %s", effectiveMember.MemberEmail, strings.Join(effectiveMember.Path, "/")))
+			}
+			if fmt.Sprint(members) != fmt.Sprint(tc.wantMembers) {
+				t.Errorf("Want members %v got %v", tc.wantMembers, members)
+			}
+			if fmt.Sprint(truncatedGroupEmails) != fmt.Sprint(tc.wantTruncated) {
+				t.Errorf("Want truncated %v got %v", tc.wantTruncated, truncatedGroupEmails)
+			}
+		})
+	}
+}
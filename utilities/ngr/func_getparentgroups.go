@@ -0,0 +1,47 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ngr
+
+// GetParentGroups browses breadth first the groups containing the member, directly or not, each group being listed once
+// the parents of members deeper than maxDepth, the member itself being at depth 1, are not looked for and these members are returned as truncatedEmails
+func GetParentGroups(directory Directory, memberEmail string, maxDepth int64) (parentEmails []string, truncatedEmails []string, err error) {
+	type nestedMember struct {
+		email string
+		depth int64
+	}
+	visited := map[string]bool{memberEmail: true}
+	queue := []nestedMember{{email: memberEmail, depth: 1}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.depth > maxDepth {
+			truncatedEmails = append(truncatedEmails, current.email)
+			continue
+		}
+		directParents, err := directory.GetDirectParents(current.email)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, parentEmail := range directParents {
+			// Cycles in nested groups are possible
+			if !visited[parentEmail] {
+				visited[parentEmail] = true
+				parentEmails = append(parentEmails, parentEmail)
+				queue = append(queue, nestedMember{email: parentEmail, depth: current.depth + 1})
+			}
+		}
+	}
+	return parentEmails, truncatedEmails, nil
+}
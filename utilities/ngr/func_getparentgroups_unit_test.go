@@ -0,0 +1,108 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ngr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnitGetParentGroups(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		directory     mapDirectory
+		memberEmail   string
+		maxDepth      int64
+		wantParents   []string
+		wantTruncated []string
+		wantErrorMsg  string
+	}{
+		{
+			name: "noParent",
+			directory: mapDirectory{
+				"g1@example.com": {"u1@example.com"},
+			},
+			memberEmail: "g1@example.com",
+			maxDepth:    5,
+		},
+		{
+			name: "nestedGroups",
+			directory: mapDirectory{
+				"g1@example.com": {"g2@example.com"},
+				"g2@example.com": {"g3@example.com"},
+				"g3@example.com": {"u1@example.com"},
+				"g4@example.com": {"g3@example.com"},
+			},
+			memberEmail: "g3@example.com",
+			maxDepth:    5,
+			wantParents: []string{"g2@example.com", "g4@example.com", "g1@example.com"},
+		},
+		{
+			name: "cycle",
+			directory: mapDirectory{
+				"g1@example.com": {"g2@example.com"},
+				"g2@example.com": {"g1@example.com"},
+			},
+			memberEmail: "g1@example.com",
+			maxDepth:    5,
+			wantParents: []string{"g2@example.com"},
+		},
+		{
+			name: "depthCutOff",
+			directory: mapDirectory{
+				"g1@example.com": {"g2@example.com"},
+				"g2@example.com": {"g3@example.com"},
+				"g3@example.com": {"u1@example.com"},
+			},
+			memberEmail:   "g3@example.com",
+			maxDepth:      1,
+			wantParents:   []string{"g2@example.com"},
+			wantTruncated: []string{"g2@example.com"},
+		},
+		{
+			name: "directoryError",
+			directory: mapDirectory{
+				"error@example.com": {"g1@example.com"},
+				"g1@example.com":    {"u1@example.com"},
+			},
+			memberEmail:  "g1@example.com",
+			maxDepth:     5,
+			wantErrorMsg: "cannot read the parents of error@example.com",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			parentEmails, truncatedEmails, err := GetParentGroups(tc.directory, tc.memberEmail, tc.maxDepth)
+			if err != nil {
+				if tc.wantErrorMsg == "" || !strings.Contains(err.Error(), tc.wantErrorMsg) {
+					t.Errorf("Want error '%s' got %v", tc.wantErrorMsg, err)
+				}
+				return
+			}
+			if tc.wantErrorMsg != "" {
+				t.Fatalf("Want error '%s' and got none", tc.wantErrorMsg)
+			}
+			if fmt.Sprint(parentEmails) != fmt.Sprint(tc.wantParents) {
+				t.Errorf("Want parents %v got %v", tc.wantParents, parentEmails)
+			}
+			if fmt.Sprint(truncatedEmails) != fmt.Sprint(tc.wantTruncated) {
+				t.Errorf("Want truncated %v got %v", tc.wantTruncated, truncatedEmails)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ngr
+
+import "github.com/BrunoReboul/ram/utilities/cai"
+
+// Directory reads the direct memberships of groups
+type Directory interface {
+	// GetDirectMembers returns the direct members of a group, groups being members of type GROUP
+	GetDirectMembers(groupEmail string) (directMembers []cai.EffectiveMember, err error)
+	// GetDirectParents returns the emails of the groups having the member as a direct member
+	GetDirectParents(memberEmail string) (parentEmails []string, err error)
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ngr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/BrunoReboul/ram/utilities/cai"
+)
+
+// mapDirectory in memory directory, the direct members of each group, a member being a group when it is a key of the map
+type mapDirectory map[string][]string
+
+func (directory mapDirectory) GetDirectMembers(groupEmail string) (directMembers []cai.EffectiveMember, err error) {
+	if groupEmail == "error@example.com" {
+		return nil, fmt.Errorf("cannot read the members of %s", groupEmail)
+	}
+	for _, memberEmail := range directory[groupEmail] {
+		memberType := "USER"
+		if _, ok := directory[memberEmail]; ok {
+			memberType = "GROUP"
+		}
+		directMembers = append(directMembers, cai.EffectiveMember{MemberEmail: memberEmail, Type: memberType})
+	}
+	return directMembers, nil
+}
+
+func (directory mapDirectory) GetDirectParents(memberEmail string) (parentEmails []string, err error) {
+	if memberEmail == "error@example.com" {
+		return nil, fmt.Errorf("cannot read the parents of %s", memberEmail)
+	}
+	for groupEmail, memberEmails := range directory {
+		for _, email := range memberEmails {
+			if email == memberEmail {
+				parentEmails = append(parentEmails, groupEmail)
+			}
+		}
+	}
+	sort.Strings(parentEmails)
+	return parentEmails, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/logging"
+)
+
+// CallWithBackoff optionally takes a token from the bucket then calls the API, and retries with an exponential backoff as long as the error is a rate limit one
+// Other errors are returned without retrying, so the caller can decide if they are transient
+// logEntry carries the caller fields, e.g. MicroserviceName, InstanceName, Environment, TriggeringPubsubID, set on the throttling log entries
+func CallWithBackoff(ctx context.Context, tokenBucket *TokenBucket, parameters Parameters, deadline time.Time, logEntry logging.Entry, call func() error) (attempts int64, err error) {
+	initialBackoff, err := time.ParseDuration(parameters.InitialBackoff)
+	if err != nil {
+		initialBackoff = time.Second
+	}
+	maxBackoff, err := time.ParseDuration(parameters.MaxBackoff)
+	if err != nil {
+		maxBackoff = 32 * time.Second
+	}
+	maxAttempts := parameters.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	for attempts = 1; ; attempts++ {
+		if tokenBucket != nil {
+			waited, err := tokenBucket.Wait(ctx, deadline)
+			if err != nil {
+				return attempts, err
+			}
+			if waited > 0 {
+				throttledEntry := logEntry
+				throttledEntry.Severity = "INFO"
+				throttledEntry.Message = "throttled"
+				throttledEntry.Description = fmt.Sprintf("waited %v for a token from %s", waited, tokenBucket.documentPath)
+				log.Println(throttledEntry)
+			}
+		}
+		err = call()
+		if err == nil || !IsRateLimitExceeded(err) {
+			return attempts, err
+		}
+		if attempts >= maxAttempts {
+			return attempts, fmt.Errorf("rate limit exceeded after %d attempts %v", attempts, err)
+		}
+		backoff := GetBackoffDuration(attempts-1, initialBackoff, maxBackoff)
+		if time.Now().Add(backoff).After(deadline) {
+			return attempts, fmt.Errorf("rate limit exceeded, backoff %v would exceed the deadline %v", backoff, err)
+		}
+		rateLimitEntry := logEntry
+		rateLimitEntry.Severity = "WARNING"
+		rateLimitEntry.Message = "rate_limit_exceeded"
+		rateLimitEntry.Description = fmt.Sprintf("attempt %d backoff %v %v", attempts, backoff, err)
+		log.Println(rateLimitEntry)
+		select {
+		case <-ctx.Done():
+			return attempts, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"google.golang.org/api/googleapi"
+)
+
+func TestUnitCallWithBackoff(t *testing.T) {
+	parameters := Parameters{
+		MaxAttempts:    3,
+		InitialBackoff: "1ms",
+		MaxBackoff:     "4ms",
+	}
+	var testCases = []struct {
+		name         string
+		errs         []error
+		wantAttempts int64
+		wantErr      bool
+	}{
+		{
+			name:         "success",
+			errs:         []error{nil},
+			wantAttempts: 1,
+			wantErr:      false,
+		},
+		{
+			name:         "successAfterRateLimit",
+			errs:         []error{&googleapi.Error{Code: 429}, nil},
+			wantAttempts: 2,
+			wantErr:      false,
+		},
+		{
+			name:         "otherErrorNotRetried",
+			errs:         []error{fmt.Errorf("404 not found")},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "maxAttemptsReached",
+			errs:         []error{&googleapi.Error{Code: 429}, &googleapi.Error{Code: 429}, &googleapi.Error{Code: 429}},
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var i int
+			attempts, err := CallWithBackoff(context.Background(), nil, parameters, time.Now().Add(time.Minute), logging.Entry{}, func() error {
+				err := tc.errs[i]
+				i++
+				return err
+			})
+			if attempts != tc.wantAttempts {
+				t.Errorf("Want %d attempts and got %d", tc.wantAttempts, attempts)
+			}
+			if tc.wantErr && err == nil {
+				t.Errorf("Want an error and got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Did not expect an error an got %s", err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import "time"
+
+// GetBackoffDuration returns the exponential backoff duration for a zero based attempt number: initial * 2^attempt, capped at max
+func GetBackoffDuration(attempt int64, initial time.Duration, max time.Duration) time.Duration {
+	backoff := initial
+	for i := int64(0); i < attempt; i++ {
+		backoff = backoff * 2
+		if backoff >= max {
+			return max
+		}
+	}
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitGetBackoffDuration(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		attempt int64
+		want    time.Duration
+	}{
+		{
+			name:    "firstAttempt",
+			attempt: 0,
+			want:    time.Second,
+		},
+		{
+			name:    "thirdAttempt",
+			attempt: 2,
+			want:    4 * time.Second,
+		},
+		{
+			name:    "cappedToMax",
+			attempt: 10,
+			want:    32 * time.Second,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := GetBackoffDuration(tc.attempt, time.Second, 32*time.Second)
+			if got != tc.want {
+				t.Errorf("Want %v and got %v", tc.want, got)
+			}
+		})
+	}
+}
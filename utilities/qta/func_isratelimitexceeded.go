@@ -0,0 +1,44 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"errors"
+
+	"google.golang.org/api/googleapi"
+)
+
+// IsRateLimitExceeded check if the error is a 429, or a 403 with a rate limit reason as returned by the Admin SDK
+func IsRateLimitExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiError *googleapi.Error
+	if !errors.As(err, &apiError) {
+		return false
+	}
+	if apiError.Code == 429 {
+		return true
+	}
+	if apiError.Code == 403 {
+		for _, errorItem := range apiError.Errors {
+			switch errorItem.Reason {
+			case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestUnitIsRateLimitExceeded(t *testing.T) {
+	var testCases = []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "noError",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "err429",
+			err:  &googleapi.Error{Code: 429},
+			want: true,
+		},
+		{
+			name: "err403UserRateLimitExceeded",
+			err:  &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}},
+			want: true,
+		},
+		{
+			name: "err403Forbidden",
+			err:  &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}},
+			want: false,
+		},
+		{
+			name: "wrappedRateLimitExceeded",
+			err:  fmt.Errorf("dirAdminService.Members.List %w", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}),
+			want: true,
+		},
+		{
+			name: "rateLimitExceededInMessageOnly",
+			err:  fmt.Errorf("dirAdminService.Members.List googleapi: Error 403: Rate Limit Exceeded, rateLimitExceeded"),
+			want: false,
+		},
+		{
+			name: "err500",
+			err:  fmt.Errorf("500 Internal Server Error"),
+			want: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsRateLimitExceeded(tc.err); got != tc.want {
+				t.Errorf("Want %v and got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+)
+
+// NewTokenBucket returns a token bucket identified by key, e.g. the directory customer ID, or nil when the rate is not strictly positive meaning no throttling
+// Each of the shards documents gets an even part of the rate and of the burst
+func NewTokenBucket(firestoreClient *firestore.Client, key string, parameters Parameters) *TokenBucket {
+	if parameters.RatePerSecond <= 0 {
+		return nil
+	}
+	shards := parameters.Shards
+	if shards < 1 {
+		shards = 1
+	}
+	burst := float64(parameters.Burst) / float64(shards)
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		burst:           burst,
+		documentPath:    fmt.Sprintf("rateLimiters/%s", key),
+		firestoreClient: firestoreClient,
+		ratePerSecond:   parameters.RatePerSecond / float64(shards),
+		shards:          shards,
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"math"
+	"time"
+)
+
+// takeToken refills the bucket for the elapsed time then takes one token if available, else returns how long to wait for the next token
+func takeToken(state bucketState, now time.Time, ratePerSecond float64, burst float64) (newState bucketState, wait time.Duration) {
+	if state.LastRefill.IsZero() {
+		state.Tokens = burst
+	} else if now.After(state.LastRefill) {
+		state.Tokens = math.Min(burst, state.Tokens+now.Sub(state.LastRefill).Seconds()*ratePerSecond)
+	}
+	state.LastRefill = now
+	if state.Tokens >= 1 {
+		state.Tokens--
+		return state, 0
+	}
+	return state, time.Duration((1 - state.Tokens) / ratePerSecond * float64(time.Second))
+}
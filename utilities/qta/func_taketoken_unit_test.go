@@ -0,0 +1,71 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnitTakeToken(t *testing.T) {
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	var testCases = []struct {
+		name       string
+		state      bucketState
+		wantTokens float64
+		wantWait   time.Duration
+	}{
+		{
+			name:       "newBucketIsFull",
+			state:      bucketState{},
+			wantTokens: 9,
+			wantWait:   0,
+		},
+		{
+			name:       "emptyBucketWaits",
+			state:      bucketState{Tokens: 0, LastRefill: now},
+			wantTokens: 0,
+			wantWait:   200 * time.Millisecond,
+		},
+		{
+			name:       "refillForElapsedTime",
+			state:      bucketState{Tokens: 0, LastRefill: now.Add(-time.Second)},
+			wantTokens: 4,
+			wantWait:   0,
+		},
+		{
+			name:       "refillCappedToBurst",
+			state:      bucketState{Tokens: 3, LastRefill: now.Add(-time.Hour)},
+			wantTokens: 9,
+			wantWait:   0,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			state, wait := takeToken(tc.state, now, 5, 10)
+			if state.Tokens != tc.wantTokens {
+				t.Errorf("Want %v tokens and got %v", tc.wantTokens, state.Tokens)
+			}
+			if wait != tc.wantWait {
+				t.Errorf("Want wait %v and got %v", tc.wantWait, wait)
+			}
+			if !state.LastRefill.Equal(now) {
+				t.Errorf("Want lastRefill %v and got %v", now, state.LastRefill)
+			}
+		})
+	}
+}
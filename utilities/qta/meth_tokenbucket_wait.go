@@ -0,0 +1,65 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Wait blocks until a token is taken from a shard of the bucket picked at random, and fails when the wait would go beyond the deadline
+func (tokenBucket *TokenBucket) Wait(ctx context.Context, deadline time.Time) (waited time.Duration, err error) {
+	documentPath := tokenBucket.documentPath
+	if tokenBucket.shards > 1 {
+		documentPath = fmt.Sprintf("%s_%d", tokenBucket.documentPath, rand.Int63n(tokenBucket.shards))
+	}
+	docRef := tokenBucket.firestoreClient.Doc(documentPath)
+	for {
+		var wait time.Duration
+		err = tokenBucket.firestoreClient.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+			var state bucketState
+			documentSnap, err := tx.Get(docRef)
+			// a not found document returns a snapshot that does not exist, the bucket then starts full
+			if documentSnap != nil && documentSnap.Exists() {
+				if err = documentSnap.DataTo(&state); err != nil {
+					return err
+				}
+			} else if err != nil && documentSnap == nil {
+				return err
+			}
+			state, wait = takeToken(state, time.Now(), tokenBucket.ratePerSecond, tokenBucket.burst)
+			return tx.Set(docRef, state)
+		})
+		if err != nil {
+			return waited, fmt.Errorf("RunTransaction %s %v", documentPath, err)
+		}
+		if wait == 0 {
+			return waited, nil
+		}
+		if time.Now().Add(wait).After(deadline) {
+			return waited, fmt.Errorf("rate limit %s waiting %v more would exceed the deadline", documentPath, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return waited, ctx.Err()
+		case <-time.After(wait):
+			waited = waited + wait
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+// Parameters rate limiting and backoff settings
+type Parameters struct {
+	RatePerSecond  float64 `yaml:"ratePerSecond"`
+	Burst          int64   `yaml:"burst"`
+	Shards         int64   `yaml:"shards"`
+	MaxAttempts    int64   `yaml:"maxAttempts" valid:"isInRange,1,10"`
+	InitialBackoff string  `yaml:"initialBackoff" valid:"isDuration"`
+	MaxBackoff     string  `yaml:"maxBackoff" valid:"isDuration"`
+}
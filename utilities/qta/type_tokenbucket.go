@@ -0,0 +1,29 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qta
+
+import (
+	"cloud.google.com/go/firestore"
+)
+
+// TokenBucket rate limiter which state is persisted in firestore documents, so it is shared by all the instances of a function
+// The rate and burst are split evenly across shards documents to limit the write contention on a single document
+type TokenBucket struct {
+	burst           float64
+	documentPath    string
+	firestoreClient *firestore.Client
+	ratePerSecond   float64
+	shards          int64
+}
@@ -18,6 +18,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/solution"
@@ -31,6 +33,18 @@ func (deployment *Deployment) CheckArguments() (err error) {
 	flag.BoolVar(&deployment.Core.Commands.Deploy, "deploy", false, "deploy one microservice instance")
 	flag.BoolVar(&deployment.Core.Commands.Check, "check", false, "with -pipe it checks if configured instances have a cloud build trigger, with -deploy a running cloud function")
 	flag.BoolVar(&deployment.Core.Commands.Dumpsettings, "dump", false, fmt.Sprintf("dump all settings in %s", solution.SettingsFileName))
+	flag.BoolVar(&deployment.Core.Commands.Lint, "lint", false, "validates offline solution.yaml, service.yaml, instance.yaml and constraint.yaml files, no API call")
+	flag.BoolVar(&deployment.Core.Commands.MakeSchemas, "schema", false, fmt.Sprintf("writes JSON schemas of settings files in %s folder", solution.SchemasFolderName))
+	flag.BoolVar(&deployment.Core.Commands.NewRule, "newrule", false, "scaffolds a new monitor rule instance folder, with rego template, constraint and test fixture, to be used with -asset and -rule")
+	flag.StringVar(&deployment.NewRule.RuleName, "rule", "", "with -newrule, rule name in snake case e.g. sa_key_age")
+	flag.StringVar(&deployment.NewRule.PolicyLibraryPath, "policylib", "", "with -newrule, path to a local policy-library checkout to import a template from")
+	flag.StringVar(&deployment.NewRule.TemplateName, "template", "", "with -newrule and -policylib, template file name in the policy-library validator folder e.g. iam_sa_key_age")
+	flag.BoolVar(&deployment.Core.Commands.Replay, "replay", false, "republishes archived dead letters of one instance, one microservice, or all to their original topic, to be used after a fix")
+	flag.StringVar(&deployment.Replay.Since, "since", "", "with -replay, only dead letters archived within this duration e.g. 24h")
+	flag.BoolVar(&deployment.Core.Commands.Report, "report", false, "exports active violations from bigquery as SARIF 2.1.0 and JUnit XML files, rules described from monitor constraints")
+	flag.StringVar(&deployment.Report.Path, "reportpath", "ram_report", "with -report or -plan, path of the report files, .sarif and .junit.xml extensions are appended")
+	flag.StringVar(&deployment.Plan.Path, "plan", "", "evaluates offline a terraform show -json plan output against monitor constraints, writes a report and fails on violations")
+	flag.StringVar(&deployment.Plan.AncestryPath, "ancestry", "", "with -plan, ancestry path where the planned resources are deployed e.g. organization/123/folder/456")
 	flag.StringVar(&deployment.Core.RepositoryPath, "repo", ".", "Path to the root of the code repository")
 	flag.StringVar(&deployment.Core.RamcliServiceAccount, "ramclisa", "", "Email of Service Account used when running ramcli")
 	var assetType = flag.String("asset", "", "asset type e.g. k8s.io/Pod")
@@ -42,11 +56,63 @@ func (deployment *Deployment) CheckArguments() (err error) {
 	if err != nil {
 		return err
 	}
+	if deployment.Core.Commands.MakeSchemas {
+		// schemas do not depend on instances
+		return nil
+	}
+	if deployment.Plan.Path != "" {
+		deployment.Core.Commands.Plan = true
+		if deployment.Plan.AncestryPath == "" {
+			return fmt.Errorf("-plan requires -ancestry")
+		}
+		if !strings.HasPrefix(deployment.Plan.AncestryPath, "organization/") {
+			return fmt.Errorf("-ancestry must start with organization/ got %s", deployment.Plan.AncestryPath)
+		}
+		// plans are evaluated against all monitor instances
+		return nil
+	}
+	if deployment.Core.Commands.NewRule {
+		if *assetType == "" || deployment.NewRule.RuleName == "" {
+			return fmt.Errorf("-newrule requires -asset and -rule")
+		}
+		if (deployment.NewRule.PolicyLibraryPath == "") != (deployment.NewRule.TemplateName == "") {
+			return fmt.Errorf("-policylib and -template must be used together")
+		}
+		deployment.Core.AssetType = *assetType
+		return nil
+	}
+	if deployment.Core.Commands.Lint && (deployment.Core.Commands.Deploy || deployment.Core.Commands.MakeReleasePipeline) {
+		return fmt.Errorf("-lint cannot be used in conjuction with -pipe or -deploy")
+	}
+	if deployment.Core.Commands.Lint && *assetType != "" {
+		// instances are listed by asset type only when deploying, lint would validate none
+		return fmt.Errorf("-lint cannot be used in conjuction with -asset, use -service and -instance or no argument to lint all instances")
+	}
 	if deployment.Core.Commands.Check {
 		if !deployment.Core.Commands.MakeReleasePipeline && !deployment.Core.Commands.Deploy {
 			return fmt.Errorf("-check can be used only in conjuction with -pipe or -deploy")
 		}
 	}
+	if deployment.Core.Commands.Replay {
+		if deployment.Core.Commands.Deploy || deployment.Core.Commands.MakeReleasePipeline || deployment.Core.Commands.Lint {
+			return fmt.Errorf("-replay cannot be used in conjuction with -pipe, -deploy or -lint")
+		}
+		if *assetType != "" {
+			return fmt.Errorf("-replay uses -service and -instance, not -asset")
+		}
+		if deployment.Replay.Since != "" {
+			if _, err = time.ParseDuration(deployment.Replay.Since); err != nil {
+				return fmt.Errorf("-since %v", err)
+			}
+		}
+	}
+	if deployment.Core.Commands.Report {
+		if deployment.Core.Commands.Deploy || deployment.Core.Commands.MakeReleasePipeline || deployment.Core.Commands.Lint || deployment.Core.Commands.Replay {
+			return fmt.Errorf("-report cannot be used in conjuction with -pipe, -deploy, -lint or -replay")
+		}
+		// reports do not depend on instances
+		return nil
+	}
 	if deployment.Core.Commands.Deploy && deployment.Core.Commands.MakeReleasePipeline {
 		return fmt.Errorf("-pipe and -deploy are mutually exclusive, starts with -pipe then do -deploy")
 	}
@@ -0,0 +1,137 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+
+	"github.com/BrunoReboul/ram/services/convertauditlog2feed"
+	"github.com/BrunoReboul/ram/services/convertlog2feed"
+	"github.com/BrunoReboul/ram/services/dumpinventory"
+	"github.com/BrunoReboul/ram/services/expandgroupmembers"
+	"github.com/BrunoReboul/ram/services/getgroupsettings"
+	"github.com/BrunoReboul/ram/services/importsnapshot"
+	"github.com/BrunoReboul/ram/services/listgroupmembers"
+	"github.com/BrunoReboul/ram/services/listgroups"
+	"github.com/BrunoReboul/ram/services/listusers"
+	"github.com/BrunoReboul/ram/services/monitor"
+	"github.com/BrunoReboul/ram/services/publish2fs"
+	"github.com/BrunoReboul/ram/services/publish2scc"
+	"github.com/BrunoReboul/ram/services/reevaluate"
+	"github.com/BrunoReboul/ram/services/servecompliance"
+	"github.com/BrunoReboul/ram/services/setalerts"
+	"github.com/BrunoReboul/ram/services/setdashboards"
+	"github.com/BrunoReboul/ram/services/setfeeds"
+	"github.com/BrunoReboul/ram/services/setlogsinks"
+	"github.com/BrunoReboul/ram/services/splitdump"
+	"github.com/BrunoReboul/ram/services/stream2bq"
+	"github.com/BrunoReboul/ram/services/upload2gcs"
+)
+
+// microserviceNames list the microservices managed by ramcli
+var microserviceNames = []string{
+	"convertauditlog2feed",
+	"convertlog2feed",
+	"dumpinventory",
+	"expandgroupmembers",
+	"getgroupsettings",
+	"importsnapshot",
+	"listgroupmembers",
+	"listgroups",
+	"listusers",
+	"monitor",
+	"publish2fs",
+	"publish2scc",
+	"reevaluate",
+	"servecompliance",
+	"setalerts",
+	"setdashboards",
+	"setfeeds",
+	"setlogsinks",
+	"splitdump",
+	"stream2bq",
+	"upload2gcs",
+}
+
+// getInstanceSettings returns pointers to a microservice service and instance settings, default values set
+func getInstanceSettings(serviceName string) (serviceSettings interface{}, instanceSettings interface{}, err error) {
+	switch serviceName {
+	case "convertauditlog2feed":
+		instanceDeployment := convertauditlog2feed.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "convertlog2feed":
+		instanceDeployment := convertlog2feed.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "dumpinventory":
+		instanceDeployment := dumpinventory.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "expandgroupmembers":
+		instanceDeployment := expandgroupmembers.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "getgroupsettings":
+		instanceDeployment := getgroupsettings.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "importsnapshot":
+		instanceDeployment := importsnapshot.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "listgroupmembers":
+		instanceDeployment := listgroupmembers.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "listgroups":
+		instanceDeployment := listgroups.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "listusers":
+		instanceDeployment := listusers.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "monitor":
+		instanceDeployment := monitor.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "publish2fs":
+		instanceDeployment := publish2fs.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "publish2scc":
+		instanceDeployment := publish2scc.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "reevaluate":
+		instanceDeployment := reevaluate.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "servecompliance":
+		instanceDeployment := servecompliance.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "setalerts":
+		instanceDeployment := setalerts.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "setdashboards":
+		instanceDeployment := setdashboards.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "setfeeds":
+		instanceDeployment := setfeeds.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "setlogsinks":
+		instanceDeployment := setlogsinks.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "splitdump":
+		instanceDeployment := splitdump.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "stream2bq":
+		instanceDeployment := stream2bq.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	case "upload2gcs":
+		instanceDeployment := upload2gcs.NewInstanceDeployment()
+		return &instanceDeployment.Settings.Service, &instanceDeployment.Settings.Instance, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown microservice %s", serviceName)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"testing"
+)
+
+func TestUnitGetInstanceSettings(t *testing.T) {
+	for _, serviceName := range microserviceNames {
+		serviceName := serviceName // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(serviceName, func(t *testing.T) {
+			t.Parallel()
+			serviceSettings, instanceSettings, err := getInstanceSettings(serviceName)
+			if err != nil {
+				t.Errorf("Did not expect an error an got %s", err.Error())
+			}
+			if serviceSettings == nil || instanceSettings == nil {
+				t.Errorf("Want settings for service %s got nil", serviceName)
+			}
+		})
+	}
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := getInstanceSettings("blabla")
+		if err == nil {
+			t.Errorf("Expect an error for an unknown microservice name, did not get it")
+		}
+	})
+}
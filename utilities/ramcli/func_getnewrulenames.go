@@ -0,0 +1,57 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/cai"
+)
+
+var ruleNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*[a-z0-9]$`)
+
+// directoryTriggerTopics GCI directory asset types are not published by CAI feeds but by the groups microservices
+var directoryTriggerTopics = map[string]string{
+	"www.googleapis.com/admin/directory/members":          "gci-groupMembers",
+	"www.googleapis.com/admin/directory/effectiveMembers": "gci-groupEffectiveMembers",
+	"groupssettings.googleapis.com/groupSettings":         "gci-groupSettings",
+}
+
+// getNewRuleNames derives from an asset type and a rule name the monitor instance name, the constraint name, the default template kind and the trigger topic
+func getNewRuleNames(assetType, ruleName string) (instanceName, constraintName, kind, triggerTopic string, err error) {
+	if !strings.Contains(assetType, "/") {
+		return "", "", "", "", fmt.Errorf("asset type '%s' should be like iam.googleapis.com/ServiceAccountKey", assetType)
+	}
+	if !ruleNameRegexp.MatchString(ruleName) {
+		return "", "", "", "", fmt.Errorf("rule name '%s' should be lower case snake case e.g. sa_key_age", ruleName)
+	}
+	assetShortTypeName := cai.GetAssetShortTypeName(assetType)
+	serviceShortName := strings.Split(assetShortTypeName, "-")[0]
+	triggerTopic = fmt.Sprintf("cai-rces-%s", assetShortTypeName)
+	if topicName, ok := directoryTriggerTopics[assetType]; ok {
+		serviceShortName = "gci"
+		triggerTopic = topicName
+	}
+	constraintName = fmt.Sprintf("%s_%s", serviceShortName, ruleName)
+	instanceName = fmt.Sprintf("monitor_%s", constraintName)
+	kind = "GCP"
+	for _, part := range strings.Split(constraintName, "_") {
+		kind = kind + strings.ToUpper(part[:1]) + part[1:]
+	}
+	kind = kind + "ConstraintV1"
+	return instanceName, constraintName, kind, triggerTopic, nil
+}
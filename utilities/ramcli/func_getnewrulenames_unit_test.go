@@ -0,0 +1,101 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"testing"
+)
+
+func TestUnitGetNewRuleNames(t *testing.T) {
+	var testCases = []struct {
+		name               string
+		assetType          string
+		ruleName           string
+		wantError          bool
+		wantInstanceName   string
+		wantConstraintName string
+		wantKind           string
+		wantTriggerTopic   string
+	}{
+		{
+			name:               "iamServiceAccountKey",
+			assetType:          "iam.googleapis.com/ServiceAccountKey",
+			ruleName:           "sa_key_age",
+			wantInstanceName:   "monitor_iam_sa_key_age",
+			wantConstraintName: "iam_sa_key_age",
+			wantKind:           "GCPIamSaKeyAgeConstraintV1",
+			wantTriggerTopic:   "cai-rces-iam-ServiceAccountKey",
+		},
+		{
+			name:               "k8s",
+			assetType:          "rbac.authorization.k8s.io/ClusterRole",
+			ruleName:           "no_wildcard",
+			wantInstanceName:   "monitor_k8srbac_no_wildcard",
+			wantConstraintName: "k8srbac_no_wildcard",
+			wantKind:           "GCPK8srbacNoWildcardConstraintV1",
+			wantTriggerTopic:   "cai-rces-k8srbac-ClusterRole",
+		},
+		{
+			name:               "gciEffectiveMembers",
+			assetType:          "www.googleapis.com/admin/directory/effectiveMembers",
+			ruleName:           "no_external_user",
+			wantInstanceName:   "monitor_gci_no_external_user",
+			wantConstraintName: "gci_no_external_user",
+			wantKind:           "GCPGciNoExternalUserConstraintV1",
+			wantTriggerTopic:   "gci-groupEffectiveMembers",
+		},
+		{
+			name:      "invalidAssetType",
+			assetType: "ServiceAccountKey",
+			ruleName:  "sa_key_age",
+			wantError: true,
+		},
+		{
+			name:      "invalidRuleName",
+			assetType: "iam.googleapis.com/ServiceAccountKey",
+			ruleName:  "SA-key-age",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			instanceName, constraintName, kind, triggerTopic, err := getNewRuleNames(tc.assetType, tc.ruleName)
+			if err != nil {
+				if !tc.wantError {
+					t.Errorf("Did not expect an error an got %s", err.Error())
+				}
+				return
+			}
+			if tc.wantError {
+				t.Errorf("Expect an error and did not get it")
+			}
+			if instanceName != tc.wantInstanceName {
+				t.Errorf("Want instanceName %s got %s", tc.wantInstanceName, instanceName)
+			}
+			if constraintName != tc.wantConstraintName {
+				t.Errorf("Want constraintName %s got %s", tc.wantConstraintName, constraintName)
+			}
+			if kind != tc.wantKind {
+				t.Errorf("Want kind %s got %s", tc.wantKind, kind)
+			}
+			if triggerTopic != tc.wantTriggerTopic {
+				t.Errorf("Want triggerTopic %s got %s", tc.wantTriggerTopic, triggerTopic)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/BrunoReboul/ram/services/monitor"
+	"github.com/BrunoReboul/ram/utilities/rpt"
+	"github.com/BrunoReboul/ram/utilities/tfp"
+)
+
+// getPlanReport evaluates the assets planned in a terraform show -json output against the monitor instances of the repository
+func getPlanReport(ctx context.Context, repositoryPath string, planPath string, ancestryPath string) (report rpt.Report, err error) {
+	planJSON, err := ioutil.ReadFile(planPath)
+	if err != nil {
+		return report, err
+	}
+	assets, unsupportedTypes, err := tfp.GetAssets(planJSON, ancestryPath)
+	if err != nil {
+		return report, err
+	}
+	if len(unsupportedTypes) > 0 {
+		log.Printf("not evaluated, unsupported resource types: %s", strings.Join(unsupportedTypes, ", "))
+	}
+	assetsJSONDocument, err := json.Marshal(assets)
+	if err != nil {
+		return report, fmt.Errorf("json.Marshal(assets) %v", err)
+	}
+	assetsByName := make(map[string]tfp.Asset)
+	for _, asset := range assets {
+		assetsByName[asset.Name] = asset
+	}
+
+	constraintFolderRelativePaths, err := GetConstraintFolderRelativePaths(repositoryPath)
+	if err != nil {
+		return report, err
+	}
+	report.Rules, err = getReportRules(repositoryPath, constraintFolderRelativePaths)
+	if err != nil {
+		return report, err
+	}
+	// constraint folders are sorted by instance, evaluate each instance once
+	var instanceFolderRelativePaths []string
+	for _, constraintFolderRelativePath := range constraintFolderRelativePaths {
+		instanceFolderRelativePath := strings.SplitN(constraintFolderRelativePath, "/constraints/", 2)[0]
+		if len(instanceFolderRelativePaths) == 0 || instanceFolderRelativePaths[len(instanceFolderRelativePaths)-1] != instanceFolderRelativePath {
+			instanceFolderRelativePaths = append(instanceFolderRelativePaths, instanceFolderRelativePath)
+		}
+	}
+	for _, instanceFolderRelativePath := range instanceFolderRelativePaths {
+		_, instanceName := getServiceAndInstanceNames(instanceFolderRelativePath)
+		offlineViolations, err := monitor.EvaluateOffline(ctx,
+			fmt.Sprintf("%s/%s", repositoryPath, instanceFolderRelativePath),
+			instanceName,
+			assetsJSONDocument)
+		if err != nil {
+			return report, fmt.Errorf("%s %v", instanceName, err)
+		}
+		for _, offlineViolation := range offlineViolations {
+			result := rpt.Result{
+				RuleName:       instanceName,
+				ConstraintName: offlineViolation.ConstraintName,
+				Severity:       offlineViolation.Severity,
+				AssetName:      offlineViolation.AssetName,
+				AssetType:      assetsByName[offlineViolation.AssetName].AssetType,
+				Message:        offlineViolation.Message,
+				ArtifactURI:    planPath,
+			}
+			if offlineViolation.Metadata != nil {
+				result.Metadata, err = json.Marshal(offlineViolation.Metadata)
+				if err != nil {
+					return report, fmt.Errorf("json.Marshal(offlineViolation.Metadata) %v", err)
+				}
+			}
+			report.Results = append(report.Results, result)
+		}
+	}
+	log.Printf("plan %d asset(s) evaluated by %d rule instance(s)", len(assets), len(instanceFolderRelativePaths))
+	return report, nil
+}
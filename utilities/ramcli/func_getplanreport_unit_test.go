@@ -0,0 +1,55 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUnitGetPlanReport(t *testing.T) {
+	report, err := getPlanReport(context.Background(),
+		"./testdata/ram_config/standard",
+		"./testdata/plan/plan.json",
+		"organization/123/folder/456")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	wantAssetName := "//cloudsql.googleapis.com/projects/app-project/instances/app-db"
+	found := false
+	for _, result := range report.Results {
+		if result.AssetName != wantAssetName {
+			t.Errorf("Want asset name %s got %s", wantAssetName, result.AssetName)
+		}
+		if result.ArtifactURI != "./testdata/plan/plan.json" {
+			t.Errorf("Want artifact uri ./testdata/plan/plan.json got %s", result.ArtifactURI)
+		}
+		if result.RuleName == "monitor_cloudsql_ssl" {
+			found = true
+			if result.AssetType != "sqladmin.googleapis.com/Instance" {
+				t.Errorf("Want asset type sqladmin.googleapis.com/Instance got %s", result.AssetType)
+			}
+			if result.ConstraintName == "" || result.Severity == "" || result.Message == "" {
+				t.Errorf("Want constraint name, severity and message got %v", result)
+			}
+		}
+		if result.RuleName == "monitor_cloudsql_backup" {
+			t.Errorf("Want no backup violation as backups are enabled in the plan")
+		}
+	}
+	if !found {
+		t.Errorf("Want a monitor_cloudsql_ssl violation")
+	}
+}
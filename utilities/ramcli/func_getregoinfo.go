@@ -0,0 +1,41 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"regexp"
+)
+
+var regoAssetTypeRegexp = regexp.MustCompile(`(?m)^[^#\n]*asset_type\s*==\s*"([^"]+)"`)
+var regoMessageRegexp = regexp.MustCompile(`(?m)^[^#\n]*message\s*:?=\s*sprintf\("([^"]*)"`)
+
+// getRegoInfo extracts from a rego template the asset types checked and the violation message formats, skipping comments
+func getRegoInfo(regoContent string) (assetTypes []string, messages []string) {
+	assetTypes = getUniqueSubmatches(regoAssetTypeRegexp, regoContent)
+	messages = getUniqueSubmatches(regoMessageRegexp, regoContent)
+	return assetTypes, messages
+}
+
+// getUniqueSubmatches returns the first submatch of each match, without duplicates, in order of appearance
+func getUniqueSubmatches(re *regexp.Regexp, s string) (values []string) {
+	found := make(map[string]bool)
+	for _, matches := range re.FindAllStringSubmatch(s, -1) {
+		if !found[matches[1]] {
+			found[matches[1]] = true
+			values = append(values, matches[1])
+		}
+	}
+	return values
+}
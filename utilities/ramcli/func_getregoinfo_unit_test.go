@@ -0,0 +1,63 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnitGetRegoInfo(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		regoContent    string
+		wantAssetTypes []string
+		wantMessages   []string
+	}{
+		{
+			name: "twoRules",
+			regoContent: `
+deny[{"msg": message}] {
+    asset.asset_type == "sqladmin.googleapis.com/Instance"
+    message := sprintf("%v does not require SSL", [asset.name])
+}
+deny[{"msg": message}] {
+	asset.asset_type == "sqladmin.googleapis.com/Instance"
+# 	asset.asset_type == "compute.googleapis.com/Instance"
+	message := sprintf("%v backup not enabled", [asset.name])
+}`,
+			wantAssetTypes: []string{"sqladmin.googleapis.com/Instance"},
+			wantMessages:   []string{"%v does not require SSL", "%v backup not enabled"},
+		},
+		{
+			name:        "noMatch",
+			regoContent: "asset_types := lib.get_default(params, \"assettypes\", {\"**\"})\nmessage := sprintf(msg_text, [asset.name])\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assetTypes, messages := getRegoInfo(tc.regoContent)
+			if !reflect.DeepEqual(assetTypes, tc.wantAssetTypes) {
+				t.Errorf("Want asset types %v got %v", tc.wantAssetTypes, assetTypes)
+			}
+			if !reflect.DeepEqual(messages, tc.wantMessages) {
+				t.Errorf("Want messages %v got %v", tc.wantMessages, messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var regoPackageRegexp = regexp.MustCompile(`(?m)^package\s+templates\.gcp\.(\w+)\s*$`)
+
+// getRegoKind returns the constraint kind from the package declaration of a rego template e.g. package templates.gcp.GCPIAMRestrictServiceAccountKeyAgeConstraintV1
+func getRegoKind(regoContent string) (kind string, err error) {
+	matches := regoPackageRegexp.FindStringSubmatch(regoContent)
+	if len(matches) != 2 {
+		return "", fmt.Errorf("no 'package templates.gcp.<kind>' declaration found in rego template")
+	}
+	return matches[1], nil
+}
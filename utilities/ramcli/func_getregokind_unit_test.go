@@ -0,0 +1,59 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"testing"
+)
+
+func TestUnitGetRegoKind(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		regoContent string
+		wantKind    string
+		wantError   bool
+	}{
+		{
+			name:        "templatePackage",
+			regoContent: "# header\n\npackage templates.gcp.GCPIAMRestrictServiceAccountKeyAgeConstraintV1\n\nimport data.validator.gcp.lib as lib\n",
+			wantKind:    "GCPIAMRestrictServiceAccountKeyAgeConstraintV1",
+		},
+		{
+			name:        "otherPackage",
+			regoContent: "package validator.gcp.lib\n",
+			wantError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			kind, err := getRegoKind(tc.regoContent)
+			if err != nil {
+				if !tc.wantError {
+					t.Errorf("Did not expect an error an got %s", err.Error())
+				}
+			} else {
+				if tc.wantError {
+					t.Errorf("Expect an error and did not get it")
+				}
+				if kind != tc.wantKind {
+					t.Errorf("Want kind %s got %s", tc.wantKind, kind)
+				}
+			}
+		})
+	}
+}
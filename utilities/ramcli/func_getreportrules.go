@@ -0,0 +1,50 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/rpt"
+)
+
+// getReportRules describes the monitor rule constraints of the repository for reports
+func getReportRules(repositoryPath string, constraintFolderRelativePaths []string) (rules []rpt.Rule, err error) {
+	for _, constraintFolderRelativePath := range constraintFolderRelativePaths {
+		parts := strings.Split(constraintFolderRelativePath, "/")
+		ruleName := parts[3]
+		parts = strings.Split(ruleName, "_")
+		if len(parts) < 2 {
+			return rules, fmt.Errorf("unexpected rule name %s", ruleName)
+		}
+		var constraint constraintInfo
+		err = ffo.ReadUnmarshalYAML(fmt.Sprintf("%s/%s/constraint.yaml", repositoryPath, constraintFolderRelativePath), &constraint)
+		if err != nil {
+			return rules, err
+		}
+		rules = append(rules, rpt.Rule{
+			RuleName:       ruleName,
+			ConstraintName: constraint.Metadata.Name,
+			ServiceName:    parts[1],
+			Severity:       constraint.Spec.Severity,
+			Category:       constraint.Metadata.Annotations.Category,
+			Kind:           constraint.Kind,
+			Description:    constraint.Metadata.Annotations.Description,
+		})
+	}
+	return rules, nil
+}
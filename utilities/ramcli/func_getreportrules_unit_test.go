@@ -0,0 +1,49 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"testing"
+)
+
+func TestUnitGetReportRules(t *testing.T) {
+	repositoryPath := "./testdata/ram_config/standard"
+	constraintFolderRelativePaths, err := GetConstraintFolderRelativePaths(repositoryPath)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	rules, err := getReportRules(repositoryPath, constraintFolderRelativePaths)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(rules) != len(constraintFolderRelativePaths) {
+		t.Errorf("Want %d rules got %d", len(constraintFolderRelativePaths), len(rules))
+	}
+	found := false
+	for _, rule := range rules {
+		if rule.RuleName == "monitor_gke_disable_default_sa" {
+			found = true
+			if rule.ConstraintName == "" || rule.Severity == "" || rule.Description == "" {
+				t.Errorf("Want constraint name, severity and description from constraint metadata got %v", rule)
+			}
+			if rule.ServiceName != "gke" {
+				t.Errorf("Want service name gke got %s", rule.ServiceName)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Want rule monitor_gke_disable_default_sa")
+	}
+}
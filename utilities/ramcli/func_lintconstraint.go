@@ -0,0 +1,42 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/validater"
+)
+
+// lintConstraint validates a constraint.yaml file offline
+func lintConstraint(repositoryPath string, constraintFolderRelativePath string) (err error) {
+	constraintFilePath := fmt.Sprintf("%s/%s/constraint.yaml", repositoryPath, constraintFolderRelativePath)
+	var constraint constraintInfo
+	err = ffo.ReadUnmarshalYAML(constraintFilePath, &constraint)
+	if err != nil {
+		return fmt.Errorf("%s %v", constraintFilePath, err)
+	}
+	err = validater.ValidateStruct(&constraint, constraintFolderRelativePath)
+	if err != nil {
+		return fmt.Errorf("%s %v", constraintFilePath, err)
+	}
+	folderName := filepath.Base(constraintFolderRelativePath)
+	if constraint.Metadata.Name != folderName {
+		return fmt.Errorf("%s metadata name '%s' should be the constraint folder name '%s'", constraintFilePath, constraint.Metadata.Name, folderName)
+	}
+	return nil
+}
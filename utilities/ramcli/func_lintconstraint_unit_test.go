@@ -0,0 +1,89 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitLintConstraint(t *testing.T) {
+	var testCases = []struct {
+		name                         string
+		repositoryPath               string
+		constraintFolderRelativePath string
+		wantErrorMsg                 string
+	}{
+		{
+			name:                         "valid",
+			repositoryPath:               "testdata/lint/valid",
+			constraintFolderRelativePath: "services/monitor/instances/monitor_iam_sa_key_age/constraints/iam_sa_key_age",
+		},
+		{
+			name:                         "nameMismatch",
+			repositoryPath:               "testdata/lint/invalid",
+			constraintFolderRelativePath: "services/monitor/instances/monitor_iam_sa_key_age/constraints/iam_sa_key_age",
+			wantErrorMsg:                 "should be the constraint folder name",
+		},
+		{
+			name:                         "missingSeverity",
+			repositoryPath:               "testdata/lint/invalid",
+			constraintFolderRelativePath: "services/monitor/instances/monitor_iam_sa_key_age/constraints/no_severity",
+			wantErrorMsg:                 "settings validation failed",
+		},
+		{
+			name:                         "missingFile",
+			repositoryPath:               "testdata/lint/invalid",
+			constraintFolderRelativePath: "services/monitor/instances/monitor_iam_sa_key_age/constraints/blabla",
+			wantErrorMsg:                 "no such file or directory",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := lintConstraint(tc.repositoryPath, tc.constraintFolderRelativePath)
+			if err != nil {
+				if tc.wantErrorMsg == "" {
+					t.Errorf("Did not expect an error an got %s", err.Error())
+				} else {
+					if !strings.Contains(err.Error(), tc.wantErrorMsg) {
+						t.Errorf("Error message should contains '%s' and is", tc.wantErrorMsg)
+						t.Log(string('\n') + err.Error())
+					}
+				}
+			} else {
+				if tc.wantErrorMsg != "" {
+					t.Errorf("Expect this error did not get it %s", tc.wantErrorMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestUnitLintConstraintStandard(t *testing.T) {
+	repositoryPath := "testdata/ram_config/standard"
+	constraintFolderRelativePaths, err := GetConstraintFolderRelativePaths(repositoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, constraintFolderRelativePath := range constraintFolderRelativePaths {
+		err = lintConstraint(repositoryPath, constraintFolderRelativePath)
+		if err != nil {
+			t.Errorf("Did not expect an error an got %s", err.Error())
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"gopkg.in/yaml.v2"
+)
+
+// makeConstraintReadme writes the readme.md of one constraint from its constraint.yaml and the rego template of its rule
+func makeConstraintReadme(repositoryPath string, constraintFolderRelativePath string) (readme string, err error) {
+	parts := strings.Split(constraintFolderRelativePath, "/")
+	microserviceName := parts[3]
+	parts = strings.Split(microserviceName, "_")
+	pathServiceName := parts[1]
+	pathRuleName := strings.Replace(microserviceName, fmt.Sprintf("%s_%s_", parts[0], parts[1]), "", 1)
+
+	constraintFolderPath := fmt.Sprintf("%s/%s", repositoryPath, constraintFolderRelativePath)
+	var constraint constraintInfo
+	err = ffo.ReadUnmarshalYAML(fmt.Sprintf("%s/constraint.yaml", constraintFolderPath), &constraint)
+	if err != nil {
+		return "", err
+	}
+
+	// rego templates are in the instance folder, test files excluded
+	regoFilePaths, err := filepath.Glob(fmt.Sprintf("%s/../../*.rego", constraintFolderPath))
+	if err != nil {
+		return "", err
+	}
+	var regoContent string
+	for _, regoFilePath := range regoFilePaths {
+		if strings.HasSuffix(regoFilePath, "_test.rego") {
+			continue
+		}
+		bytes, err := ioutil.ReadFile(regoFilePath)
+		if err != nil {
+			return "", err
+		}
+		regoContent = regoContent + string(bytes)
+	}
+	assetTypes, messages := getRegoInfo(regoContent)
+
+	readme = fmt.Sprintf("# %s\n\n%s\n\n[Back to the compliance rules summary](../../../../readme.md)\n\n", constraint.Metadata.Name, constraint.Metadata.Annotations.Description)
+	readme = readme + "Property | Value\n--- | ---\n"
+	readme = readme + fmt.Sprintf("Service | **%s**\n", pathServiceName)
+	readme = readme + fmt.Sprintf("Rule | %s\n", pathRuleName)
+	readme = readme + fmt.Sprintf("Severity | *%s*\n", constraint.Spec.Severity)
+	readme = readme + fmt.Sprintf("Category | %s\n", constraint.Metadata.Annotations.Category)
+	readme = readme + fmt.Sprintf("Kind | `%s`\n", constraint.Kind)
+
+	readme = readme + "\n## Asset types\n\n"
+	if len(assetTypes) == 0 {
+		readme = readme + "Not explicit in the rego template, see the `assettypes` parameter when set\n"
+	}
+	for _, assetType := range assetTypes {
+		readme = readme + fmt.Sprintf("- `%s`\n", assetType)
+	}
+
+	readme = readme + "\n## Match\n\nTargets:\n\n"
+	readme = readme + makeMarkdownCodeList(constraint.Spec.Match.Target)
+	readme = readme + "\nExcludes:\n\n"
+	readme = readme + makeMarkdownCodeList(constraint.Spec.Match.Exclude)
+
+	readme = readme + "\n## Parameters\n\n"
+	if constraint.Spec.Parameters == nil {
+		readme = readme + "None\n"
+	} else {
+		bytes, err := yaml.Marshal(constraint.Spec.Parameters)
+		if err != nil {
+			return "", err
+		}
+		readme = readme + fmt.Sprintf("```yaml\n%s```\n", string(bytes))
+	}
+
+	readme = readme + "\n## Violation messages\n\n"
+	if len(messages) == 0 {
+		readme = readme + "Not explicit in the rego template\n"
+	}
+	for _, message := range messages {
+		readme = readme + fmt.Sprintf("- `%s`\n", message)
+	}
+
+	err = ioutil.WriteFile(fmt.Sprintf("%s/readme.md", constraintFolderPath), []byte(readme), 0644)
+	if err != nil {
+		return "", err
+	}
+	return readme, nil
+}
+
+// makeMarkdownCodeList formats a list of strings as markdown bullet points
+func makeMarkdownCodeList(values []string) (list string) {
+	if len(values) == 0 {
+		return "- none\n"
+	}
+	for _, value := range values {
+		list = list + fmt.Sprintf("- `%s`\n", value)
+	}
+	return list
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnitMakeConstraintReadme(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		repositoryPath string
+		wantContains   []string
+	}{
+		{
+			name:           "standard",
+			repositoryPath: "testdata/ram_config/standard",
+		},
+		{
+			name:           "onlyOneConstraint",
+			repositoryPath: "testdata/ram_config/onlyoneconstraint",
+			wantContains: []string{
+				"# myorg_sanboxes_europe_bq\n",
+				"Service | **bq**\n",
+				"Rule | dataset_location\n",
+				"Severity | *critical*\n",
+				"Category | Personnal Data Compliance\n",
+				"- `dns.googleapis.com/ManagedZone`\n",
+				"- `organization/`\n",
+				"- `%v: DNSSEC is not enabled.`\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repositoryPath := copyTestRepository(t, tc.repositoryPath)
+			defer os.RemoveAll(repositoryPath)
+			constraintFolderRelativePaths, err := GetConstraintFolderRelativePaths(repositoryPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, constraintFolderRelativePath := range constraintFolderRelativePaths {
+				readme, err := makeConstraintReadme(repositoryPath, constraintFolderRelativePath)
+				if err != nil {
+					t.Errorf("Did not expect an error an got %s", err.Error())
+					continue
+				}
+				for _, wantString := range tc.wantContains {
+					if !strings.Contains(readme, wantString) {
+						t.Errorf("Readme should contains '%s' and is", wantString)
+						t.Log(string('\n') + readme)
+					}
+				}
+				ouputfilePath := fmt.Sprintf("%s/%s/readme.md", repositoryPath, constraintFolderRelativePath)
+				if _, err = os.Stat(ouputfilePath); err != nil {
+					t.Errorf("Output file not found %s", ouputfilePath)
+				}
+			}
+		})
+	}
+}
+
+// copyTestRepository copies a testdata repository into a temporary folder so that generated files do not land in the tracked tree
+func copyTestRepository(t *testing.T, repositoryPath string) (tmpRepositoryPath string) {
+	tmpRepositoryPath, err := ioutil.TempDir("", "ram_config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = filepath.Walk(repositoryPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(repositoryPath, path)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(tmpRepositoryPath, relativePath)
+		if info.IsDir() {
+			return os.MkdirAll(targetPath, 0755)
+		}
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(targetPath, bytes, 0644)
+	})
+	if err != nil {
+		os.RemoveAll(tmpRepositoryPath)
+		t.Fatal(err)
+	}
+	return tmpRepositoryPath
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// constraintsHTMLTemplate static catalogue, rows are filtered client side
+const constraintsHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Compliance rules catalogue {{.Repository}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ddd; padding: 6px; text-align: left; vertical-align: top; }
+th { background: #f2f2f2; }
+.critical { color: #b71c1c; font-weight: bold; }
+.high, .major { color: #e65100; }
+</style>
+</head>
+<body>
+<h1>Compliance rules catalogue</h1>
+<p>Repository: <b>{{.Repository}}</b> - {{len .Rows}} constraints - <i>Timestamp</i> {{.Timestamp}}</p>
+<p><input type="search" id="filter" placeholder="Filter" onkeyup="filterRows()"></p>
+<table id="constraints">
+<tr><th>Service</th><th>Rule</th><th>Constraint</th><th>Severity</th><th>Category</th><th>Kind</th><th>Description</th></tr>
+{{- range .Rows}}
+<tr><td>{{.ServiceName}}</td><td>{{.RuleName}}</td><td><a href="{{.ReadmeRelativePath}}">{{.ConstraintName}}</a></td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Category}}</td><td>{{.Kind}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+<script>
+function filterRows() {
+  var filter = document.getElementById("filter").value.toLowerCase();
+  var rows = document.getElementById("constraints").getElementsByTagName("tr");
+  for (var i = 1; i < rows.length; i++) {
+    rows[i].style.display = rows[i].textContent.toLowerCase().indexOf(filter) > -1 ? "" : "none";
+  }
+}
+</script>
+</body>
+</html>
+`
+
+type constraintsHTMLRow struct {
+	ServiceName        string
+	RuleName           string
+	ConstraintName     string
+	Severity           string
+	Category           string
+	Kind               string
+	Description        string
+	ReadmeRelativePath string
+}
+
+// makeConstraintsHTML writes a browsable static HTML catalogue from the records of makeConstraintsCSV
+func makeConstraintsHTML(repositoryPath string, records [][]string) (page string, err error) {
+	absolutePath, err := filepath.Abs(repositoryPath)
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(absolutePath, "/")
+	data := struct {
+		Repository string
+		Timestamp  time.Time
+		Rows       []constraintsHTMLRow
+	}{
+		Repository: parts[len(parts)-1],
+		Timestamp:  time.Now(),
+	}
+	// first record is the header
+	for i, record := range records {
+		if i == 0 || len(record) < 7 {
+			continue
+		}
+		data.Rows = append(data.Rows, constraintsHTMLRow{
+			ServiceName:    record[0],
+			RuleName:       record[1],
+			ConstraintName: record[2],
+			Severity:       record[3],
+			Category:       record[4],
+			Kind:           record[5],
+			Description:    record[6],
+			ReadmeRelativePath: fmt.Sprintf("instances/monitor_%s_%s/constraints/%s/readme.md",
+				record[0],
+				record[1],
+				record[2]),
+		})
+	}
+	tmpl, err := template.New("constraints").Parse(constraintsHTMLTemplate)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return "", err
+	}
+	page = buffer.String()
+	err = ioutil.WriteFile(fmt.Sprintf("%s/%s/monitor/constraints.html", repositoryPath, solution.MicroserviceParentFolderName),
+		[]byte(page), 0644)
+	if err != nil {
+		return "", err
+	}
+	return page, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestUnitMakeConstraintsHTML(t *testing.T) {
+	repositoryPath := copyTestRepository(t, "testdata/ram_config/onlyoneconstraint")
+	defer os.RemoveAll(repositoryPath)
+	records := [][]string{
+		{"serviceName", "ruleName", "constraintName", "severity", "category", "kind", "description"},
+		{"bq", "dataset_location", "myorg_sanboxes_europe_bq", "critical", "Personnal Data Compliance", "GCPBigQueryDatasetLocationConstraintV1", "BQ Dataset must be in Europe <b>"},
+	}
+	page, err := makeConstraintsHTML(repositoryPath, records)
+	if err != nil {
+		t.Fatalf("Did not expect an error an got %s", err.Error())
+	}
+	for _, wantString := range []string{
+		`<a href="instances/monitor_bq_dataset_location/constraints/myorg_sanboxes_europe_bq/readme.md">myorg_sanboxes_europe_bq</a>`,
+		`<td class="critical">critical</td>`,
+		"BQ Dataset must be in Europe &lt;b&gt;",
+		"1 constraints",
+	} {
+		if !strings.Contains(page, wantString) {
+			t.Errorf("Page should contains '%s'", wantString)
+		}
+	}
+	ouputfilePath := fmt.Sprintf("%s/services/monitor/constraints.html", repositoryPath)
+	if _, err = os.Stat(ouputfilePath); err != nil {
+		t.Errorf("Output file not found %s", ouputfilePath)
+	}
+}
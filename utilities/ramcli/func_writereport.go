@@ -0,0 +1,43 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/BrunoReboul/ram/utilities/rpt"
+)
+
+// writeReport writes the report as <reportPath>.sarif and <reportPath>.junit.xml
+func writeReport(report rpt.Report, reportPath string) (err error) {
+	sarifJSON, err := report.SARIF()
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(fmt.Sprintf("%s.sarif", reportPath), sarifJSON, 0644); err != nil {
+		return err
+	}
+	junitXML, err := report.JUnit()
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(fmt.Sprintf("%s.junit.xml", reportPath), junitXML, 0644); err != nil {
+		return err
+	}
+	log.Printf("report written to %s.sarif and %s.junit.xml", reportPath, reportPath)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/BrunoReboul/ram/services/convertauditlog2feed"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// configureConvertauditlog2feedOrganizations
+func (deployment *Deployment) configureConvertauditlog2feedOrganizations() (err error) {
+	serviceName := "convertauditlog2feed"
+	sinkNameSuffix := "audit-resources"
+	if len(deployment.Core.SolutionSettings.Monitoring.AuditLogMappings) == 0 {
+		log.Printf("skip %s, no auditLogMappings in solution settings", serviceName)
+		return nil
+	}
+
+	log.Printf("configure %s %s", serviceName, sinkNameSuffix)
+	var convertauditlog2feedInstanceDeployment convertauditlog2feed.InstanceDeployment
+	convertauditlog2feedInstance := convertauditlog2feedInstanceDeployment.Settings.Instance
+	serviceFolderPath := fmt.Sprintf("%s/%s/%s", deployment.Core.RepositoryPath, solution.MicroserviceParentFolderName, serviceName)
+	if _, err := os.Stat(serviceFolderPath); os.IsNotExist(err) {
+		os.Mkdir(serviceFolderPath, 0755)
+	}
+	instancesFolderPath := fmt.Sprintf("%s/%s", serviceFolderPath, solution.InstancesFolderName)
+	if _, err := os.Stat(instancesFolderPath); os.IsNotExist(err) {
+		os.Mkdir(instancesFolderPath, 0755)
+	}
+
+	convertauditlog2feedInstance.AuditLog.Mappings = deployment.Core.SolutionSettings.Monitoring.AuditLogMappings
+	for _, organizationID := range deployment.Core.SolutionSettings.Monitoring.OrganizationIDs {
+		convertauditlog2feedInstance.GCF.TriggerTopic = fmt.Sprintf("log-org%s-%s", organizationID, sinkNameSuffix)
+		instanceFolderPath := strings.Replace(
+			fmt.Sprintf("%s/%s_org%s_%s",
+				instancesFolderPath,
+				serviceName,
+				organizationID,
+				sinkNameSuffix), "-", "_", -1)
+		if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+			os.Mkdir(instanceFolderPath, 0755)
+		}
+		if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), convertauditlog2feedInstance); err != nil {
+			return err
+		}
+		log.Printf("done %s", instanceFolderPath)
+	}
+	return nil
+}
@@ -54,6 +54,8 @@ func (deployment *Deployment) configureConvertlog2feedOrganizations() (err error
 			directoryCustomerID = organization.Owner.DirectoryCustomerId
 		}
 		convertlog2feedInstance.GCI.SuperAdminEmail = deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs[directoryCustomerID].SuperAdminEmail
+		convertlog2feedInstance.GCI.Keyless = deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs[directoryCustomerID].Keyless
+		convertlog2feedInstance.GCI.UserSettings = len(deployment.Core.SolutionSettings.Monitoring.ListUsersDefaultSchedulers) > 0
 
 		instanceFolderPath := strings.Replace(
 			fmt.Sprintf("%s/%s_org%s_%s",
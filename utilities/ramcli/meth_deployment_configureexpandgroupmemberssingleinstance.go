@@ -0,0 +1,61 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/BrunoReboul/ram/services/expandgroupmembers"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// configureExpandGroupMembersSingleInstance when directories are monitored and the effective members topic is set in solution.yaml
+func (deployment *Deployment) configureExpandGroupMembersSingleInstance() (err error) {
+	serviceName := "expandgroupmembers"
+	if len(deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs) == 0 ||
+		deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupEffectiveMembers == "" {
+		log.Printf("skip %s as no directory or no GCIGroupEffectiveMembers topic name in solution settings", serviceName)
+		return nil
+	}
+	log.Printf("configure %s single instance", serviceName)
+	var expandgroupmembersInstanceDeployment expandgroupmembers.InstanceDeployment
+	expandgroupmembersInstance := expandgroupmembersInstanceDeployment.Settings.Instance
+	serviceFolderPath := fmt.Sprintf("%s/%s/%s", deployment.Core.RepositoryPath, solution.MicroserviceParentFolderName, serviceName)
+	if _, err := os.Stat(serviceFolderPath); os.IsNotExist(err) {
+		os.Mkdir(serviceFolderPath, 0755)
+	}
+	instancesFolderPath := fmt.Sprintf("%s/%s", serviceFolderPath, solution.InstancesFolderName)
+	if _, err := os.Stat(instancesFolderPath); os.IsNotExist(err) {
+		os.Mkdir(instancesFolderPath, 0755)
+	}
+
+	expandgroupmembersInstance.GCF.TriggerTopic = deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupMembers
+	instanceFolderPath := strings.Replace(
+		fmt.Sprintf("%s/%s_single_instance",
+			instancesFolderPath,
+			serviceName), "-", "_", -1)
+	if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+		os.Mkdir(instanceFolderPath, 0755)
+	}
+	if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), expandgroupmembersInstance); err != nil {
+		return err
+	}
+	log.Printf("done %s", instanceFolderPath)
+	return nil
+}
@@ -41,7 +41,9 @@ func (deployment *Deployment) configureGetGroupSettingsDirectories() (err error)
 	}
 
 	for directoryCustomerID, directorySettings := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
+		getgroupsettingsInstance.GCI.DirectoryCustomerID = directoryCustomerID
 		getgroupsettingsInstance.GCI.SuperAdminEmail = directorySettings.SuperAdminEmail
+		getgroupsettingsInstance.GCI.Keyless = directorySettings.Keyless
 		getgroupsettingsInstance.GCF.TriggerTopic = fmt.Sprintf("gci-groups-%s", directoryCustomerID)
 
 		instanceFolderPath := strings.Replace(
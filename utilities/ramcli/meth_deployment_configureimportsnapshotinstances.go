@@ -0,0 +1,74 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/BrunoReboul/ram/services/importsnapshot"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// configureImportsnapshotInstances writes importsnapshot instance.yaml files and subfolders, one per source, when a snapshots bucket is set
+// Existing settings like AWS entity paths are kept
+func (deployment *Deployment) configureImportsnapshotInstances() (err error) {
+	serviceName := "importsnapshot"
+	if deployment.Core.SolutionSettings.Hosting.GCS.Buckets.Snapshots.Name == "" {
+		log.Printf("skip configure %s instances, no snapshots bucket in solution settings", serviceName)
+		return nil
+	}
+	log.Printf("configure %s instances", serviceName)
+	serviceFolderPath := fmt.Sprintf("%s/%s/%s", deployment.Core.RepositoryPath, solution.MicroserviceParentFolderName, serviceName)
+	if _, err := os.Stat(serviceFolderPath); os.IsNotExist(err) {
+		os.Mkdir(serviceFolderPath, 0755)
+	}
+	instancesFolderPath := fmt.Sprintf("%s/%s", serviceFolderPath, solution.InstancesFolderName)
+	if _, err := os.Stat(instancesFolderPath); os.IsNotExist(err) {
+		os.Mkdir(instancesFolderPath, 0755)
+	}
+
+	defaultObjectNameRegexes := map[string]string{
+		"aws":   `ConfigSnapshot/.*\.json(\.gz)?$`,
+		"azure": `^azure/.*\.json(l)?(\.gz)?$`}
+	for _, source := range []string{"aws", "azure"} {
+		var importsnapshotInstanceDeployment importsnapshot.InstanceDeployment
+		importsnapshotInstance := importsnapshotInstanceDeployment.Settings.Instance
+		instanceFolderPath := fmt.Sprintf("%s/%s_%s",
+			instancesFolderPath,
+			serviceName,
+			source)
+		if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+			os.Mkdir(instanceFolderPath, 0755)
+		}
+		instanceFilePath := fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName)
+		if _, err := os.Stat(instanceFilePath); err == nil {
+			if err = ffo.ReadUnmarshalYAML(instanceFilePath, &importsnapshotInstance); err != nil {
+				return err
+			}
+		}
+		importsnapshotInstance.Source = source
+		if importsnapshotInstance.ObjectNameRegex == "" {
+			importsnapshotInstance.ObjectNameRegex = defaultObjectNameRegexes[source]
+		}
+		if err = ffo.MarshalYAMLWrite(instanceFilePath, importsnapshotInstance); err != nil {
+			return err
+		}
+		log.Printf("done %s", instanceFolderPath)
+	}
+	return nil
+}
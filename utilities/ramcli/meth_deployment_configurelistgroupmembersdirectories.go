@@ -41,7 +41,9 @@ func (deployment *Deployment) configureListGroupMembersDirectories() (err error)
 	}
 
 	for directoryCustomerID, directorySettings := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
+		listgroupmembersInstance.GCI.DirectoryCustomerID = directoryCustomerID
 		listgroupmembersInstance.GCI.SuperAdminEmail = directorySettings.SuperAdminEmail
+		listgroupmembersInstance.GCI.Keyless = directorySettings.Keyless
 		listgroupmembersInstance.GCF.TriggerTopic = fmt.Sprintf("gci-groups-%s", directoryCustomerID)
 
 		instanceFolderPath := strings.Replace(
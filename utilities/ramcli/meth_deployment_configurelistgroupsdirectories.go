@@ -43,6 +43,8 @@ func (deployment *Deployment) configureListGroupsDirectories() (err error) {
 	for directoryCustomerID, directorySettings := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
 		listgroupsInstance.GCI.DirectoryCustomerID = directoryCustomerID
 		listgroupsInstance.GCI.SuperAdminEmail = directorySettings.SuperAdminEmail
+		listgroupsInstance.GCI.Keyless = directorySettings.Keyless
+		listgroupsInstance.GCI.Backend = directorySettings.Backend
 		listgroupsInstance.SCH.Schedulers = deployment.Core.SolutionSettings.Monitoring.ListGroupsDefaultSchedulers
 
 		instanceFolderPath := strings.Replace(
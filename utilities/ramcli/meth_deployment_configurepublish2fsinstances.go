@@ -92,6 +92,22 @@ func (deployment *Deployment) configurePublish2fsInstances() (err error) {
 	}
 	log.Printf("done %s", instanceFolderPath)
 
+	if deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupEffectiveMembers != "" {
+		publish2fsInstance.GCF.TriggerTopic = deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupEffectiveMembers
+		instanceFolderPath = strings.Replace(
+			fmt.Sprintf("%s/%s_%s",
+				instancesFolderPath,
+				serviceName,
+				publish2fsInstance.GCF.TriggerTopic), "-", "_", -1)
+		if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+			os.Mkdir(instanceFolderPath, 0755)
+		}
+		if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), publish2fsInstance); err != nil {
+			return err
+		}
+		log.Printf("done %s", instanceFolderPath)
+	}
+
 	for directoryCustomerID := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
 		publish2fsInstance.GCF.TriggerTopic = fmt.Sprintf("gci-groups-%s", directoryCustomerID)
 		instanceFolderPath = strings.Replace(
@@ -107,5 +123,42 @@ func (deployment *Deployment) configurePublish2fsInstances() (err error) {
 		}
 		log.Printf("done %s", instanceFolderPath)
 	}
+
+	if len(deployment.Core.SolutionSettings.Monitoring.ListUsersDefaultSchedulers) > 0 {
+		for directoryCustomerID := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
+			publish2fsInstance.GCF.TriggerTopic = fmt.Sprintf("gci-users-%s", directoryCustomerID)
+			instanceFolderPath = strings.Replace(
+				fmt.Sprintf("%s/%s_gci_users_%s",
+					instancesFolderPath,
+					serviceName,
+					directoryCustomerID), "-", "_", -1)
+			if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+				os.Mkdir(instanceFolderPath, 0755)
+			}
+			if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), publish2fsInstance); err != nil {
+				return err
+			}
+			log.Printf("done %s", instanceFolderPath)
+		}
+	}
+
+	// admin settings converted from admin activity logs
+	for directoryCustomerID := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
+		for _, topicPrefix := range []string{"gci-domainSettings", "gci-roleAssignments"} {
+			publish2fsInstance.GCF.TriggerTopic = fmt.Sprintf("%s-%s", topicPrefix, directoryCustomerID)
+			instanceFolderPath = strings.Replace(
+				fmt.Sprintf("%s/%s_%s",
+					instancesFolderPath,
+					serviceName,
+					publish2fsInstance.GCF.TriggerTopic), "-", "_", -1)
+			if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+				os.Mkdir(instanceFolderPath, 0755)
+			}
+			if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), publish2fsInstance); err != nil {
+				return err
+			}
+			log.Printf("done %s", instanceFolderPath)
+		}
+	}
 	return nil
 }
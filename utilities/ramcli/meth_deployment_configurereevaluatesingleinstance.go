@@ -0,0 +1,51 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/BrunoReboul/ram/services/reevaluate"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// configureReevaluateSingleInstance one HTTP API for the whole solution
+func (deployment *Deployment) configureReevaluateSingleInstance() (err error) {
+	serviceName := "reevaluate"
+	log.Printf("configure %s single instance", serviceName)
+	var reevaluateInstanceDeployment reevaluate.InstanceDeployment
+	reevaluateInstance := reevaluateInstanceDeployment.Settings.Instance
+	serviceFolderPath := fmt.Sprintf("%s/%s/%s", deployment.Core.RepositoryPath, solution.MicroserviceParentFolderName, serviceName)
+	if _, err := os.Stat(serviceFolderPath); os.IsNotExist(err) {
+		os.Mkdir(serviceFolderPath, 0755)
+	}
+	instancesFolderPath := fmt.Sprintf("%s/%s", serviceFolderPath, solution.InstancesFolderName)
+	if _, err := os.Stat(instancesFolderPath); os.IsNotExist(err) {
+		os.Mkdir(instancesFolderPath, 0755)
+	}
+
+	instanceFolderPath := fmt.Sprintf("%s/%s_single_instance", instancesFolderPath, serviceName)
+	if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+		os.Mkdir(instanceFolderPath, 0755)
+	}
+	if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), reevaluateInstance); err != nil {
+		return err
+	}
+	log.Printf("done %s", instanceFolderPath)
+	return nil
+}
@@ -22,10 +22,11 @@ import (
 
 	"github.com/BrunoReboul/ram/services/setdashboards"
 	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/mon"
 	"github.com/BrunoReboul/ram/utilities/solution"
 )
 
-// configureLogSinksOrganizations
+// configureSetDashboards one instance per dashboard
 func (deployment *Deployment) configureSetDashboards() (err error) {
 	serviceName := "setdashboards"
 	serviceFolderPath := fmt.Sprintf("%s/%s/%s",
@@ -45,8 +46,8 @@ func (deployment *Deployment) configureSetDashboards() (err error) {
 	}
 
 	var dashboards = map[string][]string{
-		"RAM core microservices":   []string{"dumpinventory", "splitdump", "monitor", "stream2bq", "publish2fs", "upload2gcs"},
-		"RAM groups microservices": []string{"convertlog2feed", "listgroups", "getgroupsettings", "listgroupmembers"},
+		"RAM core microservices":   []string{"dumpinventory", "splitdump", "monitor", "stream2bq", "publish2fs", "upload2gcs", "convertauditlog2feed"},
+		"RAM groups microservices": []string{"convertlog2feed", "listgroups", "listusers", "getgroupsettings", "listgroupmembers", "expandgroupmembers"},
 	}
 	setDashboardsInstance.MON.Columns = 4
 	setDashboardsInstance.MON.WidgetTypeList = []string{"widgetGCFActiveInstances", "widgetGCFExecutionCount", "widgetGCFExecutionTime", "widgetGCFMemoryUsage"}
@@ -69,5 +70,39 @@ func (deployment *Deployment) configureSetDashboards() (err error) {
 		}
 		log.Printf("done %s", instanceFolderPath)
 	}
+
+	displayName := "RAM pipeline"
+	topicNames := deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames
+	setDashboardsInstance.MON.DisplayName = displayName
+	setDashboardsInstance.MON.MicroServiceNameList = []string{}
+	setDashboardsInstance.MON.WidgetTypeList = []string{}
+	setDashboardsInstance.MON.Widgets = []mon.WidgetParameters{
+		{Kind: "widgetText", Variables: map[string]string{
+			"title":   "About",
+			"content": "End to end latencies, backlogs and violations of the RAM pipeline. Metrics prefixed with ram_ are log-based metrics set by the setalerts microservice."}},
+		{Kind: "widgetLogMetricE2ELatency", Variables: map[string]string{"microserviceName": "monitor"}},
+		{Kind: "widgetLogMetricE2ELatency", Variables: map[string]string{"microserviceName": "stream2bq"}},
+		{Kind: "widgetLogMetricViolations"},
+		{Kind: "widgetPubSubBacklog", Variables: map[string]string{"topicName": topicNames.RAMViolation}},
+		{Kind: "widgetPubSubOldestUnackedAge", Variables: map[string]string{"topicName": topicNames.RAMViolation}},
+		{Kind: "widgetPubSubBacklog", Variables: map[string]string{"topicName": topicNames.RAMComplianceStatus}},
+		{Kind: "widgetPubSubOldestUnackedAge", Variables: map[string]string{"topicName": topicNames.RAMComplianceStatus}},
+		{Kind: "widgetBigQueryStreamingInsertErrors"},
+		{Kind: "widgetFirestoreOps"},
+	}
+	instanceFolderPath := fmt.Sprintf("%s/%s_%s",
+		instancesFolderPath,
+		serviceName,
+		strings.ToLower(strings.Replace(displayName, " ", "_", -1)))
+	if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+		os.Mkdir(instanceFolderPath, 0755)
+	}
+	if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s",
+		instanceFolderPath,
+		solution.InstanceSettingsFileName),
+		setDashboardsInstance); err != nil {
+		return err
+	}
+	log.Printf("done %s", instanceFolderPath)
 	return nil
 }
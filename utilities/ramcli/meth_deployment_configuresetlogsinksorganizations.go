@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/BrunoReboul/ram/services/setlogsinks"
+	"github.com/BrunoReboul/ram/utilities/alm"
 	"github.com/BrunoReboul/ram/utilities/ffo"
 	"github.com/BrunoReboul/ram/utilities/solution"
 )
@@ -30,9 +31,21 @@ func (deployment *Deployment) configureLogSinksOrganizations() (err error) {
 	serviceName := "setlogsinks"
 	// Case activity group
 	sinkNameSuffix := "activity-group"
-	filter := `resource.type="audited_resource" AND logName:"logs/cloudaudit.googleapis.com%2Factivity" AND protoPayload.serviceName="admin.googleapis.com" AND protoPayload.methodName:"group"`
+	// the sink name is kept for backward compatibility, it also exports domain settings, delegated admin and optionally user events
+	eventTypesFilter := `protoPayload.metadata.event.eventType="DOMAIN_SETTINGS" OR protoPayload.metadata.event.eventType="DELEGATED_ADMIN_SETTINGS"`
+	if len(deployment.Core.SolutionSettings.Monitoring.ListUsersDefaultSchedulers) > 0 {
+		eventTypesFilter = eventTypesFilter + ` OR protoPayload.metadata.event.eventType="USER_SETTINGS"`
+	}
+	filter := fmt.Sprintf(`resource.type="audited_resource" AND logName:"logs/cloudaudit.googleapis.com%%2Factivity" AND protoPayload.serviceName="admin.googleapis.com" AND (protoPayload.methodName:"group" OR %s)`, eventTypesFilter)
+	sinkNameSuffixes := []string{sinkNameSuffix}
+	filters := map[string]string{sinkNameSuffix: filter}
+	// Case audit resources, GCP resources not covered by CAI real-time feeds
+	if len(deployment.Core.SolutionSettings.Monitoring.AuditLogMappings) > 0 {
+		sinkNameSuffixes = append(sinkNameSuffixes, "audit-resources")
+		filters["audit-resources"] = alm.BuildFilter(deployment.Core.SolutionSettings.Monitoring.AuditLogMappings)
+	}
 
-	log.Printf("configure %s %s", serviceName, sinkNameSuffix)
+	log.Printf("configure %s %v", serviceName, sinkNameSuffixes)
 	var setlogsinksInstanceDeployment setlogsinks.InstanceDeployment
 	setlogsinksInstance := setlogsinksInstanceDeployment.Settings.Instance
 	serviceFolderPath := fmt.Sprintf("%s/%s/%s", deployment.Core.RepositoryPath, solution.MicroserviceParentFolderName, serviceName)
@@ -44,25 +57,27 @@ func (deployment *Deployment) configureLogSinksOrganizations() (err error) {
 		os.Mkdir(instancesFolderPath, 0755)
 	}
 
-	for _, organizationID := range deployment.Core.SolutionSettings.Monitoring.OrganizationIDs {
-		setlogsinksInstance.LSK.Parent = fmt.Sprintf("organizations/%s", organizationID)
-		setlogsinksInstance.LSK.SinkNameSuffix = sinkNameSuffix
-		setlogsinksInstance.LSK.Filter = filter
-		setlogsinksInstance.LSK.TopicName = fmt.Sprintf("log-org%s-%s", organizationID, sinkNameSuffix)
+	for _, sinkNameSuffix := range sinkNameSuffixes {
+		for _, organizationID := range deployment.Core.SolutionSettings.Monitoring.OrganizationIDs {
+			setlogsinksInstance.LSK.Parent = fmt.Sprintf("organizations/%s", organizationID)
+			setlogsinksInstance.LSK.SinkNameSuffix = sinkNameSuffix
+			setlogsinksInstance.LSK.Filter = filters[sinkNameSuffix]
+			setlogsinksInstance.LSK.TopicName = fmt.Sprintf("log-org%s-%s", organizationID, sinkNameSuffix)
 
-		instanceFolderPath := strings.Replace(
-			fmt.Sprintf("%s/%s_org%s_%s",
-				instancesFolderPath,
-				serviceName,
-				organizationID,
-				sinkNameSuffix), "-", "_", -1)
-		if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
-			os.Mkdir(instanceFolderPath, 0755)
-		}
-		if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), setlogsinksInstance); err != nil {
-			return err
+			instanceFolderPath := strings.Replace(
+				fmt.Sprintf("%s/%s_org%s_%s",
+					instancesFolderPath,
+					serviceName,
+					organizationID,
+					sinkNameSuffix), "-", "_", -1)
+			if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+				os.Mkdir(instanceFolderPath, 0755)
+			}
+			if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), setlogsinksInstance); err != nil {
+				return err
+			}
+			log.Printf("done %s", instanceFolderPath)
 		}
-		log.Printf("done %s", instanceFolderPath)
 	}
 	return nil
 }
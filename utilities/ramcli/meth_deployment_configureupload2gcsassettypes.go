@@ -90,10 +90,52 @@ func (deployment *Deployment) configureUpload2gcsMetadataTypes() (err error) {
 		log.Printf("done %s", instanceFolderPath)
 	}
 
+	// users by directory
+	if len(deployment.Core.SolutionSettings.Monitoring.ListUsersDefaultSchedulers) > 0 {
+		for directoryCustomerID := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
+			upload2gcsInstance.GCF.TriggerTopic = fmt.Sprintf("gci-users-%s", directoryCustomerID)
+			instanceFolderPath := strings.Replace(
+				fmt.Sprintf("%s/%s_%s",
+					instancesFolderPath,
+					serviceName,
+					upload2gcsInstance.GCF.TriggerTopic), "-", "_", -1)
+			if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+				os.Mkdir(instanceFolderPath, 0755)
+			}
+			if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), upload2gcsInstance); err != nil {
+				return err
+			}
+			log.Printf("done %s", instanceFolderPath)
+		}
+	}
+
+	// admin settings by directory
+	for directoryCustomerID := range deployment.Core.SolutionSettings.Monitoring.DirectoryCustomerIDs {
+		for _, topicPrefix := range []string{"gci-domainSettings", "gci-roleAssignments"} {
+			upload2gcsInstance.GCF.TriggerTopic = fmt.Sprintf("%s-%s", topicPrefix, directoryCustomerID)
+			instanceFolderPath := strings.Replace(
+				fmt.Sprintf("%s/%s_%s",
+					instancesFolderPath,
+					serviceName,
+					upload2gcsInstance.GCF.TriggerTopic), "-", "_", -1)
+			if _, err := os.Stat(instanceFolderPath); os.IsNotExist(err) {
+				os.Mkdir(instanceFolderPath, 0755)
+			}
+			if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), upload2gcsInstance); err != nil {
+				return err
+			}
+			log.Printf("done %s", instanceFolderPath)
+		}
+	}
+
 	// group membership
 
 	for _, topicName := range []string{deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupMembers,
-		deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupSettings} {
+		deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupSettings,
+		deployment.Core.SolutionSettings.Hosting.Pubsub.TopicNames.GCIGroupEffectiveMembers} {
+		if topicName == "" {
+			continue
+		}
 		upload2gcsInstance.GCF.TriggerTopic = topicName
 		instanceFolderPath := strings.Replace(
 			fmt.Sprintf("%s/%s_%s",
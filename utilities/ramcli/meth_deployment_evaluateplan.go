@@ -0,0 +1,42 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"log"
+)
+
+// evaluatePlan evaluates offline a terraform plan against monitor constraints, writes the report and fails when violations are found
+func (deployment *Deployment) evaluatePlan() (err error) {
+	report, err := getPlanReport(deployment.Core.Ctx,
+		deployment.Core.RepositoryPath,
+		deployment.Plan.Path,
+		deployment.Plan.AncestryPath)
+	if err != nil {
+		return err
+	}
+	report.ToolVersion = deployment.Core.RAMVersion
+	if err = writeReport(report, deployment.Report.Path); err != nil {
+		return err
+	}
+	for _, result := range report.Results {
+		log.Printf("VIOLATION %s %s %s %s", result.Severity, result.RuleName, result.AssetName, result.Message)
+	}
+	if len(report.Results) > 0 {
+		return fmt.Errorf("found %d violation(s) in plan %s", len(report.Results), deployment.Plan.Path)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// lint validates offline the solution, service, instance and constraint settings, no API call
+func (deployment *Deployment) lint() (err error) {
+	if len(deployment.Core.InstanceFolderRelativePaths) == 0 {
+		return fmt.Errorf("no instance to lint")
+	}
+	errors := make([]error, 0)
+	var solutionSettings solution.Settings
+	solutionConfigFilePath := fmt.Sprintf("%s/%s", deployment.Core.RepositoryPath, solution.SolutionSettingsFileName)
+	err = ffo.ReadStrictValidate("", "SolutionSettings", solutionConfigFilePath, &solutionSettings)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	lintedServiceNames := make(map[string]bool)
+	for _, instanceFolderRelativePath := range deployment.Core.InstanceFolderRelativePaths {
+		serviceName, instanceName := getServiceAndInstanceNames(instanceFolderRelativePath)
+		serviceSettings, instanceSettings, err := getInstanceSettings(serviceName)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if !lintedServiceNames[serviceName] {
+			lintedServiceNames[serviceName] = true
+			serviceConfigFilePath := fmt.Sprintf("%s/%s/%s/%s", deployment.Core.RepositoryPath, solution.MicroserviceParentFolderName, serviceName, solution.ServiceSettingsFileName)
+			if _, err := os.Stat(serviceConfigFilePath); !os.IsNotExist(err) {
+				err = ffo.ReadStrictValidate(serviceName, "ServiceSettings", serviceConfigFilePath, serviceSettings)
+				if err != nil {
+					errors = append(errors, err)
+				}
+			}
+		}
+		instanceConfigFilePath := fmt.Sprintf("%s/%s/%s", deployment.Core.RepositoryPath, instanceFolderRelativePath, solution.InstanceSettingsFileName)
+		if _, err := os.Stat(instanceConfigFilePath); !os.IsNotExist(err) {
+			err = ffo.ReadStrictValidate(instanceName, "InstanceSettings", instanceConfigFilePath, instanceSettings)
+			if err != nil {
+				errors = append(errors, err)
+			}
+		}
+		if serviceName == "monitor" {
+			constraintFolderRelativePaths, err := ffo.GetChild(deployment.Core.RepositoryPath,
+				fmt.Sprintf("%s/%s", instanceFolderRelativePath, solution.RegoConstraintsFolderName))
+			if err != nil {
+				errors = append(errors, err)
+				continue
+			}
+			sort.Strings(constraintFolderRelativePaths)
+			for _, constraintFolderRelativePath := range constraintFolderRelativePaths {
+				err = lintConstraint(deployment.Core.RepositoryPath, constraintFolderRelativePath)
+				if err != nil {
+					errors = append(errors, err)
+				}
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		s := fmt.Sprintf("Found %d errors\n", len(errors))
+		for _, e := range errors {
+			s = s + e.Error() + "\n"
+		}
+		return fmt.Errorf("%s", s)
+	}
+	log.Printf("lint done, %d instance(s) are valid", len(deployment.Core.InstanceFolderRelativePaths))
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitLint(t *testing.T) {
+	instanceFolderRelativePaths := []string{
+		"services/monitor/instances/monitor_iam_sa_key_age",
+		"services/splitdump/instances/splitdump_single_instance",
+	}
+	var testCases = []struct {
+		name                        string
+		repositoryPath              string
+		instanceFolderRelativePaths []string
+		wantErrorMsg                string
+	}{
+		{
+			name:                        "valid",
+			repositoryPath:              "testdata/lint/valid",
+			instanceFolderRelativePaths: instanceFolderRelativePaths,
+		},
+		{
+			name:                        "invalid",
+			repositoryPath:              "testdata/lint/invalid",
+			instanceFolderRelativePaths: instanceFolderRelativePaths,
+			wantErrorMsg:                "Found 4 errors",
+		},
+		{
+			name:           "noInstance",
+			repositoryPath: "testdata/lint/valid",
+			wantErrorMsg:   "no instance to lint",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var deployment Deployment
+			deployment.Core.RepositoryPath = tc.repositoryPath
+			deployment.Core.InstanceFolderRelativePaths = tc.instanceFolderRelativePaths
+			err := deployment.lint()
+			if err != nil {
+				if tc.wantErrorMsg == "" {
+					t.Errorf("Did not expect an error an got %s", err.Error())
+				} else {
+					if !strings.Contains(err.Error(), tc.wantErrorMsg) {
+						t.Errorf("Error message should contains '%s' and is", tc.wantErrorMsg)
+						t.Log(string('\n') + err.Error())
+					}
+				}
+			} else {
+				if tc.wantErrorMsg != "" {
+					t.Errorf("Expect this error did not get it %s", tc.wantErrorMsg)
+				}
+			}
+		})
+	}
+}
@@ -28,9 +28,18 @@ func (deployment *Deployment) makeConstraintsOneFiles() (err error) {
 	if err != nil {
 		return err
 	}
-	if _, err = makeConstraintsCSV(deployment.Core.RepositoryPath, constraintFolderRelativePaths); err != nil {
+	records, err := makeConstraintsCSV(deployment.Core.RepositoryPath, constraintFolderRelativePaths)
+	if err != nil {
+		return err
+	}
+	if _, err = makeConstraintsHTML(deployment.Core.RepositoryPath, records); err != nil {
 		return err
 	}
+	for _, constraintFolderRelativePath := range constraintFolderRelativePaths {
+		if _, err = makeConstraintReadme(deployment.Core.RepositoryPath, constraintFolderRelativePath); err != nil {
+			return err
+		}
+	}
 	if _, err = makeConstraintsReadme(deployment.Core.RepositoryPath, cs); err != nil {
 		return err
 	}
@@ -0,0 +1,67 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/BrunoReboul/ram/utilities/schema"
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// makeSchemas writes the JSON schemas of solution.yaml, and of each microservice service.yaml and instance.yaml
+func (deployment *Deployment) makeSchemas() (err error) {
+	schemasFolderPath := fmt.Sprintf("%s/%s", deployment.Core.RepositoryPath, solution.SchemasFolderName)
+	err = os.MkdirAll(schemasFolderPath, 0755)
+	if err != nil {
+		return err
+	}
+	var solutionSettings solution.Settings
+	err = writeSchema(fmt.Sprintf("%s/solution.schema.json", schemasFolderPath),
+		schema.Reflect(&solutionSettings, solution.SolutionSettingsFileName))
+	if err != nil {
+		return err
+	}
+	for _, serviceName := range microserviceNames {
+		serviceSettings, instanceSettings, err := getInstanceSettings(serviceName)
+		if err != nil {
+			return err
+		}
+		err = writeSchema(fmt.Sprintf("%s/%s.service.schema.json", schemasFolderPath, serviceName),
+			schema.Reflect(serviceSettings, fmt.Sprintf("%s %s", serviceName, solution.ServiceSettingsFileName)))
+		if err != nil {
+			return err
+		}
+		err = writeSchema(fmt.Sprintf("%s/%s.instance.schema.json", schemasFolderPath, serviceName),
+			schema.Reflect(instanceSettings, fmt.Sprintf("%s %s", serviceName, solution.InstanceSettingsFileName)))
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("schemas written in %s", schemasFolderPath)
+	return nil
+}
+
+func writeSchema(path string, s *schema.Schema) (err error) {
+	bytes, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bytes, 0644)
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/BrunoReboul/ram/services/monitor"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/BrunoReboul/ram/utilities/str"
+)
+
+// regoTemplateSkeleton to be completed with the rule logic
+const regoTemplateSkeleton = `
+package templates.gcp.%s
+
+import data.validator.gcp.lib as lib
+
+deny[{
+	"msg": message,
+	"details": metadata,
+}] {
+	constraint := input.constraint
+	asset := input.asset
+	asset.asset_type == "%s"
+
+	lib.get_constraint_params(constraint, params)
+
+	# TODO replace with the rule logic comparing asset.resource.data to params
+	resource := asset.resource.data
+	resource.todo != params.todo
+
+	message := sprintf("%%v: TODO describe the violation", [asset.name])
+	metadata := {"resource": asset.name}
+}
+`
+
+// constraintSkeleton metadata name MUST be the constraint folder name
+const constraintSkeleton = `apiVersion: constraints.gatekeeper.sh/v1alpha1
+kind: %s
+metadata:
+  name: %s
+  annotations:
+    description: TODO describe the rule
+spec:
+  severity: medium
+  match:
+    target: [organization/]
+    exclude:
+  parameters:
+    todo: TODO
+`
+
+// regoTestSkeleton fixtures to be completed, run with opa test including the policy-library lib folder
+const regoTestSkeleton = `
+package templates.gcp.%s
+
+test_%s_violation {
+	violations := deny with input.asset as fixture_asset_violation with input.constraint as fixture_constraint
+	count(violations) == 1
+}
+
+test_%s_no_violation {
+	violations := deny with input.asset as fixture_asset_compliant with input.constraint as fixture_constraint
+	count(violations) == 0
+}
+
+fixture_constraint = {"spec": {"parameters": {"todo": "TODO"}}}
+
+fixture_asset_violation = {
+	"name": "//TODO/violation",
+	"asset_type": "%s",
+	"resource": {"data": {"todo": "violation"}},
+}
+
+fixture_asset_compliant = {
+	"name": "//TODO/compliant",
+	"asset_type": "%s",
+	"resource": {"data": {"todo": "TODO"}},
+}
+`
+
+// newRule scaffolds a monitor instance folder with instance.yaml, rego template, constraint and test fixture, no API call
+func (deployment *Deployment) newRule() (err error) {
+	instanceName, constraintName, kind, triggerTopic, err := getNewRuleNames(deployment.Core.AssetType, deployment.NewRule.RuleName)
+	if err != nil {
+		return err
+	}
+	instanceFolderPath := fmt.Sprintf("%s/%s/monitor/%s/%s", deployment.Core.RepositoryPath, solution.MicroserviceParentFolderName, solution.InstancesFolderName, instanceName)
+	if _, err := os.Stat(instanceFolderPath); !os.IsNotExist(err) {
+		return fmt.Errorf("instance folder already exists, nothing done %s", instanceFolderPath)
+	}
+
+	regoContent := str.YAMLDisclaimer + fmt.Sprintf(regoTemplateSkeleton, kind, deployment.Core.AssetType)
+	if deployment.NewRule.TemplateName != "" {
+		templateFilePath := fmt.Sprintf("%s/validator/%s.rego", deployment.NewRule.PolicyLibraryPath, deployment.NewRule.TemplateName)
+		bytes, err := ioutil.ReadFile(templateFilePath)
+		if err != nil {
+			return fmt.Errorf("ioutil.ReadFile %v", err)
+		}
+		kind, err = getRegoKind(string(bytes))
+		if err != nil {
+			return fmt.Errorf("%s %v", templateFilePath, err)
+		}
+		// policy-library templates come with their own license header
+		regoContent = string(bytes)
+		log.Printf("imported template %s kind %s", templateFilePath, kind)
+	}
+
+	constraintFolderPath := fmt.Sprintf("%s/%s/%s", instanceFolderPath, solution.RegoConstraintsFolderName, constraintName)
+	err = os.MkdirAll(constraintFolderPath, 0755)
+	if err != nil {
+		return fmt.Errorf("os.MkdirAll %v", err)
+	}
+
+	var monitorInstanceDeployment monitor.InstanceDeployment
+	monitorInstance := monitorInstanceDeployment.Settings.Instance
+	monitorInstance.GCF.TriggerTopic = triggerTopic
+	if err = ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", instanceFolderPath, solution.InstanceSettingsFileName), monitorInstance); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		fmt.Sprintf("%s/%s.rego", instanceFolderPath, instanceName):      regoContent,
+		fmt.Sprintf("%s/%s_test.rego", instanceFolderPath, instanceName): str.YAMLDisclaimer + fmt.Sprintf(regoTestSkeleton, kind, constraintName, constraintName, deployment.Core.AssetType, deployment.Core.AssetType),
+		fmt.Sprintf("%s/constraint.yaml", constraintFolderPath):          str.YAMLDisclaimer + fmt.Sprintf(constraintSkeleton, kind, constraintName),
+	}
+	for path, content := range files {
+		err = ioutil.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			return fmt.Errorf("ioutil.WriteFile %v", err)
+		}
+		log.Printf("done %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/BrunoReboul/ram/services/monitor"
+	"github.com/BrunoReboul/ram/utilities/ffo"
+)
+
+func TestUnitNewRule(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		policyLibraryPath string
+		templateName      string
+		wantKind          string
+	}{
+		{
+			name:     "skeleton",
+			wantKind: "GCPIamSaKeyAgeConstraintV1",
+		},
+		{
+			name:              "importTemplate",
+			policyLibraryPath: "testdata/policylib",
+			templateName:      "iam_service_account_key_age",
+			wantKind:          "GCPIAMRestrictServiceAccountKeyAgeConstraintV1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repositoryPath, err := ioutil.TempDir("", "newrule")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(repositoryPath)
+
+			var deployment Deployment
+			deployment.Core.RepositoryPath = repositoryPath
+			deployment.Core.AssetType = "iam.googleapis.com/ServiceAccountKey"
+			deployment.NewRule.RuleName = "sa_key_age"
+			deployment.NewRule.PolicyLibraryPath = tc.policyLibraryPath
+			deployment.NewRule.TemplateName = tc.templateName
+			err = deployment.newRule()
+			if err != nil {
+				t.Fatalf("Did not expect an error an got %s", err.Error())
+			}
+
+			instanceFolderRelativePath := "services/monitor/instances/monitor_iam_sa_key_age"
+			var instance monitor.InstanceDeployment
+			err = ffo.ReadValidate("monitor_iam_sa_key_age", "InstanceSettings",
+				fmt.Sprintf("%s/%s/instance.yaml", repositoryPath, instanceFolderRelativePath), &instance.Settings.Instance)
+			if err != nil {
+				t.Errorf("instance.yaml %v", err)
+			}
+			if instance.Settings.Instance.GCF.TriggerTopic != "cai-rces-iam-ServiceAccountKey" {
+				t.Errorf("Want triggerTopic cai-rces-iam-ServiceAccountKey got %s", instance.Settings.Instance.GCF.TriggerTopic)
+			}
+			err = lintConstraint(repositoryPath, instanceFolderRelativePath+"/constraints/iam_sa_key_age")
+			if err != nil {
+				t.Errorf("constraint.yaml %v", err)
+			}
+			for _, fileName := range []string{"monitor_iam_sa_key_age.rego", "monitor_iam_sa_key_age_test.rego", "constraints/iam_sa_key_age/constraint.yaml"} {
+				bytes, err := ioutil.ReadFile(fmt.Sprintf("%s/%s/%s", repositoryPath, instanceFolderRelativePath, fileName))
+				if err != nil {
+					t.Errorf("%v", err)
+					continue
+				}
+				if !strings.Contains(string(bytes), tc.wantKind) {
+					t.Errorf("%s should contains kind %s", fileName, tc.wantKind)
+				}
+			}
+
+			err = deployment.newRule()
+			if err == nil {
+				t.Errorf("Expect an error when the instance folder already exists and did not get it")
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/dlq"
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// replay republishes the archived dead letters of the selected instances to their original topic
+func (deployment *Deployment) replay() (err error) {
+	bucketName := deployment.Core.SolutionSettings.Hosting.GCS.Buckets.DeadLetters.Name
+	if bucketName == "" {
+		return fmt.Errorf("-replay requires hosting gcs buckets deadLetters names to be set in %s", solution.SolutionSettingsFileName)
+	}
+	var since time.Time
+	if deployment.Replay.Since != "" {
+		duration, err := time.ParseDuration(deployment.Replay.Since)
+		if err != nil {
+			return err
+		}
+		since = time.Now().Add(-duration)
+	}
+	var total int64
+	for _, instanceFolderRelativePath := range deployment.Core.InstanceFolderRelativePaths {
+		_, instanceName := getServiceAndInstanceNames(instanceFolderRelativePath)
+		replayedCount, err := dlq.Replay(deployment.Core.Ctx,
+			deployment.Core.Services.StorageClient,
+			deployment.Core.Services.PubsubPublisherClient,
+			deployment.Core.SolutionSettings.Hosting.ProjectID,
+			bucketName,
+			instanceName,
+			since)
+		if err != nil {
+			return fmt.Errorf("%s %v", instanceName, err)
+		}
+		if replayedCount > 0 {
+			log.Printf("%s replayed %d dead letter(s)", instanceName, replayedCount)
+		}
+		total = total + replayedCount
+	}
+	log.Printf("replayed %d dead letter(s) from %d instance(s)", total, len(deployment.Core.InstanceFolderRelativePaths))
+	return nil
+}
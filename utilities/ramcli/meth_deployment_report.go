@@ -0,0 +1,97 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ramcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/BrunoReboul/ram/utilities/rpt"
+	"google.golang.org/api/iterator"
+)
+
+// activeViolationsReportQuery columns of the active_violations view needed to report, NULL values are replaced by zero values to fit the row struct
+const activeViolationsReportQuery = `
+SELECT
+    functionConfig.functionName AS ruleName,
+    IFNULL(constraintConfig.metadata.name, "") AS constraintName,
+    IFNULL(constraintConfig.spec.severity, "") AS severity,
+    feedMessage.asset.name AS assetName,
+    IFNULL(feedMessage.asset.assetType, "") AS assetType,
+    IFNULL(nonCompliance.message, "") AS message,
+    IFNULL(nonCompliance.metadata, "") AS metadata
+FROM
+    %s
+ORDER BY
+    ruleName,
+    assetName`
+
+// activeViolationRow one row of the active_violations view
+type activeViolationRow struct {
+	RuleName       string `bigquery:"ruleName"`
+	ConstraintName string `bigquery:"constraintName"`
+	Severity       string `bigquery:"severity"`
+	AssetName      string `bigquery:"assetName"`
+	AssetType      string `bigquery:"assetType"`
+	Message        string `bigquery:"message"`
+	Metadata       string `bigquery:"metadata"`
+}
+
+// report exports the active violations from bigquery as SARIF and JUnit files
+func (deployment *Deployment) report() (err error) {
+	constraintFolderRelativePaths, err := GetConstraintFolderRelativePaths(deployment.Core.RepositoryPath)
+	if err != nil {
+		return err
+	}
+	var report rpt.Report
+	report.ToolVersion = deployment.Core.RAMVersion
+	report.Rules, err = getReportRules(deployment.Core.RepositoryPath, constraintFolderRelativePaths)
+	if err != nil {
+		return err
+	}
+
+	viewName := fmt.Sprintf("`%s.%s.active_violations`",
+		deployment.Core.SolutionSettings.Hosting.ProjectID,
+		deployment.Core.SolutionSettings.Hosting.Bigquery.Dataset.Name)
+	rowIterator, err := deployment.Core.Services.BigqueryClient.Query(fmt.Sprintf(activeViolationsReportQuery, viewName)).Read(deployment.Core.Ctx)
+	if err != nil {
+		return fmt.Errorf("query %s %v", viewName, err)
+	}
+	for {
+		var row activeViolationRow
+		err = rowIterator.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("rowIterator.Next %v", err)
+		}
+		result := rpt.Result{
+			RuleName:       row.RuleName,
+			ConstraintName: row.ConstraintName,
+			Severity:       row.Severity,
+			AssetName:      row.AssetName,
+			AssetType:      row.AssetType,
+			Message:        row.Message,
+		}
+		if row.Metadata != "" {
+			result.Metadata = json.RawMessage(row.Metadata)
+		}
+		report.Results = append(report.Results, result)
+	}
+	log.Printf("report %d rule constraint(s) %d active violation(s)", len(report.Rules), len(report.Results))
+	return writeReport(report, deployment.Report.Path)
+}
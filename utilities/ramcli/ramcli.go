@@ -25,6 +25,7 @@ import (
 	"cloud.google.com/go/firestore"
 	pubsub "cloud.google.com/go/pubsub/apiv1"
 	scheduler "cloud.google.com/go/scheduler/apiv1"
+	securitycenter "cloud.google.com/go/securitycenter/apiv1p1beta1"
 
 	"github.com/BrunoReboul/ram/utilities/cai"
 	"github.com/BrunoReboul/ram/utilities/ffo"
@@ -40,8 +41,11 @@ import (
 	"google.golang.org/api/cloudresourcemanager/v1"
 	cloudresourcemanagerv2 "google.golang.org/api/cloudresourcemanager/v2"
 	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/logging/v2"
 	"google.golang.org/api/monitoring/v1"
+	monitoringv3 "google.golang.org/api/monitoring/v3"
 	"google.golang.org/api/option"
+	"google.golang.org/api/run/v1"
 	"google.golang.org/api/serviceusage/v1"
 	"google.golang.org/api/sourcerepo/v1"
 )
@@ -52,7 +56,9 @@ func Initialize(ctx context.Context, deployment *Deployment) {
 	var err error
 	creds, err := google.FindDefaultCredentials(ctx, "https://www.googleapis.com/auth/cloud-platform")
 	if err != nil {
-		log.Fatalf("ERROR - google.FindDefaultCredentials %v", err)
+		// Offline commands like -lint and -schema do not need API clients
+		log.Printf("WARNING - google.FindDefaultCredentials %v", err)
+		return
 	}
 	deployment.Core.Services.AppengineAPIService, err = appengine.NewService(ctx, option.WithCredentials(creds))
 	if err != nil {
@@ -86,10 +92,18 @@ func Initialize(ctx context.Context, deployment *Deployment) {
 	if err != nil {
 		log.Fatalln(err)
 	}
+	deployment.Core.Services.LoggingService, err = logging.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		log.Fatalln(err)
+	}
 	deployment.Core.Services.MonitoringService, err = monitoring.NewService(ctx, option.WithCredentials(creds))
 	if err != nil {
 		log.Fatalln(err)
 	}
+	deployment.Core.Services.MonitoringServicev3, err = monitoringv3.NewService(ctx, option.WithCredentials(creds))
+	if err != nil {
+		log.Fatalln(err)
+	}
 	deployment.Core.Services.ServiceusageService, err = serviceusage.NewService(ctx, option.WithCredentials(creds))
 	if err != nil {
 		log.Fatalln(err)
@@ -98,6 +112,10 @@ func Initialize(ctx context.Context, deployment *Deployment) {
 	if err != nil {
 		log.Fatalln(err)
 	}
+	deployment.Core.Services.PubsubSubscriberClient, err = pubsub.NewSubscriberClient(ctx, option.WithCredentials(creds))
+	if err != nil {
+		log.Fatalln(err)
+	}
 	deployment.Core.Services.SourcerepoService, err = sourcerepo.NewService(ctx, option.WithCredentials(creds))
 	if err != nil {
 		log.Fatalln(err)
@@ -110,6 +128,10 @@ func Initialize(ctx context.Context, deployment *Deployment) {
 	if err != nil {
 		log.Fatalln(err)
 	}
+	deployment.Core.Services.SecuritycenterClient, err = securitycenter.NewClient(ctx, option.WithCredentials(creds))
+	if err != nil {
+		log.Fatalln(err)
+	}
 }
 
 // RAMCli Real-time Asset Monitor cli
@@ -120,6 +142,21 @@ func RAMCli(deployment *Deployment) (err error) {
 	}
 	log.Printf("goVersion %s, ramVersion %s", deployment.Core.GoVersion, deployment.Core.RAMVersion)
 
+	// Offline commands, no API call
+	switch true {
+	case deployment.Core.Commands.MakeSchemas:
+		return deployment.makeSchemas()
+	case deployment.Core.Commands.Lint:
+		return deployment.lint()
+	case deployment.Core.Commands.NewRule:
+		return deployment.newRule()
+	case deployment.Core.Commands.Plan:
+		return deployment.evaluatePlan()
+	}
+	if deployment.Core.Services.CloudresourcemanagerService == nil {
+		return fmt.Errorf("ERROR - API clients not initialized, missing google default credentials")
+	}
+
 	solutionConfigFilePath := fmt.Sprintf("%s/%s", deployment.Core.RepositoryPath, solution.SolutionSettingsFileName)
 	err = ffo.ReadValidate("", "SolutionSettings", solutionConfigFilePath, &deployment.Core.SolutionSettings)
 	if err != nil {
@@ -143,6 +180,14 @@ func RAMCli(deployment *Deployment) (err error) {
 		return err
 	}
 
+	// Cloud run client cannot be initiated in the Intialize func as other clients as it requires the regional endpoint that is know only at this stage
+	deployment.Core.Services.RunService, err = run.NewService(deployment.Core.Ctx,
+		option.WithCredentials(creds),
+		option.WithEndpoint(fmt.Sprintf("https://%s-run.googleapis.com/", deployment.Core.SolutionSettings.Hosting.GCF.Region)))
+	if err != nil {
+		return err
+	}
+
 	if deployment.Core.AssetType != "" {
 		// For one (new) assetType build the list of related instances to deploy accross services. aka transversal point of view
 		// Cannot be done in checkarguments like for other deployments as requires orgIDs list that is available only after ReadValidate
@@ -230,27 +275,51 @@ func RAMCli(deployment *Deployment) (err error) {
 		if err = deployment.configureStream2bqAssetTypes(); err != nil {
 			return err
 		}
+		if err = deployment.configurePublish2sccInstances(); err != nil {
+			return err
+		}
+		if err = deployment.configureImportsnapshotInstances(); err != nil {
+			return err
+		}
 		if err = deployment.configureUpload2gcsMetadataTypes(); err != nil {
 			return err
 		}
 		if err = deployment.configureListGroupsDirectories(); err != nil {
 			return err
 		}
+		if err = deployment.configureListUsersDirectories(); err != nil {
+			return err
+		}
 		if err = deployment.configureListGroupMembersDirectories(); err != nil {
 			return err
 		}
 		if err = deployment.configureGetGroupSettingsDirectories(); err != nil {
 			return err
 		}
+		if err = deployment.configureExpandGroupMembersSingleInstance(); err != nil {
+			return err
+		}
 		if err = deployment.configureLogSinksOrganizations(); err != nil {
 			return err
 		}
 		if err = deployment.configureConvertlog2feedOrganizations(); err != nil {
 			return err
 		}
+		if err = deployment.configureConvertauditlog2feedOrganizations(); err != nil {
+			return err
+		}
 		if err = deployment.configureSetDashboards(); err != nil {
 			return err
 		}
+		if err = deployment.configureSetAlerts(); err != nil {
+			return err
+		}
+		if err = deployment.configureServeComplianceSingleInstance(); err != nil {
+			return err
+		}
+		if err = deployment.configureReevaluateSingleInstance(); err != nil {
+			return err
+		}
 	case deployment.Core.Commands.Deploy || deployment.Core.Commands.MakeReleasePipeline:
 		log.Printf("found %d instance(s)", len(deployment.Core.InstanceFolderRelativePaths))
 		if err = deployment.makeConstraintsOneFiles(); err != nil {
@@ -286,16 +355,32 @@ func RAMCli(deployment *Deployment) (err error) {
 				err = deployment.deployUpload2gcs()
 			case "listgroups":
 				err = deployment.deployListGroups()
+			case "listusers":
+				err = deployment.deployListUsers()
 			case "listgroupmembers":
 				err = deployment.deployListGroupMembers()
 			case "getgroupsettings":
 				err = deployment.deployGetGroupSettings()
+			case "expandgroupmembers":
+				err = deployment.deployExpandGroupMembers()
 			case "setlogsinks":
 				err = deployment.deploySetLogSinks()
 			case "convertlog2feed":
 				err = deployment.deployConvertLog2Feed()
+			case "convertauditlog2feed":
+				err = deployment.deployConvertAuditLog2Feed()
 			case "setdashboards":
 				err = deployment.deploySetDashboards()
+			case "setalerts":
+				err = deployment.deploySetAlerts()
+			case "servecompliance":
+				err = deployment.deployServeCompliance()
+			case "reevaluate":
+				err = deployment.deployReevaluate()
+			case "publish2scc":
+				err = deployment.deployPublish2scc()
+			case "importsnapshot":
+				err = deployment.deployImportsnapshot()
 			}
 			if breakOnFirstError {
 				if err != nil {
@@ -316,6 +401,14 @@ func RAMCli(deployment *Deployment) (err error) {
 				return fmt.Errorf("%s", s)
 			}
 		}
+	case deployment.Core.Commands.Replay:
+		if err = deployment.replay(); err != nil {
+			return err
+		}
+	case deployment.Core.Commands.Report:
+		if err = deployment.report(); err != nil {
+			return err
+		}
 	default:
 		if err = deployment.makeConstraintsOneFiles(); err != nil {
 			return err
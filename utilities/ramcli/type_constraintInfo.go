@@ -15,15 +15,21 @@
 package ramcli
 
 type constraintInfo struct {
-	Kind     string
-	Metadata struct {
-		Name        string
+	APIVersion string `yaml:"apiVersion" valid:"isNotZeroValue"`
+	Kind       string `valid:"isNotZeroValue"`
+	Metadata   struct {
+		Name        string `valid:"isNotZeroValue"`
 		Annotations struct {
-			Description string
+			Description string `valid:"isNotZeroValue"`
 			Category    string
 		}
 	}
 	Spec struct {
-		Severity string
+		Severity string `valid:"isNotZeroValue;isOneOf,critical,high,major,medium,low"`
+		Match    struct {
+			Target  []string
+			Exclude []string
+		}
+		Parameters interface{}
 	}
 }
@@ -31,4 +31,19 @@ type Deployment struct {
 			GCB gcb.Parameters
 		}
 	}
+	NewRule struct {
+		RuleName          string
+		PolicyLibraryPath string
+		TemplateName      string
+	} `yaml:"-"`
+	Replay struct {
+		Since string
+	} `yaml:"-"`
+	Report struct {
+		Path string
+	} `yaml:"-"`
+	Plan struct {
+		Path         string
+		AncestryPath string
+	} `yaml:"-"`
 }
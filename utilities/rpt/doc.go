@@ -0,0 +1,20 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpt reports compliance results in formats native to CI systems and code-scanning UIs
+//
+// SARIF 2.1.0: one rule per constraint, one result per violation, located on the asset name.
+//
+// JUnit XML: one test suite per rule, one test case per evaluated asset, failing when the asset is not compliant.
+package rpt
@@ -0,0 +1,61 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpt
+
+import "strings"
+
+// getSeverityRank orders constraint severities from 4 critical to 1 low, 0 when unknown
+func getSeverityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 4
+	case "high", "major":
+		return 3
+	case "medium":
+		return 2
+	case "low", "minor":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// getSARIFLevel maps a constraint severity to a SARIF result level
+func getSARIFLevel(severity string) string {
+	switch getSeverityRank(severity) {
+	case 4, 3:
+		return "error"
+	case 1:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// getSecuritySeverity maps a constraint severity to the CVSS like score used by code-scanning UIs to rank security findings
+func getSecuritySeverity(severity string) string {
+	switch getSeverityRank(severity) {
+	case 4:
+		return "9.0"
+	case 3:
+		return "7.0"
+	case 2:
+		return "5.0"
+	case 1:
+		return "2.0"
+	default:
+		return ""
+	}
+}
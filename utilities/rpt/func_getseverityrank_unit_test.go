@@ -0,0 +1,51 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpt
+
+import (
+	"testing"
+)
+
+func TestUnitGetSeverityRank(t *testing.T) {
+	var testCases = []struct {
+		severity             string
+		wantRank             int
+		wantLevel            string
+		wantSecuritySeverity string
+	}{
+		{severity: "critical", wantRank: 4, wantLevel: "error", wantSecuritySeverity: "9.0"},
+		{severity: "high", wantRank: 3, wantLevel: "error", wantSecuritySeverity: "7.0"},
+		{severity: "major", wantRank: 3, wantLevel: "error", wantSecuritySeverity: "7.0"},
+		{severity: "Medium", wantRank: 2, wantLevel: "warning", wantSecuritySeverity: "5.0"},
+		{severity: "low", wantRank: 1, wantLevel: "note", wantSecuritySeverity: "2.0"},
+		{severity: "", wantRank: 0, wantLevel: "warning", wantSecuritySeverity: ""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.severity, func(t *testing.T) {
+			t.Parallel()
+			if got := getSeverityRank(tc.severity); got != tc.wantRank {
+				t.Errorf("Want rank %d got %d", tc.wantRank, got)
+			}
+			if got := getSARIFLevel(tc.severity); got != tc.wantLevel {
+				t.Errorf("Want level %s got %s", tc.wantLevel, got)
+			}
+			if got := getSecuritySeverity(tc.severity); got != tc.wantSecuritySeverity {
+				t.Errorf("Want security severity %s got %s", tc.wantSecuritySeverity, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// JUnit serializes the report as JUnit XML: one test suite per rule, one test case per asset failing on its violations
+func (report Report) JUnit() (junitXML []byte, err error) {
+	testSuites := junitTestSuites{Name: ToolName}
+	suiteIndexes := make(map[string]int)
+	caseIndexes := make(map[string]int)
+	severities := make(map[string]string)
+	for _, rule := range report.Rules {
+		severities[getRuleID(rule.RuleName, rule.ConstraintName)] = rule.Severity
+		if _, ok := suiteIndexes[rule.RuleName]; !ok {
+			suiteIndexes[rule.RuleName] = len(testSuites.TestSuites)
+			testSuites.TestSuites = append(testSuites.TestSuites, junitTestSuite{Name: rule.RuleName})
+		}
+	}
+	for _, result := range report.Results {
+		suiteIndex, ok := suiteIndexes[result.RuleName]
+		if !ok {
+			suiteIndex = len(testSuites.TestSuites)
+			suiteIndexes[result.RuleName] = suiteIndex
+			testSuites.TestSuites = append(testSuites.TestSuites, junitTestSuite{Name: result.RuleName})
+		}
+		testSuite := &testSuites.TestSuites[suiteIndex]
+		caseKey := fmt.Sprintf("%s\n%s", result.RuleName, result.AssetName)
+		caseIndex, ok := caseIndexes[caseKey]
+		if !ok {
+			caseIndex = len(testSuite.TestCases)
+			caseIndexes[caseKey] = caseIndex
+			testSuite.TestCases = append(testSuite.TestCases, junitTestCase{Name: result.AssetName, ClassName: result.RuleName})
+		}
+		if result.Compliant {
+			continue
+		}
+		testCase := &testSuite.TestCases[caseIndex]
+		severity := result.Severity
+		if severity == "" {
+			severity = severities[getRuleID(result.RuleName, result.ConstraintName)]
+		}
+		text := fmt.Sprintf("%s: %s", getRuleID(result.RuleName, result.ConstraintName), result.Message)
+		if len(result.Metadata) > 0 {
+			text = fmt.Sprintf("%s\n%s", text, string(result.Metadata))
+		}
+		if testCase.Failure == nil {
+			testCase.Failure = &junitFailure{Message: result.Message, Type: severity, Text: text}
+			continue
+		}
+		// several violations of the same asset: keep the most severe as failure type
+		testCase.Failure.Message = strings.Join([]string{testCase.Failure.Message, result.Message}, "; ")
+		testCase.Failure.Text = strings.Join([]string{testCase.Failure.Text, text}, "\n")
+		if getSeverityRank(severity) > getSeverityRank(testCase.Failure.Type) {
+			testCase.Failure.Type = severity
+		}
+	}
+	for i := range testSuites.TestSuites {
+		testSuite := &testSuites.TestSuites[i]
+		testSuite.Tests = len(testSuite.TestCases)
+		for _, testCase := range testSuite.TestCases {
+			if testCase.Failure != nil {
+				testSuite.Failures++
+			}
+		}
+		testSuites.Tests = testSuites.Tests + testSuite.Tests
+		testSuites.Failures = testSuites.Failures + testSuite.Failures
+	}
+	junitXML, err = xml.MarshalIndent(testSuites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), junitXML...), nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpt
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func TestUnitReportJUnit(t *testing.T) {
+	report := getTestReport()
+	report.Results = append(report.Results, Result{
+		RuleName:       "monitor_gcs_bucket_public",
+		ConstraintName: "gcs_bucket_public",
+		AssetName:      "//storage.googleapis.com/publicbucket",
+		Message:        "publicbucket is publicly writable",
+		Metadata:       json.RawMessage(`{"role":"roles/storage.objectCreator"}`),
+	})
+	junitXML, err := report.JUnit()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if !bytes.HasPrefix(junitXML, []byte(xml.Header)) {
+		t.Errorf("Want XML header")
+	}
+	var testSuites junitTestSuites
+	if err = xml.Unmarshal(junitXML, &testSuites); err != nil {
+		t.Fatalf("Unexpected error unmarshalling JUnit XML %v", err)
+	}
+	if testSuites.Tests != 4 || testSuites.Failures != 3 {
+		t.Errorf("Want 4 tests 3 failures got %d tests %d failures", testSuites.Tests, testSuites.Failures)
+	}
+	var testCases = []struct {
+		name     string
+		tests    int
+		failures int
+	}{
+		{name: "monitor_gcs_bucket_public", tests: 2, failures: 1},
+		{name: "monitor_gcs_bucket_retention", tests: 1, failures: 1},
+		{name: "monitor_bq_dataset_location", tests: 1, failures: 1},
+	}
+	if len(testSuites.TestSuites) != len(testCases) {
+		t.Fatalf("Want %d test suites got %d", len(testCases), len(testSuites.TestSuites))
+	}
+	for i, tc := range testCases {
+		testSuite := testSuites.TestSuites[i]
+		if testSuite.Name != tc.name || testSuite.Tests != tc.tests || testSuite.Failures != tc.failures {
+			t.Errorf("Want suite %s %d tests %d failures got %s %d tests %d failures",
+				tc.name, tc.tests, tc.failures, testSuite.Name, testSuite.Tests, testSuite.Failures)
+		}
+	}
+	failure := testSuites.TestSuites[0].TestCases[0].Failure
+	if failure == nil {
+		t.Fatalf("Want a failure for publicbucket")
+	}
+	if failure.Type != "critical" {
+		t.Errorf("Want failure type critical got %s", failure.Type)
+	}
+	if failure.Message != "publicbucket is publicly accessable; publicbucket is publicly writable" {
+		t.Errorf("Want both messages got %s", failure.Message)
+	}
+	if testSuites.TestSuites[0].TestCases[1].Failure != nil {
+		t.Errorf("Want privatebucket test case to pass")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpt
+
+import (
+	"encoding/json"
+)
+
+// SARIF serializes the report as a SARIF 2.1.0 log, compliant results are not reported
+func (report Report) SARIF() (sarifJSON []byte, err error) {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           ToolName,
+				InformationURI: toolInformationURI,
+				Version:        report.ToolVersion,
+				Rules:          make([]sarifRule, 0),
+			},
+		},
+		Results: make([]sarifResult, 0),
+	}
+	ruleIndexes := make(map[string]int)
+	for _, rule := range report.Rules {
+		ruleID := getRuleID(rule.RuleName, rule.ConstraintName)
+		if _, ok := ruleIndexes[ruleID]; ok {
+			continue
+		}
+		ruleIndexes[ruleID] = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, getSARIFRule(rule))
+	}
+	for _, result := range report.Results {
+		if result.Compliant {
+			continue
+		}
+		ruleID := getRuleID(result.RuleName, result.ConstraintName)
+		ruleIndex, ok := ruleIndexes[ruleID]
+		if !ok {
+			// rule not described, e.g. removed from the repository since the evaluation
+			ruleIndex = len(run.Tool.Driver.Rules)
+			ruleIndexes[ruleID] = ruleIndex
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, getSARIFRule(Rule{
+				RuleName:       result.RuleName,
+				ConstraintName: result.ConstraintName,
+				Severity:       result.Severity}))
+		}
+		severity := result.Severity
+		if severity == "" {
+			severity = run.Tool.Driver.Rules[ruleIndex].Properties["severity"].(string)
+		}
+		uri := result.ArtifactURI
+		if uri == "" {
+			uri = result.AssetName
+		}
+		sarifResult := sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndex,
+			Level:     getSARIFLevel(severity),
+			Message:   sarifMessage{Text: result.Message},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: result.AssetName, Kind: "resource"}},
+				},
+			},
+			PartialFingerprints: map[string]string{"ramViolation/v1": getFingerprint(result)},
+			Properties:          map[string]interface{}{"assetType": result.AssetType},
+		}
+		if len(result.Metadata) > 0 && json.Valid(result.Metadata) {
+			sarifResult.Properties["metadata"] = result.Metadata
+		}
+		run.Results = append(run.Results, sarifResult)
+	}
+	return json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+// getSARIFRule describes a rule constraint as a SARIF reporting descriptor
+func getSARIFRule(rule Rule) sarifRule {
+	sarifRule := sarifRule{
+		ID:                   getRuleID(rule.RuleName, rule.ConstraintName),
+		Name:                 rule.ConstraintName,
+		DefaultConfiguration: sarifConfiguration{Level: getSARIFLevel(rule.Severity)},
+		Properties:           map[string]interface{}{"severity": rule.Severity},
+	}
+	if rule.Description != "" {
+		sarifRule.ShortDescription = &sarifMessage{Text: rule.Description}
+	}
+	var tags []string
+	for _, tag := range []string{"security", rule.ServiceName, rule.Category} {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	sarifRule.Properties["tags"] = tags
+	if securitySeverity := getSecuritySeverity(rule.Severity); securitySeverity != "" {
+		sarifRule.Properties["security-severity"] = securitySeverity
+	}
+	if rule.Kind != "" {
+		sarifRule.Properties["kind"] = rule.Kind
+	}
+	return sarifRule
+}
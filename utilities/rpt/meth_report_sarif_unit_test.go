@@ -0,0 +1,145 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpt
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func getTestReport() Report {
+	return Report{
+		ToolVersion: "v0.0.1",
+		Rules: []Rule{
+			{
+				RuleName:       "monitor_gcs_bucket_public",
+				ConstraintName: "gcs_bucket_public",
+				ServiceName:    "gcs",
+				Severity:       "critical",
+				Category:       "Data protection",
+				Kind:           "GCPStorageBucketWorldReadableConstraintV1",
+				Description:    "Buckets must not be public",
+			},
+			{
+				RuleName:       "monitor_gcs_bucket_retention",
+				ConstraintName: "gcs_bucket_retention",
+				ServiceName:    "gcs",
+				Severity:       "low",
+				Description:    "Buckets must have a retention policy",
+			},
+		},
+		Results: []Result{
+			{
+				RuleName:       "monitor_gcs_bucket_public",
+				ConstraintName: "gcs_bucket_public",
+				AssetName:      "//storage.googleapis.com/publicbucket",
+				AssetType:      "storage.googleapis.com/Bucket",
+				Message:        "publicbucket is publicly accessable",
+				Metadata:       json.RawMessage(`{"resource":"//storage.googleapis.com/publicbucket"}`),
+			},
+			{
+				RuleName:       "monitor_gcs_bucket_public",
+				ConstraintName: "gcs_bucket_public",
+				AssetName:      "//storage.googleapis.com/privatebucket",
+				AssetType:      "storage.googleapis.com/Bucket",
+				Compliant:      true,
+			},
+			{
+				RuleName:       "monitor_gcs_bucket_retention",
+				ConstraintName: "gcs_bucket_retention",
+				AssetName:      "//storage.googleapis.com/publicbucket",
+				AssetType:      "storage.googleapis.com/Bucket",
+				Message:        "publicbucket has no retention policy",
+				ArtifactURI:    "plan.json",
+			},
+			{
+				RuleName:       "monitor_bq_dataset_location",
+				ConstraintName: "bq_dataset_location",
+				Severity:       "medium",
+				AssetName:      "//bigquery.googleapis.com/projects/p/datasets/d",
+				AssetType:      "bigquery.googleapis.com/Dataset",
+				Message:        "d is in a forbidden location",
+			},
+		},
+	}
+}
+
+func TestUnitReportSARIF(t *testing.T) {
+	sarifJSON, err := getTestReport().SARIF()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	var log sarifLog
+	if err = json.Unmarshal(sarifJSON, &log); err != nil {
+		t.Fatalf("Unexpected error unmarshalling SARIF %v", err)
+	}
+	if log.Version != "2.1.0" {
+		t.Errorf("Want version 2.1.0 got %s", log.Version)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("Want 1 run got %d", len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != ToolName || run.Tool.Driver.Version != "v0.0.1" {
+		t.Errorf("Want tool %s v0.0.1 got %s %s", ToolName, run.Tool.Driver.Name, run.Tool.Driver.Version)
+	}
+	// the undescribed rule of the last result is appended
+	if len(run.Tool.Driver.Rules) != 3 {
+		t.Fatalf("Want 3 rules got %d", len(run.Tool.Driver.Rules))
+	}
+	if run.Tool.Driver.Rules[0].ID != "monitor_gcs_bucket_public/gcs_bucket_public" {
+		t.Errorf("Want rule id monitor_gcs_bucket_public/gcs_bucket_public got %s", run.Tool.Driver.Rules[0].ID)
+	}
+	if run.Tool.Driver.Rules[0].ShortDescription == nil || run.Tool.Driver.Rules[0].ShortDescription.Text != "Buckets must not be public" {
+		t.Errorf("Want rule short description from the constraint description got %v", run.Tool.Driver.Rules[0].ShortDescription)
+	}
+	if run.Tool.Driver.Rules[0].Properties["security-severity"] != "9.0" {
+		t.Errorf("Want security-severity 9.0 got %v", run.Tool.Driver.Rules[0].Properties["security-severity"])
+	}
+	// compliant results are not reported
+	if len(run.Results) != 3 {
+		t.Fatalf("Want 3 results got %d", len(run.Results))
+	}
+	var testCases = []struct {
+		ruleIndex int
+		level     string
+		uri       string
+	}{
+		{ruleIndex: 0, level: "error", uri: "//storage.googleapis.com/publicbucket"},
+		{ruleIndex: 1, level: "note", uri: "plan.json"},
+		{ruleIndex: 2, level: "warning", uri: "//bigquery.googleapis.com/projects/p/datasets/d"},
+	}
+	for i, tc := range testCases {
+		result := run.Results[i]
+		if result.RuleIndex != tc.ruleIndex {
+			t.Errorf("Result %d want rule index %d got %d", i, tc.ruleIndex, result.RuleIndex)
+		}
+		if result.RuleID != run.Tool.Driver.Rules[result.RuleIndex].ID {
+			t.Errorf("Result %d want rule id %s got %s", i, run.Tool.Driver.Rules[result.RuleIndex].ID, result.RuleID)
+		}
+		if result.Level != tc.level {
+			t.Errorf("Result %d want level %s got %s", i, tc.level, result.Level)
+		}
+		if result.Locations[0].PhysicalLocation.ArtifactLocation.URI != tc.uri {
+			t.Errorf("Result %d want uri %s got %s", i, tc.uri, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		}
+		if result.PartialFingerprints["ramViolation/v1"] == "" {
+			t.Errorf("Result %d want a fingerprint", i)
+		}
+	}
+	if run.Results[1].Locations[0].LogicalLocations[0].FullyQualifiedName != "//storage.googleapis.com/publicbucket" {
+		t.Errorf("Want logical location from asset name got %s", run.Results[1].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpt
+
+import "encoding/json"
+
+// Result the evaluation of one asset by one rule constraint, one result per non compliance message
+type Result struct {
+	RuleName       string
+	ConstraintName string
+	Severity       string
+	AssetName      string
+	AssetType      string
+	Compliant      bool
+	Message        string
+	Metadata       json.RawMessage
+	// ArtifactURI optional file the asset is declared in, e.g. a terraform plan, else the asset name is used as location
+	ArtifactURI string
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package run helps with Google cloud run services
+//
+// A RAM microservice instance deployed on cloud run executes the same EntryPoint as when deployed on cloud functions.
+// An instance is deployed on cloud run when its instance.yaml settings contain: compute: run
+// The service EntryPoint is wrapped into an HTTP handler that receives Pub/Sub push requests, rebuilds the background event metadata and returns:
+// - 204 when the EntryPoint returns nil, the message is acknowledged
+// - 500 when the EntryPoint returns an error, the message is redelivered by Pub/Sub
+//
+// The container is built by cloud build from the same sources as the cloud function, then deployed with the microservice service account.
+// The push subscription authenticates with an OIDC token of this same service account, which is granted roles/run.invoker on the cloud run service.
+// Cloud storage triggers are implemented with a bucket notification to a Pub/Sub topic named gcs-<bucketName>.
+//
+// The push subscription can filter messages on their attributes to cut invocations, with the instance.yaml setting filter, e.g.
+// filter: 'hasPrefix(attributes.ancestryPath, "organization/123/folder/456")'
+// Only messages published by RAM microservices carry these attributes. Cloud Asset Inventory real-time feed messages have none,
+// so a filter on attributes drops all of them: settings validation rejects a filter on an instance triggered by a cai-rces-* topic.
+// As a Pub/Sub filter is immutable, a filtered subscription is named <instanceName>-<filter hash>. Changing the filter creates the new
+// subscription first, then deletes the previous one, so the messages published meanwhile are delivered by at least one of them.
+package run
@@ -0,0 +1,42 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+)
+
+func TestUnitGetServiceName(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		instanceName string
+		want         string
+	}{
+		{"monitor", "monitor_iam_key_age_max", "monitor-iam-key-age-max"},
+		{"upperCase", "stream2bq_rces_iam_ServiceAccountKey", "stream2bq-rces-iam-serviceaccountkey"},
+		{"alreadyCompliant", "splitdump", "splitdump"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getServiceName(tc.instanceName)
+			if tc.want != got {
+				t.Errorf("Want %s got %s", tc.want, got)
+			}
+		})
+	}
+}
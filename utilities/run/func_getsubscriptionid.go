@@ -0,0 +1,30 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// getSubscriptionID a Pub/Sub filter is immutable, a filtered push subscription is named after a hash of its filter so a new filter gets a new subscription
+func getSubscriptionID(instanceName string, filter string) string {
+	if filter == "" {
+		return instanceName
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(filter))
+	return fmt.Sprintf("%s-%08x", instanceName, hash.Sum32())
+}
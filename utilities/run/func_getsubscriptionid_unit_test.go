@@ -0,0 +1,46 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestUnitGetSubscriptionID(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		instanceName string
+		filter       string
+		want         string
+	}{
+		{"noFilter", "monitor_iam_key_age_max", "", "^monitor_iam_key_age_max$"},
+		{"filter", "monitor_iam_key_age_max", `attributes.assetType = "iam.googleapis.com/ServiceAccountKey"`, "^monitor_iam_key_age_max-[0-9a-f]{8}$"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getSubscriptionID(tc.instanceName, tc.filter)
+			if !regexp.MustCompile(tc.want).MatchString(got) {
+				t.Errorf("Want %s got %s", tc.want, got)
+			}
+		})
+	}
+	if getSubscriptionID("splitdump", `attributes.origin = "batch-export"`) == getSubscriptionID("splitdump", `attributes.origin = "real-time"`) {
+		t.Errorf("Want a different subscription ID for a different filter")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"time"
+)
+
+// getTimeoutSeconds convert a cloud function timeout like "540s" into seconds
+func getTimeoutSeconds(timeout string) (timeoutSeconds int64, err error) {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, fmt.Errorf("time.ParseDuration %v", err)
+	}
+	if duration < time.Second {
+		return 0, fmt.Errorf("timeout must be at least one second, got %s", timeout)
+	}
+	return int64(duration / time.Second), nil
+}
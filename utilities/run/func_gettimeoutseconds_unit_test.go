@@ -0,0 +1,54 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"testing"
+)
+
+func TestUnitGetTimeoutSeconds(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		timeout   string
+		want      int64
+		wantError bool
+	}{
+		{"seconds", "540s", 540, false},
+		{"minutes", "60m", 3600, false},
+		{"noUnit", "540", 0, true},
+		{"tooShort", "500ms", 0, true},
+		{"empty", "", 0, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := getTimeoutSeconds(tc.timeout)
+			if tc.want != got {
+				t.Errorf("Want %d got %d", tc.want, got)
+			}
+			if err != nil {
+				if !tc.wantError {
+					t.Errorf("Want no error and got %v", err)
+				}
+			} else {
+				if tc.wantError {
+					t.Errorf("Want an error and got no error")
+				}
+			}
+		})
+	}
+}
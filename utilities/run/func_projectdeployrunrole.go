@@ -0,0 +1,45 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import "google.golang.org/api/iam/v1"
+
+// ProjectDeployRunRole defines the custom project role to have permissions to deploy a microservice on cloud run
+func ProjectDeployRunRole() (role iam.Role) {
+	role.Title = "ram_microservice_deploy_run"
+	role.Description = "Real-time Asset Monitor microservices permissions to deploy on cloud run"
+	role.Stage = "GA"
+	role.IncludedPermissions = []string{
+		"cloudbuild.builds.create",
+		"cloudbuild.builds.get",
+		"pubsub.subscriptions.create",
+		"pubsub.subscriptions.delete",
+		"pubsub.subscriptions.get",
+		"pubsub.subscriptions.update",
+		"pubsub.topics.attachSubscription",
+		"pubsub.topics.get",
+		"pubsub.topics.getIamPolicy",
+		"pubsub.topics.setIamPolicy",
+		"resourcemanager.projects.get",
+		"run.services.create",
+		"run.services.get",
+		"run.services.getIamPolicy",
+		"run.services.setIamPolicy",
+		"run.services.update",
+		"storage.buckets.get",
+		"storage.buckets.update",
+		"storage.objects.create"}
+	return role
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
+	"google.golang.org/api/cloudbuild/v1"
+)
+
+// buildContainerImage run a cloud build to build and push the container image from the uploaded sources
+func (serviceDeployment *ServiceDeployment) buildContainerImage() (err error) {
+	var build cloudbuild.Build
+	build.Source = &cloudbuild.Source{
+		StorageSource: &cloudbuild.StorageSource{
+			Bucket: serviceDeployment.Artifacts.StagingBucketName,
+			Object: serviceDeployment.Artifacts.SourceObjectName,
+		},
+	}
+	build.Steps = []*cloudbuild.BuildStep{
+		{
+			Name: "gcr.io/cloud-builders/docker",
+			Args: []string{"build", "-t", serviceDeployment.Artifacts.ContainerImage, "."},
+		},
+	}
+	build.Images = []string{serviceDeployment.Artifacts.ContainerImage}
+	build.Tags = []string{getServiceName(serviceDeployment.Core.InstanceName)}
+	build.Timeout = "600s"
+
+	operation, err := serviceDeployment.Core.Services.CloudbuildService.Projects.Builds.Create(
+		serviceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		&build).Context(serviceDeployment.Core.Ctx).Do()
+	if err != nil {
+		return fmt.Errorf("CloudbuildService.Projects.Builds.Create %v", err)
+	}
+	name := operation.Name
+	log.Printf("%s run container image build started", serviceDeployment.Core.InstanceName)
+	log.Println(name)
+	for {
+		time.Sleep(10 * time.Second)
+		err = erm.Retry(Retries, 5*time.Second, 30*time.Second, func() (err error) {
+			operation, err = serviceDeployment.Core.Services.CloudbuildService.Operations.Get(name).Context(serviceDeployment.Core.Ctx).Do()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if operation.Done {
+			break
+		}
+	}
+	if operation.Error != nil {
+		return fmt.Errorf("Container image build error %v", operation.Error)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
+
+	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
+)
+
+// checkRunService compare the deployed cloud run service and push subscription to the settings
+func (serviceDeployment *ServiceDeployment) checkRunService() (err error) {
+	var s string
+	retreivedService, err := serviceDeployment.Artifacts.NamespacesServicesService.Get(serviceDeployment.Artifacts.ServiceFullName).Context(serviceDeployment.Core.Ctx).Do()
+	if err != nil {
+		if erm.IsNotFound(err) {
+			return fmt.Errorf("%s run service NOT found for this instance", serviceDeployment.Core.InstanceName)
+		}
+		return fmt.Errorf("NamespacesServicesService.Get %v", err)
+	}
+	if retreivedService.Spec == nil || retreivedService.Spec.Template == nil || retreivedService.Spec.Template.Spec == nil || len(retreivedService.Spec.Template.Spec.Containers) == 0 {
+		return fmt.Errorf("%s run service found without revision template", serviceDeployment.Core.InstanceName)
+	}
+	wantSpec := serviceDeployment.Artifacts.Service.Spec.Template.Spec
+	haveSpec := retreivedService.Spec.Template.Spec
+	if wantSpec.ContainerConcurrency != haveSpec.ContainerConcurrency {
+		s = fmt.Sprintf("%scontainerConcurrency\nwant %d\nhave %d\n", s,
+			wantSpec.ContainerConcurrency,
+			haveSpec.ContainerConcurrency)
+	}
+	if wantSpec.ServiceAccountName != haveSpec.ServiceAccountName {
+		s = fmt.Sprintf("%sserviceAccountName\nwant %s\nhave %s\n", s,
+			wantSpec.ServiceAccountName,
+			haveSpec.ServiceAccountName)
+	}
+	if wantSpec.TimeoutSeconds != haveSpec.TimeoutSeconds {
+		s = fmt.Sprintf("%stimeoutSeconds\nwant %d\nhave %d\n", s,
+			wantSpec.TimeoutSeconds,
+			haveSpec.TimeoutSeconds)
+	}
+	if haveSpec.Containers[0].Resources != nil {
+		for _, key := range []string{"cpu", "memory"} {
+			if wantSpec.Containers[0].Resources.Limits[key] != haveSpec.Containers[0].Resources.Limits[key] {
+				s = fmt.Sprintf("%s%s\nwant %s\nhave %s\n", s,
+					key,
+					wantSpec.Containers[0].Resources.Limits[key],
+					haveSpec.Containers[0].Resources.Limits[key])
+			}
+		}
+	}
+	if retreivedService.Metadata.Labels["name"] != serviceDeployment.Artifacts.Service.Metadata.Labels["name"] {
+		s = fmt.Sprintf("%slabels name\nwant %s\nhave %s\n", s,
+			serviceDeployment.Artifacts.Service.Metadata.Labels["name"],
+			retreivedService.Metadata.Labels["name"])
+	}
+
+	var getSubscriptionRequest pubsubpb.GetSubscriptionRequest
+	getSubscriptionRequest.Subscription = serviceDeployment.Artifacts.SubscriptionName
+	subscription, err := serviceDeployment.Core.Services.PubsubSubscriberClient.GetSubscription(serviceDeployment.Core.Ctx, &getSubscriptionRequest)
+	if err != nil {
+		if erm.IsNotFound(err) {
+			s = fmt.Sprintf("%spush subscription NOT found %s\n", s, serviceDeployment.Artifacts.SubscriptionName)
+		} else {
+			return fmt.Errorf("PubsubSubscriberClient.GetSubscription %v", err)
+		}
+	} else {
+		wantTopic := fmt.Sprintf("projects/%s/topics/%s",
+			serviceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+			serviceDeployment.Artifacts.TriggerTopicName)
+		if subscription.Topic != wantTopic {
+			s = fmt.Sprintf("%ssubscription topic\nwant %s\nhave %s\n", s, wantTopic, subscription.Topic)
+		}
+		if retreivedService.Status != nil && subscription.PushConfig != nil {
+			if subscription.PushConfig.PushEndpoint != retreivedService.Status.Url {
+				s = fmt.Sprintf("%ssubscription pushEndpoint\nwant %s\nhave %s\n", s,
+					retreivedService.Status.Url,
+					subscription.PushConfig.PushEndpoint)
+			}
+		}
+	}
+	if len(s) > 0 {
+		return fmt.Errorf("%s run invalid service configuration:\n%s", serviceDeployment.Core.InstanceName, s)
+	}
+	return nil
+}
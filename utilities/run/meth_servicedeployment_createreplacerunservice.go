@@ -0,0 +1,88 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
+	"google.golang.org/api/run/v1"
+)
+
+// createReplaceRunService looks for an existing cloud run service replace it if found else create it, then wait for it to be ready
+func (serviceDeployment *ServiceDeployment) createReplaceRunService() (err error) {
+	parent := fmt.Sprintf("namespaces/%s", serviceDeployment.Core.SolutionSettings.Hosting.ProjectID)
+	retreivedService, err := serviceDeployment.Artifacts.NamespacesServicesService.Get(serviceDeployment.Artifacts.ServiceFullName).Context(serviceDeployment.Core.Ctx).Do()
+	if err != nil {
+		if erm.IsNotFound(err) {
+			_, err = serviceDeployment.Artifacts.NamespacesServicesService.Create(parent,
+				&serviceDeployment.Artifacts.Service).Context(serviceDeployment.Core.Ctx).Do()
+			if err != nil {
+				return fmt.Errorf("NamespacesServicesService.Create %v", err)
+			}
+		} else {
+			return fmt.Errorf("NamespacesServicesService.Get %v", err)
+		}
+	} else {
+		log.Printf("%s run replace existing cloud run service %s", serviceDeployment.Core.InstanceName, retreivedService.Metadata.Name)
+		serviceDeployment.Artifacts.Service.Metadata.ResourceVersion = retreivedService.Metadata.ResourceVersion
+		_, err = serviceDeployment.Artifacts.NamespacesServicesService.ReplaceService(serviceDeployment.Artifacts.ServiceFullName,
+			&serviceDeployment.Artifacts.Service).Context(serviceDeployment.Core.Ctx).Do()
+		if err != nil {
+			return fmt.Errorf("NamespacesServicesService.ReplaceService %v", err)
+		}
+	}
+
+	log.Printf("%s run cloud run service deployment started", serviceDeployment.Core.InstanceName)
+	var service *run.Service
+	for {
+		time.Sleep(5 * time.Second)
+		err = erm.Retry(Retries, 5*time.Second, 30*time.Second, func() (err error) {
+			service, err = serviceDeployment.Artifacts.NamespacesServicesService.Get(serviceDeployment.Artifacts.ServiceFullName).Context(serviceDeployment.Core.Ctx).Do()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		ready, message := getReadyCondition(service)
+		if ready == "True" {
+			break
+		}
+		if ready == "False" {
+			return fmt.Errorf("Service deployment error %s", message)
+		}
+	}
+	serviceDeployment.Artifacts.ServiceURL = service.Status.Url
+	return nil
+}
+
+// getReadyCondition returns the status and message of the service Ready condition
+func getReadyCondition(service *run.Service) (status string, message string) {
+	if service.Status == nil {
+		return "Unknown", ""
+	}
+	// The status is refering to a previous generation while the replace is processing
+	if service.Status.ObservedGeneration < service.Metadata.Generation {
+		return "Unknown", ""
+	}
+	for _, condition := range service.Status.Conditions {
+		if condition.Type == "Ready" {
+			return condition.Status, condition.Message
+		}
+	}
+	return "Unknown", ""
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"log"
+	"os"
+
+	"github.com/BrunoReboul/ram/utilities/ffo"
+)
+
+// Retries is the max number of tentative to get the status of an operation, to deal with transient
+const Retries = 5
+
+// Deploy build the container image, create or replace the cloud run service and its Pub/Sub push trigger
+func (serviceDeployment *ServiceDeployment) Deploy() (err error) {
+	serviceDeployment.Artifacts.NamespacesServicesService = serviceDeployment.Core.Services.RunService.Namespaces.Services
+	serviceDeployment.Artifacts.LocationsServicesService = serviceDeployment.Core.Services.RunService.Projects.Locations.Services
+	err = serviceDeployment.situate()
+	if err != nil {
+		return err
+	}
+	log.Printf("%s run situate settings done", serviceDeployment.Core.InstanceName)
+	if serviceDeployment.Core.Commands.Check {
+		return serviceDeployment.checkRunService()
+	}
+	err = ffo.TarGzSource(serviceDeployment.Artifacts.SourceTarGzFullPath, serviceDeployment.Artifacts.ZipFiles)
+	if err != nil {
+		return err
+	}
+	log.Printf("%s run sources archived", serviceDeployment.Core.InstanceName)
+	err = serviceDeployment.uploadSource()
+	if err != nil {
+		return err
+	}
+	log.Printf("%s run sources uploaded to gs://%s/%s", serviceDeployment.Core.InstanceName,
+		serviceDeployment.Artifacts.StagingBucketName,
+		serviceDeployment.Artifacts.SourceObjectName)
+	err = os.Remove(serviceDeployment.Artifacts.SourceTarGzFullPath)
+	if err != nil {
+		return err
+	}
+	log.Printf("%s run file removed %s", serviceDeployment.Core.InstanceName, serviceDeployment.Artifacts.SourceTarGzFullPath)
+	err = serviceDeployment.buildContainerImage()
+	if err != nil {
+		return err
+	}
+	log.Printf("%s run container image built %s", serviceDeployment.Core.InstanceName, serviceDeployment.Artifacts.ContainerImage)
+	err = serviceDeployment.createReplaceRunService()
+	if err != nil {
+		return err
+	}
+	log.Printf("%s run service created or replaced %s", serviceDeployment.Core.InstanceName, serviceDeployment.Artifacts.ServiceURL)
+	err = serviceDeployment.deployInvokerBinding()
+	if err != nil {
+		return err
+	}
+	if serviceDeployment.Settings.Service.GCF.FunctionType == "backgroundGCS" {
+		err = serviceDeployment.deployGCSNotification()
+		if err != nil {
+			return err
+		}
+	}
+	err = serviceDeployment.deployPushSubscription()
+	if err != nil {
+		return err
+	}
+	log.Printf("%s run push subscription deployed %s", serviceDeployment.Core.InstanceName, serviceDeployment.Artifacts.SubscriptionName)
+	return nil
+}
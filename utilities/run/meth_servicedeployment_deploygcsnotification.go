@@ -0,0 +1,70 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"log"
+
+	"cloud.google.com/go/iam"
+	"cloud.google.com/go/storage"
+	"github.com/BrunoReboul/ram/utilities/gps"
+)
+
+// deployGCSNotification replace the cloud function GCS trigger by a bucket notification to a Pub/Sub topic
+func (serviceDeployment *ServiceDeployment) deployGCSNotification() (err error) {
+	projectID := serviceDeployment.Core.SolutionSettings.Hosting.ProjectID
+	topicDeployment := gps.NewTopicDeployment()
+	topicDeployment.Core = serviceDeployment.Core
+	topicDeployment.Settings.TopicName = serviceDeployment.Artifacts.TriggerTopicName
+	err = topicDeployment.Deploy()
+	if err != nil {
+		return err
+	}
+	gcsServiceAccount, err := serviceDeployment.Core.Services.StorageClient.ServiceAccount(serviceDeployment.Core.Ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("StorageClient.ServiceAccount %v", err)
+	}
+	err = gps.SetTopicRole(serviceDeployment.Core.Ctx,
+		serviceDeployment.Core.Services.PubsubPublisherClient,
+		fmt.Sprintf("projects/%s/topics/%s", projectID, serviceDeployment.Artifacts.TriggerTopicName),
+		fmt.Sprintf("serviceAccount:%s", gcsServiceAccount),
+		iam.RoleName("roles/pubsub.publisher"))
+	if err != nil {
+		return err
+	}
+	bucket := serviceDeployment.Core.Services.StorageClient.Bucket(serviceDeployment.Settings.Instance.GCF.BucketName)
+	notifications, err := bucket.Notifications(serviceDeployment.Core.Ctx)
+	if err != nil {
+		return fmt.Errorf("bucket.Notifications %v", err)
+	}
+	for _, notification := range notifications {
+		if notification.TopicProjectID == projectID && notification.TopicID == serviceDeployment.Artifacts.TriggerTopicName {
+			log.Printf("%s run gcs notification found on bucket %s", serviceDeployment.Core.InstanceName, serviceDeployment.Settings.Instance.GCF.BucketName)
+			return nil
+		}
+	}
+	_, err = bucket.AddNotification(serviceDeployment.Core.Ctx, &storage.Notification{
+		TopicProjectID: projectID,
+		TopicID:        serviceDeployment.Artifacts.TriggerTopicName,
+		EventTypes:     []string{storage.ObjectFinalizeEvent},
+		PayloadFormat:  storage.JSONPayload,
+	})
+	if err != nil {
+		return fmt.Errorf("bucket.AddNotification %v", err)
+	}
+	log.Printf("%s run gcs notification created on bucket %s", serviceDeployment.Core.InstanceName, serviceDeployment.Settings.Instance.GCF.BucketName)
+	return nil
+}
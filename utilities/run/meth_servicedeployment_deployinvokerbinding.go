@@ -0,0 +1,63 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/BrunoReboul/ram/utilities/str"
+	"google.golang.org/api/run/v1"
+)
+
+// deployInvokerBinding grant the microservice service account the role to invoke the cloud run service, used by the push subscription
+func (serviceDeployment *ServiceDeployment) deployInvokerBinding() (err error) {
+	resource := fmt.Sprintf("projects/%s/locations/%s/services/%s",
+		serviceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		serviceDeployment.Core.SolutionSettings.Hosting.GCF.Region,
+		serviceDeployment.Artifacts.Service.Metadata.Name)
+	role := "roles/run.invoker"
+	member := fmt.Sprintf("serviceAccount:%s", serviceDeployment.Artifacts.Service.Spec.Template.Spec.ServiceAccountName)
+	policy, err := serviceDeployment.Artifacts.LocationsServicesService.GetIamPolicy(resource).Context(serviceDeployment.Core.Ctx).Do()
+	if err != nil {
+		return fmt.Errorf("LocationsServicesService.GetIamPolicy %v", err)
+	}
+	roleFound := false
+	for _, binding := range policy.Bindings {
+		if binding.Role == role {
+			if str.Find(binding.Members, member) {
+				log.Printf("%s run %s already has role %s", serviceDeployment.Core.InstanceName, member, role)
+				return nil
+			}
+			binding.Members = append(binding.Members, member)
+			roleFound = true
+			break
+		}
+	}
+	if !roleFound {
+		policy.Bindings = append(policy.Bindings, &run.Binding{
+			Role:    role,
+			Members: []string{member},
+		})
+	}
+	var setIamPolicyRequest run.SetIamPolicyRequest
+	setIamPolicyRequest.Policy = policy
+	_, err = serviceDeployment.Artifacts.LocationsServicesService.SetIamPolicy(resource, &setIamPolicyRequest).Context(serviceDeployment.Core.Ctx).Do()
+	if err != nil {
+		return fmt.Errorf("LocationsServicesService.SetIamPolicy %v", err)
+	}
+	log.Printf("%s run granted role %s", serviceDeployment.Core.InstanceName, role)
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
+
+	"google.golang.org/api/iterator"
+	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
+	"google.golang.org/genproto/protobuf/field_mask"
+)
+
+// deployPushSubscription create or update the Pub/Sub push subscription triggering the cloud run service
+func (serviceDeployment *ServiceDeployment) deployPushSubscription() (err error) {
+	var subscription pubsubpb.Subscription
+	subscription.Name = serviceDeployment.Artifacts.SubscriptionName
+	subscription.Topic = fmt.Sprintf("projects/%s/topics/%s",
+		serviceDeployment.Core.SolutionSettings.Hosting.ProjectID,
+		serviceDeployment.Artifacts.TriggerTopicName)
+	subscription.AckDeadlineSeconds = int32(serviceDeployment.Settings.Service.RUN.AckDeadlineSeconds)
+	subscription.Labels = map[string]string{"name": strings.ToLower(serviceDeployment.Core.InstanceName)}
+	subscription.Filter = serviceDeployment.Settings.Instance.GCF.Filter
+	subscription.PushConfig = &pubsubpb.PushConfig{
+		PushEndpoint: serviceDeployment.Artifacts.ServiceURL,
+		AuthenticationMethod: &pubsubpb.PushConfig_OidcToken_{
+			OidcToken: &pubsubpb.PushConfig_OidcToken{
+				ServiceAccountEmail: serviceDeployment.Artifacts.Service.Spec.Template.Spec.ServiceAccountName,
+			},
+		},
+	}
+
+	var getSubscriptionRequest pubsubpb.GetSubscriptionRequest
+	getSubscriptionRequest.Subscription = subscription.Name
+	_, err = serviceDeployment.Core.Services.PubsubSubscriberClient.GetSubscription(serviceDeployment.Core.Ctx, &getSubscriptionRequest)
+	if err != nil {
+		if !erm.IsNotFound(err) {
+			return fmt.Errorf("PubsubSubscriberClient.GetSubscription %v", err)
+		}
+		_, err = serviceDeployment.Core.Services.PubsubSubscriberClient.CreateSubscription(serviceDeployment.Core.Ctx, &subscription)
+		if err != nil {
+			return fmt.Errorf("PubsubSubscriberClient.CreateSubscription %v", err)
+		}
+		log.Printf("%s run push subscription created %s", serviceDeployment.Core.InstanceName, subscription.Name)
+	} else {
+		var updateSubscriptionRequest pubsubpb.UpdateSubscriptionRequest
+		updateSubscriptionRequest.Subscription = &subscription
+		updateSubscriptionRequest.UpdateMask = &field_mask.FieldMask{
+			Paths: []string{"ack_deadline_seconds", "labels", "push_config"},
+		}
+		_, err = serviceDeployment.Core.Services.PubsubSubscriberClient.UpdateSubscription(serviceDeployment.Core.Ctx, &updateSubscriptionRequest)
+		if err != nil {
+			return fmt.Errorf("PubsubSubscriberClient.UpdateSubscription %v", err)
+		}
+		log.Printf("%s run push subscription updated %s", serviceDeployment.Core.InstanceName, subscription.Name)
+	}
+
+	// Delete the subscriptions of previous filters only once the current one exists, so no message is lost
+	previousSubscriptionRegex := regexp.MustCompile(fmt.Sprintf("^projects/%s/subscriptions/%s(-[0-9a-f]{8})?$",
+		regexp.QuoteMeta(serviceDeployment.Core.SolutionSettings.Hosting.ProjectID),
+		regexp.QuoteMeta(serviceDeployment.Core.InstanceName)))
+	var listTopicSubscriptionsRequest pubsubpb.ListTopicSubscriptionsRequest
+	listTopicSubscriptionsRequest.Topic = subscription.Topic
+	subscriptionNameIterator := serviceDeployment.Core.Services.PubsubPublisherClient.ListTopicSubscriptions(serviceDeployment.Core.Ctx, &listTopicSubscriptionsRequest)
+	for {
+		subscriptionName, err := subscriptionNameIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("subscriptionNameIterator.Next %v", err)
+		}
+		if subscriptionName == subscription.Name || !previousSubscriptionRegex.MatchString(subscriptionName) {
+			continue
+		}
+		var deleteSubscriptionRequest pubsubpb.DeleteSubscriptionRequest
+		deleteSubscriptionRequest.Subscription = subscriptionName
+		err = serviceDeployment.Core.Services.PubsubSubscriberClient.DeleteSubscription(serviceDeployment.Core.Ctx, &deleteSubscriptionRequest)
+		if err != nil && !erm.IsNotFound(err) {
+			return fmt.Errorf("PubsubSubscriberClient.DeleteSubscription %v", err)
+		}
+		log.Printf("%s run push subscription of a previous filter deleted %s", serviceDeployment.Core.InstanceName, subscriptionName)
+	}
+	return nil
+}
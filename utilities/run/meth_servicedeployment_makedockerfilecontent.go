@@ -0,0 +1,44 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/solution"
+)
+
+// dockerfile Dockerfile skeleton, replace first %s goVersion, second by the path to function code
+// The sources are copied in the same relative path as in a cloud function so that the microservices read their settings the same way
+const dockerfile = `
+# generated code %v
+
+FROM golang:%s AS builder
+WORKDIR /app
+COPY . ./
+RUN go mod tidy && CGO_ENABLED=0 go build -o /server .
+
+FROM gcr.io/distroless/static
+WORKDIR /workspace
+COPY --from=builder /app %s
+COPY --from=builder /server /server
+CMD ["/server"]
+`
+
+// makeDockerfileContent craft the content of a cloud run Dockerfile for a RAM microservice instance
+func (serviceDeployment *ServiceDeployment) makeDockerfileContent() (dockerfileContent string) {
+	return fmt.Sprintf(dockerfile, time.Now(), serviceDeployment.Core.GoVersion, solution.PathToFunctionCode)
+}
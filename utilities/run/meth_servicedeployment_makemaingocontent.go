@@ -0,0 +1,226 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// pushPubSubMainGo main.go code skeleton, replace <serviceName> by serviceName
+const pushPubSubMainGo = `
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// generated code <timeStamp>
+
+// Package main contains a cloud run service triggered by Pub/Sub push requests
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"cloud.google.com/go/functions/metadata"
+	"github.com/BrunoReboul/ram/services/<serviceName>"
+	"github.com/BrunoReboul/ram/utilities/gps"
+)
+
+type pushRequest struct {
+	Message struct {
+		Attributes  map[string]string ` + "`json:\"attributes\"`" + `
+		Data        []byte            ` + "`json:\"data\"`" + `
+		MessageID   string            ` + "`json:\"messageId\"`" + `
+		PublishTime time.Time         ` + "`json:\"publishTime\"`" + `
+	} ` + "`json:\"message\"`" + `
+	Subscription string ` + "`json:\"subscription\"`" + `
+}
+
+var global <serviceName>.Global
+var ctx = context.Background()
+
+// EntryPoint is the handler to be executed for each Pub/Sub push request
+func EntryPoint(w http.ResponseWriter, r *http.Request) {
+	var request pushRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		// Malformed request, nothing to retry
+		log.Printf("pubsub_id no available json.NewDecoder %v", err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	ctxEvent := metadata.NewContext(r.Context(), &metadata.Metadata{
+		EventID:   request.Message.MessageID,
+		Timestamp: request.Message.PublishTime,
+		EventType: "google.pubsub.topic.publish",
+		Resource: &metadata.Resource{
+			Service: "pubsub.googleapis.com",
+			Name:    os.Getenv("TRIGGER_RESOURCE"),
+			Type:    "type.googleapis.com/google.pubsub.v1.PubsubMessage",
+		},
+	})
+	// Each request works on its own copy of the global variables to support concurrency
+	requestGlobal := global
+	err = <serviceName>.EntryPoint(ctxEvent, gps.PubSubMessage{Attributes: request.Message.Attributes, Data: request.Message.Data}, &requestGlobal)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func main() {
+	err := <serviceName>.Initialize(ctx, &global)
+	if err != nil {
+		log.Fatalf("pubsub_id %s INIT_FAILURE %v", global.PubSubID, err)
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	http.HandleFunc("/", EntryPoint)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
+`
+
+// pushGCSMainGo main.go code skeleton, replace <serviceName> by serviceName
+const pushGCSMainGo = `
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// generated code <timeStamp>
+
+// Package main contains a cloud run service triggered by GCS notifications Pub/Sub push requests
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"cloud.google.com/go/functions/metadata"
+	"github.com/BrunoReboul/ram/services/<serviceName>"
+	"github.com/BrunoReboul/ram/utilities/gcs"
+)
+
+type pushRequest struct {
+	Message struct {
+		Attributes  map[string]string ` + "`json:\"attributes\"`" + `
+		Data        []byte            ` + "`json:\"data\"`" + `
+		MessageID   string            ` + "`json:\"messageId\"`" + `
+		PublishTime time.Time         ` + "`json:\"publishTime\"`" + `
+	} ` + "`json:\"message\"`" + `
+	Subscription string ` + "`json:\"subscription\"`" + `
+}
+
+var global <serviceName>.Global
+var ctx = context.Background()
+
+// EntryPoint is the handler to be executed for each GCS notification Pub/Sub push request
+func EntryPoint(w http.ResponseWriter, r *http.Request) {
+	var request pushRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		// Malformed request, nothing to retry
+		log.Printf("pubsub_id no available json.NewDecoder %v", err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var gcsEvent gcs.Event
+	err = json.Unmarshal(request.Message.Data, &gcsEvent)
+	if err != nil {
+		// Malformed notification, nothing to retry
+		log.Printf("pubsub_id %s json.Unmarshal %v", request.Message.MessageID, err)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	ctxEvent := metadata.NewContext(r.Context(), &metadata.Metadata{
+		EventID:   request.Message.MessageID,
+		Timestamp: request.Message.PublishTime,
+		EventType: "google.storage.object.finalize",
+		Resource: &metadata.Resource{
+			Service: "storage.googleapis.com",
+			Name:    os.Getenv("TRIGGER_RESOURCE") + "/objects/" + request.Message.Attributes["objectId"],
+			Type:    "storage#object",
+		},
+	})
+	// Each request works on its own copy of the global variables to support concurrency
+	requestGlobal := global
+	err = <serviceName>.EntryPoint(ctxEvent, gcsEvent, &requestGlobal)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func main() {
+	err := <serviceName>.Initialize(ctx, &global)
+	if err != nil {
+		log.Fatalf("pubsub_id %s INIT_FAILURE %v", global.PubSubID, err)
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	http.HandleFunc("/", EntryPoint)
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
+`
+
+// makeMainGoContent craft the content of a cloud run main.go file for a RAM microservice instance
+func (serviceDeployment *ServiceDeployment) makeMainGoContent() (mainGoContent string, err error) {
+	timeStamp := fmt.Sprintf("%s", time.Now())
+	switch serviceDeployment.Settings.Service.GCF.FunctionType {
+	case "backgroundPubSub":
+		return strings.Replace(strings.Replace(pushPubSubMainGo,
+			"<serviceName>", serviceDeployment.Core.ServiceName, -1), "<timeStamp>", timeStamp, -1), nil
+	case "backgroundGCS":
+		return strings.Replace(strings.Replace(pushGCSMainGo,
+			"<serviceName>", serviceDeployment.Core.ServiceName, -1), "<timeStamp>", timeStamp, -1), nil
+	default:
+		return "", fmt.Errorf("functionType provided not managed: %s", serviceDeployment.Settings.Service.GCF.FunctionType)
+	}
+}
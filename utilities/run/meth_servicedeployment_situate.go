@@ -0,0 +1,149 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BrunoReboul/ram/utilities/ffo"
+	"github.com/BrunoReboul/ram/utilities/solution"
+	"github.com/google/uuid"
+	"google.golang.org/api/run/v1"
+)
+
+// maxAckDeadlineSeconds is the max value of a Pub/Sub subscription acknowledgement deadline
+const maxAckDeadlineSeconds = 600
+
+func (serviceDeployment *ServiceDeployment) situate() (err error) {
+	projectID := serviceDeployment.Core.SolutionSettings.Hosting.ProjectID
+	serviceName := getServiceName(serviceDeployment.Core.InstanceName)
+
+	// Default to cloud function settings when not set for cloud run
+	if serviceDeployment.Settings.Service.RUN.Concurrency == 0 {
+		serviceDeployment.Settings.Service.RUN.Concurrency = 1
+	}
+	if serviceDeployment.Settings.Service.RUN.CPU == "" {
+		serviceDeployment.Settings.Service.RUN.CPU = "1"
+	}
+	if serviceDeployment.Settings.Service.RUN.MemoryMb == 0 {
+		serviceDeployment.Settings.Service.RUN.MemoryMb = serviceDeployment.Settings.Service.GCF.AvailableMemoryMb
+	}
+	if serviceDeployment.Settings.Service.RUN.TimeoutSeconds == 0 {
+		serviceDeployment.Settings.Service.RUN.TimeoutSeconds, err = getTimeoutSeconds(serviceDeployment.Settings.Service.GCF.Timeout)
+		if err != nil {
+			return err
+		}
+	}
+	if serviceDeployment.Settings.Service.RUN.AckDeadlineSeconds == 0 {
+		serviceDeployment.Settings.Service.RUN.AckDeadlineSeconds = serviceDeployment.Settings.Service.RUN.TimeoutSeconds
+		if serviceDeployment.Settings.Service.RUN.AckDeadlineSeconds > maxAckDeadlineSeconds {
+			serviceDeployment.Settings.Service.RUN.AckDeadlineSeconds = maxAckDeadlineSeconds
+		}
+	}
+
+	switch serviceDeployment.Settings.Service.GCF.FunctionType {
+	case "backgroundPubSub":
+		serviceDeployment.Artifacts.TriggerTopicName = serviceDeployment.Settings.Instance.GCF.TriggerTopic
+		serviceDeployment.Artifacts.TriggerResourceName = fmt.Sprintf("projects/%s/topics/%s",
+			projectID,
+			serviceDeployment.Artifacts.TriggerTopicName)
+	case "backgroundGCS":
+		serviceDeployment.Artifacts.TriggerTopicName = fmt.Sprintf("gcs-%s", serviceDeployment.Settings.Instance.GCF.BucketName)
+		serviceDeployment.Artifacts.TriggerResourceName = fmt.Sprintf("projects/_/buckets/%s",
+			serviceDeployment.Settings.Instance.GCF.BucketName)
+	default:
+		return fmt.Errorf("functionType provided not managed: %s", serviceDeployment.Settings.Service.GCF.FunctionType)
+	}
+	serviceDeployment.Artifacts.SubscriptionName = fmt.Sprintf("projects/%s/subscriptions/%s",
+		projectID,
+		getSubscriptionID(serviceDeployment.Core.InstanceName, serviceDeployment.Settings.Instance.GCF.Filter))
+
+	serviceDeployment.Artifacts.ServiceFullName = fmt.Sprintf("namespaces/%s/services/%s", projectID, serviceName)
+	serviceDeployment.Artifacts.ContainerImage = fmt.Sprintf("gcr.io/%s/%s:%s",
+		projectID,
+		serviceName,
+		time.Now().Format("20060102t150405"))
+	serviceDeployment.Artifacts.StagingBucketName = fmt.Sprintf("%s_cloudbuild", projectID)
+	serviceDeployment.Artifacts.SourceObjectName = fmt.Sprintf("source/%s.tgz", uuid.New())
+	serviceDeployment.Artifacts.SourceTarGzFullPath = fmt.Sprintf("./%s.tgz", uuid.New())
+
+	serviceDeployment.Artifacts.Service.ApiVersion = "serving.knative.dev/v1"
+	serviceDeployment.Artifacts.Service.Kind = "Service"
+	serviceDeployment.Artifacts.Service.Metadata = &run.ObjectMeta{
+		Name:      serviceName,
+		Namespace: projectID,
+		Labels:    map[string]string{"name": strings.ToLower(serviceDeployment.Core.InstanceName)},
+		Annotations: map[string]string{
+			"run.googleapis.com/ingress": "all",
+		},
+	}
+	revisionAnnotations := make(map[string]string)
+	if serviceDeployment.Settings.Service.RUN.MaxInstances > 0 {
+		revisionAnnotations["autoscaling.knative.dev/maxScale"] = fmt.Sprintf("%d", serviceDeployment.Settings.Service.RUN.MaxInstances)
+	}
+	serviceDeployment.Artifacts.Service.Spec = &run.ServiceSpec{
+		Template: &run.RevisionTemplate{
+			Metadata: &run.ObjectMeta{
+				Annotations: revisionAnnotations,
+			},
+			Spec: &run.RevisionSpec{
+				ContainerConcurrency: serviceDeployment.Settings.Service.RUN.Concurrency,
+				ServiceAccountName: fmt.Sprintf("%s@%s.iam.gserviceaccount.com",
+					serviceDeployment.Core.ServiceName,
+					projectID),
+				TimeoutSeconds: serviceDeployment.Settings.Service.RUN.TimeoutSeconds,
+				Containers: []*run.Container{
+					{
+						Image: serviceDeployment.Artifacts.ContainerImage,
+						Env: []*run.EnvVar{
+							{
+								Name:  "TRIGGER_RESOURCE",
+								Value: serviceDeployment.Artifacts.TriggerResourceName,
+							},
+						},
+						Resources: &run.ResourceRequirements{
+							Limits: map[string]string{
+								"cpu":    serviceDeployment.Settings.Service.RUN.CPU,
+								"memory": fmt.Sprintf("%dMi", serviceDeployment.Settings.Service.RUN.MemoryMb),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if len(serviceDeployment.Artifacts.ZipFiles) == 0 {
+		serviceDeployment.Artifacts.ZipFiles = make(map[string]string)
+	}
+	mainGoContent, err := serviceDeployment.makeMainGoContent()
+	if err != nil {
+		return err
+	}
+	serviceDeployment.Artifacts.ZipFiles["main.go"] = mainGoContent
+	serviceDeployment.Artifacts.ZipFiles["go.mod"] = serviceDeployment.makeGoModContent()
+	serviceDeployment.Artifacts.ZipFiles["Dockerfile"] = serviceDeployment.makeDockerfileContent()
+	serviceDeployment.Artifacts.ZipFiles[solution.SettingsFileName] = serviceDeployment.Artifacts.InstanceDeploymentYAMLContent
+
+	if serviceDeployment.Core.Commands.Dumpsettings {
+		err := ffo.MarshalYAMLWrite(fmt.Sprintf("%s/%s", serviceDeployment.Core.RepositoryPath, "run_deployment.yaml"), serviceDeployment)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"cloud.google.com/go/storage"
+)
+
+// uploadSource copy the source archive to the cloud build staging bucket, create the bucket when missing
+func (serviceDeployment *ServiceDeployment) uploadSource() (err error) {
+	bucket := serviceDeployment.Core.Services.StorageClient.Bucket(serviceDeployment.Artifacts.StagingBucketName)
+	_, err = bucket.Attrs(serviceDeployment.Core.Ctx)
+	if err != nil {
+		if err != storage.ErrBucketNotExist {
+			return fmt.Errorf("bucket.Attrs %v", err)
+		}
+		err = bucket.Create(serviceDeployment.Core.Ctx, serviceDeployment.Core.SolutionSettings.Hosting.ProjectID, nil)
+		if err != nil {
+			return fmt.Errorf("bucket.Create %v", err)
+		}
+		log.Printf("%s run staging bucket created %s", serviceDeployment.Core.InstanceName, serviceDeployment.Artifacts.StagingBucketName)
+	}
+	file, err := os.Open(serviceDeployment.Artifacts.SourceTarGzFullPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	objectWriter := bucket.Object(serviceDeployment.Artifacts.SourceObjectName).NewWriter(serviceDeployment.Core.Ctx)
+	_, err = io.Copy(objectWriter, file)
+	if err != nil {
+		return fmt.Errorf("io.Copy %v", err)
+	}
+	err = objectWriter.Close()
+	if err != nil {
+		return fmt.Errorf("objectWriter.Close %v", err)
+	}
+	return nil
+}
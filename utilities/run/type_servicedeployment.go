@@ -0,0 +1,56 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"github.com/BrunoReboul/ram/utilities/deploy"
+	"github.com/BrunoReboul/ram/utilities/gcf"
+	"google.golang.org/api/run/v1"
+)
+
+// ServiceDeployment settings and artifacts structure
+type ServiceDeployment struct {
+	Artifacts struct {
+		NamespacesServicesService     *run.NamespacesServicesService        `yaml:"-"`
+		LocationsServicesService      *run.ProjectsLocationsServicesService `yaml:"-"`
+		Service                       run.Service
+		ServiceFullName               string
+		ServiceURL                    string
+		ContainerImage                string
+		SourceTarGzFullPath           string
+		SourceObjectName              string
+		StagingBucketName             string
+		SubscriptionName              string
+		TriggerTopicName              string
+		TriggerResourceName           string
+		InstanceDeploymentYAMLContent string
+		ZipFiles                      map[string]string
+	}
+	Core     *deploy.Core
+	Settings struct {
+		Service struct {
+			GCF gcf.Parameters
+			RUN Parameters
+		}
+		Instance struct {
+			GCF gcf.Event
+		}
+	}
+}
+
+// NewServiceDeployment create deployment structure
+func NewServiceDeployment() *ServiceDeployment {
+	return &ServiceDeployment{}
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	securitycenter "cloud.google.com/go/securitycenter/apiv1p1beta1"
+	"google.golang.org/api/iterator"
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DeactivateFindings sets INACTIVE the active findings of a source for one asset and one rule, returns the number of findings deactivated
+// Findings of the still violated constraints, and findings with a more recent event time, are kept active
+// The event time is set to the deactivation one, so a late violation does not reactivate the finding
+func DeactivateFindings(ctx context.Context, securitycenterClient *securitycenter.Client, sourceName string, assetName string, ruleName string, violatedConstraintNames []string, eventTime time.Time) (count int, err error) {
+	violated := make(map[string]bool)
+	for _, constraintName := range violatedConstraintNames {
+		violated[constraintName] = true
+	}
+	var listFindingsRequest securitycenterpb.ListFindingsRequest
+	listFindingsRequest.Parent = sourceName
+	listFindingsRequest.Filter = fmt.Sprintf(`resource_name = "%s" AND category = "%s" AND state = "ACTIVE"`, assetName, ruleName)
+	findingIterator := securitycenterClient.ListFindings(ctx, &listFindingsRequest)
+	for {
+		listFindingsResult, err := findingIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("findingIterator.Next %s %w", sourceName, err)
+		}
+		finding := listFindingsResult.Finding
+		if violated[finding.SourceProperties["constraintName"].GetStringValue()] {
+			continue
+		}
+		if finding.EventTime.AsTime().After(eventTime) {
+			continue
+		}
+		var updateFindingRequest securitycenterpb.UpdateFindingRequest
+		updateFindingRequest.Finding = &securitycenterpb.Finding{
+			Name:      finding.Name,
+			State:     securitycenterpb.Finding_INACTIVE,
+			EventTime: timestamppb.New(eventTime),
+		}
+		updateFindingRequest.UpdateMask = &fieldmaskpb.FieldMask{Paths: []string{"state", "event_time"}}
+		if _, err = securitycenterClient.UpdateFinding(ctx, &updateFindingRequest); err != nil {
+			return count, fmt.Errorf("securitycenterClient.UpdateFinding %s %w", finding.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestUnitDeactivateFindings(t *testing.T) {
+	sourceName := "organizations/123456789012/sources/456"
+	assetName := "//storage.googleapis.com/mybucket"
+	ruleName := "monitor_gcs_bucket_public"
+	eventTime := time.Date(2020, 12, 2, 1, 30, 0, 0, time.UTC)
+	newFinding := func(id string, assetName string, ruleName string, constraintName string, state securitycenterpb.Finding_State, eventTime time.Time) *securitycenterpb.Finding {
+		return &securitycenterpb.Finding{
+			Name:         sourceName + "/findings/" + id,
+			ResourceName: assetName,
+			Category:     ruleName,
+			State:        state,
+			EventTime:    timestamppb.New(eventTime),
+			SourceProperties: map[string]*structpb.Value{
+				"constraintName": structpb.NewStringValue(constraintName),
+			},
+		}
+	}
+	var testCases = []struct {
+		name                    string
+		findings                []*securitycenterpb.Finding
+		violatedConstraintNames []string
+		wantCount               int
+		wantActiveIDs           []string
+	}{
+		{
+			name: "twoActiveMessages",
+			findings: []*securitycenterpb.Finding{
+				newFinding("a", assetName, ruleName, "constraint_a", securitycenterpb.Finding_ACTIVE, eventTime.Add(-time.Minute)),
+				newFinding("b", assetName, ruleName, "constraint_b", securitycenterpb.Finding_ACTIVE, eventTime.Add(-time.Minute)),
+			},
+			wantCount: 2,
+		},
+		{
+			name: "alreadyInactive",
+			findings: []*securitycenterpb.Finding{
+				newFinding("a", assetName, ruleName, "constraint_a", securitycenterpb.Finding_INACTIVE, eventTime.Add(-time.Minute)),
+			},
+			wantCount: 0,
+		},
+		{
+			name: "otherRuleOtherAsset",
+			findings: []*securitycenterpb.Finding{
+				newFinding("a", assetName, "monitor_other_rule", "constraint_a", securitycenterpb.Finding_ACTIVE, eventTime.Add(-time.Minute)),
+				newFinding("b", "//storage.googleapis.com/otherbucket", ruleName, "constraint_b", securitycenterpb.Finding_ACTIVE, eventTime.Add(-time.Minute)),
+			},
+			wantCount:     0,
+			wantActiveIDs: []string{"a", "b"},
+		},
+		{
+			name: "stillViolatedConstraintKept",
+			findings: []*securitycenterpb.Finding{
+				newFinding("a", assetName, ruleName, "constraint_a", securitycenterpb.Finding_ACTIVE, eventTime),
+				newFinding("b", assetName, ruleName, "constraint_b", securitycenterpb.Finding_ACTIVE, eventTime.Add(-time.Minute)),
+			},
+			violatedConstraintNames: []string{"constraint_a"},
+			wantCount:               1,
+			wantActiveIDs:           []string{"a"},
+		},
+		{
+			name: "moreRecentFindingKept",
+			findings: []*securitycenterpb.Finding{
+				newFinding("a", assetName, ruleName, "constraint_a", securitycenterpb.Finding_ACTIVE, eventTime.Add(time.Minute)),
+			},
+			wantCount:     0,
+			wantActiveIDs: []string{"a"},
+		},
+		{
+			name:      "noFinding",
+			wantCount: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			fake := &fakeSecurityCenter{findings: make(map[string]*securitycenterpb.Finding)}
+			wasActive := make(map[string]bool)
+			for _, finding := range tc.findings {
+				fake.findings[finding.Name] = finding
+				wasActive[finding.Name] = finding.State == securitycenterpb.Finding_ACTIVE
+			}
+			securitycenterClient, stop := newFakeSecurityCenterClient(t, fake)
+			defer stop()
+
+			count, err := DeactivateFindings(context.Background(), securitycenterClient, sourceName, assetName, ruleName, tc.violatedConstraintNames, eventTime)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if count != tc.wantCount {
+				t.Errorf("Want %d findings deactivated got %d", tc.wantCount, count)
+			}
+			wantActive := make(map[string]bool)
+			for _, id := range tc.wantActiveIDs {
+				wantActive[sourceName+"/findings/"+id] = true
+			}
+			for _, finding := range fake.findings {
+				if wantActive[finding.Name] && finding.State != securitycenterpb.Finding_ACTIVE {
+					t.Errorf("Want finding %s active", finding.Name)
+				}
+				if !wantActive[finding.Name] && finding.State == securitycenterpb.Finding_ACTIVE {
+					t.Errorf("Want finding %s inactive", finding.Name)
+				}
+				if wasActive[finding.Name] && !wantActive[finding.Name] && !finding.EventTime.AsTime().Equal(eventTime) {
+					t.Errorf("Want finding %s event time %v got %v", finding.Name, eventTime, finding.EventTime.AsTime())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"crypto/md5"
+	"fmt"
+)
+
+// GetFindingID returns a stable finding ID, 32 alphanumeric characters, per asset, rule and constraint
+// so a new violation updates the existing finding even when its non compliance message changes
+func GetFindingID(assetName string, ruleName string, constraintName string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(assetName+"\n"+ruleName+"\n"+constraintName)))
+}
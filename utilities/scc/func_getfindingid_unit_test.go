@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestUnitGetFindingID(t *testing.T) {
+	findingID := GetFindingID("//storage.googleapis.com/mybucket", "monitor_gcs_bucket_public", "no_public_bucket")
+	if !regexp.MustCompile(`^[a-zA-Z0-9]{1,32}$`).MatchString(findingID) {
+		t.Errorf("Want 1 to 32 alphanumeric characters got %s", findingID)
+	}
+	if findingID != GetFindingID("//storage.googleapis.com/mybucket", "monitor_gcs_bucket_public", "no_public_bucket") {
+		t.Errorf("Want the same finding ID for the same asset, rule and constraint")
+	}
+	if findingID == GetFindingID("//storage.googleapis.com/mybucket", "monitor_gcs_bucket_public", "no_public_bucket_europe") {
+		t.Errorf("Want a different finding ID for another constraint")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"strings"
+
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+)
+
+// GetSeverity maps a constraint spec severity to a finding severity
+func GetSeverity(constraintSeverity string) securitycenterpb.Finding_Severity {
+	switch strings.ToLower(constraintSeverity) {
+	case "critical":
+		return securitycenterpb.Finding_CRITICAL
+	case "high", "major":
+		return securitycenterpb.Finding_HIGH
+	case "medium":
+		return securitycenterpb.Finding_MEDIUM
+	case "low", "minor":
+		return securitycenterpb.Finding_LOW
+	default:
+		return securitycenterpb.Finding_SEVERITY_UNSPECIFIED
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"testing"
+
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+)
+
+func TestUnitGetSeverity(t *testing.T) {
+	var testCases = []struct {
+		constraintSeverity string
+		want               securitycenterpb.Finding_Severity
+	}{
+		{constraintSeverity: "critical", want: securitycenterpb.Finding_CRITICAL},
+		{constraintSeverity: "high", want: securitycenterpb.Finding_HIGH},
+		{constraintSeverity: "major", want: securitycenterpb.Finding_HIGH},
+		{constraintSeverity: "Medium", want: securitycenterpb.Finding_MEDIUM},
+		{constraintSeverity: "low", want: securitycenterpb.Finding_LOW},
+		{constraintSeverity: "minor", want: securitycenterpb.Finding_LOW},
+		{constraintSeverity: "", want: securitycenterpb.Finding_SEVERITY_UNSPECIFIED},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.constraintSeverity, func(t *testing.T) {
+			t.Parallel()
+			got := GetSeverity(tc.constraintSeverity)
+			if tc.want != got {
+				t.Errorf("Want %s got %s", tc.want, got)
+			}
+		})
+	}
+}
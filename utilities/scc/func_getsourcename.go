@@ -0,0 +1,44 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"context"
+	"fmt"
+
+	securitycenter "cloud.google.com/go/securitycenter/apiv1p1beta1"
+	"google.golang.org/api/iterator"
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+)
+
+// GetSourceName returns the name of the organization source having this display name, empty when not found
+func GetSourceName(ctx context.Context, securitycenterClient *securitycenter.Client, parent string, displayName string) (sourceName string, err error) {
+	var listSourcesRequest securitycenterpb.ListSourcesRequest
+	listSourcesRequest.Parent = parent
+	sourceIterator := securitycenterClient.ListSources(ctx, &listSourcesRequest)
+	for {
+		source, err := sourceIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("sourceIterator.Next %s %v", parent, err)
+		}
+		if source.DisplayName == displayName {
+			return source.Name, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"context"
+	"fmt"
+
+	securitycenter "cloud.google.com/go/securitycenter/apiv1p1beta1"
+	"google.golang.org/api/iterator"
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+)
+
+// UpsertFinding creates the finding or updates all its mutable fields when it already exists
+// Skips the update when the existing finding has a more recent event time, so a late or redelivered violation does not reactivate it
+func UpsertFinding(ctx context.Context, securitycenterClient *securitycenter.Client, finding *securitycenterpb.Finding) (upserted bool, err error) {
+	var listFindingsRequest securitycenterpb.ListFindingsRequest
+	listFindingsRequest.Parent = finding.Parent
+	listFindingsRequest.Filter = fmt.Sprintf(`name = "%s"`, finding.Name)
+	findingIterator := securitycenterClient.ListFindings(ctx, &listFindingsRequest)
+	listFindingsResult, err := findingIterator.Next()
+	if err != nil && err != iterator.Done {
+		return false, fmt.Errorf("findingIterator.Next %s %w", finding.Name, err)
+	}
+	if err == nil && listFindingsResult.Finding.EventTime.AsTime().After(finding.EventTime.AsTime()) {
+		return false, nil
+	}
+
+	var updateFindingRequest securitycenterpb.UpdateFindingRequest
+	updateFindingRequest.Finding = finding
+	if _, err = securitycenterClient.UpdateFinding(ctx, &updateFindingRequest); err != nil {
+		return false, fmt.Errorf("securitycenterClient.UpdateFinding %s %w", finding.Name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestUnitUpsertFinding(t *testing.T) {
+	fake := &fakeSecurityCenter{findings: make(map[string]*securitycenterpb.Finding)}
+	securitycenterClient, stop := newFakeSecurityCenterClient(t, fake)
+	defer stop()
+
+	eventTime := time.Date(2020, 12, 2, 1, 30, 0, 0, time.UTC)
+	findingName := "organizations/123456789012/sources/456/findings/" + GetFindingID("//storage.googleapis.com/mybucket", "monitor_gcs_bucket_public", "no_public_bucket")
+	finding := &securitycenterpb.Finding{
+		Name:         findingName,
+		Parent:       "organizations/123456789012/sources/456",
+		ResourceName: "//storage.googleapis.com/mybucket",
+		State:        securitycenterpb.Finding_ACTIVE,
+		Category:     "monitor_gcs_bucket_public",
+		Severity:     GetSeverity("major"),
+		EventTime:    timestamppb.New(eventTime),
+		SourceProperties: map[string]*structpb.Value{
+			"message": structpb.NewStringValue("bucket is public"),
+		},
+	}
+	upserted, err := UpsertFinding(context.Background(), securitycenterClient, finding)
+	if err != nil {
+		t.Fatalf("Unexpected error on create %v", err)
+	}
+	if !upserted {
+		t.Errorf("Want finding created")
+	}
+	finding.Severity = GetSeverity("critical")
+	finding.EventTime = timestamppb.New(eventTime.Add(time.Minute))
+	upserted, err = UpsertFinding(context.Background(), securitycenterClient, finding)
+	if err != nil {
+		t.Fatalf("Unexpected error on update %v", err)
+	}
+	if !upserted {
+		t.Errorf("Want finding updated")
+	}
+	finding.Severity = GetSeverity("minor")
+	finding.EventTime = timestamppb.New(eventTime)
+	upserted, err = UpsertFinding(context.Background(), securitycenterClient, finding)
+	if err != nil {
+		t.Fatalf("Unexpected error on late update %v", err)
+	}
+	if upserted {
+		t.Errorf("Want late update skipped")
+	}
+	if len(fake.findings) != 1 {
+		t.Fatalf("Want 1 finding got %d", len(fake.findings))
+	}
+	got := fake.findings[findingName]
+	if got == nil {
+		t.Fatalf("Want finding %s got none", findingName)
+	}
+	if got.Severity != securitycenterpb.Finding_CRITICAL {
+		t.Errorf("Want severity %s got %s", securitycenterpb.Finding_CRITICAL, got.Severity)
+	}
+	if got.SourceProperties["message"].GetStringValue() != "bucket is public" {
+		t.Errorf("Want message source property got %v", got.SourceProperties["message"])
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"fmt"
+	"log"
+
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+)
+
+// Deploy the RAM source in an organization, if it does not exist yet
+func (sourceDeployment *SourceDeployment) Deploy() (err error) {
+	parent := fmt.Sprintf("organizations/%s", sourceDeployment.Artifacts.OrganizationID)
+	displayName := sourceDeployment.Settings.Service.SCC.SourceDisplayName
+	log.Printf("%s scc source %s in %s", sourceDeployment.Core.InstanceName, displayName, parent)
+	sourceName, err := GetSourceName(sourceDeployment.Core.Ctx, sourceDeployment.Core.Services.SecuritycenterClient, parent, displayName)
+	if err != nil {
+		return err
+	}
+	if sourceName != "" {
+		log.Printf("%s scc source found %s", sourceDeployment.Core.InstanceName, sourceName)
+		return nil
+	}
+	var createSourceRequest securitycenterpb.CreateSourceRequest
+	createSourceRequest.Parent = parent
+	createSourceRequest.Source = &securitycenterpb.Source{
+		DisplayName: displayName,
+		Description: "Real-time Asset Monitor violations of the monitor rules",
+	}
+	source, err := sourceDeployment.Core.Services.SecuritycenterClient.CreateSource(sourceDeployment.Core.Ctx, &createSourceRequest)
+	if err != nil {
+		return fmt.Errorf("sourceDeployment.Core.Services.SecuritycenterClient.CreateSource %v", err)
+	}
+	log.Printf("%s scc source created %s", sourceDeployment.Core.InstanceName, source.Name)
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scc
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	securitycenter "cloud.google.com/go/securitycenter/apiv1p1beta1"
+	"google.golang.org/api/option"
+	securitycenterpb "google.golang.org/genproto/googleapis/cloud/securitycenter/v1p1beta1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeSecurityCenter local fake of the SCC API keeping sources and findings in memory
+type fakeSecurityCenter struct {
+	securitycenterpb.UnimplementedSecurityCenterServer
+	mu       sync.Mutex
+	findings map[string]*securitycenterpb.Finding
+	sources  []*securitycenterpb.Source
+}
+
+var activeFindingsFilterRegex = regexp.MustCompile(`^resource_name = "(.*)" AND category = "(.*)" AND state = "ACTIVE"$`)
+var nameFilterRegex = regexp.MustCompile(`^name = "(.*)"$`)
+
+func (fake *fakeSecurityCenter) ListSources(ctx context.Context, request *securitycenterpb.ListSourcesRequest) (*securitycenterpb.ListSourcesResponse, error) {
+	var response securitycenterpb.ListSourcesResponse
+	for _, source := range fake.sources {
+		if strings.HasPrefix(source.Name, request.Parent+"/sources/") {
+			response.Sources = append(response.Sources, source)
+		}
+	}
+	return &response, nil
+}
+
+func (fake *fakeSecurityCenter) UpdateFinding(ctx context.Context, request *securitycenterpb.UpdateFindingRequest) (*securitycenterpb.Finding, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if request.UpdateMask == nil {
+		fake.findings[request.Finding.Name] = proto.Clone(request.Finding).(*securitycenterpb.Finding)
+		return request.Finding, nil
+	}
+	finding, ok := fake.findings[request.Finding.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "finding %s not found", request.Finding.Name)
+	}
+	for _, path := range request.UpdateMask.Paths {
+		switch path {
+		case "state":
+			finding.State = request.Finding.State
+		case "event_time":
+			finding.EventTime = request.Finding.EventTime
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported update mask path %s", path)
+		}
+	}
+	return proto.Clone(finding).(*securitycenterpb.Finding), nil
+}
+
+func (fake *fakeSecurityCenter) ListFindings(ctx context.Context, request *securitycenterpb.ListFindingsRequest) (*securitycenterpb.ListFindingsResponse, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var response securitycenterpb.ListFindingsResponse
+	if matches := nameFilterRegex.FindStringSubmatch(request.Filter); matches != nil {
+		if finding, ok := fake.findings[matches[1]]; ok && strings.HasPrefix(matches[1], request.Parent+"/findings/") {
+			response.ListFindingsResults = append(response.ListFindingsResults,
+				&securitycenterpb.ListFindingsResponse_ListFindingsResult{Finding: proto.Clone(finding).(*securitycenterpb.Finding)})
+		}
+		return &response, nil
+	}
+	matches := activeFindingsFilterRegex.FindStringSubmatch(request.Filter)
+	if matches == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported filter %s", request.Filter)
+	}
+	for name, finding := range fake.findings {
+		if strings.HasPrefix(name, request.Parent+"/findings/") &&
+			finding.ResourceName == matches[1] &&
+			finding.Category == matches[2] &&
+			finding.State == securitycenterpb.Finding_ACTIVE {
+			response.ListFindingsResults = append(response.ListFindingsResults,
+				&securitycenterpb.ListFindingsResponse_ListFindingsResult{Finding: proto.Clone(finding).(*securitycenterpb.Finding)})
+		}
+	}
+	return &response, nil
+}
+
+// newFakeSecurityCenterClient serves the fake on a local port and returns a client connected to it, and a function to stop the server
+func newFakeSecurityCenterClient(t *testing.T, fake *fakeSecurityCenter) (*securitycenter.Client, func()) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	securitycenterpb.RegisterSecurityCenterServer(server, fake)
+	go server.Serve(listener)
+	securitycenterClient, err := securitycenter.NewClient(context.Background(),
+		option.WithEndpoint(listener.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithInsecure()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return securitycenterClient, func() {
+		securitycenterClient.Close()
+		server.Stop()
+	}
+}
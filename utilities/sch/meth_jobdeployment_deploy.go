@@ -17,7 +17,8 @@ package sch
 import (
 	"fmt"
 	"log"
-	"strings"
+
+	"github.com/BrunoReboul/ram/utilities/erm"
 
 	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
 )
@@ -33,7 +34,7 @@ func (jobDeployment *JobDeployment) Deploy() (err error) {
 	getJobRequest.Name = name
 	retreivedJob, err := jobDeployment.Core.Services.CloudSchedulerClient.GetJob(jobDeployment.Core.Ctx, &getJobRequest)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "notfound") {
+		if erm.IsNotFound(err) {
 			var pubsubTarget schedulerpb.PubsubTarget
 			pubsubTarget.TopicName = fmt.Sprintf("projects/%s/topics/%s",
 				jobDeployment.Core.SolutionSettings.Hosting.ProjectID,
@@ -18,6 +18,6 @@ package sch
 type Parameters struct {
 	Schedulers map[string]struct {
 		JobName  string `yaml:"jobName"`
-		Schedule string
+		Schedule string `valid:"isCron"`
 	}
 }
@@ -0,0 +1,20 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema generates JSON schemas from settings structures
+//
+// Keys follow the gopkg.in/yaml.v2 rules used to read the settings: the yaml tag name when set, else the lower case field name.
+// Fields tagged yaml:"-" are skipped. Struct do not accept additional properties so that editors and CI reject typos.
+// Validater tags are translated to schema keywords when possible.
+package schema
@@ -0,0 +1,84 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"strconv"
+	"strings"
+)
+
+const validTagKeyName = "valid"
+
+// durationPattern matches Go durations like "600s" or "1h30m"
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// cronPattern matches the five fields of a unix-cron expression, values are checked by the validater
+const cronPattern = `^\S+\s+\S+\s+\S+\s+\S+\s+\S+$`
+
+// applyValidTag translates a validater tag into schema keywords, returns true when the property is required
+func applyValidTag(s *Schema, tagValue string) (required bool) {
+	if tagValue == "" || tagValue == "-" {
+		return false
+	}
+	for _, rule := range strings.Split(tagValue, ";") {
+		tagValueParts := strings.Split(rule, ",")
+		tagPrefix := tagValueParts[0]
+		tagArguments := tagValueParts[1:]
+		switch tagPrefix {
+		case "isNotZeroValue":
+			one := int64(1)
+			switch s.Type {
+			case "string":
+				s.MinLength = &one
+			case "array":
+				s.MinItems = &one
+			case "integer":
+				s.Not = &Schema{Const: 0}
+			}
+			required = true
+		case "isAvailableMemory":
+			s.Enum = []interface{}{128, 256, 512, 1024, 2048}
+		case "isOneOf":
+			s.Enum = make([]interface{}, 0, len(tagArguments)+1)
+			// zero value is accepted by the validater
+			s.Enum = append(s.Enum, "")
+			for _, acceptedValue := range tagArguments {
+				s.Enum = append(s.Enum, acceptedValue)
+			}
+		case "isMatching":
+			s.Pattern = strings.Join(tagArguments, ",")
+		case "isMapKeyMatching":
+			s.PropertyNames = &Schema{Pattern: strings.Join(tagArguments, ",")}
+		case "isMapValueMatching":
+			s.AdditionalProperties = &Schema{Type: "string", Pattern: strings.Join(tagArguments, ",")}
+		case "isDuration":
+			s.Pattern = durationPattern
+		case "isCron":
+			s.Pattern = cronPattern
+		case "isEmail":
+			s.Format = "email"
+		case "isInRange":
+			if len(tagArguments) == 2 {
+				if min, err := strconv.ParseFloat(tagArguments[0], 64); err == nil {
+					s.Minimum = &min
+				}
+				if max, err := strconv.ParseFloat(tagArguments[1], 64); err == nil {
+					s.Maximum = &max
+				}
+			}
+		}
+	}
+	return required
+}
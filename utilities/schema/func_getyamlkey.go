@@ -0,0 +1,37 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// getYAMLKey returns the key used by gopkg.in/yaml.v2 for a struct field, and false when the field is not serialized
+func getYAMLKey(field reflect.StructField) (key string, ok bool) {
+	if field.PkgPath != "" {
+		// unexported field
+		return "", false
+	}
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false
+	}
+	key = strings.Split(tag, ",")[0]
+	if key == "" {
+		key = strings.ToLower(field.Name)
+	}
+	return key, true
+}
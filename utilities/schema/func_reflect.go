@@ -0,0 +1,30 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"reflect"
+)
+
+const draft07 = "http://json-schema.org/draft-07/schema#"
+
+// Reflect returns the JSON schema of a settings structure
+func Reflect(settings interface{}, title string) *Schema {
+	typ := reflect.TypeOf(settings)
+	s := reflectType(typ)
+	s.Schema = draft07
+	s.Title = title
+	return s
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testSettings struct {
+	Name        string `valid:"isNotZeroValue"`
+	TopicName   string `yaml:"topicName,omitempty"`
+	Ignored     string `yaml:"-"`
+	hidden      string
+	MemoryMb    int64             `yaml:"memoryMb" valid:"isAvailableMemory"`
+	Labels      map[string]string `yaml:"labels"`
+	AssetTypes  []string          `yaml:"assetTypes" valid:"isNotZeroValue"`
+	Enabled     bool
+	StartTime   time.Time `yaml:"startTime" valid:"-"`
+	Constraints []interface{}
+	Nested      struct {
+		Count int64 `valid:"isNotZeroValue"`
+	}
+	ContentType string            `yaml:"contentType" valid:"isOneOf,RESOURCE,IAM_POLICY"`
+	Topic       string            `valid:"isMatching,^[a-z]{2,4}$"`
+	Timeout     string            `valid:"isDuration"`
+	Email       string            `valid:"isEmail"`
+	PerPage     int64             `yaml:"perPage" valid:"isInRange,1,200"`
+	IDs         map[string]string `yaml:"ids" valid:"isMapKeyMatching,^[a-z]+$;isMapValueMatching,^[0-9]+$"`
+}
+
+func TestUnitReflect(t *testing.T) {
+	var s testSettings
+	s.hidden = "not serialized"
+	got := Reflect(&s, "testSettings")
+	if got.Schema != draft07 {
+		t.Errorf("Want $schema %s got %s", draft07, got.Schema)
+	}
+
+	var testCases = []struct {
+		name     string
+		key      string
+		wantType string
+		wantJSON string
+	}{
+		{"lowerCaseFieldName", "name", "string", `{"type":"string","minLength":1}`},
+		{"yamlTagName", "topicName", "string", `{"type":"string"}`},
+		{"enum", "memoryMb", "integer", `{"type":"integer","enum":[128,256,512,1024,2048]}`},
+		{"map", "labels", "object", `{"type":"object","additionalProperties":{"type":"string"}}`},
+		{"slice", "assetTypes", "array", `{"type":"array","items":{"type":"string"},"minItems":1}`},
+		{"bool", "enabled", "boolean", `{"type":"boolean"}`},
+		{"time", "startTime", "string", `{"type":"string","format":"date-time"}`},
+		{"any", "constraints", "array", `{"type":"array","items":{}}`},
+		{"oneOf", "contentType", "string", `{"type":"string","enum":["","RESOURCE","IAM_POLICY"]}`},
+		{"matching", "topic", "string", `{"type":"string","pattern":"^[a-z]{2,4}$"}`},
+		{"duration", "timeout", "string", `{"type":"string","pattern":"^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"}`},
+		{"email", "email", "string", `{"type":"string","format":"email"}`},
+		{"range", "perPage", "integer", `{"type":"integer","minimum":1,"maximum":200}`},
+		{"mapMatching", "ids", "object", `{"type":"object","additionalProperties":{"type":"string","pattern":"^[0-9]+$"},"propertyNames":{"pattern":"^[a-z]+$"}}`},
+		{"nested", "nested", "object", `{"type":"object","properties":{"count":{"type":"integer","not":{"const":0}}},"additionalProperties":false,"required":["count"]}`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			property, ok := got.Properties[tc.key]
+			if !ok {
+				t.Fatalf("Want property %s not found", tc.key)
+			}
+			if property.Type != tc.wantType {
+				t.Errorf("Want type %s got %s", tc.wantType, property.Type)
+			}
+			b, err := json.Marshal(property)
+			if err != nil {
+				t.Fatalf("json.Marshal %v", err)
+			}
+			if string(b) != tc.wantJSON {
+				t.Errorf("Want %s got %s", tc.wantJSON, string(b))
+			}
+		})
+	}
+
+	for _, key := range []string{"ignored", "hidden", "Ignored"} {
+		if _, ok := got.Properties[key]; ok {
+			t.Errorf("Want property %s skipped", key)
+		}
+	}
+	if len(got.Required) != 2 || got.Required[0] != "name" || got.Required[1] != "assetTypes" {
+		t.Errorf("Want required [name assetTypes] got %v", got.Required)
+	}
+}
@@ -25,5 +25,6 @@ const (
 	MicroserviceParentFolderName = "services"
 	InstancesFolderName          = "instances"
 	RegoConstraintsFolderName    = "constraints"
+	SchemasFolderName            = "schemas"
 	SolutionName                 = "ram"
 )
@@ -16,7 +16,7 @@ package solution
 
 // Situate set settings from settings based on a given situation
 // Situation is the environment name (string)
-// Set settings are: folderID, projectID, Stackdriver projectID, Buckets names
+// Set settings are: folderID, projectID, Stackdriver projectID, Buckets names, alerting thresholds defaults
 func (settings *Settings) Situate(environmentName string) {
 	settings.Hosting.OrganizationID = settings.Hosting.OrganizationIDs[environmentName]
 	settings.Hosting.FolderID = settings.Hosting.FolderIDs[environmentName]
@@ -24,6 +24,8 @@ func (settings *Settings) Situate(environmentName string) {
 	settings.Hosting.Stackdriver.ProjectID = settings.Hosting.Stackdriver.ProjectIDs[environmentName]
 	settings.Hosting.GCS.Buckets.CAIExport.Name = settings.Hosting.GCS.Buckets.CAIExport.Names[environmentName]
 	settings.Hosting.GCS.Buckets.AssetsJSONFile.Name = settings.Hosting.GCS.Buckets.AssetsJSONFile.Names[environmentName]
+	settings.Hosting.GCS.Buckets.DeadLetters.Name = settings.Hosting.GCS.Buckets.DeadLetters.Names[environmentName]
+	settings.Hosting.GCS.Buckets.Snapshots.Name = settings.Hosting.GCS.Buckets.Snapshots.Names[environmentName]
 	if settings.Hosting.GCB.QueueTTL == "" {
 		settings.Hosting.GCB.QueueTTL = "7200s"
 	}
@@ -33,4 +35,16 @@ func (settings *Settings) Situate(environmentName string) {
 	if settings.Hosting.GCS.Buckets.AssetsJSONFile.DeleteAgeInDays == 0 {
 		settings.Hosting.GCS.Buckets.AssetsJSONFile.DeleteAgeInDays = 365
 	}
+	if settings.Hosting.GCS.Buckets.DeadLetters.DeleteAgeInDays == 0 {
+		settings.Hosting.GCS.Buckets.DeadLetters.DeleteAgeInDays = 90
+	}
+	if settings.Hosting.GCS.Buckets.Snapshots.DeleteAgeInDays == 0 {
+		settings.Hosting.GCS.Buckets.Snapshots.DeleteAgeInDays = 7
+	}
+	if settings.Hosting.Stackdriver.Alerting.ErrorRatePercent == 0 {
+		settings.Hosting.Stackdriver.Alerting.ErrorRatePercent = 5
+	}
+	if settings.Hosting.Stackdriver.Alerting.LatencyE2EP95Seconds == 0 {
+		settings.Hosting.Stackdriver.Alerting.LatencyE2EP95Seconds = 600
+	}
 }
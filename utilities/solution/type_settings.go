@@ -14,20 +14,28 @@
 
 package solution
 
+import "github.com/BrunoReboul/ram/utilities/alm"
+
 // Settings settings common to all services / all instances
 type Settings struct {
 	Hosting struct {
 		OrganizationID   string            `yaml:"organizationID,omitempty"`
-		OrganizationIDs  map[string]string `yaml:"organizationIDs"`
+		OrganizationIDs  map[string]string `yaml:"organizationIDs" valid:"isMapKeyMatching,^[a-zA-Z0-9_-]+$;isMapValueMatching,^[0-9]+$"`
 		BillingAccountID string            `yaml:"billingAccountID"`
 		FolderID         string            `yaml:"folderID,omitempty"`
-		FolderIDs        map[string]string `yaml:"folderIDs"`
+		FolderIDs        map[string]string `yaml:"folderIDs" valid:"isMapKeyMatching,^[a-zA-Z0-9_-]+$;isMapValueMatching,^[0-9]+$"`
 		ProjectID        string            `yaml:"projectID,omitempty"`
 		ProjectLabels    map[string]string `yaml:"projectLabels"`
-		ProjectIDs       map[string]string `yaml:"projectIDs"`
+		ProjectIDs       map[string]string `yaml:"projectIDs" valid:"isMapKeyMatching,^[a-zA-Z0-9_-]+$;isMapValueMatching,^[a-z][a-z0-9-]{4,28}[a-z0-9]$"`
 		Stackdriver      struct {
+			Alerting struct {
+				ErrorRatePercent     int64    `yaml:"errorRatePercent" valid:"isInRange,0,100"`
+				InitFailures         int64    `yaml:"initFailures"`
+				LatencyE2EP95Seconds int64    `yaml:"latencyE2EP95Seconds"`
+				NotificationChannels []string `yaml:"notificationChannels,omitempty"`
+			} `yaml:"alerting,omitempty"`
 			ProjectID  string            `yaml:"projectID,omitempty"`
-			ProjectIDs map[string]string `yaml:"projectIDs"`
+			ProjectIDs map[string]string `yaml:"projectIDs" valid:"isMapKeyMatching,^[a-zA-Z0-9_-]+$;isMapValueMatching,^[a-z][a-z0-9-]{4,28}[a-z0-9]$"`
 		}
 		Repository struct {
 			Name string `valid:"isNotZeroValue"`
@@ -36,7 +44,7 @@ type Settings struct {
 			Region string `valid:"isNotZeroValue"`
 		}
 		GCB struct {
-			QueueTTL string `yaml:"queueTtl"`
+			QueueTTL string `yaml:"queueTtl" valid:"isDuration"`
 		}
 		GCF struct {
 			Region string `valid:"isNotZeroValue"`
@@ -44,15 +52,25 @@ type Settings struct {
 		GCS struct {
 			Buckets struct {
 				CAIExport struct {
-					Name            string `yaml:",omitempty"`
-					Names           map[string]string
-					DeleteAgeInDays int64 `yaml:"deleteAgeInDays,omitempty"`
+					Name            string            `yaml:",omitempty"`
+					Names           map[string]string `valid:"isMapValueMatching,^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$"`
+					DeleteAgeInDays int64             `yaml:"deleteAgeInDays,omitempty"`
 				} `yaml:"CAIExport"`
 				AssetsJSONFile struct {
-					Name            string `yaml:",omitempty"`
-					Names           map[string]string
-					DeleteAgeInDays int64 `yaml:"deleteAgeInDays,omitempty"`
+					Name            string            `yaml:",omitempty"`
+					Names           map[string]string `valid:"isMapValueMatching,^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$"`
+					DeleteAgeInDays int64             `yaml:"deleteAgeInDays,omitempty"`
 				} `yaml:"assetsJSONFile"`
+				DeadLetters struct {
+					Name            string            `yaml:",omitempty"`
+					Names           map[string]string `valid:"isMapValueMatching,^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$"`
+					DeleteAgeInDays int64             `yaml:"deleteAgeInDays,omitempty"`
+				} `yaml:"deadLetters,omitempty"`
+				Snapshots struct {
+					Name            string            `yaml:",omitempty"`
+					Names           map[string]string `valid:"isMapValueMatching,^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$"`
+					DeleteAgeInDays int64             `yaml:"deleteAgeInDays,omitempty"`
+				} `yaml:"snapshots,omitempty"`
 			}
 		}
 		Bigquery struct {
@@ -66,11 +84,12 @@ type Settings struct {
 		}
 		Pubsub struct {
 			TopicNames struct {
-				IAMPolicies         string `yaml:"IAMPolicies" valid:"isNotZeroValue"`
-				RAMViolation        string `yaml:"RAMViolation" valid:"isNotZeroValue"`
-				RAMComplianceStatus string `yaml:"RAMComplianceStatus" valid:"isNotZeroValue"`
-				GCIGroupMembers     string `yaml:"GCIGroupMembers"`
-				GCIGroupSettings    string `yaml:"GCIGroupSettings"`
+				IAMPolicies              string `yaml:"IAMPolicies" valid:"isNotZeroValue;isMatching,^[a-zA-Z][a-zA-Z0-9_.~+%-]{2,254}$"`
+				RAMViolation             string `yaml:"RAMViolation" valid:"isNotZeroValue;isMatching,^[a-zA-Z][a-zA-Z0-9_.~+%-]{2,254}$"`
+				RAMComplianceStatus      string `yaml:"RAMComplianceStatus" valid:"isNotZeroValue;isMatching,^[a-zA-Z][a-zA-Z0-9_.~+%-]{2,254}$"`
+				GCIGroupMembers          string `yaml:"GCIGroupMembers" valid:"isMatching,^[a-zA-Z][a-zA-Z0-9_.~+%-]{2,254}$"`
+				GCIGroupSettings         string `yaml:"GCIGroupSettings" valid:"isMatching,^[a-zA-Z][a-zA-Z0-9_.~+%-]{2,254}$"`
+				GCIGroupEffectiveMembers string `yaml:"GCIGroupEffectiveMembers" valid:"isMatching,^[a-zA-Z][a-zA-Z0-9_.~+%-]{2,254}$"`
 			} `yaml:"topicNames"`
 		}
 		FireStore struct {
@@ -87,16 +106,23 @@ type Settings struct {
 		} `yaml:"labelKeyNames"`
 		DefaultSchedulers map[string]struct {
 			JobName  string `yaml:"jobName"`
-			Schedule string
+			Schedule string `valid:"isCron"`
 		} `yaml:"defaultSchedulers"`
 		DirectoryCustomerIDs map[string]struct {
-			SuperAdminEmail string `yaml:"superAdminEmail"`
+			SuperAdminEmail string `yaml:"superAdminEmail" valid:"isEmail"`
+			Keyless         bool   `yaml:"keyless,omitempty"`
+			Backend         string `yaml:"backend,omitempty" valid:"isOneOf,directory,cloudidentity"`
 		} `yaml:"directoryCustomerIDs"`
 		ListGroupsDefaultSchedulers map[string]struct {
 			JobName  string `yaml:"jobName"`
-			Schedule string
+			Schedule string `valid:"isCron"`
 		} `yaml:"listGroupsDefaultSchedulers"`
-		AssetTypes struct {
+		ListUsersDefaultSchedulers map[string]struct {
+			JobName  string `yaml:"jobName"`
+			Schedule string `valid:"isCron"`
+		} `yaml:"listUsersDefaultSchedulers"`
+		AuditLogMappings map[string]alm.Mapping `yaml:"auditLogMappings,omitempty"`
+		AssetTypes       struct {
 			IAMPolicies []string `yaml:"iamPolicies"`
 			Resources   []string `yaml:"resources"`
 		} `yaml:"assetTypes"`
@@ -0,0 +1,22 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tfp helps with terraform plans
+//
+// It converts the planned google_* resources of a "terraform show -json" output into the Cloud Asset Inventory like assets that monitor rego templates evaluate.
+//
+// Resource attributes become resource.data: snake_case keys turn camelCase, blocks limited to one item become objects, unset values are dropped, then per type renames align the few names that differ from the API.
+// IAM member, binding and policy resources are merged into the iam_policy of the asset they apply to.
+// The ancestry path is the one provided for the plan, completed by the project from the resource, else from the provider config.
+package tfp
@@ -0,0 +1,127 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import "strings"
+
+// singleBlocks terraform google provider blocks limited to one item, converted to objects like in the API
+var singleBlocks = map[string]bool{
+	"addons_config":                     true,
+	"authenticator_groups_config":       true,
+	"backup_configuration":              true,
+	"binary_authorization":              true,
+	"boot_disk":                         true,
+	"client_certificate_config":         true,
+	"database_encryption":               true,
+	"default_encryption_configuration":  true,
+	"dnssec_config":                     true,
+	"encryption":                        true,
+	"horizontal_pod_autoscaling":        true,
+	"http_load_balancing":               true,
+	"iam_configuration":                 true,
+	"initialize_params":                 true,
+	"ip_allocation_policy":              true,
+	"ip_configuration":                  true,
+	"kubernetes_dashboard":              true,
+	"location_preference":               true,
+	"log_config":                        true,
+	"logging":                           true,
+	"maintenance_policy":                true,
+	"maintenance_window":                true,
+	"management":                        true,
+	"master_auth":                       true,
+	"master_authorized_networks_config": true,
+	"network_policy":                    true,
+	"network_policy_config":             true,
+	"node_config":                       true,
+	"private_cluster_config":            true,
+	"private_visibility_config":         true,
+	"retention_policy":                  true,
+	"scheduling":                        true,
+	"service_account":                   true,
+	"settings":                          true,
+	"shielded_instance_config":          true,
+	"shielded_nodes":                    true,
+	"versioning":                        true,
+	"website":                           true,
+	"workload_identity_config":          true,
+	"workload_metadata_config":          true,
+}
+
+// keptKeysMaps map attributes whose keys are user defined, not converted to camelCase
+var keptKeysMaps = map[string]bool{
+	"labels":          true,
+	"metadata":        true,
+	"resource_labels": true,
+	"user_labels":     true,
+}
+
+// convertValues converts terraform planned values to the API representation: camelCase keys, single blocks as objects, unset values dropped
+func convertValues(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		data := make(map[string]interface{})
+		for k, childValue := range v {
+			if childValue == nil {
+				continue
+			}
+			if keptKeysMaps[k] {
+				if m, ok := childValue.(map[string]interface{}); ok {
+					if len(m) > 0 {
+						data[toCamelCase(k)] = m
+					}
+					continue
+				}
+			}
+			if converted := convertValues(k, childValue); converted != nil {
+				data[toCamelCase(k)] = converted
+			}
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		return data
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		if singleBlocks[key] && len(v) == 1 {
+			return convertValues(key, v[0])
+		}
+		var values []interface{}
+		for _, item := range v {
+			if converted := convertValues(key, item); converted != nil {
+				values = append(values, converted)
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		return values
+	default:
+		return value
+	}
+}
+
+// toCamelCase converts a snake_case key to camelCase
+func toCamelCase(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// getAncestryPath completes the plan ancestry path with the resource project, a planned project is located from its org_id or folder_id
+func getAncestryPath(ancestryPath string, resourceType string, values map[string]interface{}, project string) string {
+	ancestryPath = strings.Trim(ancestryPath, "/")
+	if resourceType == "google_project" {
+		if orgID := getString(values, "org_id"); orgID != "" {
+			ancestryPath = fmt.Sprintf("organization/%s", orgID)
+		}
+		if folderID := strings.TrimPrefix(getString(values, "folder_id"), "folders/"); folderID != "" {
+			folder := fmt.Sprintf("folder/%s", folderID)
+			if i := strings.Index(ancestryPath+"/", folder+"/"); i >= 0 {
+				ancestryPath = ancestryPath[:i+len(folder)]
+			} else {
+				ancestryPath = joinPath(ancestryPath, folder)
+			}
+		}
+		project = getString(values, "project_id")
+	}
+	if project == "" {
+		return ancestryPath
+	}
+	return joinPath(ancestryPath, fmt.Sprintf("project/%s", project))
+}
+
+func joinPath(path string, child string) string {
+	if path == "" {
+		return child
+	}
+	return path + "/" + child
+}
+
+// getString returns a planned value when it is a known string
+func getString(values map[string]interface{}, key string) string {
+	if value, ok := values[key].(string); ok {
+		return value
+	}
+	return ""
+}
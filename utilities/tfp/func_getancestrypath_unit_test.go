@@ -0,0 +1,76 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import (
+	"testing"
+)
+
+func TestUnitGetAncestryPath(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		ancestryPath     string
+		resourceType     string
+		values           map[string]interface{}
+		project          string
+		wantAncestryPath string
+	}{
+		{
+			name:             "resourceInProject",
+			ancestryPath:     "organization/123/folder/456/",
+			resourceType:     "google_storage_bucket",
+			project:          "p1",
+			wantAncestryPath: "organization/123/folder/456/project/p1",
+		},
+		{
+			name:             "unknownProject",
+			ancestryPath:     "organization/123",
+			resourceType:     "google_storage_bucket",
+			wantAncestryPath: "organization/123",
+		},
+		{
+			name:             "projectInOrg",
+			ancestryPath:     "organization/123/folder/456",
+			resourceType:     "google_project",
+			values:           map[string]interface{}{"project_id": "p2", "org_id": "789"},
+			wantAncestryPath: "organization/789/project/p2",
+		},
+		{
+			name:             "projectInParentFolder",
+			ancestryPath:     "organization/123/folder/456/folder/789",
+			resourceType:     "google_project",
+			values:           map[string]interface{}{"project_id": "p3", "folder_id": "folders/456"},
+			wantAncestryPath: "organization/123/folder/456/project/p3",
+		},
+		{
+			name:             "projectInChildFolder",
+			ancestryPath:     "organization/123/folder/456",
+			resourceType:     "google_project",
+			values:           map[string]interface{}{"project_id": "p4", "folder_id": "789"},
+			wantAncestryPath: "organization/123/folder/456/folder/789/project/p4",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getAncestryPath(tc.ancestryPath, tc.resourceType, tc.values, tc.project)
+			if got != tc.wantAncestryPath {
+				t.Errorf("Want %s got %s", tc.wantAncestryPath, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var namePlaceholderRegexp = regexp.MustCompile(`{([a-z_]+)(:base)?}`)
+
+// getAssetName replaces the name format placeholders by the planned values, unknown values are named unknown
+func getAssetName(nameFormat string, values map[string]interface{}, project string) string {
+	return namePlaceholderRegexp.ReplaceAllStringFunc(nameFormat, func(placeholder string) string {
+		matches := namePlaceholderRegexp.FindStringSubmatch(placeholder)
+		value := ""
+		if v, ok := values[matches[1]]; ok && v != nil {
+			value = fmt.Sprintf("%v", v)
+		}
+		if value == "" && matches[1] == "project" {
+			value = project
+		}
+		if value == "" {
+			return "unknown"
+		}
+		if matches[2] != "" {
+			parts := strings.Split(value, "/")
+			value = parts[len(parts)-1]
+		}
+		return value
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import (
+	"testing"
+)
+
+func TestUnitGetAssetName(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		nameFormat string
+		values     map[string]interface{}
+		project    string
+		wantName   string
+	}{
+		{
+			name:       "projectFromValues",
+			nameFormat: "//cloudsql.googleapis.com/projects/{project}/instances/{name}",
+			values:     map[string]interface{}{"project": "p1", "name": "db"},
+			project:    "provider",
+			wantName:   "//cloudsql.googleapis.com/projects/p1/instances/db",
+		},
+		{
+			name:       "projectFromProvider",
+			nameFormat: "//cloudsql.googleapis.com/projects/{project}/instances/{name}",
+			values:     map[string]interface{}{"name": "db"},
+			project:    "provider",
+			wantName:   "//cloudsql.googleapis.com/projects/provider/instances/db",
+		},
+		{
+			name:       "base",
+			nameFormat: "//storage.googleapis.com/{bucket:base}",
+			values:     map[string]interface{}{"bucket": "b/logs"},
+			wantName:   "//storage.googleapis.com/logs",
+		},
+		{
+			name:       "knownAfterApply",
+			nameFormat: "//cloudkms.googleapis.com/{key_ring}/cryptoKeys/{name}",
+			values:     map[string]interface{}{"name": "key"},
+			wantName:   "//cloudkms.googleapis.com/unknown/cryptoKeys/key",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getAssetName(tc.nameFormat, tc.values, tc.project)
+			if got != tc.wantName {
+				t.Errorf("Want %s got %s", tc.wantName, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetAssets converts the resources planned in a terraform show -json output into assets located under ancestryPath
+// Resource types without a mapping are returned in unsupportedTypes, data sources are ignored
+func GetAssets(planJSON []byte, ancestryPath string) (assets []Asset, unsupportedTypes []string, err error) {
+	var p plan
+	if err = json.Unmarshal(planJSON, &p); err != nil {
+		return nil, nil, fmt.Errorf("json.Unmarshal plan %v", err)
+	}
+	if p.FormatVersion == "" {
+		return nil, nil, fmt.Errorf("missing format_version, expecting the output of terraform show -json on a plan file")
+	}
+	providerProject := getProviderProject(p)
+	var resources []resource
+	collectResources(p.PlannedValues.RootModule, &resources)
+
+	assetIndexes := make(map[string]int)
+	unsupported := make(map[string]bool)
+	for _, r := range resources {
+		if r.Mode != "managed" {
+			continue
+		}
+		mapping, ok := assetTypeMappings[r.Type]
+		if !ok {
+			if strings.HasPrefix(r.Type, "google_") {
+				unsupported[r.Type] = true
+			}
+			continue
+		}
+		if r.Values == nil {
+			r.Values = make(map[string]interface{})
+		}
+		project := getString(r.Values, "project")
+		if project == "" {
+			project = providerProject
+		}
+		name := getAssetName(mapping.NameFormat, r.Values, project)
+		i, ok := assetIndexes[name]
+		if !ok {
+			path := getAncestryPath(ancestryPath, r.Type, r.Values, project)
+			assets = append(assets, Asset{
+				Name:               name,
+				AncestryPath:       path,
+				AncestryPathLegacy: path,
+				AssetType:          mapping.AssetType,
+				AssetTypeLegacy:    mapping.AssetType,
+			})
+			i = len(assets) - 1
+			assetIndexes[name] = i
+		}
+		asset := &assets[i]
+		asset.Addresses = append(asset.Addresses, r.Address)
+		if mapping.IAM != "" {
+			bindings, err := getIAMBindings(mapping.IAM, r.Values)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %v", r.Address, err)
+			}
+			if asset.IamPolicy == nil {
+				asset.IamPolicy = &IamPolicy{}
+				asset.IamPolicyLegacy = asset.IamPolicy
+			}
+			mergeBindings(asset.IamPolicy, bindings)
+			continue
+		}
+		data, _ := convertValues("", r.Values).(map[string]interface{})
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		for _, rename := range mapping.Renames {
+			renamePath(data, rename[0], rename[1])
+		}
+		for _, key := range mapping.Lists {
+			forceList(data, key)
+		}
+		if mapping.Adjust != nil {
+			mapping.Adjust(data)
+		}
+		asset.Resource = &Resource{Data: data}
+	}
+	for resourceType := range unsupported {
+		unsupportedTypes = append(unsupportedTypes, resourceType)
+	}
+	sort.Strings(unsupportedTypes)
+	return assets, unsupportedTypes, nil
+}
+
+func collectResources(m module, resources *[]resource) {
+	*resources = append(*resources, m.Resources...)
+	for _, child := range m.ChildModules {
+		collectResources(child, resources)
+	}
+}
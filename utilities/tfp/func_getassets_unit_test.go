@@ -0,0 +1,89 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestUnitGetAssets(t *testing.T) {
+	planJSON, err := ioutil.ReadFile("testdata/plan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assets, unsupportedTypes, err := GetAssets(planJSON, "organization/123/folder/456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"google_cloud_scheduler_job"}; !reflect.DeepEqual(unsupportedTypes, want) {
+		t.Errorf("Want unsupported types %v got %v", want, unsupportedTypes)
+	}
+	if len(assets) != 2 {
+		t.Fatalf("Want 2 assets got %d", len(assets))
+	}
+
+	var testCases = []struct {
+		name             string
+		asset            Asset
+		wantName         string
+		wantAncestryPath string
+		wantData         string
+		wantIamPolicy    string
+	}{
+		{
+			name:             "bucket",
+			asset:            assets[0],
+			wantName:         "//storage.googleapis.com/logs-bucket",
+			wantAncestryPath: "organization/123/folder/456/project/app-project",
+			wantData:         `{"forceDestroy":false,"iamConfiguration":{"uniformBucketLevelAccess":{"enabled":false}},"labels":{"cost_center":"it"},"location":"EUROPE-WEST1","name":"logs-bucket","versioning":{"enabled":true}}`,
+			wantIamPolicy:    `{"bindings":[{"role":"roles/storage.objectViewer","members":["allUsers","group:auditors@example.com"]}]}`,
+		},
+		{
+			name:             "firewall",
+			asset:            assets[1],
+			wantName:         "//compute.googleapis.com/projects/net-project/global/firewalls/allow-ssh",
+			wantAncestryPath: "organization/123/folder/456/project/net-project",
+			wantData:         `{"allowed":[{"IPProtocol":"tcp","ports":["22"]}],"direction":"INGRESS","logConfig":{"enable":true,"metadata":"INCLUDE_ALL_METADATA"},"name":"allow-ssh","network":"default","project":"net-project","sourceRanges":["0.0.0.0/0"]}`,
+			wantIamPolicy:    "null",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if tc.asset.Name != tc.wantName {
+				t.Errorf("Want name %s got %s", tc.wantName, tc.asset.Name)
+			}
+			if tc.asset.AncestryPathLegacy != tc.wantAncestryPath {
+				t.Errorf("Want ancestry_path %s got %s", tc.wantAncestryPath, tc.asset.AncestryPathLegacy)
+			}
+			var data []byte
+			if tc.asset.Resource != nil {
+				data, _ = json.Marshal(tc.asset.Resource.Data)
+			}
+			if string(data) != tc.wantData {
+				t.Errorf("Want data %s got %s", tc.wantData, string(data))
+			}
+			iamPolicy, _ := json.Marshal(tc.asset.IamPolicyLegacy)
+			if string(iamPolicy) != tc.wantIamPolicy {
+				t.Errorf("Want iam_policy %s got %s", tc.wantIamPolicy, string(iamPolicy))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// getIAMBindings returns the bindings granted by an IAM member, binding or policy resource
+func getIAMBindings(iam string, values map[string]interface{}) (bindings []Binding, err error) {
+	switch iam {
+	case "member":
+		role, member := getString(values, "role"), getString(values, "member")
+		if role != "" && member != "" {
+			bindings = append(bindings, Binding{Role: role, Members: []string{member}})
+		}
+	case "binding":
+		binding := Binding{Role: getString(values, "role")}
+		if members, ok := values["members"].([]interface{}); ok {
+			for _, member := range members {
+				if m, ok := member.(string); ok {
+					binding.Members = append(binding.Members, m)
+				}
+			}
+		}
+		if binding.Role != "" && len(binding.Members) > 0 {
+			bindings = append(bindings, binding)
+		}
+	case "policy":
+		// policy_data is unknown until apply when built from a data source depending on other resources
+		policyData := getString(values, "policy_data")
+		if policyData == "" {
+			return nil, nil
+		}
+		var policy IamPolicy
+		if err = json.Unmarshal([]byte(policyData), &policy); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal policy_data %v", err)
+		}
+		bindings = policy.Bindings
+	}
+	return bindings, nil
+}
+
+// mergeBindings adds bindings to a policy, members of the same role are merged
+func mergeBindings(policy *IamPolicy, bindings []Binding) {
+	for _, binding := range bindings {
+		merged := false
+		for i := range policy.Bindings {
+			if policy.Bindings[i].Role == binding.Role {
+				for _, member := range binding.Members {
+					if !contains(policy.Bindings[i].Members, member) {
+						policy.Bindings[i].Members = append(policy.Bindings[i].Members, member)
+					}
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			policy.Bindings = append(policy.Bindings, binding)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import "encoding/json"
+
+// getProviderProject returns the project set as a constant in the google or google-beta provider config, empty when not found
+func getProviderProject(p plan) string {
+	for _, key := range []string{"google", "google-beta"} {
+		config, ok := p.Configuration.ProviderConfig[key]
+		if !ok {
+			continue
+		}
+		var project string
+		if json.Unmarshal(config.Expressions["project"].ConstantValue, &project) == nil && project != "" {
+			return project
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import "strings"
+
+// renamePath moves the value at a camelCase path to a dotted path relative to the object holding it, [] iterates over arrays
+func renamePath(data map[string]interface{}, from string, to string) {
+	parts := strings.SplitN(from, ".", 2)
+	if len(parts) == 1 {
+		value, ok := data[from]
+		if !ok {
+			return
+		}
+		delete(data, from)
+		setPath(data, to, value)
+		return
+	}
+	child := data[strings.TrimSuffix(parts[0], "[]")]
+	switch c := child.(type) {
+	case map[string]interface{}:
+		renamePath(c, parts[1], to)
+	case []interface{}:
+		for _, item := range c {
+			if m, ok := item.(map[string]interface{}); ok {
+				renamePath(m, parts[1], to)
+			}
+		}
+	}
+}
+
+// setPath sets a value at a dotted path, creating intermediate objects
+func setPath(data map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := data[part].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			data[part] = child
+		}
+		data = child
+	}
+	data[parts[len(parts)-1]] = value
+}
+
+// forceList wraps into an array the object found at a top level key
+func forceList(data map[string]interface{}, key string) {
+	if m, ok := data[key].(map[string]interface{}); ok {
+		data[key] = []interface{}{m}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+// Asset Cloud Asset Inventory like asset, with the legacy field names used by policy library templates
+type Asset struct {
+	Name               string     `json:"name"`
+	AncestryPath       string     `json:"ancestryPath"`
+	AncestryPathLegacy string     `json:"ancestry_path"`
+	AssetType          string     `json:"assetType"`
+	AssetTypeLegacy    string     `json:"asset_type"`
+	IamPolicy          *IamPolicy `json:"iamPolicy,omitempty"`
+	IamPolicyLegacy    *IamPolicy `json:"iam_policy,omitempty"`
+	Resource           *Resource  `json:"resource,omitempty"`
+	Addresses          []string   `json:"-"`
+}
+
+// Resource Cloud Asset Inventory like resource
+type Resource struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// IamPolicy Cloud Asset Inventory like IAM policy
+type IamPolicy struct {
+	Bindings []Binding `json:"bindings"`
+}
+
+// Binding role granted to members
+type Binding struct {
+	Role    string   `json:"role"`
+	Members []string `json:"members"`
+}
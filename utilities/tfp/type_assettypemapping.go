@@ -0,0 +1,190 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import "strings"
+
+// assetTypeMapping how a terraform resource type maps to a Cloud Asset Inventory asset
+type assetTypeMapping struct {
+	AssetType string
+	// NameFormat asset name, {attribute} placeholders are replaced by planned values, {attribute:base} by their last path segment
+	NameFormat string
+	// IAM is member, binding or policy when the resource grants roles on the asset, empty otherwise
+	IAM string
+	// Renames from camelCase path to API name, relative to the object holding the field, [] iterates over arrays
+	Renames [][2]string
+	// Lists paths to API arrays that terraform declares as blocks limited to one item
+	Lists []string
+	// Adjust sets values the API derives from others
+	Adjust func(data map[string]interface{})
+}
+
+// assetTypeMappings supported terraform resource types
+var assetTypeMappings = map[string]assetTypeMapping{
+	"google_bigquery_dataset": {
+		AssetType:  "bigquery.googleapis.com/Dataset",
+		NameFormat: "//bigquery.googleapis.com/projects/{project}/datasets/{dataset_id}",
+		Renames:    [][2]string{{"datasetId", "datasetReference.datasetId"}},
+	},
+	"google_bigquery_dataset_iam_binding": {
+		AssetType:  "bigquery.googleapis.com/Dataset",
+		NameFormat: "//bigquery.googleapis.com/projects/{project}/datasets/{dataset_id}",
+		IAM:        "binding",
+	},
+	"google_bigquery_dataset_iam_member": {
+		AssetType:  "bigquery.googleapis.com/Dataset",
+		NameFormat: "//bigquery.googleapis.com/projects/{project}/datasets/{dataset_id}",
+		IAM:        "member",
+	},
+	"google_bigquery_dataset_iam_policy": {
+		AssetType:  "bigquery.googleapis.com/Dataset",
+		NameFormat: "//bigquery.googleapis.com/projects/{project}/datasets/{dataset_id}",
+		IAM:        "policy",
+	},
+	"google_compute_firewall": {
+		AssetType:  "compute.googleapis.com/Firewall",
+		NameFormat: "//compute.googleapis.com/projects/{project}/global/firewalls/{name}",
+		Renames: [][2]string{
+			{"allow", "allowed"},
+			{"deny", "denied"},
+			{"allowed[].protocol", "IPProtocol"},
+			{"denied[].protocol", "IPProtocol"}},
+		Lists: []string{"allowed", "denied"},
+		Adjust: func(data map[string]interface{}) {
+			// a log_config block, or the deprecated enable_logging, enables the firewall logs
+			if logConfig, ok := data["logConfig"].(map[string]interface{}); ok {
+				logConfig["enable"] = true
+			}
+			if enableLogging, ok := data["enableLogging"].(bool); ok {
+				if _, ok := data["logConfig"]; !ok {
+					data["logConfig"] = map[string]interface{}{"enable": enableLogging}
+				}
+				delete(data, "enableLogging")
+			}
+		},
+	},
+	"google_compute_instance": {
+		AssetType:  "compute.googleapis.com/Instance",
+		NameFormat: "//compute.googleapis.com/projects/{project}/zones/{zone}/instances/{name}",
+		Renames: [][2]string{
+			{"networkInterface", "networkInterfaces"},
+			{"networkInterfaces[].accessConfig", "accessConfigs"},
+			{"serviceAccount", "serviceAccounts"}},
+		Lists: []string{"networkInterfaces", "serviceAccounts"},
+	},
+	"google_compute_network": {
+		AssetType:  "compute.googleapis.com/Network",
+		NameFormat: "//compute.googleapis.com/projects/{project}/global/networks/{name}",
+	},
+	"google_compute_subnetwork": {
+		AssetType:  "compute.googleapis.com/Subnetwork",
+		NameFormat: "//compute.googleapis.com/projects/{project}/regions/{region}/subnetworks/{name}",
+	},
+	"google_container_cluster": {
+		AssetType:  "container.googleapis.com/Cluster",
+		NameFormat: "//container.googleapis.com/projects/{project}/locations/{location}/clusters/{name}",
+		Renames:    [][2]string{{"enableLegacyAbac", "legacyAbac.enabled"}},
+	},
+	"google_container_node_pool": {
+		AssetType:  "container.googleapis.com/NodePool",
+		NameFormat: "//container.googleapis.com/projects/{project}/locations/{location}/clusters/{cluster:base}/nodePools/{name}",
+		Renames:    [][2]string{{"nodeConfig", "config"}},
+	},
+	"google_dns_managed_zone": {
+		AssetType:  "dns.googleapis.com/ManagedZone",
+		NameFormat: "//dns.googleapis.com/projects/{project}/managedZones/{name}",
+	},
+	"google_kms_crypto_key": {
+		AssetType:  "cloudkms.googleapis.com/CryptoKey",
+		NameFormat: "//cloudkms.googleapis.com/{key_ring}/cryptoKeys/{name}",
+	},
+	"google_kms_crypto_key_iam_binding": {
+		AssetType:  "cloudkms.googleapis.com/CryptoKey",
+		NameFormat: "//cloudkms.googleapis.com/{crypto_key_id}",
+		IAM:        "binding",
+	},
+	"google_kms_crypto_key_iam_member": {
+		AssetType:  "cloudkms.googleapis.com/CryptoKey",
+		NameFormat: "//cloudkms.googleapis.com/{crypto_key_id}",
+		IAM:        "member",
+	},
+	"google_project": {
+		AssetType:  "cloudresourcemanager.googleapis.com/Project",
+		NameFormat: "//cloudresourcemanager.googleapis.com/projects/{project_id}",
+	},
+	"google_project_iam_binding": {
+		AssetType:  "cloudresourcemanager.googleapis.com/Project",
+		NameFormat: "//cloudresourcemanager.googleapis.com/projects/{project:base}",
+		IAM:        "binding",
+	},
+	"google_project_iam_member": {
+		AssetType:  "cloudresourcemanager.googleapis.com/Project",
+		NameFormat: "//cloudresourcemanager.googleapis.com/projects/{project:base}",
+		IAM:        "member",
+	},
+	"google_project_iam_policy": {
+		AssetType:  "cloudresourcemanager.googleapis.com/Project",
+		NameFormat: "//cloudresourcemanager.googleapis.com/projects/{project:base}",
+		IAM:        "policy",
+	},
+	"google_pubsub_topic": {
+		AssetType:  "pubsub.googleapis.com/Topic",
+		NameFormat: "//pubsub.googleapis.com/projects/{project}/topics/{name}",
+	},
+	"google_pubsub_topic_iam_binding": {
+		AssetType:  "pubsub.googleapis.com/Topic",
+		NameFormat: "//pubsub.googleapis.com/projects/{project}/topics/{topic:base}",
+		IAM:        "binding",
+	},
+	"google_pubsub_topic_iam_member": {
+		AssetType:  "pubsub.googleapis.com/Topic",
+		NameFormat: "//pubsub.googleapis.com/projects/{project}/topics/{topic:base}",
+		IAM:        "member",
+	},
+	"google_service_account": {
+		AssetType:  "iam.googleapis.com/ServiceAccount",
+		NameFormat: "//iam.googleapis.com/projects/{project}/serviceAccounts/{account_id}@{project}.iam.gserviceaccount.com",
+	},
+	"google_sql_database_instance": {
+		AssetType:  "sqladmin.googleapis.com/Instance",
+		NameFormat: "//cloudsql.googleapis.com/projects/{project}/instances/{name}",
+	},
+	"google_storage_bucket": {
+		AssetType:  "storage.googleapis.com/Bucket",
+		NameFormat: "//storage.googleapis.com/{name}",
+		Renames:    [][2]string{{"uniformBucketLevelAccess", "iamConfiguration.uniformBucketLevelAccess.enabled"}},
+		Adjust: func(data map[string]interface{}) {
+			// the API returns locations upper case
+			if location, ok := data["location"].(string); ok {
+				data["location"] = strings.ToUpper(location)
+			}
+		},
+	},
+	"google_storage_bucket_iam_binding": {
+		AssetType:  "storage.googleapis.com/Bucket",
+		NameFormat: "//storage.googleapis.com/{bucket:base}",
+		IAM:        "binding",
+	},
+	"google_storage_bucket_iam_member": {
+		AssetType:  "storage.googleapis.com/Bucket",
+		NameFormat: "//storage.googleapis.com/{bucket:base}",
+		IAM:        "member",
+	},
+	"google_storage_bucket_iam_policy": {
+		AssetType:  "storage.googleapis.com/Bucket",
+		NameFormat: "//storage.googleapis.com/{bucket:base}",
+		IAM:        "policy",
+	},
+}
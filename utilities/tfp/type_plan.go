@@ -0,0 +1,53 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfp
+
+import "encoding/json"
+
+// plan the subset of terraform show -json output used to build assets
+type plan struct {
+	FormatVersion string `json:"format_version"`
+	PlannedValues struct {
+		RootModule module `json:"root_module"`
+	} `json:"planned_values"`
+	Configuration struct {
+		ProviderConfig map[string]providerConfig `json:"provider_config"`
+	} `json:"configuration"`
+}
+
+// module resources planned in a module and its child modules
+type module struct {
+	Address      string     `json:"address"`
+	Resources    []resource `json:"resources"`
+	ChildModules []module   `json:"child_modules"`
+}
+
+// resource planned values of one resource instance
+type resource struct {
+	Address      string                 `json:"address"`
+	Mode         string                 `json:"mode"`
+	Type         string                 `json:"type"`
+	Name         string                 `json:"name"`
+	ProviderName string                 `json:"provider_name"`
+	Values       map[string]interface{} `json:"values"`
+}
+
+// providerConfig provider block, only constant expressions can be resolved before apply
+type providerConfig struct {
+	Name        string `json:"name"`
+	Expressions map[string]struct {
+		ConstantValue json.RawMessage `json:"constant_value"`
+	} `json:"expressions"`
+}
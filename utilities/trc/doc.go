@@ -0,0 +1,24 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package trc helps with distributed tracing: one OpenTelemetry span per microservice hop exported to Cloud Trace
+//
+// The W3C trace context is propagated from hop to hop in the traceparent Pub/Sub message attribute.
+// When a message has no traceparent, e.g. a Cloud Asset Inventory real-time feed message,
+// the trace ID is derived from the origin step of the step stack, so the hops of a same origin event share a trace.
+//
+// Sampling is decided on the trace ID, so all the hops of a trace take the same decision.
+// A sampled dump trace contains one span per asset.
+//
+// The trace ID is also set in log entries to correlate logs and traces.
+package trc
@@ -0,0 +1,33 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"crypto/sha256"
+
+	"github.com/BrunoReboul/ram/utilities/logging"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+)
+
+// getStepSpanContext derives a span context from a step ID, the same step always gives the same trace ID
+func getStepSpanContext(step logging.Step) (spanContext apitrace.SpanContext) {
+	if step.StepID == "" {
+		return spanContext
+	}
+	sum := sha256.Sum256([]byte(step.StepID))
+	copy(spanContext.TraceID[:], sum[:16])
+	copy(spanContext.SpanID[:], sum[16:24])
+	return spanContext
+}
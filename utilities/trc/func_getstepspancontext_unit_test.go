@@ -0,0 +1,55 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"testing"
+
+	"github.com/BrunoReboul/ram/utilities/logging"
+)
+
+func TestUnitGetStepSpanContext(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		step      logging.Step
+		wantValid bool
+	}{
+		{
+			name:      "cai_step",
+			step:      logging.Step{StepID: "//compute.googleapis.com/projects/p/zones/z/instances/i/2020-12-01T10:00:00Z"},
+			wantValid: true,
+		},
+		{
+			name:      "empty_step",
+			step:      logging.Step{},
+			wantValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := getStepSpanContext(tc.step)
+			if got.IsValid() != tc.wantValid {
+				t.Errorf("Want valid %v got %v", tc.wantValid, got.IsValid())
+			}
+			again := getStepSpanContext(tc.step)
+			if got != again {
+				t.Errorf("Want the same span context for the same step, got %v and %v", got, again)
+			}
+		})
+	}
+}
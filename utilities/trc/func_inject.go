@@ -0,0 +1,29 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagators"
+)
+
+// Inject sets the traceparent of the current span in the Pub/Sub message attributes
+func Inject(ctx context.Context, attributes map[string]string) {
+	if attributes == nil {
+		return
+	}
+	propagators.TraceContext{}.Inject(ctx, attributesCarrier(attributes))
+}
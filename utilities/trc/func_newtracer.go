@@ -0,0 +1,48 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"context"
+
+	texporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
+	apitrace "go.opentelemetry.io/otel/api/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewTracer returns a tracer exporting to the Cloud Trace of the project, or a no export tracer when sampling is zero
+func NewTracer(ctx context.Context, projectID string, microserviceName string, parameters Parameters) (tracer Tracer, err error) {
+	tracer.projectID = projectID
+	if parameters.SamplePercent == 0 {
+		tracer.tracer = apitrace.NoopTracerProvider().Tracer(microserviceName)
+		return tracer, nil
+	}
+	ratioSampler := sdktrace.TraceIDRatioBased(float64(parameters.SamplePercent) / 100)
+	tracerProvider, flush, err := texporter.NewExportPipeline(
+		[]texporter.Option{
+			texporter.WithContext(ctx),
+			texporter.WithProjectID(projectID),
+		},
+		sdktrace.WithConfig(sdktrace.Config{
+			DefaultSampler: sdktrace.ParentBased(ratioSampler, sdktrace.WithRemoteParentNotSampled(ratioSampler)),
+		}),
+	)
+	if err != nil {
+		return tracer, err
+	}
+	tracer.flush = flush
+	tracer.tracer = tracerProvider.Tracer(microserviceName)
+	return tracer, nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+// EndSpan ends the span and flushes it to Cloud Trace before the serverless instance is throttled
+func (tracer Tracer) EndSpan(span trace.Span) {
+	span.End()
+	if tracer.flush != nil {
+		tracer.flush()
+	}
+}
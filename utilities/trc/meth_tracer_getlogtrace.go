@@ -0,0 +1,34 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/api/trace"
+)
+
+// GetLogTrace returns the trace to be set in log entries, empty when no trace
+func (tracer Tracer) GetLogTrace(ctx context.Context) string {
+	spanContext := trace.SpanFromContext(ctx).SpanContext()
+	if !spanContext.IsValid() {
+		spanContext = trace.RemoteSpanContextFromContext(ctx)
+	}
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return fmt.Sprintf("projects/%s/traces/%s", tracer.projectID, spanContext.TraceID.String())
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"context"
+
+	"github.com/BrunoReboul/ram/utilities/logging"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/propagators"
+)
+
+// StartSpan starts the span of a microservice hop, child of the traceparent message attribute when present,
+// else child of the origin step of the step stack.
+// The span starts at the last step timestamp, aka the triggering message publish time
+func (tracer Tracer) StartSpan(ctx context.Context, name string, attributes map[string]string, stepStack logging.Steps) (spanCtx context.Context, span trace.Span) {
+	spanCtx = propagators.TraceContext{}.Extract(ctx, attributesCarrier(attributes))
+	if !trace.RemoteSpanContextFromContext(spanCtx).IsValid() && len(stepStack) > 0 {
+		spanCtx = trace.ContextWithRemoteSpanContext(spanCtx, getStepSpanContext(stepStack[0]))
+	}
+	spanOptions := []trace.SpanOption{trace.WithSpanKind(trace.SpanKindConsumer)}
+	if len(stepStack) > 0 {
+		lastStep := stepStack[len(stepStack)-1]
+		spanOptions = append(spanOptions,
+			trace.WithAttributes(
+				label.String("ram.origin_step_id", stepStack[0].StepID),
+				label.String("ram.step_id", lastStep.StepID)))
+		if !lastStep.StepTimestamp.IsZero() {
+			spanOptions = append(spanOptions, trace.WithTimestamp(lastStep.StepTimestamp))
+		}
+	}
+	return tracer.tracer.Start(spanCtx, name, spanOptions...)
+}
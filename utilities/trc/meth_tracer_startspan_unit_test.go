@@ -0,0 +1,64 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BrunoReboul/ram/utilities/logging"
+)
+
+func TestUnitStartSpan(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		attributes map[string]string
+		stepStack  logging.Steps
+		wantTrace  string
+	}{
+		{
+			name:       "from_traceparent",
+			attributes: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			stepStack:  logging.Steps{{StepID: "origin"}},
+			wantTrace:  "projects/myproject/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:      "from_step_stack",
+			stepStack: logging.Steps{{StepID: "origin"}},
+			wantTrace: "projects/myproject/traces/" + getStepSpanContext(logging.Step{StepID: "origin"}).TraceID.String(),
+		},
+		{
+			name:      "no_trace",
+			wantTrace: "",
+		},
+	}
+
+	tracer, err := NewTracer(context.Background(), "myproject", "test", Parameters{SamplePercent: 0})
+	if err != nil {
+		t.Fatalf("Did not expect an error an got %s", err.Error())
+	}
+	for _, tc := range testCases {
+		tc := tc // https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			spanCtx, span := tracer.StartSpan(context.Background(), tc.name, tc.attributes, tc.stepStack)
+			defer tracer.EndSpan(span)
+			got := tracer.GetLogTrace(spanCtx)
+			if got != tc.wantTrace {
+				t.Errorf("Want %s got %s", tc.wantTrace, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trc
+
+// attributesCarrier adapts Pub/Sub message attributes to the OpenTelemetry text map carrier
+type attributesCarrier map[string]string
+
+// Get returns the value of an attribute
+func (carrier attributesCarrier) Get(key string) string {
+	return carrier[key]
+}
+
+// Set sets the value of an attribute
+func (carrier attributesCarrier) Set(key string, value string) {
+	carrier[key] = value
+}
@@ -13,4 +13,13 @@
 // limitations under the License.
 
 // Package validater helps to validate struct fields
+//
+// Rules are set in the `valid` struct tag, separated by ';', arguments separated by ','
+//
+// isNotZeroValue, isAvailableMemory, isOneOf,a,b, isMatching,regex, isMapKeyMatching,regex, isMapValueMatching,regex,
+// isCron, isDuration, isEmail, isInRange,min,max
+//
+// Except isNotZeroValue, rules on strings accept the zero value, e.g. `valid:"isNotZeroValue;isDuration"` makes a duration required
+//
+// Rules across several fields are checked by the Validate() error method of the struct, when it has one
 package validater
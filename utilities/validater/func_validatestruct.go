@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -55,7 +56,7 @@ func (v isNotZeroValueValidater) validate(value interface{}) (bool, error) {
 		if value.(int64) == 0 {
 			return false, fmt.Errorf("Should NOT be a zero value %s", kind)
 		}
-	case reflect.Slice:
+	case reflect.Slice, reflect.Map:
 		if reflect.ValueOf(value).Len() == 0 {
 			return false, fmt.Errorf("Should NOT be a zero value %s", kind)
 		}
@@ -85,16 +86,45 @@ func (v isAvailableMemoryMbValidater) validate(value interface{}) (bool, error)
 	return false, fmt.Errorf("Should be one of %v", acceptedValueList)
 }
 
+// getValidater returns the validater matching a tag value, rules are separated by ';' and rule arguments by ','
 func getValidater(kind reflect.Kind, tagValue string) validater {
+	if tagValue == "" || tagValue == "-" {
+		return defaultValidater{}
+	}
+	rules := strings.Split(tagValue, ";")
+	if len(rules) > 1 {
+		var v multiValidater
+		for _, rule := range rules {
+			v.validaters = append(v.validaters, getValidater(kind, rule))
+		}
+		return v
+	}
 	tagValueParts := strings.Split(tagValue, ",")
 	tagPrefix := tagValueParts[0]
+	tagArguments := tagValueParts[1:]
 	switch tagPrefix {
 	case "isNotZeroValue":
 		return isNotZeroValueValidater{}
 	case "isAvailableMemory":
 		return isAvailableMemoryMbValidater{}
+	case "isOneOf":
+		return isOneOfValidater{acceptedValueList: tagArguments}
+	case "isMatching":
+		return newIsMatchingValidater(strings.Join(tagArguments, ","))
+	case "isMapKeyMatching":
+		return newIsMapMatchingValidater(strings.Join(tagArguments, ","), true)
+	case "isMapValueMatching":
+		return newIsMapMatchingValidater(strings.Join(tagArguments, ","), false)
+	case "isCron":
+		return isCronValidater{}
+	case "isDuration":
+		return isDurationValidater{}
+	case "isEmail":
+		return isEmailValidater{}
+	case "isInRange":
+		return newIsInRangeValidater(tagArguments)
 	}
-	return defaultValidater{}
+	return invalidRuleValidater{err: fmt.Errorf("Unknown validation rule '%s'", tagValue)}
 }
 
 // getValidationErrors recursively loop through a struct to find validation errors
@@ -116,6 +146,10 @@ func getValidationErrors(structure interface{}, pedigree string) []error {
 		typeField := value.Type().Field(i)
 		if valueField.Kind() == reflect.Interface {
 			valueField = valueField.Elem()
+			if !valueField.IsValid() {
+				// nil interface, e.g. free form parameters not set, nothing to validate
+				continue
+			}
 		}
 		// time.Time type is retreived as struct, but contains only filtered or unexported fields. Results: crach the validater
 		// variable of type time.Type MUST discard validater. time.Time is retreived as struct with only unexported field, leading to crash recurusivity of validater
@@ -124,6 +158,20 @@ func getValidationErrors(structure interface{}, pedigree string) []error {
 			// log.Printf("Explore %s %s", typeField.Type.Kind(), typeField.Name)
 			childErrs := getValidationErrors(valueField.Interface(), fmt.Sprintf("%s/%s", pedigree, typeField.Name))
 			errs = append(errs, childErrs...)
+		} else if typeField.Tag.Get(tagKeyName) != "-" &&
+			valueField.Kind() == reflect.Map && valueField.Type().Elem().Kind() == reflect.Struct {
+			// map of structs, e.g. schedulers, are validated as a whole then explored key by key
+			validater := getValidater(typeField.Type.Kind(), typeField.Tag.Get(tagKeyName))
+			ok, err := validater.validate(valueField.Interface())
+			if !ok {
+				errs = append(errs, fmt.Errorf("Validater error %s '%s' %v", pedigree, typeField.Name, err))
+			}
+			keys := valueField.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j]) })
+			for _, key := range keys {
+				childErrs := getValidationErrors(valueField.MapIndex(key).Interface(), fmt.Sprintf("%s/%s/%v", pedigree, typeField.Name, key))
+				errs = append(errs, childErrs...)
+			}
 		} else {
 			// log.Printf("%s %s %s %s", pedigree, typeField.Name, typeField.Type.Kind(), typeField.Tag.Get(tagKeyName))
 			validater := getValidater(typeField.Type.Kind(), typeField.Tag.Get(tagKeyName))
@@ -133,6 +181,11 @@ func getValidationErrors(structure interface{}, pedigree string) []error {
 			}
 		}
 	}
+	if v, ok := value.Interface().(structValidater); ok {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Validater error %s %v", pedigree, err))
+		}
+	}
 	return errs
 }
 
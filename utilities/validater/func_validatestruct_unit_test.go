@@ -16,12 +16,26 @@ package validater
 
 import (
 	"bytes"
+	"fmt"
 	"log"
 	"os"
 	"strings"
 	"testing"
 )
 
+// exclusiveFields accepts only one of its fields set
+type exclusiveFields struct {
+	A string
+	B string
+}
+
+func (e exclusiveFields) Validate() error {
+	if e.A != "" && e.B != "" {
+		return fmt.Errorf("A and B are exclusive")
+	}
+	return nil
+}
+
 func TestUnitValidater(t *testing.T) {
 	type isNotZeroValueString struct {
 		S string `valid:"isNotZeroValue"`
@@ -170,6 +184,26 @@ func TestUnitValidater(t *testing.T) {
 				"my/pe/di/gree/LevelB/LevelC/IsNotZeroValueInt64",
 			},
 		},
+		{
+			name:           "structValidaterValid",
+			structure:      exclusiveFields{A: "a"},
+			pedigree:       "my/pe/di/gree",
+			wantValidation: true,
+		},
+		{
+			name: "structValidaterInvalidInLevel",
+			structure: struct {
+				ExclusiveFields exclusiveFields
+			}{
+				ExclusiveFields: exclusiveFields{A: "a", B: "b"},
+			},
+			pedigree:          "my/pe/di/gree",
+			wantValidation:    false,
+			wantErrorMsgCount: 1,
+			wantErrorMsgContains: []string{
+				"my/pe/di/gree/ExclusiveFields A and B are exclusive",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -213,6 +247,108 @@ func TestUnitValidater(t *testing.T) {
 	}
 }
 
+func TestUnitValidaterRules(t *testing.T) {
+	type isOneOf struct {
+		S string `valid:"isOneOf,RESOURCE,IAM_POLICY"`
+	}
+	type isMatching struct {
+		S string `valid:"isMatching,^[a-z]{2,4}$"`
+	}
+	type isCron struct {
+		S string `valid:"isCron"`
+	}
+	type isDuration struct {
+		S string `valid:"isDuration"`
+	}
+	type isEmail struct {
+		S string `valid:"isEmail"`
+	}
+	type isInRange struct {
+		I int64 `valid:"isInRange,1,200"`
+	}
+	type isMapMatching struct {
+		M map[string]string `valid:"isNotZeroValue;isMapKeyMatching,^[a-z]+$;isMapValueMatching,^[0-9]+$"`
+	}
+	type isNotZeroValueAndOneOf struct {
+		S string `valid:"isNotZeroValue;isOneOf,a,b"`
+	}
+	type mapOfStruct struct {
+		Schedulers map[string]struct {
+			Schedule string `valid:"isCron"`
+		}
+	}
+	type unknownRule struct {
+		S string `valid:"isBlaBla"`
+	}
+	var testCases = []struct {
+		name              string
+		structure         interface{}
+		wantValidation    bool
+		wantErrorMsgCount int
+	}{
+		{name: "isOneOfValid", structure: isOneOf{"IAM_POLICY"}, wantValidation: true},
+		{name: "isOneOfEmpty", structure: isOneOf{""}, wantValidation: true},
+		{name: "isOneOfInvalid", structure: isOneOf{"ORG_POLICY"}, wantErrorMsgCount: 1},
+		{name: "isMatchingValid", structure: isMatching{"abc"}, wantValidation: true},
+		{name: "isMatchingInvalid", structure: isMatching{"abcdef"}, wantErrorMsgCount: 1},
+		{name: "isCronValid", structure: isCron{"0 */6 * * *"}, wantValidation: true},
+		{name: "isCronValidNames", structure: isCron{"30 2 1-15 JAN,jul MON-FRI"}, wantValidation: true},
+		{name: "isCronFieldCount", structure: isCron{"0 */6 * *"}, wantErrorMsgCount: 1},
+		{name: "isCronOutOfRange", structure: isCron{"0 24 * * *"}, wantErrorMsgCount: 1},
+		{name: "isCronBadRange", structure: isCron{"0 5-2 * * *"}, wantErrorMsgCount: 1},
+		{name: "isCronBadStep", structure: isCron{"*/0 * * * *"}, wantErrorMsgCount: 1},
+		{name: "isDurationValid", structure: isDuration{"600s"}, wantValidation: true},
+		{name: "isDurationInvalid", structure: isDuration{"600"}, wantErrorMsgCount: 1},
+		{name: "isDurationNegative", structure: isDuration{"-1s"}, wantErrorMsgCount: 1},
+		{name: "isEmailValid", structure: isEmail{"admin@example.com"}, wantValidation: true},
+		{name: "isEmailInvalid", structure: isEmail{"admin.example.com"}, wantErrorMsgCount: 1},
+		{name: "isEmailWithDisplayName", structure: isEmail{"Admin <admin@example.com>"}, wantErrorMsgCount: 1},
+		{name: "isInRangeValid", structure: isInRange{200}, wantValidation: true},
+		{name: "isInRangeTooHigh", structure: isInRange{201}, wantErrorMsgCount: 1},
+		{name: "isInRangeTooLow", structure: isInRange{0}, wantErrorMsgCount: 1},
+		{name: "isMapMatchingValid", structure: isMapMatching{map[string]string{"dev": "111", "prd": "222"}}, wantValidation: true},
+		{name: "isMapMatchingEmpty", structure: isMapMatching{map[string]string{}}, wantErrorMsgCount: 1},
+		{name: "isMapMatchingBadKey", structure: isMapMatching{map[string]string{"DEV": "111"}}, wantErrorMsgCount: 1},
+		{name: "isMapMatchingBadValue", structure: isMapMatching{map[string]string{"dev": "abc"}}, wantErrorMsgCount: 1},
+		{name: "isNotZeroValueAndOneOfEmpty", structure: isNotZeroValueAndOneOf{""}, wantErrorMsgCount: 1},
+		{
+			name: "mapOfStructOneInvalid",
+			structure: mapOfStruct{Schedulers: map[string]struct {
+				Schedule string `valid:"isCron"`
+			}{"dev": {"0 * * * *"}, "prd": {"every day"}}},
+			wantErrorMsgCount: 1,
+		},
+		{name: "unknownRule", structure: unknownRule{"blabla"}, wantErrorMsgCount: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// DO NOT RUN CONCURENTLY (aka no t.Parallel() ), as the log output is captured
+			var buffer bytes.Buffer
+			log.SetOutput(&buffer)
+			defer func() {
+				log.SetOutput(os.Stderr)
+			}()
+			err := ValidateStruct(tc.structure, "my/pe/di/gree")
+			errorMsgString := buffer.String()
+			foundErrorMsgCount := countRune(errorMsgString, '\n')
+			if tc.wantErrorMsgCount != foundErrorMsgCount {
+				t.Errorf("Want %d error messages, got %d", tc.wantErrorMsgCount, foundErrorMsgCount)
+				t.Log("Error message list:" + string('\n') + errorMsgString)
+			}
+			if tc.wantValidation {
+				if err != nil {
+					t.Errorf("Want NO error, got %v", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("Should send back an error and is NOT")
+				}
+			}
+		})
+	}
+}
+
 func countRune(s string, r rune) int {
 	count := 0
 	for _, c := range s {
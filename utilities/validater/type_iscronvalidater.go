@@ -0,0 +1,114 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validater
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// cronField describes one field of a unix-cron expression
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// isCronValidater accepts only unix-cron expressions as used by Cloud Scheduler, e.g. "0 */6 * * *". Zero value is accepted, combine with isNotZeroValue to make it required
+type isCronValidater struct {
+}
+
+// validate interface returns true for a valid field, false and why in the error otherwise
+func (v isCronValidater) validate(value interface{}) (bool, error) {
+	s, ok := value.(string)
+	if !ok {
+		return false, fmt.Errorf("Unmanaged kind by 'isCronValidater' %s", reflect.TypeOf(value).Kind())
+	}
+	if s == "" {
+		return true, nil
+	}
+	parts := strings.Fields(s)
+	if len(parts) != len(cronFields) {
+		return false, fmt.Errorf("'%s' should be a unix-cron expression with %d fields, got %d", s, len(cronFields), len(parts))
+	}
+	for i, part := range parts {
+		err := cronFields[i].check(part)
+		if err != nil {
+			return false, fmt.Errorf("'%s' should be a unix-cron expression %v", s, err)
+		}
+	}
+	return true, nil
+}
+
+// check validates one field of a cron expression: list of '*', value, or range, each with an optional step
+func (f cronField) check(part string) error {
+	for _, item := range strings.Split(part, ",") {
+		rangePart := item
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangePart = item[:i]
+			step, err := strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return fmt.Errorf("invalid step in %s field '%s'", f.name, item)
+			}
+		}
+		if rangePart == "*" {
+			continue
+		}
+		bounds := strings.Split(rangePart, "-")
+		if len(bounds) > 2 {
+			return fmt.Errorf("invalid range in %s field '%s'", f.name, item)
+		}
+		values := make([]int, 0, len(bounds))
+		for _, bound := range bounds {
+			value, err := f.parseValue(bound)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		if len(values) == 2 && values[0] > values[1] {
+			return fmt.Errorf("invalid range in %s field '%s'", f.name, item)
+		}
+	}
+	return nil
+}
+
+// parseValue converts a number or a name, e.g. MON, into an integer within the field bounds
+func (f cronField) parseValue(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.ToUpper(s) == name {
+			return i + f.min, nil
+		}
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field '%s'", f.name, s)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("%s field value %d should be between %d and %d", f.name, value, f.min, f.max)
+	}
+	return value, nil
+}
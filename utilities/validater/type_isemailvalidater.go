@@ -0,0 +1,41 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validater
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+)
+
+// isEmailValidater accepts only a bare email address, e.g. superAdminEmail. Zero value is accepted, combine with isNotZeroValue to make it required
+type isEmailValidater struct {
+}
+
+// validate interface returns true for a valid field, false and why in the error otherwise
+func (v isEmailValidater) validate(value interface{}) (bool, error) {
+	s, ok := value.(string)
+	if !ok {
+		return false, fmt.Errorf("Unmanaged kind by 'isEmailValidater' %s", reflect.TypeOf(value).Kind())
+	}
+	if s == "" {
+		return true, nil
+	}
+	address, err := mail.ParseAddress(s)
+	if err != nil || address.Address != s {
+		return false, fmt.Errorf("'%s' should be an email address", s)
+	}
+	return true, nil
+}
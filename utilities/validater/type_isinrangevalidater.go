@@ -0,0 +1,60 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validater
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// isInRangeValidater accepts only integers between a min and a max, both included, e.g. maxResultsPerPage
+type isInRangeValidater struct {
+	min int64
+	max int64
+}
+
+// newIsInRangeValidater parses the min and max rule arguments, or returns an invalid rule validater
+func newIsInRangeValidater(arguments []string) validater {
+	if len(arguments) != 2 {
+		return invalidRuleValidater{err: fmt.Errorf("Validation rule isInRange expects min and max, got %v", arguments)}
+	}
+	min, err := strconv.ParseInt(arguments[0], 10, 64)
+	if err != nil {
+		return invalidRuleValidater{err: fmt.Errorf("Invalid min in validation rule isInRange %v", err)}
+	}
+	max, err := strconv.ParseInt(arguments[1], 10, 64)
+	if err != nil {
+		return invalidRuleValidater{err: fmt.Errorf("Invalid max in validation rule isInRange %v", err)}
+	}
+	return isInRangeValidater{min: min, max: max}
+}
+
+// validate interface returns true for a valid field, false and why in the error otherwise
+func (v isInRangeValidater) validate(value interface{}) (bool, error) {
+	var i int64
+	switch n := value.(type) {
+	case int64:
+		i = n
+	case int:
+		i = int64(n)
+	default:
+		return false, fmt.Errorf("Unmanaged kind by 'isInRangeValidater' %s", reflect.TypeOf(value).Kind())
+	}
+	if i < v.min || i > v.max {
+		return false, fmt.Errorf("%d should be between %d and %d", i, v.min, v.max)
+	}
+	return true, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validater
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// isMapMatchingValidater accepts only maps of strings where each key, or each value, matches a regular expression, e.g. organizationIDs
+type isMapMatchingValidater struct {
+	re       *regexp.Regexp
+	checkKey bool
+}
+
+// newIsMapMatchingValidater compiles the regular expression, or returns an invalid rule validater
+func newIsMapMatchingValidater(expr string, checkKey bool) validater {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return invalidRuleValidater{err: fmt.Errorf("Invalid regular expression in validation rule %v", err)}
+	}
+	return isMapMatchingValidater{re: re, checkKey: checkKey}
+}
+
+// validate interface returns true for a valid field, false and why in the error otherwise
+func (v isMapMatchingValidater) validate(value interface{}) (bool, error) {
+	m := reflect.ValueOf(value)
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return false, fmt.Errorf("Unmanaged kind by 'isMapMatchingValidater' %s", m.Kind())
+	}
+	keys := make([]string, 0, m.Len())
+	for _, key := range m.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if v.checkKey {
+			if !v.re.MatchString(key) {
+				return false, fmt.Errorf("key '%s' should match %s", key, v.re.String())
+			}
+			continue
+		}
+		mapValue := m.MapIndex(reflect.ValueOf(key))
+		if mapValue.Kind() != reflect.String {
+			return false, fmt.Errorf("Unmanaged map value kind by 'isMapMatchingValidater' %s", mapValue.Kind())
+		}
+		if !v.re.MatchString(mapValue.String()) {
+			return false, fmt.Errorf("value '%s' of key '%s' should match %s", mapValue.String(), key, v.re.String())
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validater
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// isMatchingValidater accepts only strings matching a regular expression, e.g. topic and bucket names. Zero value is accepted, combine with isNotZeroValue to make it required
+type isMatchingValidater struct {
+	re *regexp.Regexp
+}
+
+// newIsMatchingValidater compiles the regular expression, or returns an invalid rule validater
+func newIsMatchingValidater(expr string) validater {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return invalidRuleValidater{err: fmt.Errorf("Invalid regular expression in validation rule %v", err)}
+	}
+	return isMatchingValidater{re: re}
+}
+
+// validate interface returns true for a valid field, false and why in the error otherwise
+func (v isMatchingValidater) validate(value interface{}) (bool, error) {
+	s, ok := value.(string)
+	if !ok {
+		return false, fmt.Errorf("Unmanaged kind by 'isMatchingValidater' %s", reflect.TypeOf(value).Kind())
+	}
+	if s == "" || v.re.MatchString(s) {
+		return true, nil
+	}
+	return false, fmt.Errorf("'%s' should match %s", s, v.re.String())
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validater
+
+import (
+	"fmt"
+	"strings"
+)
+
+// multiValidater chains several validation rules on the same field
+type multiValidater struct {
+	validaters []validater
+}
+
+// validate interface returns true for a valid field, false and why in the error otherwise
+func (v multiValidater) validate(value interface{}) (bool, error) {
+	var reasons []string
+	for _, validater := range v.validaters {
+		ok, err := validater.validate(value)
+		if !ok {
+			reasons = append(reasons, err.Error())
+		}
+	}
+	if len(reasons) > 0 {
+		return false, fmt.Errorf("%s", strings.Join(reasons, ", "))
+	}
+	return true, nil
+}